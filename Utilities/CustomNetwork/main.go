@@ -0,0 +1,155 @@
+package main
+
+// CustomNetwork generates everything needed to start a private custom network: the genesis
+// definition with the initial balances and servers, and a factomd.conf for every server.
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/state"
+)
+
+// balances collects the repeated -balance flags
+type balances []state.GenesisBalance
+
+func (b *balances) String() string {
+	return fmt.Sprint(*b)
+}
+
+func (b *balances) Set(value string) error {
+	parts := strings.Split(value, "=")
+	if len(parts) != 2 {
+		return fmt.Errorf("balance %q is not of the form FA...=amount", value)
+	}
+	if !primitives.ValidateFUserStr(parts[0]) {
+		return fmt.Errorf("%q is not a factoid address", parts[0])
+	}
+	factoshis, err := primitives.ConvertFixedPoint(parts[1])
+	if err != nil {
+		return fmt.Errorf("%q is not an amount of factoids", parts[1])
+	}
+	amount, err := strconv.ParseUint(factoshis, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%q is not an amount of factoids", parts[1])
+	}
+	*b = append(*b, state.GenesisBalance{Address: parts[0], Amount: amount})
+	return nil
+}
+
+const nodeConfig = `; factomd.conf for %s of the custom network %q
+; Start with: factomd -network=CUSTOM -customnet=%s -config=%s
+[app]
+Network                 = CUSTOM
+NodeMode                = SERVER
+ExchangeRate            = %d
+CustomGenesisFile       = genesis.json
+CustomSpecialPeers      = "%s"
+; The bootstrap identity is taken from the genesis file, it is listed here for reference
+CustomBootstrapIdentity = %s
+CustomBootstrapKey      = %s
+IdentityChainID         = %s
+LocalServerPrivKey      = %s
+LocalServerPublicKey    = %s
+`
+
+// identityKeys is written next to the factomd.conf of a server, and for the skeleton identity
+const identityKeys = `; Keys of the identity %s
+IdentityChainID   = %s
+ManagementChainID = %s
+IdentityKey1      = %s
+IdentityKey2      = %s
+IdentityKey3      = %s
+IdentityKey4      = %s
+BlockSigningKey   = %s
+`
+
+func writeIdentityKeys(filename string, id *state.GenesisIdentity) error {
+	text := fmt.Sprintf(identityKeys, id.IdentityChainID, id.IdentityChainID, id.ManagementChainID,
+		id.IdentityKeys[0].PrivateKeyString(), id.IdentityKeys[1].PrivateKeyString(),
+		id.IdentityKeys[2].PrivateKeyString(), id.IdentityKeys[3].PrivateKeyString(),
+		id.SigningKey.PrivateKeyString())
+	return ioutil.WriteFile(filename, []byte(text), 0600)
+}
+
+func main() {
+	var initial balances
+	var (
+		name   = flag.String("name", "", "The -customnet name of the new network")
+		feds   = flag.Int("feds", 1, "Number of federated servers")
+		audits = flag.Int("audits", 0, "Number of audit servers")
+		rate   = flag.Uint64("rate", 1000, "Initial exchange rate in factoshis per entry credit")
+		peers  = flag.String("peers", "", "Special peers of every node, eg \"10.0.0.1:8110 10.0.0.2:8110\"")
+		out    = flag.String("o", "", "Directory to write the network to (defaults to the network name)")
+	)
+	flag.Var(&initial, "balance", "Initial balance as FA...=factoids, repeat for each address")
+	flag.Parse()
+
+	if *name == "" || *feds < 1 || *audits < 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+	if *out == "" {
+		*out = *name
+	}
+
+	fmt.Println("Creating the identity chains, this takes a while")
+	if err := writeNetwork(*out, *name, *feds, *audits, *rate, *peers, initial); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// writeNetwork generates a custom network and writes the directories of its servers, and the keys
+// of its skeleton identity, to out
+func writeNetwork(out string, name string, feds int, audits int, rate uint64, peers string, initial balances) error {
+	genesis, skeleton, servers := state.NewCustomNetwork(name, rate, initial, feds, audits)
+	if err := genesis.Validate(); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(genesis, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(out, 0700); err != nil {
+		return err
+	}
+	if err := writeIdentityKeys(filepath.Join(out, "skeleton.keys"), skeleton); err != nil {
+		return err
+	}
+	fmt.Printf("%-8s %s %s\n", "skeleton", skeleton.IdentityChainID, filepath.Join(out, "skeleton.keys"))
+
+	bootID, bootKey := genesis.BootstrapIdentity()
+	for i, id := range servers {
+		node := fmt.Sprintf("fed%d", i)
+		if i >= feds {
+			node = fmt.Sprintf("audit%d", i-feds)
+		}
+		dir := filepath.Join(out, node)
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, "genesis.json"), data, 0600); err != nil {
+			return err
+		}
+		if err := writeIdentityKeys(filepath.Join(dir, "identity.keys"), id); err != nil {
+			return err
+		}
+		config := filepath.Join(dir, "factomd.conf")
+		text := fmt.Sprintf(nodeConfig, node, name, name, config, rate, peers, bootID, bootKey,
+			id.IdentityChainID, id.SigningKey.PrivateKeyString(), id.SigningKey.PublicKeyString())
+		if err := ioutil.WriteFile(config, []byte(text), 0600); err != nil {
+			return err
+		}
+		fmt.Printf("%-8s %s %s\n", node, id.IdentityChainID, config)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+// readKey returns the public key of a private key in a keys file written by writeIdentityKeys
+func readKey(t *testing.T, filename string, name string) string {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[0] == name {
+			key, err := primitives.NewPrivateKeyFromHex(fields[2])
+			if err != nil {
+				t.Fatal(err)
+			}
+			return key.PublicKeyString()
+		}
+	}
+	t.Fatalf("%s has no %s", filename, name)
+	return ""
+}
+
+// TestBootCustomNetwork generates a custom network and boots its federated server from the
+// generated factomd.conf
+func TestBootCustomNetwork(t *testing.T) {
+	dir, err := ioutil.TempDir("", "CustomNetwork")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var initial balances
+	address := "FA2jK2HcLnRdS94dEcU27rF3meoJfpUcZPSinpb7AwQvPRY6RL1Q"
+	if err := initial.Set(address + "=12.5"); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeNetwork(dir, "boottest", 1, 0, 1000, "", initial); err != nil {
+		t.Fatal(err)
+	}
+	genesis, err := state.ReadCustomGenesis(filepath.Join(dir, "fed0", "genesis.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	skeletonKey := readKey(t, filepath.Join(dir, "skeleton.keys"), "BlockSigningKey")
+
+	state0 := testHelper.SetupSim("L", map[string]string{
+		"--network":   "CUSTOM",
+		"--customnet": "boottest",
+		"--config":    filepath.Join(dir, "fed0", "factomd.conf"),
+	}, 6, 0, 0, t)
+	testHelper.WaitBlocks(state0, 3)
+
+	fed := genesis.FederatedServers[0]
+	if state0.GetIdentityChainID().String() != fed.IdentityChainID || !state0.Leader {
+		t.Errorf("the node runs as %s (leader %v), expected the federated server %s", state0.GetIdentityChainID(), state0.Leader, fed.IdentityChainID)
+	}
+	var fa [32]byte
+	copy(fa[:], primitives.ConvertUserStrToAddress(address))
+	if balance := state0.FactoidState.GetFactoidBalance(fa); balance != 1250000000 {
+		t.Errorf("the genesis balance is %d", balance)
+	}
+
+	id := state0.IdentityControl.GetIdentity(state0.GetIdentityChainID())
+	if id == nil || id.ManagementChainID.IsZero() || id.SigningKey.String() != fed.PublicKey {
+		t.Errorf("the identity chains of the federated server were not created: %v", id)
+	}
+	if skeleton := state0.GetNetworkSkeletonIdentity().String(); skeleton != genesis.SkeletonIdentity {
+		t.Errorf("the skeleton identity is %s, expected %s", skeleton, genesis.SkeletonIdentity)
+	}
+	if key := state0.GetNetworkSkeletonKey().String(); key != skeletonKey {
+		t.Errorf("the skeleton key is %s, expected %s", key, skeletonKey)
+	}
+	testHelper.ShutDownEverything(t)
+}
@@ -0,0 +1,105 @@
+package main
+
+// MessageTrace reads the -debuglog logs of several nodes, correlates their records by message
+// hash and reconstructs the path of each message through the nodes: where it was received from,
+// held, acked, processed and sent on, with the time of each step.  The traces are written as
+// JSON, and optionally as an HTML timeline.
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+func main() {
+	jsonOut := flag.String("json", "-", "File to write the traces to as JSON, - for stdout, \"\" for none")
+	htmlOut := flag.String("html", "", "File to write the HTML timeline to")
+	hashes := flag.String("hash", "", "Comma separated hashes, or hash prefixes, of the messages to trace.  All messages when empty")
+	msgType := flag.String("type", "", "Only trace messages of this type, as the logs name it, like EOM or \"Commit Chain\"")
+	minNodes := flag.Int("minnodes", 1, "Only trace messages seen on at least this many nodes")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] <log files or directories>...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	t := new(Tracer)
+	for _, path := range flag.Args() {
+		if err := t.ReadPath(path); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	var prefixes []string
+	for _, h := range strings.Split(*hashes, ",") {
+		if h = strings.ToLower(strings.TrimSpace(h)); h != "" {
+			prefixes = append(prefixes, h)
+		}
+	}
+
+	var traces []*Trace
+	for _, tr := range t.Traces() {
+		if len(tr.Nodes) < *minNodes || *msgType != "" && !strings.EqualFold(tr.MsgType, *msgType) {
+			continue
+		}
+		if len(prefixes) > 0 && !matches(tr, prefixes) {
+			continue
+		}
+		traces = append(traces, tr)
+	}
+
+	if *jsonOut != "" {
+		err := writeFile(*jsonOut, func(w io.Writer) error {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(traces)
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if *htmlOut != "" {
+		if err := writeFile(*htmlOut, func(w io.Writer) error { return WriteTimeline(w, traces) }); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// matches returns true if the msghash or hash of the trace starts with one of the prefixes.  The
+// hashes of text logs are 6 hex digits, so a longer prefix matches the 6 digits it starts with.
+func matches(tr *Trace, prefixes []string) bool {
+	for _, p := range prefixes {
+		for _, h := range []string{tr.MsgHash, tr.Hash} {
+			if h != "" && (strings.HasPrefix(h, p) || strings.HasPrefix(p, h)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func writeFile(name string, write func(w io.Writer) error) error {
+	if name == "-" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
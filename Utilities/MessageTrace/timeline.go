@@ -0,0 +1,89 @@
+package main
+
+import (
+	"html/template"
+	"io"
+)
+
+// The HTML timeline has a section per message and a row per node, with a mark for each event at
+// its time since the message was first seen.  Hovering over a mark shows the event.
+
+type timelineMark struct {
+	Left  float64 // percent of the duration of the trace
+	Event *Event
+}
+
+type timelineRow struct {
+	Path  *NodePath
+	Marks []timelineMark
+}
+
+type timelineTrace struct {
+	Trace *Trace
+	Rows  []timelineRow
+}
+
+var timelineTemplate = template.Must(template.New("timeline").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Message trace</title>
+<style>
+body { font-family: sans-serif; font-size: 13px; }
+section { margin-bottom: 2em; }
+h2 { font-size: 15px; margin-bottom: 0; }
+.message { color: #555; font-family: monospace; word-break: break-all; }
+table { border-collapse: collapse; width: 100%; }
+td { padding: 2px 6px; white-space: nowrap; }
+td.line { width: 100%; }
+.line div { position: relative; height: 14px; border-bottom: 1px solid #ccc; }
+.line span { position: absolute; top: 1px; width: 8px; height: 12px; margin-left: -4px; background: #888; }
+.received { background: #2a7ab0 !important; }
+.dropped { background: #c0392b !important; }
+.held { background: #e67e22 !important; }
+.released { background: #f1c40f !important; }
+.acked { background: #8e44ad !important; }
+.processlist { background: #16a085 !important; }
+.processed { background: #27ae60 !important; }
+.sent, .rebroadcast { background: #34495e !important; }
+.legend span { display: inline-block; width: 10px; height: 10px; margin: 0 4px 0 12px; background: #888; }
+</style>
+</head>
+<body>
+<p class="legend">
+<span class="received"></span>received <span class="held"></span>held <span class="released"></span>released
+<span class="acked"></span>acked <span class="processlist"></span>process list <span class="processed"></span>processed
+<span class="sent"></span>sent or rebroadcast <span class="dropped"></span>dropped <span></span>queued or executed
+</p>
+{{range .}}<section>
+<h2>{{.Trace.MsgType}} {{.Trace.MsgHash}}</h2>
+<p class="message">{{.Trace.Message}}</p>
+<p>first seen on {{.Trace.Origin}} at {{.Trace.First.Format "15:04:05.000"}}, over {{.Trace.Duration}} ms on {{len .Rows}} nodes</p>
+<table>
+{{range .Rows}}<tr><td>{{.Path.Node}}</td><td>{{with .Path.From}}from {{.}}{{end}}</td><td class="line"><div>{{range .Marks}}<span class="{{.Event.Stage}}" style="left: {{.Left}}%" title="+{{.Event.Offset}} ms {{.Event.Stage}}: {{.Event.Log}} {{.Event.Note}}{{with .Event.Peer}} ({{.}}){{end}}"></span>{{end}}</div></td></tr>
+{{end}}</table>
+</section>
+{{end}}</body>
+</html>
+`))
+
+// WriteTimeline writes the traces as an HTML timeline
+func WriteTimeline(w io.Writer, traces []*Trace) error {
+	var view []timelineTrace
+	for _, tr := range traces {
+		v := timelineTrace{Trace: tr}
+		for _, n := range tr.Nodes {
+			row := timelineRow{Path: n}
+			for _, e := range n.events {
+				left := 0.0
+				if tr.Duration > 0 {
+					left = 100 * float64(e.Offset) / float64(tr.Duration)
+				}
+				row.Marks = append(row.Marks, timelineMark{left, e})
+			}
+			v.Rows = append(v.Rows, row)
+		}
+		view = append(view, v)
+	}
+	return timelineTemplate.Execute(w, view)
+}
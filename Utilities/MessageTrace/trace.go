@@ -0,0 +1,468 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FactomProject/factomd/log"
+)
+
+// The stages of the path of a message through a node
+const (
+	StageReceived    = "received"    // came in from a peer or the API
+	StageDropped     = "dropped"     // dropped anywhere
+	StageQueued      = "queued"      // put on or taken off a queue
+	StageHeld        = "held"        // put in holding, or held waiting on something
+	StageReleased    = "released"    // deleted from holding
+	StageAcked       = "acked"       // an ack of the message was seen, the events of the ack itself
+	StageProcessList = "processlist" // added to or logged from the process list
+	StageProcessed   = "processed"   // processed in the process list
+	StageSent        = "sent"        // sent by the node that made it
+	StageRebroadcast = "rebroadcast" // sent on by a node that received it
+	StageExecuted    = "executed"    // anything else the node did with it
+)
+
+// Event is one record of a message in the log of a node
+type Event struct {
+	Node     string    `json:"node"`
+	Log      string    `json:"log"`
+	Seq      int       `json:"seq"`
+	Time     time.Time `json:"time"`
+	Offset   int64     `json:"offset"` // milliseconds since the message was first seen on any node
+	Stage    string    `json:"stage"`
+	Note     string    `json:"note"`
+	Peer     string    `json:"peer,omitempty"`
+	DBHeight *int      `json:"dbheight,omitempty"`
+	Minute   *int      `json:"minute,omitempty"`
+	Ack      string    `json:"ack,omitempty"` // the msghash of the ack, for acked events
+
+	msgHash  string // as long as the log has it, 6 hex digits in text logs
+	hash     string
+	msgType  string
+	message  string
+	embedded bool
+}
+
+// NodePath is the path of a message through one node.  The times are milliseconds since the
+// message was first seen on any node, nil when the node didn't log that stage.
+type NodePath struct {
+	Node      string `json:"node"`
+	From      string `json:"from,omitempty"` // the peer the message was first received from
+	Received  *int64 `json:"received,omitempty"`
+	Held      *int64 `json:"held,omitempty"`
+	Acked     *int64 `json:"acked,omitempty"`
+	Processed *int64 `json:"processed,omitempty"`
+	Sent      *int64 `json:"sent,omitempty"` // sent or rebroadcast
+	Dropped   *int64 `json:"dropped,omitempty"`
+
+	events []*Event
+}
+
+// Trace is everything the logs say about one message, on every node
+type Trace struct {
+	MsgHash  string      `json:"msghash"`
+	Hash     string      `json:"hash,omitempty"`
+	MsgType  string      `json:"msgtype"`
+	Message  string      `json:"message"`
+	Origin   string      `json:"origin"` // the node that logged the message first
+	First    time.Time   `json:"first"`
+	Duration int64       `json:"duration"` // milliseconds from the first to the last event
+	Nodes    []*NodePath `json:"nodes"`
+	Events   []*Event    `json:"events"`
+}
+
+// Tracer collects the events of the logs it reads and correlates them into traces
+type Tracer struct {
+	events []*Event
+}
+
+// The line of a message in a text log, as log.logMessage writes it
+var textLine = regexp.MustCompile(`^\s*(\d+) (\d\d):(\d\d):(\d\d)\.(\d{3}) (.*?) M-([0-9a-f?]{6})\S*\|R-[0-9a-f?]{6}\S*\|H-([0-9a-f?]{6})\S*\|\S+\s+(.*?)\[\s*\d+\]:(.*)$`)
+
+// The height and minute a note of a node starts with
+var statePrefix = regexp.MustCompile(`^\s*(\d+)-:-(\d+) (.*)$`)
+
+// The peer at the start of a NetworkInputs note, and the node at the end of a line that is sent to one
+var (
+	inputPeer = regexp.MustCompile(`^(peer-\d+|from API|API)\b`)
+	sentTo    = regexp.MustCompile(` (FNode\d+|RandomPeer)\s*$`)
+)
+
+// ReadPath reads a log file, or every .txt file of a directory
+func (t *Tracer) ReadPath(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return t.ReadFile(path)
+	}
+	names, err := filepath.Glob(filepath.Join(path, "*.txt"))
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := t.ReadFile(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadFile reads a log named node_log.txt, as -debuglog names them
+func (t *Tracer) ReadFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	base := strings.TrimSuffix(filepath.Base(name), ".txt")
+	node, logName := "", base
+	if i := strings.Index(base, "_"); i >= 0 {
+		node, logName = base[:i], base[i+1:]
+	}
+	if err := t.Read(f, node, logName, info.ModTime()); err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	return nil
+}
+
+// Read reads the records of one log.  Text logs only have the time of day, they are dated by
+// modTime, the time the log was last written.
+func (t *Tracer) Read(r io.Reader, node string, logName string, modTime time.Time) error {
+	var events []*Event
+	var last time.Duration
+	days := 0
+
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(line, "{") {
+			if e := jsonEvent(line, node, logName); e != nil {
+				events = append(events, e)
+			}
+		} else if e, clock := textEvent(line, node, logName); e != nil {
+			if clock < last-12*time.Hour { // past midnight
+				days++
+			}
+			last = clock
+			e.Time = time.Time{}.Add(time.Duration(days)*24*time.Hour + clock)
+			events = append(events, e)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// The last text line was written on the day of modTime
+	y, m, d := modTime.Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, modTime.Location()).AddDate(0, 0, -days)
+	for _, e := range events {
+		if e.Time.Year() == 1 {
+			e.Time = start.Add(e.Time.Sub(time.Time{}))
+		}
+	}
+	t.events = append(t.events, events...)
+	return nil
+}
+
+// textEvent parses a line of a text log, returning nil for lines that are not the first line of
+// a message, and the time of day of the line
+func textEvent(line string, node string, logName string) (*Event, time.Duration) {
+	m := textLine.FindStringSubmatch(line)
+	if m == nil || m[7] == "??????" {
+		return nil, 0
+	}
+
+	e := new(Event)
+	e.Node, e.Log = node, logName
+	e.Seq, _ = strconv.Atoi(m[1])
+	var clock time.Duration
+	for i, unit := range []time.Duration{time.Hour, time.Minute, time.Second, time.Millisecond} {
+		n, _ := strconv.Atoi(m[2+i])
+		clock += time.Duration(n) * unit
+	}
+
+	e.Note = strings.TrimSpace(m[6])
+	if p := statePrefix.FindStringSubmatch(e.Note); p != nil {
+		dbheight, _ := strconv.Atoi(p[1])
+		minute, _ := strconv.Atoi(p[2])
+		e.DBHeight, e.Minute = &dbheight, &minute
+		e.Note = strings.TrimSpace(p[3])
+	}
+	if strings.HasSuffix(e.Note, " EmbeddedMsg:") {
+		e.embedded = true
+		e.Note = strings.TrimSuffix(e.Note, " EmbeddedMsg:")
+	}
+
+	e.msgHash, e.hash = m[7], m[8]
+	if e.hash == "??????" {
+		e.hash = ""
+	}
+	e.msgType = strings.TrimSpace(m[9])
+	e.message = strings.TrimSpace(m[10])
+	if to := sentTo.FindStringSubmatch(e.message); to != nil {
+		e.Peer = to[1]
+		e.message = strings.TrimSpace(strings.TrimSuffix(e.message, to[0]))
+	}
+	return e, clock
+}
+
+// jsonEvent parses a record of a -logjson log, returning nil for records of no message
+func jsonEvent(line string, node string, logName string) *Event {
+	r := new(log.Record)
+	if json.Unmarshal([]byte(line), r) != nil || r.MsgHash == "" {
+		return nil
+	}
+
+	e := new(Event)
+	e.Node, e.Log = node, logName
+	if r.Node != "" {
+		e.Node = strings.ToLower(r.Node)
+	}
+	if r.Log != "" {
+		e.Log = r.Log
+		if i := strings.Index(r.Log, "_"); i >= 0 && strings.EqualFold(r.Log[:i], e.Node) {
+			e.Log = r.Log[i+1:]
+		}
+	}
+	e.Seq = r.Seq
+	e.Time, _ = time.Parse(time.RFC3339Nano, r.Time)
+	e.Note = r.Msg
+	e.Peer = r.Peer
+	e.DBHeight, e.Minute = r.DBHeight, r.Minute
+	e.msgHash, e.hash = r.MsgHash, r.Hash
+	e.msgType, e.message = r.MsgType, r.Message
+	e.embedded = r.Embedded
+	return e
+}
+
+// stage works out the stage of a record from the log it is in and its note
+func stage(logName string, note string) string {
+	l, n := strings.ToLower(logName), strings.ToLower(note)
+	switch {
+	case strings.Contains(n, "drop"):
+		return StageDropped
+	case l == "networkinputs":
+		return StageReceived
+	case l == "networkoutputs":
+		if strings.HasPrefix(n, "send") {
+			return StageSent
+		}
+		return StageQueued
+	case l == "holding":
+		if strings.HasPrefix(n, "add") {
+			return StageHeld
+		}
+		return StageReleased
+	case strings.Contains(n, "hold"):
+		return StageHeld
+	case l == "process" && strings.HasPrefix(n, "done"):
+		return StageProcessed
+	case l == "processlist":
+		return StageProcessList
+	case strings.Contains(l, "queue") && !strings.HasPrefix(n, "execute"):
+		return StageQueued
+	}
+	return StageExecuted
+}
+
+// textHash is how many hex digits of a hash text logs have
+const textHash = 6
+
+// Traces correlates the events by message hash.  The events of JSON logs are correlated by the
+// full hash, and those of text logs by the 6 hex digits they have, joining the trace of the one
+// message with a full hash that starts with them if there is one.  The events of an ack are also
+// added to the trace of the message it acks, as acked events.  The traces are in the order the
+// messages were first seen.
+func (t *Tracer) Traces() []*Trace {
+	traces := make(map[string]*Trace)     // by full msghash, or by 6 digits for text events
+	prefixes := make(map[string][]*Trace) // the traces of full msghashes, by their first 6 digits
+
+	find := func(e *Event) *Trace {
+		h := strings.ToLower(e.msgHash)
+		if len(h) <= textHash {
+			if full := prefixes[h]; len(full) == 1 {
+				return full[0]
+			}
+		}
+		tr := traces[h]
+		if tr == nil {
+			tr = &Trace{MsgHash: h}
+			traces[h] = tr
+			if len(h) > textHash {
+				prefixes[h[:textHash]] = append(prefixes[h[:textHash]], tr)
+			}
+		}
+		return tr
+	}
+	add := func(tr *Trace, e *Event) {
+		if len(e.hash) > len(tr.Hash) {
+			tr.Hash = strings.ToLower(e.hash)
+		}
+		if tr.MsgType == "" {
+			tr.MsgType = e.msgType
+		}
+		if len(e.message) > len(tr.Message) {
+			tr.Message = e.message
+		}
+	}
+
+	// The full hashes first, so the text events know which traces they can join
+	of := make([]*Trace, len(t.events))
+	for i, e := range t.events {
+		if len(e.msgHash) > textHash {
+			of[i] = find(e)
+		}
+	}
+	for i, e := range t.events {
+		if of[i] == nil {
+			of[i] = find(e)
+		}
+		add(of[i], e)
+	}
+
+	// The traces of the messages an ack can refer to, by their hash and its first 6 digits
+	byHash := make(map[string][]*Trace)
+	byPrefix := make(map[string][]*Trace)
+	for _, tr := range traces {
+		if tr.MsgType == "Ack" {
+			continue
+		}
+		if tr.Hash != "" {
+			byHash[tr.Hash] = append(byHash[tr.Hash], tr)
+		}
+		if len(tr.Hash) > textHash {
+			byPrefix[tr.Hash[:textHash]] = append(byPrefix[tr.Hash[:textHash]], tr)
+		}
+	}
+	ackedTraces := func(hash string) []*Trace {
+		hash = strings.ToLower(hash)
+		var list []*Trace
+		if len(hash) > textHash {
+			list = append(list, byHash[hash]...)
+			return append(list, byHash[hash[:textHash]]...)
+		}
+		list = append(list, byHash[hash]...)
+		if full := byPrefix[hash]; len(full) == 1 {
+			list = append(list, full[0])
+		}
+		return list
+	}
+
+	var acks []*Event
+	for i, e := range t.events {
+		tr := of[i]
+		if e.embedded { // logged again after the ack that refers to it, the acked events cover it
+			continue
+		}
+		e.Stage = stage(e.Log, e.Note)
+		tr.Events = append(tr.Events, e)
+		if e.msgType == "Ack" && e.hash != "" {
+			acks = append(acks, e)
+		}
+	}
+	for _, ack := range acks {
+		for _, tr := range ackedTraces(ack.hash) {
+			acked := *ack
+			acked.Stage = StageAcked
+			acked.Ack = strings.ToLower(ack.msgHash)
+			tr.Events = append(tr.Events, &acked)
+		}
+	}
+
+	var list []*Trace
+	for _, tr := range traces {
+		if len(tr.Events) == 0 {
+			continue
+		}
+		tr.finish()
+		list = append(list, tr)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if !list[i].First.Equal(list[j].First) {
+			return list[i].First.Before(list[j].First)
+		}
+		return list[i].Events[0].Seq < list[j].Events[0].Seq
+	})
+	return list
+}
+
+// finish orders the events of a trace and works out the path through each node
+func (tr *Trace) finish() {
+	sort.SliceStable(tr.Events, func(i, j int) bool {
+		a, b := tr.Events[i], tr.Events[j]
+		if !a.Time.Equal(b.Time) {
+			return a.Time.Before(b.Time)
+		}
+		return a.Seq < b.Seq
+	})
+	tr.First = tr.Events[0].Time
+	tr.Origin = tr.Events[0].Node
+	tr.Duration = int64(tr.Events[len(tr.Events)-1].Time.Sub(tr.First) / time.Millisecond)
+
+	nodes := make(map[string]*NodePath)
+	for _, e := range tr.Events {
+		e.Offset = int64(e.Time.Sub(tr.First) / time.Millisecond)
+		n := nodes[e.Node]
+		if n == nil {
+			n = &NodePath{Node: e.Node}
+			nodes[e.Node] = n
+			tr.Nodes = append(tr.Nodes, n)
+		}
+		n.events = append(n.events, e)
+
+		offset := e.Offset
+		first := func(at **int64) {
+			if *at == nil {
+				*at = &offset
+			}
+		}
+		switch e.Stage {
+		case StageReceived:
+			if n.Received == nil {
+				n.From = e.Peer
+				if n.From == "" {
+					if p := inputPeer.FindString(e.Note); p != "" {
+						n.From = p
+					}
+				}
+			}
+			first(&n.Received)
+		case StageHeld:
+			first(&n.Held)
+		case StageAcked:
+			first(&n.Acked)
+		case StageProcessed:
+			first(&n.Processed)
+		case StageSent:
+			if n.Received != nil {
+				e.Stage = StageRebroadcast
+			}
+			if e.Peer == "" && strings.HasPrefix(e.Note, "Send P2P ") {
+				e.Peer = strings.TrimPrefix(e.Note, "Send P2P ")
+			}
+			first(&n.Sent)
+		case StageDropped:
+			first(&n.Dropped)
+		}
+	}
+}
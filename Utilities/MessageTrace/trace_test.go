@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/globals"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/log"
+)
+
+// debugLogsToTempDir writes the debug logs that match regex to a new temporary directory.  The
+// returned function closes the logs, removes the directory and puts the debug log settings back.
+func debugLogsToTempDir(t *testing.T, regex string) (dir string, restore func()) {
+	dir, err := ioutil.TempDir("", "messagetrace")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldRegex, oldJSON := globals.Params.DebugLogRegEx, globals.Params.Logjson
+	log.Cleanup()
+	globals.Params.DebugLogRegEx = dir + string(os.PathSeparator) + regex
+
+	return dir, func() {
+		log.Cleanup()
+		os.RemoveAll(dir)
+		globals.Params.DebugLogRegEx, globals.Params.Logjson = oldRegex, oldJSON
+		globals.Params.DebugLogLocation = ""
+		globals.LastDebugLogRegEx = ""
+	}
+}
+
+// TestTraces writes the logs of a leader as text and of a follower as JSON, and checks the path
+// of an EOM and its ack through both
+func TestTraces(t *testing.T) {
+	dir, restore := debugLogsToTempDir(t, "tracetest")
+	defer restore()
+
+	eom := new(messages.EOM)
+	eom.Timestamp = primitives.NewTimestampNow()
+	eom.ChainID = primitives.NewHash([]byte("messagetrace"))
+
+	ack := new(messages.Ack)
+	ack.Timestamp = primitives.NewTimestampNow()
+	ack.LeaderChainID = eom.ChainID
+	ack.MessageHash = eom.GetHash()
+	ack.SerialHash = primitives.NewZeroHash()
+	ack.DBHeight = 12
+
+	leader := log.Fields{Node: "tracetest0", DBHeight: 12, Minute: 3, VM: 0}
+	log.NodeLogMessage(leader, "NetworkOutputs", "Send broadcast", eom)
+	log.NodeLogMessage(leader, "NetworkOutputs", "Send broadcast", ack)
+	log.NodeLogMessage(leader, "process", "done 12/0/0", eom)
+
+	globals.Params.Logjson = true
+	follower := log.Fields{Node: "tracetest1", DBHeight: 12, Minute: 3, VM: 0}
+	eom.SetNetworkOrigin("peer-0")
+	log.NodeLogMessage(follower, "NetworkInputs", "peer-0, enqueue", eom)
+	log.NodeLogMessage(follower, "holding", "add", eom)
+	log.NodeLogMessage(follower, "NetworkInputs", "peer-0, enqueue", ack)
+	log.NodeLogMessage(follower, "process", "done 12/0/0", eom)
+	log.NodeLogMessage(follower, "NetworkOutputs", "Send broadcast", eom)
+
+	tracer := new(Tracer)
+	if err := tracer.ReadPath(dir); err != nil {
+		t.Fatal(err)
+	}
+	traces := tracer.Traces()
+	if len(traces) != 2 {
+		t.Fatalf("expected the traces of the EOM and the ack, got %d", len(traces))
+	}
+
+	var tr *Trace
+	for _, x := range traces {
+		if x.MsgType == "EOM" {
+			tr = x
+		}
+	}
+	if tr == nil {
+		t.Fatal("no trace of the EOM")
+	}
+	if tr.MsgHash != eom.GetMsgHash().String() || tr.Hash != eom.GetHash().String() || tr.Origin != "tracetest0" {
+		t.Errorf("bad trace %s %s %s", tr.MsgHash, tr.Hash, tr.Origin)
+	}
+	if len(tr.Nodes) != 2 {
+		t.Fatalf("expected the EOM on 2 nodes, got %d", len(tr.Nodes))
+	}
+
+	stages := func(n *NodePath) string {
+		var s []string
+		for _, e := range n.events {
+			s = append(s, e.Stage)
+		}
+		return strings.Join(s, " ")
+	}
+	for _, n := range tr.Nodes {
+		switch n.Node {
+		case "tracetest0":
+			if s := stages(n); s != "sent acked processed" {
+				t.Errorf("bad path on the leader: %s", s)
+			}
+			if n.Sent == nil || n.Processed == nil || n.Received != nil {
+				t.Errorf("bad path on the leader %+v", n)
+			}
+		case "tracetest1":
+			if s := stages(n); s != "received held acked processed rebroadcast" {
+				t.Errorf("bad path on the follower: %s", s)
+			}
+			if n.From != "peer-0" || n.Received == nil || n.Held == nil || n.Acked == nil || n.Sent == nil {
+				t.Errorf("bad path on the follower %+v", n)
+			}
+		default:
+			t.Errorf("unexpected node %s", n.Node)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := WriteTimeline(buf, traces); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), tr.MsgHash) || !strings.Contains(buf.String(), `class="rebroadcast"`) {
+		t.Errorf("the timeline is missing the EOM")
+	}
+
+	if !matches(tr, []string{tr.MsgHash[:6]}) || !matches(tr, []string{tr.Hash}) || matches(tr, []string{"zz"}) {
+		t.Error("matches failed")
+	}
+}
+
+// TestTracesByFullHash checks that messages with the same first 6 digits are traced apart in JSON
+// logs, and that a text event only joins the trace of a full hash it is the prefix of alone
+func TestTracesByFullHash(t *testing.T) {
+	now := time.Now()
+	event := func(node string, seq int, msgHash string) *Event {
+		return &Event{Node: node, Log: "process", Seq: seq, Time: now.Add(time.Duration(seq) * time.Millisecond), Note: "done", msgHash: msgHash, msgType: "EOM"}
+	}
+	tracer := new(Tracer)
+	tracer.events = []*Event{
+		event("fnode0", 1, "a1b2c3000000"),
+		event("fnode0", 2, "a1b2c3ffffff"),
+		event("fnode1", 3, "a1b2c3"),
+		event("fnode0", 4, "d4e5f6000000"),
+		event("fnode1", 5, "d4e5f6"),
+	}
+
+	nodes := make(map[string]int)
+	for _, tr := range tracer.Traces() {
+		nodes[tr.MsgHash] = len(tr.Nodes)
+	}
+	want := map[string]int{"a1b2c3000000": 1, "a1b2c3ffffff": 1, "a1b2c3": 1, "d4e5f6000000": 2}
+	if !reflect.DeepEqual(nodes, want) {
+		t.Errorf("expected the traces %v, got %v", want, nodes)
+	}
+}
+
+// TestTextEvent checks a text line of a message with the fields of a node
+func TestTextEvent(t *testing.T) {
+	line := "     1234 10:11:12.345       12-:-3 peer-2, enqueue                                 M-a1b2c3|R-a1b2c3|H-d4e5f6|0xc000123456                        EOM[ 0]:EOM-   DBh/VMh/h 12/0/-- minute 3 FNode01"
+	e, clock := textEvent(line, "fnode0", "networkinputs")
+	if e == nil {
+		t.Fatal("the line did not parse")
+	}
+	if e.Seq != 1234 || clock.String() != "10h11m12.345s" || e.Note != "peer-2, enqueue" || *e.DBHeight != 12 || *e.Minute != 3 {
+		t.Errorf("bad event %+v %s", e, clock)
+	}
+	if e.msgHash != "a1b2c3" || e.hash != "d4e5f6" || e.msgType != "EOM" || e.Peer != "FNode01" {
+		t.Errorf("bad message %+v", e)
+	}
+
+	if e, _ := textEvent("     1235 10:11:12.346 a printf line", "fnode0", "networkinputs"); e != nil {
+		t.Error("a printf line parsed as a message")
+	}
+}
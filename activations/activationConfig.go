@@ -0,0 +1,120 @@
+// This set of functions lets a custom network schedule activations without changing the code
+
+package activations
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ActivationByName looks up an activation by the name used in the config file and the API
+func ActivationByName(name string) (ActivationType, bool) {
+	for id, n := range ActivationNameMap {
+		if strings.EqualFold(n, name) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// ParseActivationHeights parses a list of activation heights in the form
+// "AuthorityMaxDelta=1000, TestNetCoinBasePeriod=20"
+func ParseActivationHeights(list string) (map[ActivationType]int, error) {
+	named := make(map[string]int)
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, "=")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("activation height %q is not of the form Name=height", item)
+		}
+		height, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("activation height %q is not a number", item)
+		}
+		named[strings.TrimSpace(parts[0])] = height
+	}
+	return activationHeights(named)
+}
+
+// LoadActivationHeights reads activation heights from a JSON file mapping the activation
+// names to heights, eg {"AuthorityMaxDelta": 1000}
+func LoadActivationHeights(filename string) (map[ActivationType]int, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	named := make(map[string]int)
+	if err := json.Unmarshal(data, &named); err != nil {
+		return nil, fmt.Errorf("activation file %s: %v", filename, err)
+	}
+	return activationHeights(named)
+}
+
+// activationHeights validates the names and heights of a set of activations
+func activationHeights(named map[string]int) (map[ActivationType]int, error) {
+	heights := make(map[ActivationType]int, len(named))
+	for name, height := range named {
+		id, ok := ActivationByName(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown activation %q", name)
+		}
+		if height < 0 {
+			return nil, fmt.Errorf("activation %s has a negative height %d", name, height)
+		}
+		heights[id] = height
+	}
+	return heights, nil
+}
+
+// SetActivationHeights overrides the activation heights for a network.  The schedule of the
+// main network is part of the protocol so it can never be overridden.
+func SetActivationHeights(network string, heights map[ActivationType]int) error {
+	if network == "MAIN" {
+		return fmt.Errorf("activation heights can not be overridden on the MAIN network")
+	}
+	for id := range heights {
+		if _, ok := ActivationMap[id]; !ok {
+			return fmt.Errorf("invalid activation %d", id)
+		}
+	}
+	for id, height := range heights {
+		ActivationMap[id].ActivationHeight[network] = height
+	}
+	return nil
+}
+
+// ScheduledActivation is when an activation happens on the network this node runs on
+type ScheduledActivation struct {
+	Name        string `json:"name"`
+	Id          int    `json:"id"`
+	Description string `json:"description"`
+	Height      int    `json:"height"` // math.MaxInt32 means never
+}
+
+// Schedule returns the activations in effect for the network this node runs on
+func Schedule() []ScheduledActivation {
+	netName := networkname()
+	var schedule []ScheduledActivation
+	for id, a := range ActivationMap {
+		h, ok := a.ActivationHeight[netName]
+		if !ok {
+			h = a.DefaultHeight
+		}
+		schedule = append(schedule, ScheduledActivation{a.Name, int(id), a.Description, h})
+	}
+	sort.Slice(schedule, func(i, j int) bool { return schedule[i].Id < schedule[j].Id })
+	return schedule
+}
+
+// NetworkName returns the name activations are scheduled under for the network this node
+// runs on, eg "MAIN" or "CUSTOM:fct_community_test"
+func NetworkName() string {
+	return networkname()
+}
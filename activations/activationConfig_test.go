@@ -0,0 +1,60 @@
+package activations_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	. "github.com/FactomProject/factomd/activations"
+)
+
+func TestParseActivationHeights(t *testing.T) {
+	heights, err := ParseActivationHeights("AuthorityMaxDelta=1000, testnetcoinbaseperiod = 20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(heights) != 2 || heights[AUTHRORITY_SET_MAX_DELTA] != 1000 || heights[TESTNET_COINBASE_PERIOD] != 20 {
+		t.Errorf("wrong heights %v", heights)
+	}
+
+	for _, bad := range []string{"NoSuchActivation=5", "AuthorityMaxDelta", "AuthorityMaxDelta=x", "AuthorityMaxDelta=-1"} {
+		if _, err := ParseActivationHeights(bad); err == nil {
+			t.Errorf("expected an error parsing %q", bad)
+		}
+	}
+}
+
+func TestLoadActivationHeights(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "Activations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString(`{"AuthorityMaxDelta": 77}`)
+	file.Close()
+
+	heights, err := LoadActivationHeights(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(heights) != 1 || heights[AUTHRORITY_SET_MAX_DELTA] != 77 {
+		t.Errorf("wrong heights %v", heights)
+	}
+}
+
+func TestSetActivationHeights(t *testing.T) {
+	heights := map[ActivationType]int{AUTHRORITY_SET_MAX_DELTA: 5}
+	if err := SetActivationHeights("MAIN", heights); err == nil {
+		t.Error("overrode the activation heights of the main network")
+	}
+	if ActivationMap[AUTHRORITY_SET_MAX_DELTA].ActivationHeight["MAIN"] != 222874 {
+		t.Error("main network activation height changed")
+	}
+
+	if err := SetActivationHeights("CUSTOM:activation_test", heights); err != nil {
+		t.Fatal(err)
+	}
+	if ActivationMap[AUTHRORITY_SET_MAX_DELTA].ActivationHeight["CUSTOM:activation_test"] != 5 {
+		t.Error("custom network activation height not set")
+	}
+}
@@ -17,6 +17,7 @@ const (
 	TESTNET_COINBASE_PERIOD                = iota // 1 -- this is a passing activation and this ID may be reused once that height is passes and the references are removed
 	//
 	AUTHRORITY_SET_MAX_DELTA = iota
+	RCD_2_MULTISIG           = iota
 	ACTIVATION_TYPE_COUNT    = iota - 1 // Always Last
 )
 
@@ -54,6 +55,14 @@ func init() {
 				"CUSTOM:fct_community_test": 109387,
 			},
 		},
+		Activation{"RCD2Multisig", RCD_2_MULTISIG,
+			"Accept factoid transactions spending from RCD type 2 multisig addresses",
+			math.MaxInt32, // inactive unless overridden below
+			map[string]int{
+				"MAIN":  math.MaxInt32,
+				"LOCAL": 25,
+			},
+		},
 	}
 
 	if ACTIVATION_TYPE_COUNT != len(activations) {
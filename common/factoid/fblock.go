@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/FactomProject/factomd/activations"
 	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
@@ -533,7 +534,21 @@ func (b *FBlock) GetExchRate() uint64 {
 	return b.ExchRate
 }
 
+// validateRCDs rejects a transaction spending from a multisig address before RCD type 2 is
+// activated.  The transactions of a block are acknowledged while the previous block is the
+// highest completed one, which is the height the messages are checked at.
+func (b FBlock) validateRCDs(trans interfaces.ITransaction) error {
+	if UsesRCD_2(trans) && !activations.IsActive(activations.RCD_2_MULTISIG, int(b.DBHeight)-1) {
+		return fmt.Errorf("multisig (RCD type 2) addresses are not activated in block %d", b.DBHeight)
+	}
+	return nil
+}
+
 func (b FBlock) ValidateTransaction(index int, trans interfaces.ITransaction) error {
+	if err := b.validateRCDs(trans); err != nil {
+		return err
+	}
+
 	// Calculate the fee due.
 	err := trans.Validate(index)
 	if err != nil {
@@ -581,6 +596,11 @@ func (b FBlock) ValidateTransaction(index int, trans interfaces.ITransaction) er
 }
 
 func (b FBlock) Validate() error {
+	for _, trans := range b.Transactions {
+		if err := b.validateRCDs(trans); err != nil {
+			return err
+		}
+	}
 	for i, trans := range b.Transactions {
 		if err := b.ValidateTransaction(i, trans); err != nil {
 			return nil
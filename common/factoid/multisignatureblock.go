@@ -0,0 +1,214 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factoid
+
+import (
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+/**************************************
+ * MultiSignatureBlock
+ *
+ * The signature block of an RCD_2.  An RCD_2 only holds the addresses
+ * that can sign for it, so each signer reveals the RCD behind its address
+ * along with the signature block that satisfies that RCD.  The index of a
+ * signer is the position of its address in the RCD_2.  The signers are
+ * RCD_1s, and there are no more of them than the RCD_2 requires.
+ **************************************/
+
+type MultiSignatureBlock struct {
+	Signers []*MultiSigner `json:"signers"`
+
+	required int // the signatures the RCD_2 requires, the most signers unmarshalled if not 0
+}
+
+// MultiSigner is one of the addresses of an RCD_2 signing a transaction
+type MultiSigner struct {
+	Index    int                        `json:"index"`
+	RCD      interfaces.IRCD            `json:"rcd"`
+	SigBlock interfaces.ISignatureBlock `json:"sigblock"`
+}
+
+var _ interfaces.ISignatureBlock = (*MultiSignatureBlock)(nil)
+
+func (b *MultiSignatureBlock) IsSameAs(s interfaces.ISignatureBlock) bool {
+	if s == nil {
+		return b == nil
+	}
+	other, ok := s.(*MultiSignatureBlock)
+	if !ok {
+		return false
+	}
+	data, err := b.MarshalBinary()
+	if err != nil {
+		return false
+	}
+	data2, err := other.MarshalBinary()
+	if err != nil {
+		return false
+	}
+	return primitives.AreBytesEqual(data, data2)
+}
+
+// AddSigner adds the signature block of the address at index in the RCD_2, and the RCD of that address
+func (b *MultiSignatureBlock) AddSigner(index int, rcd interfaces.IRCD, sigblk interfaces.ISignatureBlock) {
+	b.Signers = append(b.Signers, &MultiSigner{Index: index, RCD: rcd, SigBlock: sigblk})
+}
+
+// AddSignature signs for the first signer without a signature, so signers can be added by
+// AddSigner with a nil signature block and signed after.  If every signer has signed, the
+// signature is kept as a signer without an RCD, which MarshalBinary and CheckSig reject.
+func (b *MultiSignatureBlock) AddSignature(sig interfaces.ISignature) {
+	for _, signer := range b.Signers {
+		if signer == nil {
+			continue
+		}
+		if signer.SigBlock == nil {
+			signer.SigBlock = new(SignatureBlock)
+		}
+		if single, ok := signer.SigBlock.(*SignatureBlock); ok && len(single.Signatures) == 0 {
+			single.AddSignature(sig)
+			return
+		}
+	}
+	b.Signers = append(b.Signers, &MultiSigner{Index: -1, SigBlock: &SignatureBlock{Signatures: []interfaces.ISignature{sig}}})
+}
+
+func (b *MultiSignatureBlock) GetSignature(index int) interfaces.ISignature {
+	sigs := b.GetSignatures()
+	if len(sigs) <= index {
+		return nil
+	}
+	return sigs[index]
+}
+
+// GetSignatures returns the signatures of every signer, in order
+func (b *MultiSignatureBlock) GetSignatures() []interfaces.ISignature {
+	var sigs []interfaces.ISignature
+	for _, signer := range b.Signers {
+		if signer != nil && signer.SigBlock != nil {
+			sigs = append(sigs, signer.SigBlock.GetSignatures()...)
+		}
+	}
+	return sigs
+}
+
+func (b *MultiSignatureBlock) UnmarshalBinary(data []byte) error {
+	_, err := b.UnmarshalBinaryData(data)
+	return err
+}
+
+func (e *MultiSignatureBlock) JSONByte() ([]byte, error) {
+	return primitives.EncodeJSON(e)
+}
+
+func (e *MultiSignatureBlock) JSONString() (string, error) {
+	return primitives.EncodeJSONString(e)
+}
+
+func (b MultiSignatureBlock) String() string {
+	txt, err := b.CustomMarshalText()
+	if err != nil {
+		return "<error>"
+	}
+	return string(txt)
+}
+
+func (b MultiSignatureBlock) MarshalBinary() ([]byte, error) {
+	buf := primitives.NewBuffer(nil)
+	err := buf.PushUInt16(uint16(len(b.Signers)))
+	if err != nil {
+		return nil, err
+	}
+	for _, signer := range b.Signers {
+		if signer == nil || signer.RCD == nil || signer.SigBlock == nil {
+			return nil, fmt.Errorf("MultiSignatureBlock has an incomplete signer")
+		}
+		err = buf.PushUInt16(uint16(signer.Index))
+		if err != nil {
+			return nil, err
+		}
+		err = buf.PushBinaryMarshallable(signer.RCD)
+		if err != nil {
+			return nil, err
+		}
+		err = buf.PushBinaryMarshallable(signer.SigBlock)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (b MultiSignatureBlock) CustomMarshalText() ([]byte, error) {
+	var out primitives.Buffer
+
+	out.WriteString("Multi Signature Block: \n")
+	for _, signer := range b.Signers {
+		if signer == nil || signer.RCD == nil || signer.SigBlock == nil {
+			continue
+		}
+		out.WriteString(fmt.Sprintf(" signer %d: ", signer.Index))
+		txt, err := signer.RCD.CustomMarshalText()
+		if err != nil {
+			return nil, err
+		}
+		out.Write(txt)
+		txt, err = signer.SigBlock.CustomMarshalText()
+		if err != nil {
+			return nil, err
+		}
+		out.Write(txt)
+	}
+
+	return out.DeepCopyBytes(), nil
+}
+
+func (b *MultiSignatureBlock) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	buf := primitives.NewBuffer(data)
+	count, err := buf.PopUInt16()
+	if err != nil {
+		return nil, err
+	}
+	max := MaxRCD2Signatures
+	if b.required > 0 && b.required < max {
+		max = b.required
+	}
+	if int(count) > max {
+		return nil, fmt.Errorf("MultiSignatureBlock has %d signers, more than the %d allowed", count, max)
+	}
+	b.Signers = nil
+	for i := 0; i < int(count); i++ {
+		signer := new(MultiSigner)
+		index, err := buf.PopUInt16()
+		if err != nil {
+			return nil, err
+		}
+		signer.Index = int(index)
+
+		t, err := buf.PeekByte()
+		if err != nil {
+			return nil, err
+		}
+		if t != 1 {
+			return nil, fmt.Errorf("Invalid type byte for the authorization of a signer: %x ", t)
+		}
+		signer.RCD = CreateRCD([]byte{t})
+		err = buf.PopBinaryMarshallable(signer.RCD)
+		if err != nil {
+			return nil, err
+		}
+		signer.SigBlock = NewSignatureBlock(signer.RCD)
+		err = buf.PopBinaryMarshallable(signer.SigBlock)
+		if err != nil {
+			return nil, err
+		}
+		b.Signers = append(b.Signers, signer)
+	}
+	return buf.DeepCopyBytes(), nil
+}
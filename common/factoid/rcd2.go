@@ -7,6 +7,7 @@ package factoid
 import (
 	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 
 	"github.com/FactomProject/factomd/common/interfaces"
@@ -17,37 +18,43 @@ import (
  * RCD 2
  ************************/
 
+// MaxRCD2Signatures is the most signatures an RCD_2 can require, and so the most signers a
+// MultiSignatureBlock can have
+const MaxRCD2Signatures = 64
+
 // Type 2 RCD implement multisig
-// m of n
+// n of m
 // Must have m addresses from which to choose, no fewer, no more
-// Must have n RCD, no fewer no more.
-// NOTE: This does mean you can have a multisig nested in a
-// multisig.  It just works.
-
+// Must have n signatures from those addresses, no fewer.
+// The address of an RCD_2 is the hash of the RCD, like an RCD_1.  The
+// addresses it holds are addresses of RCD_1s, and are satisfied by a
+// MultiSignatureBlock revealing those RCDs along with their signatures.
+// A multisig can't be nested in a multisig, so every signature checked is
+// one the fee pays for.
 type RCD_2 struct {
-	M           int                   // Number signatures required
-	N           int                   // Total signatures possible
-	N_Addresses []interfaces.IAddress // n addresses
+	M           int                   // Total signatures possible, the number of addresses
+	N           int                   // Number signatures required
+	N_Addresses []interfaces.IAddress // m addresses
 }
 
 var _ interfaces.IRCD = (*RCD_2)(nil)
 
-/*************************************
- *       Stubs
- *************************************/
+/***************************************
+ *       Methods
+ ***************************************/
 
 func (b RCD_2) GetAddress() (interfaces.IAddress, error) {
-	return nil, nil
+	data, err := b.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return CreateAddress(primitives.Shad(data)), nil
 }
 
 func (b RCD_2) NumberOfSignatures() int {
-	return 1
+	return b.N
 }
 
-/***************************************
- *       Methods
- ***************************************/
-
 func (b RCD_2) IsSameAs(rcd interfaces.IRCD) bool {
 	return b.String() == rcd.String()
 }
@@ -57,20 +64,51 @@ func (b RCD_2) UnmarshalBinary(data []byte) error {
 	return err
 }
 
+// CheckSig is true when the signature block is a MultiSignatureBlock with valid signatures for
+// n different addresses of the RCD.  More signers than n are rejected, as the fee only pays for n
+// signatures.
 func (b RCD_2) CheckSig(trans interfaces.ITransaction, sigblk interfaces.ISignatureBlock) bool {
-	return false
+	multi, ok := sigblk.(*MultiSignatureBlock)
+	if !ok || b.N < 1 || b.N > b.M || b.N > MaxRCD2Signatures || len(b.N_Addresses) != b.M || len(multi.Signers) > b.N {
+		return false
+	}
+	signed := make(map[int]bool)
+	for _, signer := range multi.Signers {
+		if signer == nil || signer.Index < 0 || signer.Index >= b.M || signed[signer.Index] {
+			return false
+		}
+		if _, ok := signer.RCD.(*RCD_1); !ok {
+			return false
+		}
+		address, err := signer.RCD.GetAddress()
+		if err != nil || address == nil || !address.IsSameAs(b.N_Addresses[signer.Index]) {
+			return false
+		}
+		if !signer.RCD.CheckSig(trans, signer.SigBlock) {
+			return false
+		}
+		signed[signer.Index] = true
+	}
+	return len(signed) >= b.N
 }
 
 func (e *RCD_2) JSONByte() ([]byte, error) {
 	return primitives.EncodeJSON(e)
 }
 
-// TODO: Fix Json marshaling of RCD_2. Right now the RCD type
-// is not included in the json marshal.
 func (e *RCD_2) JSONString() (string, error) {
 	return primitives.EncodeJSONString(e)
 }
 
+// MarshalJSON writes the RCD as the hex of its binary, which starts with the RCD type
+func (e *RCD_2) MarshalJSON() ([]byte, error) {
+	data, err := e.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(hex.EncodeToString(data))
+}
+
 func (b RCD_2) String() string {
 	txt, err := b.CustomMarshalText()
 	if err != nil {
@@ -93,6 +131,13 @@ func (w RCD_2) Clone() interfaces.IRCD {
 func (a RCD_2) MarshalBinary() ([]byte, error) {
 	var out primitives.Buffer
 
+	if a.N < 1 || a.N > a.M || len(a.N_Addresses) != a.M {
+		return nil, fmt.Errorf("RCD_2 requires %d of %d signatures and has %d addresses", a.N, a.M, len(a.N_Addresses))
+	}
+	if a.N > MaxRCD2Signatures {
+		return nil, fmt.Errorf("RCD_2 requires %d signatures, more than the %d allowed", a.N, MaxRCD2Signatures)
+	}
+
 	binary.Write(&out, binary.BigEndian, uint8(2))
 	binary.Write(&out, binary.BigEndian, uint16(a.N))
 	binary.Write(&out, binary.BigEndian, uint16(a.M))
@@ -126,6 +171,12 @@ func (t *RCD_2) UnmarshalBinaryData(data []byte) (newData []byte, err error) {
 			t.M, t.N,
 		)
 	}
+	if t.N < 1 {
+		return nil, fmt.Errorf("Error: RCD_2.UnmarshalBinary: at least one signature must be required")
+	}
+	if t.N > MaxRCD2Signatures {
+		return nil, fmt.Errorf("Error: RCD_2.UnmarshalBinary: %d signatures required, more than the %d allowed", t.N, MaxRCD2Signatures)
+	}
 
 	sigLimit := len(data) / 32
 	if t.M > sigLimit {
@@ -168,3 +219,13 @@ func (a RCD_2) CustomMarshalText() ([]byte, error) {
 
 	return out.DeepCopyBytes(), nil
 }
+
+// UsesRCD_2 is true if any input of the transaction spends from a multisig address
+func UsesRCD_2(trans interfaces.ITransaction) bool {
+	for _, rcd := range trans.GetRCDs() {
+		if _, ok := rcd.(*RCD_2); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -5,11 +5,16 @@
 package factoid_test
 
 import (
+	"encoding/hex"
+	"encoding/json"
 	"math/rand"
+	"strings"
 	"testing"
 
+	"github.com/FactomProject/factomd/activations"
 	. "github.com/FactomProject/factomd/common/factoid"
 	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/testHelper"
 )
 
 func TestUnmarshalNilRCD_2(t *testing.T) {
@@ -114,6 +119,206 @@ func TestRCD2Clone(t *testing.T) {
 	}
 }
 
+func TestUnmarshalRCD2NoSignaturesRequired(t *testing.T) {
+	data := append([]byte{2, 0, 0, 0, 1}, make([]byte, 32)...)
+	rcd := new(RCD_2)
+	if _, err := rcd.UnmarshalBinaryData(data); err == nil {
+		t.Error("unmarshalled an RCD_2 that needs no signatures")
+	}
+	data[2] = 1
+	if _, err := rcd.UnmarshalBinaryData(data); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRCD2CheckSig(t *testing.T) {
+	// A 2 of 3 multisig of three RCD_1 addresses
+	rcds := make([]interfaces.IRCD, 3)
+	addresses := make([]interfaces.IAddress, 3)
+	for i := range rcds {
+		rcds[i] = testHelper.NewFactoidRCDAddress(uint64(i))
+		addresses[i], _ = rcds[i].GetAddress()
+	}
+	rcd, err := NewRCD_2(2, 3, addresses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rcd.NumberOfSignatures() != 2 {
+		t.Errorf("RCD_2 needs %d signatures", rcd.NumberOfSignatures())
+	}
+	address, err := rcd.GetAddress()
+	if err != nil || address == nil {
+		t.Fatal("RCD_2 has no address", err)
+	}
+
+	tx := new(Transaction)
+	tx.AddInput(address, 1000)
+	tx.AddOutput(testHelper.NewFactoidAddress(9), 1000)
+	tx.AddAuthorization(rcd)
+	if err := tx.Validate(1); err != nil {
+		t.Fatal(err)
+	}
+	data, err := tx.MarshalBinarySig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign := func(signers ...int) *MultiSignatureBlock {
+		block := new(MultiSignatureBlock)
+		for _, i := range signers {
+			block.AddSigner(i, rcds[i], NewSingleSignatureBlock(testHelper.NewPrivKey(uint64(i)), data))
+		}
+		return block
+	}
+
+	for _, signers := range [][]int{{}, {0}, {1, 1}} {
+		tx.SetSignatureBlock(0, sign(signers...))
+		if tx.ValidateSignatures() == nil {
+			t.Errorf("signatures %v satisfied a 2 of 3 multisig", signers)
+		}
+	}
+	wrong := sign(0, 2)
+	wrong.Signers[1].RCD = rcds[1]
+	tx.SetSignatureBlock(0, wrong)
+	if tx.ValidateSignatures() == nil {
+		t.Error("the RCD of another address satisfied a multisig")
+	}
+
+	extra := sign(2, 0)
+	extra.AddSigner(1, rcds[1], NewSingleSignatureBlock(testHelper.NewPrivKey(1), data))
+	tx.SetSignatureBlock(0, extra)
+	if tx.ValidateSignatures() == nil {
+		t.Error("a multisig was satisfied by more signers than the fee pays for")
+	}
+	if bin, err := tx.MarshalBinary(); err != nil {
+		t.Error(err)
+	} else if _, err := new(Transaction).UnmarshalBinaryData(bin); err == nil {
+		t.Error("unmarshalled more signers than the multisig requires")
+	}
+
+	// Signers are added first and signed after
+	later := new(MultiSignatureBlock)
+	later.AddSigner(2, rcds[2], nil)
+	later.AddSigner(0, rcds[0], nil)
+	later.AddSignature(NewED25519Signature(testHelper.NewPrivKey(2), data))
+	later.AddSignature(NewED25519Signature(testHelper.NewPrivKey(0), data))
+	tx.SetSignatureBlock(0, later)
+	if err := tx.ValidateSignatures(); err != nil {
+		t.Errorf("signatures added after the signers: %v", err)
+	}
+	later.AddSignature(NewED25519Signature(testHelper.NewPrivKey(1), data))
+	if _, err := later.MarshalBinary(); err == nil {
+		t.Error("marshalled a signature with no signer to take it")
+	}
+
+	tx.SetSignatureBlock(0, sign(2, 0))
+	if err := tx.ValidateSignatures(); err != nil {
+		t.Fatal(err)
+	}
+
+	bin, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx2 := new(Transaction)
+	rest, err := tx2.UnmarshalBinaryData(bin)
+	if err != nil || len(rest) != 0 {
+		t.Fatal("unmarshal", err, len(rest))
+	}
+	if !tx.IsSameAs(tx2) {
+		t.Error("transactions are not the same after unmarshalling")
+	}
+	if err := tx2.ValidateSignatures(); err != nil {
+		t.Error(err)
+	}
+
+	j, err := json.Marshal(tx2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rcdBin, _ := rcd.MarshalBinary()
+	if !strings.Contains(string(j), `"`+hex.EncodeToString(rcdBin)+`"`) {
+		t.Errorf("the JSON of the transaction does not hold the RCD %s", j)
+	}
+}
+
+func TestRCD2NestedSigner(t *testing.T) {
+	inner := nextAuth2_rcd2()
+	innerAddress, _ := inner.GetAddress()
+	rcd, err := NewRCD_2(1, 1, []interfaces.IAddress{innerAddress})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := new(Transaction)
+	block := new(MultiSignatureBlock)
+	block.AddSigner(0, inner, new(MultiSignatureBlock))
+	if rcd.CheckSig(tx, block) {
+		t.Error("a multisig nested in a multisig was accepted")
+	}
+	bin, err := block.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := new(MultiSignatureBlock).UnmarshalBinaryData(bin); err == nil {
+		t.Error("unmarshalled a multisig nested in a multisig")
+	}
+}
+
+func TestRCD2SignatureLimit(t *testing.T) {
+	addresses := make([]interfaces.IAddress, MaxRCD2Signatures+1)
+	for i := range addresses {
+		addresses[i] = nextAddress()
+	}
+	rcd, _ := NewRCD_2(MaxRCD2Signatures+1, MaxRCD2Signatures+1, addresses)
+	if _, err := rcd.MarshalBinary(); err == nil {
+		t.Errorf("marshalled an RCD_2 that requires more than %d signatures", MaxRCD2Signatures)
+	}
+
+	data := []byte{0xff, 0xff}
+	if _, err := new(MultiSignatureBlock).UnmarshalBinaryData(data); err == nil {
+		t.Error("unmarshalled 65535 signers")
+	}
+}
+
+func TestFBlockRCD2Activation(t *testing.T) {
+	network := activations.NetworkName()
+	old, set := activations.ActivationMap[activations.RCD_2_MULTISIG].ActivationHeight[network]
+	defer func() {
+		if set {
+			activations.ActivationMap[activations.RCD_2_MULTISIG].ActivationHeight[network] = old
+		} else {
+			delete(activations.ActivationMap[activations.RCD_2_MULTISIG].ActivationHeight, network)
+		}
+	}()
+	if err := activations.SetActivationHeights(network, map[activations.ActivationType]int{activations.RCD_2_MULTISIG: 25}); err != nil {
+		t.Fatal(err)
+	}
+
+	rcd := nextAuth2_rcd2()
+	address, _ := rcd.GetAddress()
+	tx := new(Transaction)
+	tx.AddInput(address, 1000)
+	tx.AddOutput(testHelper.NewFactoidAddress(9), 1000)
+	tx.AddAuthorization(rcd)
+
+	block := func(height uint32) *FBlock {
+		b := NewFBlock(nil).(*FBlock)
+		b.DBHeight = height
+		b.Transactions = []interfaces.ITransaction{new(Transaction), tx}
+		return b
+	}
+	// The block at the activation height holds transactions acknowledged before it
+	if err := block(25).Validate(); err == nil || !strings.Contains(err.Error(), "not activated") {
+		t.Errorf("a block before the activation spends from a multisig address: %v", err)
+	}
+	if err := block(25).ValidateTransaction(1, tx); err == nil || !strings.Contains(err.Error(), "not activated") {
+		t.Errorf("a transaction before the activation spends from a multisig address: %v", err)
+	}
+	if err := block(26).Validate(); err != nil {
+		t.Errorf("a block after the activation cannot spend from a multisig address: %v", err)
+	}
+}
+
 func nextAuth2_rcd2() *RCD_2 {
 	if r == nil {
 		r = rand.New(rand.NewSource(1))
@@ -132,3 +132,11 @@ func NewSingleSignatureBlock(priv, data []byte) *SignatureBlock {
 	s.AddSignature(NewED25519Signature(priv, data))
 	return s
 }
+
+// NewSignatureBlock returns an empty signature block of the kind the RCD is satisfied by
+func NewSignatureBlock(rcd interfaces.IRCD) interfaces.ISignatureBlock {
+	if multi, ok := rcd.(*RCD_2); ok {
+		return &MultiSignatureBlock{required: multi.N}
+	}
+	return new(SignatureBlock)
+}
@@ -6,6 +6,7 @@ package factoid
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"runtime/debug"
 	"time"
@@ -176,18 +177,26 @@ func (t *Transaction) SetTimestamp(ts interfaces.Timestamp) {
 
 func (t *Transaction) SetSignatureBlock(i int, sig interfaces.ISignatureBlock) {
 	for len(t.SigBlocks) <= i {
-		t.SigBlocks = append(t.SigBlocks, new(SignatureBlock))
+		t.SigBlocks = append(t.SigBlocks, t.newSignatureBlock(len(t.SigBlocks)))
 	}
 	t.SigBlocks[i] = sig
 }
 
 func (t *Transaction) GetSignatureBlock(i int) interfaces.ISignatureBlock {
 	for len(t.SigBlocks) <= i {
-		t.SigBlocks = append(t.SigBlocks, new(SignatureBlock))
+		t.SigBlocks = append(t.SigBlocks, t.newSignatureBlock(len(t.SigBlocks)))
 	}
 	return t.SigBlocks[i]
 }
 
+// newSignatureBlock returns an empty signature block for the RCD of input i
+func (t *Transaction) newSignatureBlock(i int) interfaces.ISignatureBlock {
+	if i < len(t.RCDs) {
+		return NewSignatureBlock(t.RCDs[i])
+	}
+	return new(SignatureBlock)
+}
+
 func (t *Transaction) AddRCD(rcd interfaces.IRCD) {
 	t.RCDs = append(t.RCDs, rcd)
 	t.clearCaches()
@@ -232,6 +241,31 @@ func (t Transaction) CalculateFee(factoshisPerEC uint64) (uint64, error) {
 	return fee, nil
 }
 
+// EstimateFee returns the fee CalculateFee would charge a transaction with the given number of
+// inputs, outputs, entry credit outputs and signatures, without building it.  Amounts are taken
+// at their largest encoding and each signature with its RCD_1, so the fee is never too small.
+func EstimateFee(factoshisPerEC uint64, inputs int, outputs int, ecOutputs int, signatures int) (uint64, error) {
+	if inputs < 1 || inputs > 255 || outputs < 0 || outputs > 255 || ecOutputs < 0 || ecOutputs > 255 {
+		return 0, fmt.Errorf("A transaction has from 1 to 255 inputs and up to 255 outputs of each kind")
+	}
+	if signatures < inputs {
+		return 0, fmt.Errorf("Every input needs at least one signature")
+	}
+
+	amount := int(primitives.VarIntLength(math.MaxInt64))
+	size := 1 + 6 + 3 // version, timestamp and the number of inputs, outputs and ec outputs
+	size += (inputs + outputs + ecOutputs) * (constants.ADDRESS_LENGTH + amount)
+	size += signatures * (2 + 1 + constants.ADDRESS_LENGTH + constants.SIGNATURE_LENGTH) // index, RCD_1 and signature
+	if size > constants.MAX_TRANSACTION_SIZE {
+		return 0, fmt.Errorf("Transaction is greater than the max transaction size")
+	}
+
+	fee := factoshisPerEC * uint64((size+1023)/1024)
+	fee += factoshisPerEC * 10 * uint64(outputs+ecOutputs)
+	fee += factoshisPerEC * uint64(signatures)
+	return fee, nil
+}
+
 // Checks that the sum of the given amounts do not cross
 // a signed boundary.  Returns false if invalid, and the
 // sum if valid.  Returns 0 and true if nothing is passed in.
@@ -401,7 +435,7 @@ func (t *Transaction) GetSignatureBlocks() []interfaces.ISignatureBlock {
 		return t.SigBlocks
 	}
 	for i := len(t.SigBlocks); i < len(t.Inputs); i++ { // If too short, then
-		t.SigBlocks = append(t.SigBlocks, new(SignatureBlock)) // pad it with
+		t.SigBlocks = append(t.SigBlocks, t.newSignatureBlock(i)) // pad it with
 	} // signature blocks.
 	return t.SigBlocks
 }
@@ -512,7 +546,7 @@ func (t *Transaction) UnmarshalBinaryData(data []byte) ([]byte, error) {
 		if err != nil {
 			return nil, err
 		}
-		t.SigBlocks[i] = new(SignatureBlock)
+		t.SigBlocks[i] = NewSignatureBlock(t.RCDs[i])
 		err = buf.PopBinaryMarshallable(t.SigBlocks[i])
 		if err != nil {
 			return nil, err
@@ -612,7 +646,7 @@ func (t Transaction) MarshalBinary() ([]byte, error) {
 		// we don't want to restrict what might be required to
 		// sign an input.
 		if len(t.SigBlocks) <= i {
-			t.SigBlocks = append(t.SigBlocks, new(SignatureBlock))
+			t.SigBlocks = append(t.SigBlocks, t.newSignatureBlock(i))
 		}
 		err = buf.PushBinaryMarshallable(t.SigBlocks[i])
 		if err != nil {
@@ -708,7 +742,7 @@ func (t *Transaction) CustomMarshalText() (text []byte, err error) {
 		out.Write(text)
 
 		for len(t.SigBlocks) <= i {
-			t.SigBlocks = append(t.SigBlocks, new(SignatureBlock))
+			t.SigBlocks = append(t.SigBlocks, t.newSignatureBlock(i))
 		}
 		text, err := t.SigBlocks[i].CustomMarshalText()
 		if err != nil {
@@ -233,6 +233,41 @@ func TestValidateAmounts(t *testing.T) {
 	}
 }
 
+func TestEstimateFee(t *testing.T) {
+	tx := new(Transaction)
+	for i := 0; i < 3; i++ {
+		tx.AddInput(testHelper.NewFactoidAddress(uint64(i)), 0x6FFFFFFFFFFFFFFF)
+		tx.AddAuthorization(testHelper.NewFactoidRCDAddress(uint64(i)))
+	}
+	tx.AddOutput(testHelper.NewFactoidAddress(5), 1)
+	tx.AddECOutput(testHelper.NewECAddress(6), 1)
+	for i := 0; i < 3; i++ {
+		tx.SetSignatureBlock(i, NewSingleSignatureBlock(testHelper.NewPrivKey(uint64(i)), []byte("data")))
+	}
+
+	fee, err := tx.CalculateFee(1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	estimate, err := EstimateFee(1000, 3, 1, 1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimate != fee {
+		t.Errorf("estimated a fee of %d for a transaction that pays %d", estimate, fee)
+	}
+
+	// A transaction that needs more than one KiB pays for each KiB
+	if estimate, _ = EstimateFee(1000, 1, 30, 0, 1); estimate != 2000+300000+1000 {
+		t.Errorf("estimated a fee of %d for 30 outputs", estimate)
+	}
+	for _, shape := range [][4]int{{0, 1, 0, 0}, {2, 1, 0, 1}, {1, 256, 0, 1}, {100, 100, 0, 100}} {
+		if _, err := EstimateFee(1000, shape[0], shape[1], shape[2], shape[3]); err == nil {
+			t.Errorf("estimated the fee of an invalid transaction %v", shape)
+		}
+	}
+}
+
 func TestUnmarshalTransaction(t *testing.T) {
 	str := "02014f8a7fcd1b000000"
 	h, err := hex.DecodeString(str)
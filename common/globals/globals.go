@@ -23,6 +23,7 @@ type FactomParams struct {
 	DropRate                 int
 	Journal                  string
 	Journaling               bool
+	JournalStop              string
 	Follower                 bool
 	Leader                   bool
 	Db                       string
@@ -64,6 +65,7 @@ type FactomParams struct {
 	TorManage                bool
 	TorUpload                bool
 	Sim_Stdin                bool
+	SimAPI                   bool
 	ExposeProfiling          bool
 	UseLogstash              bool
 	LogstashURL              string
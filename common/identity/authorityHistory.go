@@ -0,0 +1,71 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package identity
+
+import (
+	"sync"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// AuthorityCheckpointInterval is how many blocks apart an AuthorityHistory keeps its copies of the set
+const AuthorityCheckpointInterval = 1000
+
+// AuthorityHistory answers the authority set at any height without replaying the admin blocks from 0
+// each time.  It advances one set as higher heights are asked for, and keeps a copy of it every
+// interval blocks, so a lower height only replays the blocks since the copy before it.
+type AuthorityHistory struct {
+	mutex       sync.Mutex
+	interval    uint32
+	fetch       func(uint32) (interfaces.IAdminBlock, error)
+	latest      *AuthoritySet
+	checkpoints []*AuthoritySet // the set at every multiple of interval up to latest
+}
+
+func NewAuthorityHistory(interval uint32, fetch func(uint32) (interfaces.IAdminBlock, error)) *AuthorityHistory {
+	h := new(AuthorityHistory)
+	h.interval = interval
+	h.fetch = fetch
+	h.latest = NewAuthoritySet()
+	return h
+}
+
+// At returns the authority set at height.  The set is a copy the caller may advance.
+func (h *AuthorityHistory) At(height uint32) (*AuthoritySet, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.latest.next > height {
+		checkpoint := h.checkpoints[height/h.interval]
+		if checkpoint.DBHeight == height {
+			return checkpoint.Clone(), nil
+		}
+		if h.latest.DBHeight == height {
+			return h.latest.Clone(), nil
+		}
+		set := checkpoint.Clone()
+		if err := set.AdvanceTo(height, h.fetch); err != nil {
+			return nil, err
+		}
+		return set, nil
+	}
+
+	for h.latest.next <= height {
+		target := uint32(len(h.checkpoints)) * h.interval
+		if target > height {
+			target = height
+		}
+		// Advance a copy, so a missing block leaves the set where it was
+		set := h.latest.Clone()
+		if err := set.AdvanceTo(target, h.fetch); err != nil {
+			return nil, err
+		}
+		h.latest = set
+		if target == uint32(len(h.checkpoints))*h.interval {
+			h.checkpoints = append(h.checkpoints, h.latest.Clone())
+		}
+	}
+	return h.latest.Clone(), nil
+}
@@ -0,0 +1,252 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package identity
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/FactomProject/factomd/common/adminBlock"
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The kinds of AuthorityChange
+const (
+	AuthorityAdded           = "added"
+	AuthorityRemoved         = "removed"
+	AuthorityStatus          = "status"
+	AuthoritySigningKey      = "signing-key"
+	AuthorityEfficiency      = "efficiency"
+	AuthorityCoinbaseAddress = "coinbase-address"
+)
+
+// AuthoritySet is the set of federated and audit servers in effect at a directory block height,
+// rebuilt from the admin block history rather than taken from the running identity manager.
+type AuthoritySet struct {
+	DBHeight uint32
+
+	// Every identity the admin blocks have named, servers or not, so an efficiency or coinbase
+	// address set outside of the authority set is still known when the identity joins it
+	identities map[[32]byte]*Authority
+	// Every signing key of an identity, with the height it became active, oldest first
+	keys map[[32]byte][]HistoricKey
+	// Entries already seen that only take effect after DBHeight
+	pending []interfaces.IABEntry
+	// Height of the next admin block to apply
+	next uint32
+}
+
+// AuthorityChange is one difference between the authority sets at two heights
+type AuthorityChange struct {
+	AuthorityChainID interfaces.IHash
+	Change           string
+	From             string
+	To               string
+}
+
+func NewAuthoritySet() *AuthoritySet {
+	as := new(AuthoritySet)
+	as.identities = make(map[[32]byte]*Authority)
+	as.keys = make(map[[32]byte][]HistoricKey)
+	return as
+}
+
+// BuildAuthoritySet replays the admin blocks from 0 to height to rebuild the authority set at height
+func BuildAuthoritySet(height uint32, fetch func(uint32) (interfaces.IAdminBlock, error)) (*AuthoritySet, error) {
+	as := NewAuthoritySet()
+	err := as.AdvanceTo(height, fetch)
+	if err != nil {
+		return nil, err
+	}
+	return as, nil
+}
+
+// AdvanceTo applies the admin blocks after the ones already applied, up to and including height.
+// Entries that name a later height for themselves, such as a server added for the next block, are
+// held until the set reaches that height.
+func (as *AuthoritySet) AdvanceTo(height uint32, fetch func(uint32) (interfaces.IAdminBlock, error)) error {
+	if as.next > 0 && height < as.DBHeight {
+		return fmt.Errorf("Authority set is at height %d and cannot go back to %d", as.DBHeight, height)
+	}
+	as.DBHeight = height
+
+	pending := as.pending
+	as.pending = nil
+	for _, entry := range pending {
+		as.applyEntry(entry)
+	}
+
+	for ; as.next <= height; as.next++ {
+		block, err := fetch(as.next)
+		if err != nil {
+			return err
+		}
+		if block == nil {
+			return fmt.Errorf("Admin block %d not found", as.next)
+		}
+		for _, entry := range block.GetABEntries() {
+			as.applyEntry(entry)
+		}
+	}
+	return nil
+}
+
+func (as *AuthoritySet) applyEntry(entry interfaces.IABEntry) {
+	switch e := entry.(type) {
+	case *adminBlock.AddFederatedServer:
+		if e.DBHeight > as.DBHeight {
+			as.pending = append(as.pending, entry)
+			return
+		}
+		as.getIdentity(e.IdentityChainID).Status = constants.IDENTITY_FEDERATED_SERVER
+	case *adminBlock.AddAuditServer:
+		if e.DBHeight > as.DBHeight {
+			as.pending = append(as.pending, entry)
+			return
+		}
+		as.getIdentity(e.IdentityChainID).Status = constants.IDENTITY_AUDIT_SERVER
+	case *adminBlock.RemoveFederatedServer:
+		if e.DBHeight > as.DBHeight {
+			as.pending = append(as.pending, entry)
+			return
+		}
+		as.getIdentity(e.IdentityChainID).Status = constants.IDENTITY_UNASSIGNED
+	case *adminBlock.AddFederatedServerSigningKey:
+		if e.DBHeight > as.DBHeight {
+			as.pending = append(as.pending, entry)
+			return
+		}
+		auth := as.getIdentity(e.IdentityChainID)
+		auth.SigningKey = e.PublicKey
+		as.keys[auth.AuthorityChainID.Fixed()] = append(as.keys[auth.AuthorityChainID.Fixed()], HistoricKey{e.DBHeight, e.PublicKey})
+	case *adminBlock.ServerFault:
+		// The audit server replaced the faulted federated server, which became an audit server,
+		// from the block after the fault
+		if e.DBHeight+1 > as.DBHeight {
+			as.pending = append(as.pending, entry)
+			return
+		}
+		as.getIdentity(e.AuditServerID).Status = constants.IDENTITY_FEDERATED_SERVER
+		as.getIdentity(e.ServerID).Status = constants.IDENTITY_AUDIT_SERVER
+	case *adminBlock.AddEfficiency:
+		as.getIdentity(e.IdentityChainID).Efficiency = e.Efficiency
+	case *adminBlock.AddFactoidAddress:
+		as.getIdentity(e.IdentityChainID).CoinbaseAddress = e.FactoidAddress
+	}
+}
+
+func (as *AuthoritySet) getIdentity(chainID interfaces.IHash) *Authority {
+	auth, ok := as.identities[chainID.Fixed()]
+	if !ok {
+		auth = NewAuthority()
+		auth.AuthorityChainID = primitives.NewHash(chainID.Bytes())
+		as.identities[chainID.Fixed()] = auth
+	}
+	return auth
+}
+
+// GetFederatedServers returns the federated servers at the height, sorted by chain id
+func (as *AuthoritySet) GetFederatedServers() []*Authority {
+	return as.withStatus(constants.IDENTITY_FEDERATED_SERVER)
+}
+
+// GetAuditServers returns the audit servers at the height, sorted by chain id
+func (as *AuthoritySet) GetAuditServers() []*Authority {
+	return as.withStatus(constants.IDENTITY_AUDIT_SERVER)
+}
+
+func (as *AuthoritySet) withStatus(status uint8) []*Authority {
+	auths := make([]*Authority, 0)
+	for _, auth := range as.identities {
+		if auth.Status == status {
+			auths = append(auths, auth)
+		}
+	}
+	sort.Slice(auths, func(i, j int) bool {
+		return auths[i].AuthorityChainID.String() < auths[j].AuthorityChainID.String()
+	})
+	return auths
+}
+
+// GetKeyHistory returns every signing key an identity has had up to the height, with the height
+// each became active, oldest first
+func (as *AuthoritySet) GetKeyHistory(chainID interfaces.IHash) []HistoricKey {
+	return append([]HistoricKey{}, as.keys[chainID.Fixed()]...)
+}
+
+// Clone returns a copy of the set that can be advanced on its own
+func (as *AuthoritySet) Clone() *AuthoritySet {
+	b := NewAuthoritySet()
+	b.DBHeight = as.DBHeight
+	b.next = as.next
+	b.pending = append(b.pending, as.pending...)
+	for k, v := range as.identities {
+		b.identities[k] = v.Clone()
+	}
+	for k, v := range as.keys {
+		b.keys[k] = append([]HistoricKey{}, v...)
+	}
+	return b
+}
+
+// Changes lists how the servers of the set differ in the set to, sorted by chain id.  Identities
+// that are servers in neither set are left out.
+func (as *AuthoritySet) Changes(to *AuthoritySet) []AuthorityChange {
+	var servers []*Authority
+	seen := make(map[[32]byte]bool)
+	for _, set := range []*AuthoritySet{as, to} {
+		for _, auth := range append(set.GetFederatedServers(), set.GetAuditServers()...) {
+			if !seen[auth.AuthorityChainID.Fixed()] {
+				seen[auth.AuthorityChainID.Fixed()] = true
+				servers = append(servers, auth)
+			}
+		}
+	}
+	sort.Slice(servers, func(i, j int) bool {
+		return servers[i].AuthorityChainID.String() < servers[j].AuthorityChainID.String()
+	})
+
+	changes := make([]AuthorityChange, 0)
+	for _, server := range servers {
+		id := server.AuthorityChainID
+		before, after := as.identities[id.Fixed()], to.identities[id.Fixed()]
+		if before == nil {
+			before = NewAuthority()
+		}
+		if after == nil {
+			after = NewAuthority()
+		}
+		change := func(kind string, from string, to string) {
+			if from != to {
+				changes = append(changes, AuthorityChange{id, kind, from, to})
+			}
+		}
+
+		wasServer, isServer := before.Type() >= 0, after.Type() >= 0
+		switch {
+		case !wasServer:
+			change(AuthorityAdded, "", statusToJSONString(after.Status))
+		case !isServer:
+			change(AuthorityRemoved, statusToJSONString(before.Status), "")
+		default:
+			change(AuthorityStatus, statusToJSONString(before.Status), statusToJSONString(after.Status))
+		}
+		change(AuthoritySigningKey, before.SigningKey.String(), after.SigningKey.String())
+		change(AuthorityEfficiency, fmt.Sprintf("%d", before.Efficiency), fmt.Sprintf("%d", after.Efficiency))
+		change(AuthorityCoinbaseAddress, CoinbaseAddressString(before.CoinbaseAddress), CoinbaseAddressString(after.CoinbaseAddress))
+	}
+	return changes
+}
+
+// CoinbaseAddressString returns the user facing factoid address of a coinbase address, or an empty
+// string if none was set
+func CoinbaseAddressString(address interfaces.IAddress) string {
+	if address == nil || address.IsZero() {
+		return ""
+	}
+	return primitives.ConvertFctAddressToUserStr(address)
+}
@@ -0,0 +1,239 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package identity_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/adminBlock"
+	. "github.com/FactomProject/factomd/common/identity"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+func TestAuthoritySet(t *testing.T) {
+	fed := primitives.RandomHash()
+	audit := primitives.RandomHash()
+	key1 := *primitives.RandomPrivateKey().Pub
+	key2 := *primitives.RandomPrivateKey().Pub
+	coinbase := primitives.RandomHash()
+
+	// 0: fed added for 1, with a key
+	// 2: audit added for 3, fed changes key for 3 and sets its efficiency and coinbase address
+	// 4: fed removed for 5, audit promoted for 5
+	var blocks []interfaces.IAdminBlock
+	for i := 0; i < 6; i++ {
+		var prev interfaces.IAdminBlock
+		if i > 0 {
+			prev = blocks[i-1]
+		}
+		blocks = append(blocks, adminBlock.NewAdminBlock(prev))
+	}
+	blocks[0].AddFedServer(fed)
+	blocks[0].AddFederatedServerSigningKey(fed, key1.Fixed())
+	blocks[2].AddAuditServer(audit)
+	blocks[2].AddFederatedServerSigningKey(fed, key2.Fixed())
+	blocks[2].AddEfficiency(fed, 4000)
+	blocks[2].AddCoinbaseAddress(fed, coinbase)
+	blocks[4].RemoveFederatedServer(fed)
+	blocks[4].AddFedServer(audit)
+	for _, block := range blocks {
+		block.InsertIdentityABEntries()
+	}
+
+	fetch := func(height uint32) (interfaces.IAdminBlock, error) {
+		if int(height) >= len(blocks) {
+			return nil, fmt.Errorf("no block %d", height)
+		}
+		return blocks[height], nil
+	}
+
+	set, err := BuildAuthoritySet(0, fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set.GetFederatedServers()) != 0 {
+		t.Errorf("Server added for height 1 is already in the set at 0")
+	}
+
+	set, err = BuildAuthoritySet(2, fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	feds := set.GetFederatedServers()
+	if len(feds) != 1 || !feds[0].AuthorityChainID.IsSameAs(fed) {
+		t.Fatalf("Expected %s as the only federated server, found %v", fed.String(), feds)
+	}
+	if !feds[0].SigningKey.IsSameAs(&key1) {
+		t.Errorf("Expected signing key %s, found %s", key1.String(), feds[0].SigningKey.String())
+	}
+	if feds[0].Efficiency != 4000 {
+		t.Errorf("Expected efficiency 4000, found %d", feds[0].Efficiency)
+	}
+	if len(set.GetAuditServers()) != 0 {
+		t.Errorf("Server added for height 3 is already in the set at 2")
+	}
+
+	later := set.Clone()
+	err = later.AdvanceTo(3, fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(later.GetAuditServers()) != 1 {
+		t.Errorf("Expected an audit server at 3, found %d", len(later.GetAuditServers()))
+	}
+	if !later.GetFederatedServers()[0].SigningKey.IsSameAs(&key2) {
+		t.Errorf("Expected the new signing key at 3")
+	}
+	keys := later.GetKeyHistory(fed)
+	if len(keys) != 2 || keys[0].ActiveDBHeight != 1 || keys[1].ActiveDBHeight != 3 {
+		t.Errorf("Unexpected key history %v", keys)
+	}
+	if len(set.GetKeyHistory(fed)) != 1 {
+		t.Errorf("Advancing a clone changed the original set")
+	}
+	if err := later.AdvanceTo(2, fetch); err == nil {
+		t.Errorf("Expected an error moving the set back")
+	}
+
+	changes := set.Changes(later)
+	expected := map[string]bool{AuthorityAdded: true, AuthoritySigningKey: true}
+	if len(changes) != len(expected) {
+		t.Errorf("Expected %d changes from 2 to 3, found %v", len(expected), changes)
+	}
+	for _, c := range changes {
+		if !expected[c.Change] {
+			t.Errorf("Unexpected change %v", c)
+		}
+	}
+
+	err = later.AdvanceTo(5, fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	feds = later.GetFederatedServers()
+	if len(feds) != 1 || !feds[0].AuthorityChainID.IsSameAs(audit) || len(later.GetAuditServers()) != 0 {
+		t.Errorf("Expected the audit server to be the only server at 5")
+	}
+	for _, c := range set.Changes(later) {
+		switch {
+		case c.AuthorityChainID.IsSameAs(fed):
+			if c.Change != AuthorityRemoved && c.Change != AuthoritySigningKey {
+				t.Errorf("Unexpected change %v", c)
+			}
+		case c.AuthorityChainID.IsSameAs(audit):
+			if c.Change != AuthorityAdded || c.To != "federated" {
+				t.Errorf("Unexpected change %v", c)
+			}
+		}
+	}
+
+	if _, err := BuildAuthoritySet(6, fetch); err == nil {
+		t.Errorf("Expected an error past the last admin block")
+	}
+}
+
+func TestAuthoritySetServerFault(t *testing.T) {
+	fed := primitives.RandomHash()
+	audit := primitives.RandomHash()
+
+	// 0: fed and audit added for 1
+	// 2: fed faulted and replaced by audit
+	var blocks []interfaces.IAdminBlock
+	for i := 0; i < 4; i++ {
+		var prev interfaces.IAdminBlock
+		if i > 0 {
+			prev = blocks[i-1]
+		}
+		blocks = append(blocks, adminBlock.NewAdminBlock(prev))
+	}
+	blocks[0].AddFedServer(fed)
+	blocks[0].AddAuditServer(audit)
+	fault := new(adminBlock.ServerFault)
+	fault.Timestamp = primitives.NewTimestampNow()
+	fault.ServerID, fault.AuditServerID, fault.DBHeight = fed, audit, 2
+	if err := blocks[2].AddServerFault(fault); err != nil {
+		t.Fatal(err)
+	}
+	fetch := func(height uint32) (interfaces.IAdminBlock, error) {
+		return blocks[height], nil
+	}
+
+	set, err := BuildAuthoritySet(2, fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feds := set.GetFederatedServers(); len(feds) != 1 || !feds[0].AuthorityChainID.IsSameAs(fed) {
+		t.Errorf("The faulted server is replaced in the block of the fault")
+	}
+	if err := set.AdvanceTo(3, fetch); err != nil {
+		t.Fatal(err)
+	}
+	feds, audits := set.GetFederatedServers(), set.GetAuditServers()
+	if len(feds) != 1 || !feds[0].AuthorityChainID.IsSameAs(audit) || len(audits) != 1 || !audits[0].AuthorityChainID.IsSameAs(fed) {
+		t.Errorf("Expected the audit server to replace the faulted server after the fault, found %v and %v", feds, audits)
+	}
+}
+
+func TestAuthorityHistory(t *testing.T) {
+	var blocks []interfaces.IAdminBlock
+	for i := 0; i < 12; i++ {
+		var prev interfaces.IAdminBlock
+		if i > 0 {
+			prev = blocks[i-1]
+		}
+		block := adminBlock.NewAdminBlock(prev)
+		server := primitives.RandomHash()
+		block.AddFedServer(server)
+		block.AddFederatedServerSigningKey(server, primitives.RandomPrivateKey().Pub.Fixed())
+		if i%3 == 0 {
+			block.AddAuditServer(primitives.RandomHash())
+		}
+		blocks = append(blocks, block)
+	}
+	fetches := 0
+	fetch := func(height uint32) (interfaces.IAdminBlock, error) {
+		fetches++
+		if int(height) >= len(blocks) {
+			return nil, fmt.Errorf("no block %d", height)
+		}
+		return blocks[height], nil
+	}
+
+	history := NewAuthorityHistory(4, fetch)
+	for _, height := range []uint32{5, 2, 11, 0, 8, 9, 4, 11, 3} {
+		set, err := history.At(height)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := BuildAuthoritySet(height, fetch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if set.DBHeight != height || len(set.Changes(expected)) != 0 {
+			t.Errorf("The set at %d differs from the set replayed from 0: %v", height, set.Changes(expected))
+		}
+		if len(set.GetFederatedServers()) != len(expected.GetFederatedServers()) {
+			t.Errorf("The set at %d has %d federated servers, expected %d", height, len(set.GetFederatedServers()), len(expected.GetFederatedServers()))
+		}
+	}
+
+	// The highest set is kept, so asking for it again reads no blocks
+	fetches = 0
+	if _, err := history.At(11); err != nil || fetches != 0 {
+		t.Errorf("Read %d blocks for the highest set: %v", fetches, err)
+	}
+	// A lower height replays from the copy of the set before it
+	if _, err := history.At(10); err != nil || fetches != 2 {
+		t.Errorf("Read %d blocks for the set at 10, expected blocks 9 and 10 after the copy at 8: %v", fetches, err)
+	}
+	if _, err := history.At(12); err == nil {
+		t.Errorf("Expected an error past the last admin block")
+	}
+	if set, err := history.At(11); err != nil || set.DBHeight != 11 {
+		t.Errorf("A missing block moved the set: %v", err)
+	}
+}
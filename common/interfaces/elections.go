@@ -64,3 +64,19 @@ type IElectionsFactory interface {
 	//
 	//	NewElectionAdapter(el IElections) IElectionAdapter
 }
+
+// ElectionRecord is the history of one election, kept so it can be audited after a fault
+type ElectionRecord struct {
+	DBHeight     uint32   `json:"dbheight"`
+	Minute       int      `json:"minute"` // -1 for a DBSig election
+	VMIndex      int      `json:"vmindex"`
+	FaultedIndex int      `json:"faultedindex"` // index of the faulted leader in the federated servers
+	FaultedID    string   `json:"faultedid"`
+	Volunteers   []string `json:"volunteers"` // audit servers that volunteered, in the order they did
+	Rounds       int      `json:"rounds"`
+	Level        int      `json:"level"` // highest vote level seen
+	Winner       string   `json:"winner,omitempty"`
+	Outcome      string   `json:"outcome"`   // running, elected or abandoned
+	StartTime    int64    `json:"starttime"` // unix milliseconds
+	Duration     int64    `json:"duration"`  // milliseconds
+}
@@ -28,3 +28,60 @@ type IProcessList interface {
 type IRequest interface {
 	//Key() (thekey [32]byte)
 }
+
+// ProcessListStatus is a snapshot of a process list for monitoring
+type ProcessListStatus struct {
+	DBHeight     uint32     `json:"dbheight"`
+	Complete     bool       `json:"complete"`
+	FedServers   []string   `json:"fedservers"`
+	AuditServers []string   `json:"auditservers"`
+	VMs          []VMStatus `json:"vms"`
+}
+
+// VMStatus is a snapshot of one VM of a process list
+type VMStatus struct {
+	VMIndex      int      `json:"vmindex"`
+	Leader       string   `json:"leader"`       // identity of the server leading the VM in its current minute
+	LeaderMinute int      `json:"leaderminute"` // the minute the leader has acknowledged up to
+	Height       int      `json:"height"`       // messages processed
+	Length       int      `json:"length"`       // messages acknowledged
+	Missing      []int    `json:"missing"`      // heights below Length we have no message for
+	Synced       bool     `json:"synced"`
+	DBSig        bool     `json:"dbsig"` // the DBSig in slot 0 has been processed
+	EOMs         int      `json:"eoms"`  // EOMs processed
+	Faulted      bool     `json:"faulted"`
+	WhenFaulted  int64    `json:"whenfaulted"` // unix time the VM was faulted, 0 if not faulted
+	FaultFlag    int      `json:"faultflag"`
+	ProcessTime  int64    `json:"processtime"` // unix milliseconds of the last progress on the VM
+	Messages     []VMSlot `json:"messages"`
+}
+
+// VMSlot is an acknowledged message at a height in a VM
+type VMSlot struct {
+	Height    int    `json:"height"`
+	Processed bool   `json:"processed"`
+	Type      string `json:"type"`
+	MsgHash   string `json:"msghash"`
+	AckHash   string `json:"ackhash"`
+}
+
+// MessageStatus explains where a node has a message and why it is still held
+type MessageStatus struct {
+	MsgHash         string   `json:"msghash"`
+	Type            string   `json:"type,omitempty"`
+	Locations       []string `json:"locations"`              // holding, dependentholding, processlist and acks
+	DependentKey    string   `json:"dependentkey,omitempty"` // the hash the message waits on in dependent holding
+	DependentOn     string   `json:"dependenton,omitempty"`  // what the dependent key stands for
+	DBHeight        uint32   `json:"dbheight,omitempty"`     // where the message is in a process list
+	VMIndex         int      `json:"vmindex,omitempty"`
+	VMHeight        int      `json:"vmheight,omitempty"`
+	AckHash         string   `json:"ackhash,omitempty"`
+	Validated       bool     `json:"validated"` // the node has validated the message recently
+	ValidToSend     int      `json:"validtosend"`
+	ValidToExecute  int      `json:"validtoexecute"`
+	Age             int64    `json:"age"` // seconds since the message timestamp
+	Stale           bool     `json:"stale"`
+	StaleReason     string   `json:"stalereason,omitempty"`
+	InMsgQueueDepth int      `json:"inmsgqueuedepth"`
+	History         []string `json:"history"` // debug log lines mentioning the message, oldest first
+}
@@ -0,0 +1,47 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// ISimController controls the nodes of a simulation.  The engine provides it when it runs
+// simulated nodes, and the debug API uses it for the sim methods.  Nodes are given by their
+// index in the simulation.
+type ISimController interface {
+	Nodes() []SimNode
+	SelectNode(node int) (*SimResult, error)
+	PromoteLeader(node int, newIdentity bool) (*SimResult, error) // newIdentity attaches the next free identity first
+	PromoteAudit(node int, newIdentity bool) (*SimResult, error)
+	RemoveServer(node int, audit bool) (*SimResult, error)
+	SetOffline(node int, offline bool) (*SimResult, error)
+	KillNode(node int) (*SimResult, error)
+	AddIdentities(count int) (*SimResult, error)
+	SetDropRate(node int, rate int) (*SimResult, error) // node -1 sets every node, rate is out of 1000
+	SetLogging(option string, on bool) (*SimResult, error)
+
+	// The network faults, see the N commands of the simulator control
+	PartitionNodes(groups [][]int) (*SimResult, error)
+	HealPartitions(height *uint32, minute int) (*SimResult, error) // a nil height heals now
+	SetLinkFaults(from int, to int, latency int64, jitter int64, reorder int, duplicate int) (*SimResult, error)
+	ClearNetworkFaults() (*SimResult, error)
+}
+
+// SimNode is the state of one node of a simulation
+type SimNode struct {
+	Index    int    `json:"index"`
+	Name     string `json:"name"`
+	Identity string `json:"identity"`
+	Role     string `json:"role"`     // Leader, Audit or Follower
+	Focus    bool   `json:"focus"`    // the node the simulator commands act on
+	Offline  bool   `json:"offline"`  // the network of the node is off
+	Stopped  bool   `json:"stopped"`  // the node was killed or shut down
+	DropRate int    `json:"droprate"` // out of 1000 messages
+	Height   uint32 `json:"height"`   // the leader height
+}
+
+// SimResult is the outcome of a simulator operation
+type SimResult struct {
+	Message    string    `json:"message"`
+	Nodes      []SimNode `json:"nodes,omitempty"`      // the nodes the operation changed, as they are now
+	Identities []string  `json:"identities,omitempty"` // identity chains added by the operation
+}
@@ -33,6 +33,7 @@ type IQueue interface {
 // accidentally
 type IState interface {
 	GetRunState() runstate.RunState
+	AddShutdownHandler(handler func()) // called once the node shut down and closed its database
 	// Server
 	GetFactomNodeName() string
 	GetSalt(Timestamp) uint32 // A secret number computed from a TS that tests if a message was issued from this server or not
@@ -207,6 +208,9 @@ type IState interface {
 	IncDBStateAnswerCnt()
 
 	GetPendingTransactions(interface{}) []IPendingTransaction
+	GetPendingPool(address string) []IPendingPoolItem
+	ValidateWithoutSubmit(msg IMsg) *IValidationReport
+	GetUpcomingGrants() []IScheduledGrant
 	// MISC
 	// ====
 
@@ -295,6 +299,7 @@ type IState interface {
 	ExchangeRateAuthorityIsValid(IEBEntry) bool
 	FerEntryIsValid(passedFEREntry IFEREntry) bool
 	GetPredictiveFER() uint64
+	GetFERChange() (price uint64, height uint32)
 
 	// Identity Section
 	VerifyIsAuthority(cid IHash) bool // True if is authority
@@ -307,9 +312,12 @@ type IState interface {
 	AddAuthorityDelta(changeString string)
 
 	GetElections() IElections
+	GetElectionHistory() []ElectionRecord
 	GetAuthorities() []IAuthority
 	GetAuthorityInterface(chainid IHash) IAuthority
 	GetLeaderPL() IProcessList
+	GetProcessListStatus(dbheight uint32) *ProcessListStatus
+	GetMessageStatus(hash IHash) *MessageStatus
 	GetLLeaderHeight() uint32
 	GetEntryDBHeightComplete() uint32
 	GetMissingEntryCount() uint32
@@ -95,3 +95,43 @@ type IPendingTransaction struct {
 	ECOutputs     []ITransAddress `json:"ecoutputs"`
 	Fees          uint64          `json:"fees"`
 }
+
+// IScheduledGrant is a grant that will be declared in the coinbase descriptor at Height
+type IScheduledGrant struct {
+	Height  uint32
+	Amount  uint64
+	Address IAddress
+}
+
+// IPendingPoolItem is a factoid transaction, entry credit commit or entry reveal that is not in a
+// saved block yet
+type IPendingPoolItem struct {
+	TransactionID   IHash    `json:"transactionid"` // the entry hash of a reveal
+	MsgHash         IHash    `json:"msghash"`
+	Type            string   `json:"type"`  // factoid-transaction, commit-chain, commit-entry or reveal-entry
+	State           string   `json:"state"` // held, held-for-fct-balance, held-for-ec-balance, held-for-commit, held-for-chain-head, awaiting-minute, acked or processed
+	DBHeight        uint32   `json:"dbheight,omitempty"`
+	VMIndex         int      `json:"vmindex"`
+	Minute          int      `json:"minute"`
+	Position        int      `json:"position"`         // messages ahead of it in its VM, in holding or waiting on the same thing
+	Priority        int      `json:"priority"`         // pending items this node gets to before it
+	Fee             uint64   `json:"fee"`              // factoshis a factoid transaction or entry credits a commit pays
+	Reason          string   `json:"reason,omitempty"` // why a held message is not acked, e.g. insufficient-balance
+	ValidationError string   `json:"validationerror,omitempty"`
+	ExpiresIn       int64    `json:"expiresin"` // seconds until a held message is too old to be acknowledged
+	Addresses       []string `json:"addresses"` // the FA and EC addresses it spends from or pays to
+}
+
+// IValidationReport is the result of validating a factoid transaction or entry credit commit
+// without submitting it
+type IValidationReport struct {
+	TransactionID IHash                  `json:"transactionid"`
+	Valid         bool                   `json:"valid"`
+	Rejections    []IValidationRejection `json:"rejections"`
+}
+
+// IValidationRejection is one reason a transaction or commit would be rejected or held
+type IValidationRejection struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
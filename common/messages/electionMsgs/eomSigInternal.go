@@ -150,6 +150,7 @@ func (m *EomSigInternal) ElectionProcess(is interfaces.IState, elect interfaces.
 		// Reset elections as we moved forward
 		if int(m.DBHeight) > e.DBHeight && e.Electing != -1 {
 			e.Electing = -1
+			s.ElectionEnded(nil)
 		}
 
 		// Sort leaders, on block boundaries
@@ -98,6 +98,10 @@ func (m *FedVoteLevelMsg) ElectionProcess(is interfaces.IState, elect interfaces
 		}
 	}
 
+	if s, ok := is.(*state.State); ok {
+		s.ElectionLevel(int(m.Level))
+	}
+
 	/******  Election Adapter Control   ******/
 	/**	Controlling the inner election state**/
 	m.processIfCommitted(is, elect) // This will end the election if it's over
@@ -169,6 +173,9 @@ func (m *FedVoteLevelMsg) processIfCommitted(is interfaces.IState, elect interfa
 		is.InMsgQueue().Enqueue(m)
 		// End the election by setting this to '-1'
 		e.Electing = -1
+		if s, ok := is.(*state.State); ok {
+			s.ElectionEnded(m.Volunteer.ServerID)
+		}
 		e.LogPrintf("election", "**** Election is over. Elected %d[%x] ****", m.Volunteer.ServerIdx, m.Volunteer.ServerID.Bytes()[3:6])
 
 		e.LogPrintf("faulting", "**** Election is over. Elected %d[%x] ****", m.Volunteer.ServerIdx, m.Volunteer.ServerID.Bytes()[3:6])
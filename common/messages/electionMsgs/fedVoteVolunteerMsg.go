@@ -79,6 +79,9 @@ func (m *FedVoteVolunteerMsg) ElectionProcess(is interfaces.IState, elect interf
 	e.Msg = m.Missing
 	e.Ack = m.Ack
 	e.VName = m.ServerName
+	if s, ok := is.(*state.State); ok {
+		s.ElectionVolunteer(m.ServerID)
+	}
 
 	/******  Election Adapter Control   ******/
 	/**	Controlling the inner election state**/
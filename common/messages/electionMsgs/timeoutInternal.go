@@ -159,6 +159,7 @@ func (m *TimeoutInternal) ElectionProcess(is interfaces.IState, elect interfaces
 
 		e.Electing = electing
 		e.FedID = fedID
+		s.ElectionStarted(uint32(m.DBHeight), m.ComparisonMinute(), e.VMIndex, electing, fedID)
 
 		// Reset this value when we start an election
 		for len(e.Round) <= e.Electing {
@@ -190,6 +191,7 @@ func (m *TimeoutInternal) ElectionProcess(is interfaces.IState, elect interfaces
 
 	// New timeout, new round of elections.
 	e.Round[e.Electing]++
+	s.ElectionRound(e.Round[e.Electing])
 
 	// If we don't have all our sync messages, we will have to come back around and see if all is well.
 	// Start our timer to timeout this sync
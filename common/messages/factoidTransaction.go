@@ -7,6 +7,7 @@ package messages
 import (
 	"fmt"
 
+	"github.com/FactomProject/factomd/activations"
 	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/factoid"
 	"github.com/FactomProject/factomd/common/interfaces"
@@ -108,6 +109,11 @@ func (m *FactoidTransaction) Validate(state interfaces.IState) int {
 		return -1 // No, object!
 	}
 
+	// Multisig addresses can only spend once they are activated
+	if factoid.UsesRCD_2(m.Transaction) && !state.IsActive(activations.RCD_2_MULTISIG) {
+		return -1
+	}
+
 	// Is the transaction properly signed?
 	err = m.Transaction.ValidateSignatures()
 	if err != nil {
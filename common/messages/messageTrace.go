@@ -13,6 +13,8 @@ var LogPrintf = log.LogPrintf
 var CheckFileName = log.CheckFileName
 var StateLogMessage = log.StateLogMessage
 var StateLogPrintf = log.StateLogPrintf
+var NodeLogMessage = log.NodeLogMessage
+var NodeLogPrintf = log.NodeLogPrintf
 var LogMessage = log.LogMessage
 
 type foo func(data []byte) (interfaces.IMsg, error)
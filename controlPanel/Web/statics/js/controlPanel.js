@@ -109,6 +109,7 @@ function updataDataDumps() {
 
     $("#dump6 #dumpElections").text(obj.ElectionDataDump.Elections)
     $("#dump6 #dumpSimulatedElections").text(obj.ElectionDataDump.SimulatedElection)
+    $("#dump6 #dumpElectionHistory").text(obj.ElectionDataDump.History)
     if(obj.LogSettingsDump.CurrentLogSettings == "") {
         obj.LogSettingsDump.CurrentLogSettings = " "
     }
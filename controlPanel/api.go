@@ -0,0 +1,170 @@
+package controlPanel
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	llog "github.com/FactomProject/factomd/log"
+)
+
+// The JSON API of the control panel, under /api/v1/.  It serves what the panel displays for
+// dashboards of our own.  See the README for the endpoints.
+
+// APIVersion is the version in the path of the JSON API
+const APIVersion = "v1"
+
+// APISummary is the summary of the node
+type APISummary struct {
+	NodeName         string `json:"nodename"`
+	Version          string `json:"version"`
+	GitBuild         string `json:"gitbuild"`
+	IdentityChainID  string `json:"identitychainid"`
+	NodeHeight       uint32 `json:"nodeheight"`
+	LeaderHeight     uint32 `json:"leaderheight"`
+	CompleteHeight   uint32 `json:"completeheight"` // height of the entries, the second pass of the sync
+	FederatedServers int    `json:"federatedservers"`
+	AuditServers     int    `json:"auditservers"`
+	IgnoreDone       bool   `json:"ignoredone"`
+	Role             string `json:"role"` // the role of the user asking
+}
+
+// APIDisconnectRequest is the body of a peer disconnect
+type APIDisconnectRequest struct {
+	Peer string `json:"peer"` // the PeerHash of the peer, as the peers list gives it
+}
+
+// APIDisconnectResponse is the reply to a peer disconnect
+type APIDisconnectResponse struct {
+	Peer string `json:"peer"`
+	Id   string `json:"id"` // the Hash of the peer in the peers list
+}
+
+// APIError is the body of any error reply of the API
+type APIError struct {
+	Error string `json:"error"`
+}
+
+// AddAPIEndpoints adds the JSON API to the control panel mux
+func AddAPIEndpoints(mux *http.ServeMux) {
+	prefix := "/api/" + APIVersion
+	mux.HandleFunc(prefix+"/summary", apiHandler("GET", RoleReadOnly, apiSummary))
+	mux.HandleFunc(prefix+"/peers", apiHandler("GET", RoleReadOnly, apiPeers))
+	mux.HandleFunc(prefix+"/peers/totals", apiHandler("GET", RoleReadOnly, apiPeerTotals))
+	mux.HandleFunc(prefix+"/peers/disconnect", apiHandler("POST", RoleReadWrite, apiDisconnect))
+	mux.HandleFunc(prefix+"/transactions", apiHandler("GET", RoleReadOnly, apiTransactions))
+	mux.HandleFunc(prefix+"/datadump", apiHandler("GET", RoleReadOnly, apiDataDump))
+}
+
+// apiHandler checks the user has the role an endpoint needs before calling it
+func apiHandler(method string, needs int, h func(w http.ResponseWriter, r *http.Request, role int)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				llog.LogPrintf("recovery", "Control Panel has encountered a panic in the API. %v", rec)
+				writeAPIError(w, http.StatusInternalServerError, fmt.Sprintf("%v", rec))
+			}
+		}()
+
+		role, ok := requestRole(r)
+		if !ok {
+			w.Header().Add("WWW-Authenticate", `Basic realm="factomd Control Panel"`)
+			writeAPIError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		if role < needs {
+			writeAPIError(w, http.StatusForbidden, fmt.Sprintf("needs the %s role", RoleName(needs)))
+			return
+		}
+		if r.Method != method {
+			writeAPIError(w, http.StatusMethodNotAllowed, fmt.Sprintf("use %s", method))
+			return
+		}
+		RequestData()
+		h(w, r, role)
+	}
+}
+
+func writeAPI(w http.ResponseWriter, status int, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		status = http.StatusInternalServerError
+		data, _ = json.Marshal(APIError{err.Error()})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+func writeAPIError(w http.ResponseWriter, status int, msg string) {
+	writeAPI(w, status, APIError{msg})
+}
+
+func apiSummary(w http.ResponseWriter, r *http.Request, role int) {
+	s := new(APISummary)
+	s.Role = RoleName(role)
+	if indexTemplateData != nil {
+		s.GitBuild = indexTemplateData.GitBuild
+	}
+	s.Version = StatePointer.GetFactomdVersion()
+	s.FederatedServers, s.AuditServers = serverCounts()
+
+	DisplayStateMutex.RLock()
+	s.NodeName = DisplayState.NodeName
+	if DisplayState.IdentityChainID != nil {
+		s.IdentityChainID = DisplayState.IdentityChainID.String()
+	}
+	s.NodeHeight = DisplayState.CurrentNodeHeight
+	s.LeaderHeight = DisplayState.LeaderHeight
+	if DisplayState.CurrentNodeHeight > DisplayState.LeaderHeight {
+		s.LeaderHeight = DisplayState.CurrentNodeHeight
+	}
+	s.CompleteHeight = DisplayState.CurrentEBDBHeight
+	s.IgnoreDone = DisplayState.IgnoreDone
+	DisplayStateMutex.RUnlock()
+
+	writeAPI(w, http.StatusOK, s)
+}
+
+func apiPeers(w http.ResponseWriter, r *http.Request, role int) {
+	if AllConnections == nil {
+		writeAPI(w, http.StatusOK, ConnectionInfoArray{})
+		return
+	}
+	writeAPI(w, http.StatusOK, AllConnections.SortedConnections())
+}
+
+func apiPeerTotals(w http.ResponseWriter, r *http.Request, role int) {
+	if AllConnections == nil {
+		writeAPI(w, http.StatusOK, NewAllConnectionTotals())
+		return
+	}
+	AllConnections.Lock.Lock()
+	totals := AllConnections.Totals
+	AllConnections.Lock.Unlock()
+	writeAPI(w, http.StatusOK, totals)
+}
+
+func apiDisconnect(w http.ResponseWriter, r *http.Request, role int) {
+	req := new(APIDisconnectRequest)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil || req.Peer == "" {
+		writeAPIError(w, http.StatusBadRequest, `expected {"peer": "<PeerHash>"}`)
+		return
+	}
+	disconnectPeer(req.Peer)
+	writeAPI(w, http.StatusOK, APIDisconnectResponse{Peer: req.Peer, Id: hashPeerAddress(req.Peer)})
+}
+
+func apiTransactions(w http.ResponseWriter, r *http.Request, role int) {
+	RecentTransactionsMutex.Lock()
+	defer RecentTransactionsMutex.Unlock()
+	if RecentTransactions == nil {
+		writeAPI(w, http.StatusOK, new(LastDirectoryBlockTransactions))
+		return
+	}
+	writeAPI(w, http.StatusOK, RecentTransactions)
+}
+
+func apiDataDump(w http.ResponseWriter, r *http.Request, role int) {
+	writeAPI(w, http.StatusOK, getDataDump())
+}
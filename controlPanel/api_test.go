@@ -0,0 +1,118 @@
+package controlPanel_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/FactomProject/factomd/controlPanel"
+	"github.com/FactomProject/factomd/state"
+)
+
+func apiRequest(mux *http.ServeMux, method string, path string, user string, pass string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if user != "" {
+		req.SetBasicAuth(user, pass)
+	}
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	return w
+}
+
+func TestParseControlPanelUser(t *testing.T) {
+	u, err := ParseControlPanelUser("alice:secret:readwrite")
+	if err != nil || u.Name != "alice" || u.Role != RoleReadWrite {
+		t.Errorf("got %+v %v", u, err)
+	}
+	u, err = ParseControlPanelUser("bob:se:cret:readonly")
+	if err != nil || u.Name != "bob" || u.Role != RoleReadOnly {
+		t.Errorf("password with a ':', got %+v %v", u, err)
+	}
+	for _, bad := range []string{"alice", "alice:secret", ":secret:readonly", "alice::readonly", "alice:secret:admin"} {
+		if _, err := ParseControlPanelUser(bad); err == nil {
+			t.Errorf("%q should not parse", bad)
+		}
+	}
+
+	errs := SetControlPanelUsers([]string{"alice:secret:readwrite", "hunter2"})
+	defer SetControlPanelUsers(nil)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "line 2") || strings.Contains(errs[0].Error(), "hunter2") {
+		t.Errorf("expected an error for line 2 without the line in it, got %v", errs)
+	}
+}
+
+func TestAPIRoles(t *testing.T) {
+	StatePointer = new(state.State)
+	StatePointer.ControlPanelSetting = RoleReadWrite
+	defer SetControlPanelUsers(nil)
+	if errs := SetControlPanelUsers([]string{"viewer:view:readonly", "operator:op:readwrite", "broken", "colon:a:b:readonly"}); len(errs) != 1 {
+		t.Errorf("expected 1 error for the broken user, got %v", errs)
+	}
+
+	mux := http.NewServeMux()
+	AddAPIEndpoints(mux)
+
+	if w := apiRequest(mux, "GET", "/api/v1/summary", "", "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("no user: expected 401, got %d", w.Code)
+	}
+	if w := apiRequest(mux, "GET", "/api/v1/summary", "viewer", "wrong", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("wrong password: expected 401, got %d", w.Code)
+	}
+
+	w := apiRequest(mux, "GET", "/api/v1/summary", "viewer", "view", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("viewer summary: expected 200, got %d %s", w.Code, w.Body.String())
+	}
+	summary := new(APISummary)
+	if err := json.Unmarshal(w.Body.Bytes(), summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary.Role != "readonly" {
+		t.Errorf("expected the readonly role, got %q", summary.Role)
+	}
+	if w := apiRequest(mux, "GET", "/api/v1/summary", "colon", "a:b", ""); w.Code != http.StatusOK {
+		t.Errorf("password with a ':': expected 200, got %d", w.Code)
+	}
+
+	if w := apiRequest(mux, "POST", "/api/v1/peers/disconnect", "viewer", "view", `{"peer":"abc"}`); w.Code != http.StatusForbidden {
+		t.Errorf("viewer disconnect: expected 403, got %d", w.Code)
+	}
+	if w := apiRequest(mux, "GET", "/api/v1/peers/disconnect", "operator", "op", ""); w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("disconnect with GET: expected 405, got %d", w.Code)
+	}
+	if w := apiRequest(mux, "POST", "/api/v1/peers/disconnect", "operator", "op", `{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("disconnect without a peer: expected 400, got %d", w.Code)
+	}
+	w = apiRequest(mux, "POST", "/api/v1/peers/disconnect", "operator", "op", `{"peer":"abc"}`)
+	if w.Code != http.StatusOK {
+		t.Errorf("operator disconnect: expected 200, got %d %s", w.Code, w.Body.String())
+	}
+
+	// A readonly control panel makes everyone readonly
+	StatePointer.ControlPanelSetting = RoleReadOnly
+	if w := apiRequest(mux, "POST", "/api/v1/peers/disconnect", "operator", "op", `{"peer":"abc"}`); w.Code != http.StatusForbidden {
+		t.Errorf("operator disconnect on a readonly panel: expected 403, got %d", w.Code)
+	}
+
+	for _, path := range []string{"/api/v1/peers", "/api/v1/peers/totals", "/api/v1/transactions"} {
+		if w := apiRequest(mux, "GET", path, "operator", "op", ""); w.Code != http.StatusOK {
+			t.Errorf("%s: expected 200, got %d %s", path, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestAPIOpenAccess(t *testing.T) {
+	StatePointer = new(state.State)
+	StatePointer.ControlPanelSetting = RoleReadOnly
+	SetControlPanelUsers(nil)
+
+	mux := http.NewServeMux()
+	AddAPIEndpoints(mux)
+
+	// With no users and no RPC user anyone can read
+	if w := apiRequest(mux, "GET", "/api/v1/summary", "", "", ""); w.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", w.Code)
+	}
+}
@@ -15,7 +15,6 @@ import (
 
 	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/directoryBlock"
-	"github.com/FactomProject/factomd/common/globals"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/FactomProject/factomd/controlPanel/files"
@@ -112,6 +111,9 @@ func ServeControlPanel(displayStateChannel chan state.DisplayState, statePointer
 
 	StatePointer = statePointer
 	StatePointer.ControlPanelDataRequest = true // Request initial State
+	for _, err := range SetControlPanelUsers(StatePointer.ControlPanelUsers) {
+		fmt.Println("Control Panel:", err)
+	}
 	// Wait for initial State
 	DisplayState = <-displayStateChannel
 
@@ -152,6 +154,7 @@ func ServeControlPanel(displayStateChannel chan state.DisplayState, statePointer
 	controlPanelMux.HandleFunc("/post", postHandler)
 	controlPanelMux.HandleFunc("/factomd", factomdHandler)
 	controlPanelMux.HandleFunc("/factomdBatch", factomdBatchHandler)
+	AddAPIEndpoints(controlPanelMux)
 
 	tlsIsEnabled, tlsPrivate, tlsPublic := StatePointer.GetTlsInfo()
 	if tlsIsEnabled {
@@ -248,11 +251,14 @@ func postHandler(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	case "changelogs":
-		// >= 2 means we have write access
-		if StatePointer.ControlPanelSetting == 2 {
+		if role, _ := requestRole(r); role >= RoleReadWrite {
 			newRegex := r.FormValue("logsetting")
 			fmt.Printf("Changing log regex to: '%s'\n", newRegex)
-			globals.Params.DebugLogRegEx = newRegex
+			if err := llog.SetDebugLogRegEx(newRegex, 0); err != nil {
+				data, _ := json.Marshal(map[string]string{"Error": err.Error()})
+				w.Write(data)
+				return
+			}
 		} else {
 			w.Write([]byte(`{"Error": "Access denied"}`))
 			return
@@ -298,13 +304,14 @@ func factomdBatchHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "GET" {
 		return
 	}
+	role, _ := requestRole(r)
 	batch := r.FormValue("batch")
 	batchData := make([]byte, 0)
 	batchData = append(batchData, []byte(`[`)...)
 
 	items := strings.Split(batch, ",")
 	for _, item := range items {
-		data := factomdQuery(item, "", true)
+		data := factomdQuery(item, "", true, role)
 		batchData = append(batchData, data...)
 		batchData = append(batchData, []byte(`,`)...)
 	}
@@ -327,9 +334,10 @@ func factomdHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "GET" {
 		return
 	}
+	role, _ := requestRole(r)
 	item := r.FormValue("item")   // Item wanted
 	value := r.FormValue("value") // Optional argument
-	data := factomdQuery(item, value, false)
+	data := factomdQuery(item, value, false, role)
 	w.Write([]byte(data))
 }
 
@@ -354,7 +362,7 @@ func RequestData() {
 	requestMutex = false
 }
 
-func factomdQuery(item string, value string, batchQueried bool) []byte {
+func factomdQuery(item string, value string, batchQueried bool, role int) []byte {
 	if !batchQueried {
 		RequestData()
 	}
@@ -401,17 +409,7 @@ func factomdQuery(item string, value string, batchQueried bool) []byte {
 		DisplayState = Fnodes[index]*/
 		return []byte(fmt.Sprintf("%d", index))
 	case "servercount": // TODO
-		DisplayStateMutex.RLock()
-		feds := 0
-		auds := 0
-		for _, a := range DisplayState.Authorities {
-			if a.Status == 1 {
-				feds++
-			} else if a.Status == 2 {
-				auds++
-			}
-		}
-		DisplayStateMutex.RUnlock()
+		feds, auds := serverCounts()
 		return []byte(fmt.Sprintf(`{"fed":%d,"aud":%d}`, feds, auds))
 	case "channelLength":
 		return []byte(fmt.Sprintf(`{"length":%d}`, len(DisplayStateChannel)))
@@ -440,10 +438,7 @@ func factomdQuery(item string, value string, batchQueried bool) []byte {
 		if len(value) > 0 {
 			hash = hashPeerAddress(value)
 		}
-		DisplayStateMutex.RLock()
-		CPS := DisplayState.ControlPanelSetting
-		DisplayStateMutex.RUnlock()
-		if CPS == 2 {
+		if role >= RoleReadWrite {
 			disconnectPeer(value)
 			return []byte(`{"Access":"granted", "Id":"` + hash + `"}`)
 		} else {
@@ -453,6 +448,20 @@ func factomdQuery(item string, value string, batchQueried bool) []byte {
 	return []byte("")
 }
 
+// serverCounts returns the number of federated and audit servers
+func serverCounts() (feds int, auds int) {
+	DisplayStateMutex.RLock()
+	defer DisplayStateMutex.RUnlock()
+	for _, a := range DisplayState.Authorities {
+		if a.Status == 1 {
+			feds++
+		} else if a.Status == 2 {
+			auds++
+		}
+	}
+	return feds, auds
+}
+
 func disconnectPeer(hash string) {
 	if Controller != nil {
 		fmt.Println("ControlPanel: Sent a disconnect signal.")
@@ -834,7 +843,7 @@ func doEvery(d time.Duration, f func(time.Time)) {
 }
 
 func checkControlPanelPassword(response http.ResponseWriter, request *http.Request) bool {
-	if false == checkAuthHeader(request) {
+	if _, ok := requestRole(request); !ok {
 		remoteIP := ""
 		remoteIP += strings.Split(request.RemoteAddr, ":")[0]
 		fmt.Printf("Unauthorized Control Panel client connection attempt from %s\n", remoteIP)
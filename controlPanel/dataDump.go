@@ -34,6 +34,7 @@ type DataDump struct {
 	ElectionDataDump struct {
 		Elections         string
 		SimulatedElection string
+		History           string
 	}
 	LogSettingsDump struct {
 		CurrentLogSettings string
@@ -41,6 +42,14 @@ type DataDump struct {
 }
 
 func GetDataDumps() []byte {
+	ret, err := json.Marshal(getDataDump())
+	if err != nil {
+		return []byte(`{"list":"none"}`)
+	}
+	return ret
+}
+
+func getDataDump() *DataDump {
 	holder := new(DataDump)
 	DisplayStateMutex.RLock()
 	DsCopy := DisplayState.Clone()
@@ -65,14 +74,11 @@ func GetDataDumps() []byte {
 
 	holder.ElectionDataDump.Elections = DsCopy.Election
 	holder.ElectionDataDump.SimulatedElection = DsCopy.SimElection
+	holder.ElectionDataDump.History = DsCopy.ElectionHistory
 
 	holder.LogSettingsDump.CurrentLogSettings = globals.LastDebugLogRegEx
 
-	ret, err := json.Marshal(holder)
-	if err != nil {
-		return []byte(`{"list":"none"}`)
-	}
-	return ret
+	return holder
 }
 
 func SortedConnectionString() string {
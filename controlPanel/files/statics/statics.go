@@ -143,11 +143,11 @@ var staticFiles = map[string]*staticFilesFile{
 		size:  0,
 	},
 	"js/controlPanel.js": {
-		data:  "\x1f\x8b\b\x00\x00\x00\x00\x00\x02\xff\xec<ks\xdb8\x92\xdf\xf5+z\x98\xec\x8a\x1cK\x94\x9c\xcc\xe4\xeeƖ\xab\xe2x\xb2\xeb\x9d\xc4\xc9ľ\xbd\x0f9\xd7\x15DB\x12\x12\x12`\x00жj\xc6\xff\xfd\n\x0f\x92\x00E\xea1\x8f\xd4~ة\x9aX\x02\xfa\x85\xeeFw\xb3\x01\xea\x0eqHJ\xce1\x95\u007f\xc7d\xb9\x920\x83\xe9@\x8df\x18\xa5\x98;\x83\x03\x81\xe5%\x95\x98ߡ,,\x8b\x14I\xfc\xf7\x9b\xb7oFϧ\xd3it\xa2q\x04\xe6w\x98\xbf\xa3\x19\xa1\x18f\xb0@\x99\xc0\x83\xc9\x04\xfe[\xe0\x14$\x03\x83\x05\x82\xe5\x18\xe4\x8aХ\x80\f\v\x01\v\x8e\xbf\x94\x98\xcalm\xc8|&Eũ&3x\x1a\xde\x13\x9a\xb2\xfb(\xce\x18J\xc3\x01\x00\xc0\xa2\xa4\x89$\x8c\x86\x11\xfc\xa2\a\x00\x1a\xc9\xc2\xc8\x0e\t,oH\x8eY)\xc3\n\x01\x1c\x8c^\xbc\xc7\x11\x1c\x9b\xc5\xe9o\x83\xe8d0\xa8\t\xb8\xf0\x9a\xd4\xd3\x18}B\x0f\xe10\x9e\fG\x96\xb6(\x93\x04\v\xf1\x83#\xe7/\xb5L\x9e\xaa$/\xb1\xe12\xd2\u007f0\xe7\x8c\xef\x81gtc\xc4\x03xT\x12\x02\x90\x05\x84߸\x80\xd5Z\x9f\x86\xc1\x133>\x16\x12\xc9R\x04Q,\xf1\x83\f\x83\xd7(\x91,O\xe1\x8aI\xf8PRJ\xe820j\xe0X\x96\x9c*\xe2\x803\x81\xf7\xa6\xe4Ry\xac\xa4Rh\x84\xa6\xf8\x81\xa2\xbbq\x8e\b\r\xa2x\x85ī\f\t\x11\x06D\x8cQ\"\xc9\x1d\x0e\xa2J\xe2\xc9\x04\xde\"B\xe1\x06\xcd\a\x8e\x95\xb4W\x86\x118c/\xb3\xec=\xc6\\X\xebM&p\xc1\xb0\x00|\x87\xf9\x1a\x10er\x859$\xeb$3\xea\"\x8b\xf0\x1b\xd7\xcf\"\xdf\u007fn8\xa2\x02i\u074bƏ|\xbfll\xe6j\x06\xbaݷ6\x91\x81%\x8b\x96.\x18\xc7{\xe8\xe2\x02KD2\x9c\xfa\xfa@\x17\xea\xff2/\x8c\xa8\x8f\x83\xc1\xe3@o;\xb3\xdd\xeeW\x98\xc2=\x06qOd\xb2\x02\x89\xe6b\xf04\fb\xf5a\x9c0*9\xcb\xc6\x05\xa28\x83\x8c\x00\n\xa28\xc9H\xf29\xf4\x9d\xcf\xd9D\x03o\xe3\r\xc0\xca\xd7\xdaA\x8f#x>\x9dF\x83\xc7H\xed\xdd\xe0IZ\xe6\x85f\x87\b\xc5\x1c\x9e,\xca,\x13\tǘ\x8eY\xa1hՌ[n/\x1f\xe4K\x8e\x11\xcc\xe0\xd3\xcf%\xe6\xebP\xae\x88\x88bA\xe6\x99\n!a\x10;\xcaj\xe0cɖ\xcb\f[}6\xdc4\x8cG\xc9\x03Ds\xc1\xb2R\xe2q\x87|[\x11\x17\xe4\x01\xa7\x9dXJ\x03\xca\x1e7\xac\xd0\xda\aFA[~\xb0\xb1\x1f\xe0\xac\xd3\x00Z\xc3j\x03y\xec\xb7x\xcb\xc6f\x95\x8eC\aQ\xccq\xce\xee*\xc9W$\xc5v\xb7+Ќ%(\xdb\x01\x93Z\x8f\v\xa2\x18\xa5i\x1b\xe6\xb16\xba\xe7\xe0_kq\x1d\x12\xf9+\xeb\x05p\x96սz\xb32?\t\xb8\xdbO\vű(`\x06_\xd4j\xae%\x928\fj\xc2#\b\x82Q\xbdv\x05i#\x0f\x9b\u007f\x82\x19\xfc\xe3\xfa\xddU\\ .\xb0\x99k$+\xf3\xe2\x18\xf4\x9f\xeb\x15㲊\xb7l\xfe)\xae\xf8\x1f\xc7zJ}\xecD\xfc\x80\xee\xbb\xd1>\xa0\xfb^\xa4\xeb5Mt\x1c\xef\xe4g&\r\xb2\x87\xfd\xacf\xf9\x9e\xb3\xa4\v\xfbY7[\x8bx\x85\x1fd7\x96\x9a\xe9E{\xcf\xf1]7\x9a\x9a\xb1rz\x88Ϸ\xea\xe6y#\xa4\x87\xf5\x9d\xc1zY\xcaU\x17\xdaw\xb1\x9aa\x9cH\x82Eԅy\x99b*\xbbQ\xf5T?\xe6\xdb\xf5\x15S\xde\u0605j\xe6Z\xb2~o\xf0^1ڳ\xc8\xef\xbb-a\xf1\xae{\xbc\xed\xfbX\xcd\xe0\xb4C;/\f\xe6\x8f\x19\xae\xf7d\x8d^\rVd\xea\x81\xd6j-\x8dk\x92\x97\x19\x928ݏ\xd8\x06xTe{\x05\xfe\x86-\xaf\xb1\x94*kh\xe8W\xa6\xf2u\x86a6\x83 pk\xc3}\xf1 \x80\xa0\xce\xf2\xcd2\xfe\x03\x9e\xd8\xfaz\x9c\xb1\xe5\xf8\x0ee\xa5g\xbbݤu؉TJo՞~\x8d\xd2\x17y8N0\x95.l0:<\x04M&\xb6b\xb98?\xcfX\xf2\xd9T`\xd5B\"\xf8f\xa6\x15uA8N$\xe3k\r\x14_\x9c\x1b\xb8F\x9f\x86\xc4Ox\xfd\xf6\x83\xcd\x06\x8d_\xf9\xb8\x1a&\xf2\xd0\xceY\xba\xd6\xc3[\xd0j\x18\x1f\xf5u\x99e\u007fGb\xb5\x05\xb3\x02i\xf1Ts\xaa\xf0\x11\x12\xe9\xbcЇ^\xc3t\xe0\xfb\xdaڦ(\a\xd7\x18n\x9cV\x90\xe3\xb9\x02\u074b\x88\xa5b\xbd^W\xe4$u]@ً\x96YS\xf5\x1a?\uf00c\x17\x8c\xff\x88\x92U\x93\xafu\xb2\xf5\x9f\x9e\xc8\u008c\xc67L\xa2\xec\x92\x16\xa5\x843\x98\xc6\xd3\xe9\xf4\xb8\xfd\x9ce\xfd\xa8@\xd4r\x13p\x06*\x83?\xbc!B\xa1\xc99K\xd7\xf0$\x80#\xb0D\x1f./\xa28\xc3t)W\x8al\x9bb\xab\xfc\xae\xfeۃK\x10\xc5\x05\xc7\x05\xa6i\x18\xfco\v\xfdTr \xe9l\xe8\xcb\x01G\x10\f\xcfڰ\x06>=;E\x1ae\xa1\x1f\x81\xc6\x02#\x9e\xac\xc6\x19\xa1\x9f\x87 \xd7\x05\xb63$E\xc9\xe7\xe1\xd9&\xe1\xd3\t:;\x9dȴ\x97\xbe\x83\xd2(Z#\x1e\x88$\x0e\xc5zWʭh\xa7\x13\xc9ς\xa85Z=\xf2\xed2\xf6\x19H\x1e8&>\x9en\x18yO\x8b\u0099\xa1\x84\x84\f\xab\x1a.\xb4\x0f\xf0\xae\xc7\xf8\x0e4\xe8\xfa\xfcX\xb7\x01\xfc\xed\xf4#\x95\x9c\xe0\xbe-dg7\xb7\r\xa6\x92\xaf\xfdU\xe9G\x02\x89\xb2\x81\xbfD\xbb\xf15\xc2X*\x80\xfa\xe1:Tf\xb1j\xa8\xe4ح\xd0#\b\"\xcf6\x8e]**z\xbfi\x96\xb1\x0e\x82[\xf6\x9b\xdd\xc1\xc1Q\x03\xaeX\xc0\x93d\x85\b\xbd\xbchº\xce\v\x06Jϑ4\xeaܦ{\x90\xf2\xa9\xf4\x9a\xafY۾\xe2\x05\xaa>\xc5B\xe8\x1awO\xe9\x8ci\xf4\xbf+5\xa8(\")y\x18\xa8m\xaej|=\x17l\x97\xb3GL\x9c$L\xc8\x0e\x15\xfe\xf8\xea\x15\x13ro\x19=2\x1e\x85~\xe7\uf2a4\xbbݭ?\x8c\xbaA\xd4\x17\xb0+\x88\x9e\xcaTC\xb7\xd4;\xdc\x1dW5l\x1dU}N[\xa2j\xc5\xd0z\xc6\x1e\x8c4\xe4\n\xa3\xd4\xe5d\xbd\x12\xf6\xe4f,\xe3\x120v\xe9\t\xae]\xa1\xb5c\x03\xff渺\x0f\x9d\xddAug\f\x1dt\xc6<'\xde\xd9ܸ%\xe2\x1d\x90C\xea\x90g*\xe7\xc9\x04\x9e\xc3\xcf%\xe6\x04s\x01\x84\xc29\x92\xc9j\xa3\x99[\xb5\x15\x9dRz\xae\x00\u007fv\xea\xe9|m\xc0Fn\x83|\x94\xb0\xbc\xc8pEbdZ\xa3\t+\xa9\x1c%+D)\xce\xdeh\xc1\x0e.\xbc+v\xa0\v\xec\x8f\xd3\xdb\xd8|ד\x997w\xec\xcd)\x89\xbc\xe9g\xde\xf4\x02\xa7\xc2N<\xbf\x8d\x178գ\xa8tGQ\x99\xda\x16\xb0(^\x93;lg\xbe\xbb\xb5Z\x1e\xb4Z\xc1\v\x9c\xea%\aQ|\x87\xb2P\xb1\x88Z \xa8\xf4@\x14?[\xae\xb6O!\xb4\".\xa9\f+\r4\xa4(Kq]R+2\r\x88Q\x8b\u007ftQS\xca\\B\xc6\xe4\xef9[r,\xc49\xe2J\xc65M^\x13\xae\xbd*.\xec\xd48\xc7\x12\xf3`\xe4K8\xf2\xb8\x18\x92\x05\xe6ʓ\xf5i\x89\r\xf1\xbe(37\x996\xd0\xc7\xd3iW\x1f\xb9\x01\b=\xd6\x13\x8f3|[\xe3\xbb(o\x91\\ŋ\x8c1\x1e\xda\xc1\xc8{:\x1dn[\xec\xe6\xc8X\xedơݔ\x15\x97#\b\xfe\x02\xd7k\x9a\xe0\x14\xf4>\xf5m\xa8\xd2\x10[\x80\x9a\xf0\xd4`\xf7\xa6}\xae\xec4h\xe3\xff\xfe\xc6r\xad\xd98\xf8v\x83^\xe3\x84Ѵۢ\xfe\xae\xed7\xa9\xa5\xf1\xc7\x1a\xb6&\x1a\xfar\xec\xb6o\x8d\xb9ie3\xd5e\xeb>=\xecgl\x8b\xbdir\xdf>\xbd6wM\x0e\x17D\x14\x192\x11\x15^\x99\xf8\b6\xa6X\x90\x84Q\xc12\x1cgl\x19\x06\n\x04L\x00\xfd!\x18\xd5\xf1\xa8\xb73\xe2:\x01I\xeb\x9d;\x82\x1c=T\xed\xe60G\x0f\x9e\xe16\xb7\xdbD\x837\xfa\u007f\x1a\x924\x8a\xefI*Wap<\x9d\xfe\xc5d\x18\u05f8\x87\x11\xb1\xd0J\xa9Uwys-\x18\xf3\xcbD=@d8\u05cbH\x18\xa5\xb6\xb9U\x9d3\x9a\x93\x9d\xb0\x99\x89\x15\xdaͺ\xc0n\xbeO\x90\xc0\x10\x88\x02'\x04e\xff\x970\xba \xcb\xe0\a/\x8d[&\x1b\xdd\xef\x14Ӡ\x95\xf1+Ђ\xb3\"\f$\x91\x99\xae|\xaf\ryP\x02h\x89\x16dYr\xa4W\xb4 \x19\x8e\xdat\xe6\x1c\xa3\xcf'}B\xe6iF(\xfeӥ\xccsDS\xd0g\xa5{\xc9\xc7\xf1\xb2\xcc\x10w\xe4J\xf1\x02\x95\x99\xec\x16\xd4;p\xd8_ʠ>z~\x1c\xe8\x93\xf1\x02c\xae\xeaZ\xac\xb2\xf4\xc7 \xb8ե\xcd3[\xda\xf4W6\xcd\xe1\xa8v\x87\x8d\xa2F\xd1\xd5e\x99\x18\xa9\x8f\"\x18\x81W\xa7|@\xf7\xdbJ\x155]W\n\xef(\xae\x8b\x95z\xb0.Q\x9a3\x1b\xc5N\t\xf5\xca\xd6\x03:\xda(X[]4\xe1\xc2HVE^ˣ\xaa\xf4\xfc\xd8[\x9fVW\xd1OUʪtg\x8bF\xb8\x19\x04%M\xf1\x82P\x9c:\xcf|&\x17\xa9\xf5W\x85\xe5\x821\xfdW\xc5H=\xf1\xa5D\x19\x91\xeb\xba:\x9dں\xbc\x15\xe0\x0f\xa7\xb4`<G\xf2g3\xa8\xdb\fJ5\xf6\xfb˻e\xe4v\x06{\t\x97\x85O\xef|-\xb1\xa8\x15\xa6\xbf]c*\xb5>G\x95>\xe2\xb7X\b\xb44S\xfb\xf1I\xd9=\xdd\xc9\xe9\x03N0\xb9\xc3i\x0f\xb7j:rs\x9526\x9ag\x18\xceY\xbav\r\xdem햘\xe6A@?\x06`\xaf\xf7\x12\xb9\xcf<\xde)c\xf50\xb3\xf9Ĳ\xe1I\x1d{\xaf\xf6O@w\x8c\xa4\xb0*i\xcaUa\xcd\x16@\xf1=\xacd\x9eU{[ح\x98\xaaM\x8a\xe0KI\x92\xcf \nDG@$ܓ,\x839\x86\x8c\xe4D\xe24֤)\xbe\u05fb\xb6\xae;\x16\x8cC\xa8\x8fT\x15\x11]$9E\x05\xe60Ӄ\x1f5ȭ3a䎋R\xa8\xa4\x83\xb9\xf6.\xb7\xd9]u#\xe0H\xc3o\xed\x01%\x8c\xaa:L\x81\xbd\xaa\x13N\x13\xcfZi\xab\x83\xec\x82\xd04\f\x9e\x90\x02H\x10\xe9|\xe6\x84ú\u007fӃc+D\xdd\x17\xf1\x97\xe2=\xd9ne\xab\xd4^\xf9ob\x93\xe5\xcb4U%C\xb4'\r+FK\x82\xc9\x04\xfe\x892{-d\x17\x91\x94\b\x9b\xb0\xeb\xf6\x919\x1e\x1a\xb5\x166\xd8A\x8e\x954E\xc6\xd1\a\x9b\xcf\xe0\xbb\x14Zi\xc36\xb0t\xee\xd1\xdaU\x9ai\f|\xc5$n\xb5\xfd\xadg\xc3l\x1fm\xbb-\xd7{\x92\xac\f\xd6\n\x89\xb1\xd4\xda\xd4>[\x159\xd1\t\xf8k\x8e%c\x99\x01\xc4_B\x85\x1f\xc5jw\x85]B\x9e\xc0\xe0`=XK\xa8\x84\xd0\xf8\x97O\\\xe7\xca}\xbd\xacM\xaf\x93\xd4A\xee\xe2R\xac=\xb7Mr\xe0\x9fʸ\x9b\x14\xa7*z\xea\xdbH\x11\xfc\xa2x_as-O\x85@\xf5\x17\xd3t\xb3\xf3\xe7\xe4\x00\xdb\xeb\xd3\xf2l\x88\xd9\x11K\xbdNОv\xf0s\xa3g\t'#\xeek\x85Mj\x1b\x84:l@\x16\xe17]\"\xd6WR\xfcl\xdd&\x19E\xd1f\a\xb5Eʭ\\\xa3]\xc0u\xf5\xb8\x83o\x8f\xe2\xf7Լ\xc0u[&r\xd3-\xfc\xfa+\xec\x87U\x19\xaa.4\xf65\x93C\xa4\x85\u007f\xd0\x0e\x11Ε\n\xb7,\xf1h\xea|\xd3]\xf3\xec\xef\xa5\xdc\x16/\x87뫍\xe9鬮\x89\xf6\xd4[\x8bX\a\x9d\x83\xf4\xe7\x90\xeb\xd7aE\xdb\xd7c\xab\x9a;D\x979S\x11\xbf3\xfe\xb6j\x8c\x1b\x92\xe3\xd7Z&\xb9\xbf\x8e\xba\xc9o\xa7|\x90\xd66\x19ؾ\xc9\x16\x0e\x1bJ\xda8yqj\xbf\xfa\xe3\x11\x1c{:\xad'N\xe1\xd9\xd4\xc6\xf4\xcb\x05\xb0;\xcc\xe1\xd9TW\xa0\xfain\x04\x8cfk\xc8\x18J\xe1\xd94\x86\xffQ\xc5\xe6\x12K\xe08G\x84\x12\xba\x04\x8a\x1f$\x14H\x88\xb8}Hek\xa3ל\xe57\xac\xb8\xd17\x1e\xddD\xd2u\x9a\xb0\x993\xf6:f\xafu\xbb\xf5\x94]\x83\x93\xa2{^\xc3\x10HT\xb8\x9c\r\x17\b\x16H\x9f߀y\xee\x1e\x9e\x9dNH?\xa2\xaaT E\x12\x8dm\xb9Q\x11\xb2e\nHV\fA\x97H\xb3\xe1\xf0\xec\rC)\xa1\xcb8\x8eO'\nu\xeb\xe1\xbd9h\xaa\xbcd\xb8\x1b\xd6\xc9]{@\xb7\xbcp\x0f\f\x15-\x87\xa0+\xce\xd9p|<\xdd\x03\xa5\n\x10\xfb\xa3U'jM\xad;\xact:/\xa5d\x14$\xa1k@\x19\xe6rxvQC\xf5\x1e\xa3u\x9d\x86m\xbb\x00\xb2銨\xf8\xb7'\xfe\xdb\x13\xff$O\xdc,\xbb\x1e\xfd\xf6ƫ\f#Z\x16\xf0\x81\x95\x92P<\xf8\rM\fU\x9ezM\x8c\xee\a[\xf5H\x95de\x8aE\x18X\xff\b\xdc\xcaT\x91\xb1\xd7\xf1E\xd84\tF\xd0M\xbb\xcaˑ\x17\xf3w4S\xfa\xb2\x1bY\x84\xfb\xac@\xb7\xea\x1aߎ\x83C\x1a9\x9bAa\x0f\x96>\xb7ͅ\f\xea\x1e\xd1c\x04\xf6d\xf9e\x9aBF\x84\xc4T\xe5bɠq10\xae\xa5߷\xb0\xe1\x87\xd1p\x98\xb3R\xe0\xb2\x18\x8e\x1c\xbb\x83\xdb\x0fhN\x89m\x8a\xf5\xee\x92;p\xfe\x9a\xdc&B\xd4j\xe1\xee>j\xb6\xf7\x9b^\xeaw\x96\xb4\xeaSL\x89\xd7\"\xad\xaa \x05w\x99\xf6t2\xea^wJ\x04\x9ag\x8a\xc0\x01\xe8\xc6\f\x17\x96\xf3\xa0Ӕ\xbfS\x8cC\x04y)%\xce\vټ\x0f\xf5h\x0f\x9d\xa2\xc1\xe0\x9aqY\x15D\xe6m\xa0\xeeb\xc9\xccM&\xa0\x10\b]V\x1fa\xbe\x86\v{62\xa8\x82\xc08\xb5#m_\x01\xc7'\xf4\xfbfa\x10\vCpL\xf2e\xf7k\rd\x11\xbaR\x1aQ\xdc\xd7\xc1<\x96cE\xcf\x12\xdb\xf6\xf6G/\x92q@\xc1\x93`\x14\x90|9)\x8b\xb8\xa8\xde\x01k\xbf\xb4\xf1\xe7rN\xd9=mx\xab`\x8c8Gk۫ꊶK,u\xf8\xb8C\xd9\xcb\x1d\xa0\xbd\x95\xbf\xa1\xe10[\xaa\xa0\x802e\x83\xb0\x12\xfbR\xbc\xc1Bܬ\x10\x1d\x19\xb8Q\xcdS\xe3nr\rl\xb3\v\xd50=\x8e\xd6\xd8Z;\xa8\xe3g\x97\xef\x1b\x0f\xd3\xc1\xfck\xf9\x16)\x0e\xb2\xad\x0f~\xb0?\xfd\xa1ܾ\x86\x0f5\xf9g\xab\xef\x90\xe2w{M\xcb!\xae1\x95\x8dK\xd8\xd6\xc9\xd7r\n\xc5\xee C\xb5\x11\x0ev\x8c?\x9c\xe3\xd7p\x0ek\x95\xad\x9e\x91\x8b\xe5\xefv\x8d\xdf\x10O\xaa\x86O\xe3BN\xf7\xe8k\xb9Q\xc5\xf2 \xc3v!\x1d\xecN\u007f\x1a\xe7\xaf\xe1V\x8e\xa5\xfee\\\xab\xbe\xb0\xe0\n\x90Yޯ\xed\xa4+C\x89\x8d\x14\xd5u\x9e~w\xf1ʴ\xeamlϬ\x9b\x85\x9c\xa9\xf3\xf4\xca0\x85\x99\xc30\xae\xef)-\x18\xb7\x87\xb13\x98\x9e\x987z\xe1\xb4B\xb2\x03GG\x95\x182/\xfe\xa9_\boh\xb9\a\xb52/`f\xd6W\rWUy\xffҌ\x10\xaa\xa27\xdc\xc7p|\x02\x9f\xe0\f\xc6\xc7\xf0\u05ff\xc27m\x05\x86\x0e\xefO\xb71\xa1\x14\xf3\x1b\xfc GV\xbaf$:\x81O\xe3\xb1\xfb\xac\xe7\xa2\x1e\x1d\xdf\xfa\v\xf9t[\xc3!\x17\x04\xf9\xb3\x8f]\xf5\xfc\xd6%\xfc\x8b\xae\xc0\xd8f\x93\xa0\x11b\xb0AE\xe6\x85\xf5)s\xaf\xc0\xccz\u05fd\xbc\xdd\x16\xa2\x11\xcck߶\x17X\x90~_AH\xae\x9eF\xe0\xd7_\xc1\x8e\xcf\xdd\xf1j\xc1\x96\xcfԲ%\x8b\x10\xb5O)\xe6]\xb7*<<\x15\x18\xae\x8b\x8cH\xa5\x88X\xa8Oa\x10\xeaؤ\xdfzV\xc3z\xfe\xe3\xf1m5\rf\xda\xf8z\xc2\xe8\x1dV\xdekN\x114\xd2\xc7\xe9\xedȠ\u007f<\xbe\xd5Qd^\xf1\x98\xfb<\xe6\x96Ǽ\x9bǼ\x93Ǽ\xe61wy(\x05(\xf8S\x8d\xd6Z\xed\xb1o\x9c\xe9\xc0\xb3\x8c[|\xfda\x86\x19W<k\xbd\x9a\x86\xc3\xdc\xfd\xaa\xa6M\x00BMܙ\x9b\x91y3\xa2֦\x06O\xf5\\\xe7ڪxec\x15\x9cj\xc2'@\x8e\x8elg\x80,«2\x9fc\x1e\xce?\x92[\xd3{\xb9BWA\xfbr\x95=\x84h~\xc8\xc2b!\x1f\xab\x854u\xf7M\x1b\xe9\x14\\·1<\xf3q{ض\xefVn>\x8b9\x86E\xd78q\xfd\xca\xdc}\x15!\xd2\xf6陜G\x83\xf6\xbdU4ҤF\xc1\xaf\xc1h>Ҙ\xb6\xb61\x1cf*Ʃ}X\u007f\xeb\xf3\x91\n\xe5tf\xa8\xb4,\xac/!ٴ\xe5\xc6\xd6J\tj\xfeu\x95\xf8<=l,C\x92\x1c\xb7\xdd[\x8d\xed\xe3\xc9\xe6'\x804\x1d\x15\xf2H\x8e\x9d\xfdzbH\xda\xf9*\xf2\x9c³nj\x03\xb0\xa7Yr\x859\x06\"\x00\xc1\x14rB'+>IU\r@$\x88\x15+\xb3\x14\x84\xd4\aZ\x1c#\x89\xb9A\x94+D!c\xf7\x98C\x8a)\xcb\t\xd5\xe6\x8e\xe1e\x9a\x12\xba\x84cH\u061dn\u05ed0L\xf5\xa5N\xfd\x1b'Z\xb8\x8f\xd3ۣ#O\\\x15z\x9an\xaa\xc0I\xf3\xab,V\xec\x06\x15\xbe5[\xaev\xd7N\x1a9\xa1\xdbi\xbc\x98\xee&\xb2\xe2\xdbi<\u007f1݃J\x8a\xd6\xdb\xc9\xfc\xe7\x8b\xef\f\x9dn\xd71a\x97\xea]8\x02\xe3#\xb5\v\x99\xaf\x0e\xb7\x9f\xce7\x98\x19TsG\xba%o\x1b\xfb\xed\x0e\xec\x9d\x04\xfe\xb6\x1f\x81\xf6JM\x97|\x85\xd6B\xa2\xe4\xf3\b(\xc6iV\x97a\xca\xf1Ud\xad\xe6\xadw\x9bKm\xf7+\x92a\b\x89W\x8b\x90\x05\xd4\xd4>\x92[\x98\xcdf-\x9a\xe0\xc51U\xf4\xd5w\xe4\xdc#\x05;\xaf\xcb\xda\x13O\xea%\x96\x97\xef\xf5\xe5Z\xbe\x0e\x91\xbd\xdd\xf6\xcb\x00&\xdf\xc2SU\xf7\xff\xe3\xfa\xddU8\\IY\xfc0\x99\x90\x82\xd0\x05\x8b\t\x9b\f\xe1\b,4\x1c\xc1\xd0}~K\x91D6\xc0\xbaaN\rǉa\xe4\xfef\x14\x04\x97\xd7\xef\xf5K\x02\x1a\x82\xf1\xa5~\xf5\x03\xdeq\xb2$\xb4\x99\xb0\xa8\xe6\xbd\x10\xdd]\xfdvR\xfd\x82\x91P\x9bU\xde3\xc8ؒ\bI\x92Z\x1aѬԿ\x16\xf3Ž\"D\x16\xd5w8\x9b\xb9\xef\xbfU\"rD?\x8f\x97\xfaw\x81<\xc7q\xb0\xc6\xdf\xf7`\xb1,\rzB\xae\x81\xe0\xd8\x00xvqoU\xccտ#\xc8\xed-\x8a\xe6\xa7p\xf4\x84\xca\t\xf5Ee\x95(*8o\xa2-\xdb\x14\xc2)\xfc47\xaaT\t\vfu\x8a\xd4T'p\x8c\x8f\x9eG\xb1d\xaf\xc9\x03N\xc3\xe3\xa8b\n\xa7\xae\x8a\x14\xe2\\\xbf\xd1\xf1ӹ\xa7\x1c\b]J/\xa2M\xb4M~/Z\xfc\\\xf2o\xcf7\xd4\xd8G濶\x90\xf9\xdby\xb5\xe4\x1cf\xb5\xae\xec\xdar\xf3\xfac-eސ\xaf\xb5:1\x10m\x0e\x9a\x9a\xd1C\xe0\u05c9zT;\xf2\xdcz\xef\xe3\xe0\xff\x03\x00\x00\xff\xff)8\x16\xb5RO\x00\x00",
-		hash:  "46d8be37725d5545969ad568d4407717ae4378eb9b4ad596c62888e831cfbfa7",
+		data:  "\x1f\x8b\b\x00\x00\x00\x00\x00\x02\xff\xec|\xebs۶\xb2\xf8w\xfe\x15[&爬%JN\xda\xfc~\xb7\xb6<\x13\xc7ͩo\xf3j\xec{\xee\x87\\\xcf\x1d\x88\x84$$\x14\xc0\x00\xa0mM\xea\xff\xfd\x0e\x1e$\x01\x8aԣm2\xe7\xc3\xe9Lc\t\xfb\xc4\xeeb\xb1\\\x80\xbaE\x1cҒsL\xe5/\x98,\x96\x12\xa60\t\xd4h\x8eQ\x86\xb93\x18\b,/\xa9\xc4\xfc\x16\xe5QYdH\xe2_\xae_\xbf\x1a>\x9dL&\xf1\x89\xa6\x11\x98\xdfb\xfe\x96\xe6\x84b\x98\xc2\x1c\xe5\x02\a\xe31\xfc\x97\xc0\x19H\x06\x86\n\x04[a\x90KB\x17\x02r,\x04\xcc9\xfe\\b*\xf3\xb5a\xf3\x89\x14\x95\xa4\x9aM\xf08\xba#4cwq\x923\x94E\x01\x00\xc0\xbc\xa4\xa9$\x8cF1|\xd1\x03\x00\x8dfQl\x87\x04\x96\xd7d\x85Y)\xa3\x8a\x00\x1c\x8a^\xba\x87!\x1c\x9b\xc9\xe9oA|\x12\x045\x03\x17_\xb3z\x9c\xa0\x8f\xe8>\x1a$\xe3\xc1\xd0\xf2\x16e\x9ab!~r\xf4\xfcR\xeb\xe4\x99J\xf2\x12\x1b)C\xfd\as\xce\xf8\x1et\xc66F=\x80\a\xa5!\x00\x99C\xf4\x9d\x8bX\xcd\xf5q\x14>2\xe3#!\x91,E\x18'\x12\xdf\xcb(|\x89R\xc9V\x19\xbca\x12ޗ\x94\x12\xba\b\x8d\x198\x96%\xa7\x8a9\xe0\\\xe0\xbd9\xb9\\\x1e*\xad\x14\x19\xa1\x19\xbe\xa7\xe8v\xb4B\x84\x86q\xb2D\xe2E\x8e\x84\x88B\"F(\x95\xe4\x16\x87q\xa5\xf1x\f\xaf\x11\xa1p\x8df\x81\xe3%\x1d\x95Q\f\xce\xd8\xf3<\x7f\x871\x17\xd6{\xe31\\0,\x00\xdfb\xbe\x06D\x99\\b\x0e\xe9:͍\xb9\xc8<\xfa\u038d\xb3؏\x9fk\x8e\xa8@\xda\xf6\xa2\x89#?.\x1b\x9f\xb9\x96\x81\xee\xf0\xad]dpɼe\v\xc6\xf1\x1e\xb6\xb8\xc0\x12\x91\x1cg\xbe=Ѕ\xfa\xbf\\\x15FՇ x\b\xf4\xb2\xd3S\x81\xbb%\xa6p\x87A\xdc\x11\x99.A\xa2\x99\b\x1eGa\xa2>\x8cRF%g\xf9\xa8@\x14\xe7\x90\x13@a\x9c\xa49I?E~\xf09\x8b(\xf0\x16^\x00\xf0\xa5ѥYA\x0fCx:\x99\xc4\xc1C\xac\xd6n\xf8(+W\x85\x16\x87\b\xc5\x1c\x1e\xcd\xcb<\x17)ǘ\x8eX\xa1xՂ[a/\xef\xe5s\x8e\x11L\xe1\xe3o%\xe6\xebH.\x89\x88\x13Af\xb9J!Q\x988\xc6j\xf0\x13\xc9\x16\x8b\x1c[{6\xd24\x8e\xc7\xc9CD3\xc1\xf2R\xe2Q\x87~[\t\xe7\xe4\x1eg\x9dT\xca\x02\xca\x1f\u05ec\xd0\xd6\aFA{>\xd8X\x0fp\xd6\xe9\x00\xf8b\x17\x90'~K\xb4l,V\xe9\x04t\x18'\x1c\xaf\xd8m\xa5\xf9\x92d8\x8ckԜ\xa5(߁\x93و\v\xe3\x04eY\x1b\xe7\xa1v\xba\x17\xe0\xdfjr\x1d\x1a\xf93\xebEp\xa6\xd5={33\x7f\x13p\x97\x9fV\x8acQ\xc0\x14>\xab\xd9\\I$q\x14\u058c\x87\x10\x86\xc3z\xee\n\xd3f\x1e6\xfb\bS\xf8ϫ\xb7o\x92\x02q\x81\r\xacѬ\\\x15Ǡ\xff\\-\x19\x97U\xbee\xb3\x8fI%\xff8\xd1 \xf5\xb1\x93\xf0=\xba\xeb&{\x8f\xeez\x89\xae\xd64\xd5y\xbcS\x9e\x01\x1ab\x8f\xfaI-\xf2\x1dgi\x17\xf5\x93n\xb1\x96\xf0\r\xbe\x97\xddT\n\xd2K\xf6\x8e\xe3\xdbn2\x05\xb1zz\x84O\xb7\xda\xe6i\xa3\xa4G\xf5\x83\xa1z^\xcae\x17\xd9\x0f\x89\x820N$\xc1\"\ue8bc\xcc0\x95ݤ\x1a\xd4O\xf9z\xfd\x86e\xb8\x9b\xd4\xc0Z\xba\xfeh\xe8^0\xda3\xc9\x1f\xbb=a\xe9\xaez\xa2\xed\xc7DAp\xd6a\x9dg\x86\xf2\xe7\x1c\xd7k\xb2&\xaf\x06+6\xf5\x80\x88\xbbx\\\x91U\x99#\x89\xb3\xfd\x98m\xa0\xc7\xdb\x14\xfb\x85\b\xc9\xf8z+G\x8b\x13WU\x83Bz\xc5\x16WXJ\xb5\xfbh\x9c\x17\xa6\x82v\x86a:\x850tk\xcc}\xe9 \x84\xb0\xae\x16\x1a\xcd\xff\x1f<\xb2u\xfa(g\x8b\xd1-\xcaK/\x06v\xb3\xd6\xe9+V\xa5A\xab\x86\xf5k\x9d\xbe\f\xc6q\x8a\xa9tq\xc3\xe1\xe1\xa9l<\xb6\x95\xcf\xc5\xf9y\xce\xd2O\xa6\x92\xab&\x12\xc3wSm\xa8\v\xc2q\xaa̮\x91\x92\x8bs\x83\xd7\xd8Ӱ\xf8\x15\xaf_\xbf\xb7\xbbJ\x13\x9f>\xadƉ=\xb2s\x96\xad\xf5\xf0\x16\xb2\x1a\xc7'}Y\xe6\xf9/H,\xb7PV(-\x99\n\xa6\n(!Ѫ\xd8B^\xe3t\xd0\xfb\xd6\xdaf(\x87\xd68n\x94U\x98\xa3\x99B\u074b\x89\xe5b\xa3^W\xf6$sC@\xf9\x8b\x96yS=\x03\xf4`&s\xc6\x7fF\xe9\xb2\xd9\xf7\xf5\xa6\xed?\x85\x91\xb9\x19M\xae\x99D\xf9%-J\tg0I&\x93ɱ\x8f\tU\x05] j\xa5\t8\x03U\tܿ\"B\x91\xc9\x19\xcb\xd6\xf0(\x84#\xb0L\xef//\xe2$\xc7t!\x97\x8am\x9bc\xab\x8c\xaf\xfe\xdbCJ\x18'\x05\xc7\x05\xa6Y\x14\xfeO\x8b\xfcTr \xd9t\xe0\xeb\x01G\x10\x0e\xceڸ\x06?;;E\x9ad\xae\x1f\xa5F\x02#\x9e.G9\xa1\x9f\x06 \xd7\x05\xb6\x10\x92\xa1\xf4\xd3\xe0l\x93\xf1\xe9\x18\x9d\x9d\x8ee\xd6\xcb\xdf!i\f\xad\t\x0f$\x12\x87R\xbd-\xe5V\xb2ӱ\xe4ga\xdc\x1a\xad\x1e\x1dw9\xfb\f$\x0f\x1d\x17\x1fO6\x9c\xbc\xa7G\xe1\xccpBBFU-\x18\xd9F@\xf3\xdf\x03\xf8\x01\x14t}~\xa8\xdb\t\xfer\xfa\x99JNp\xdf\x12\xb2\xd0\xcde\x83\xa9\xe4k\x7fV\xfa\xd1B\xa2<\xf0\xa7h\x17\xbe&\x18I\x85P?\xa4G\xca-\xd6\f\x95\x1e\xbb\rz\x04a\xec\xf9\xc6\xf1K\xc5E\xaf7-2\xd1Ip\xcbz\xb3+8<jЕ\bx\x94.\x11\xa1\x97\x17\x80\xbc}\xc1`i\x18\xc9\xe2\xcee\xba\a+\x9fK\xaf\xfb\x9a\xb9\xed\xab^\xa8\xea\\,\x84\xae\x95\xf7\xd4θF\xff\xbbT\x83\x8a#\x92\x92G\xa1Z\xe6\xeaYA\xc3\xc2\xedz\xf6\xa8\x89Ӕ\t\xd9a\u009f_\xbc`B\ueb63\xc7\xc6\xe3\xd0\x1f\xfc]\x99tw\xb8\xf5\xa7Q7\x89\xfa\nv%\xd1S\x99i\xec\x96y\a\xbb\xf3\xaaƭ\xb3\xaa/iKV\xad\x04\xda\xc8\xd8C\x90\xc6\\b\x94\xb9\x92lT\u009eҌg\\\x06\xc6/=ɵ+\xb5v,\xe0?\x9cW\xf7\xe1\xb3;\xa9\xee̡Ag\xces\xf2\x9d\xdd\x1b\xb7d\xbc\x03\xf6\x90:\xe5\x99\xcay<\x86\xa7\xa0\xba\x14\x04s\x01\x84\xc29\x92\xe9r\xa3)\\\xb5'\x9dRz\xa6\x10\x7fs\xea\xe9\xd5ڠ\r\xddF\xfb0e\xab\"\xc7\x15\x8b\xa1i\xb1\xa6\xac\xa4r\x98.\x11\xa58\x7f\xa5\x15;\xb8\xf0\xaeā.\xb0?Ln\x12\xf3]\x03s\x0fv\xec\xc1\x94F\x1e\xf8\x89\a\x9e\xe3LX\xc0ӛd\x8e3=\x8aJw\x14\x95\x99m%\x8b\xe2%\xb9\xc5\x16\xf2Í\xb5r\xd0j)\xcfq\xa6\xa7\x1cƉ:kP\"\xe2\x16\n*=\x14%ϖ\xab\xed\xd3\fm\x88K*\xa3\xca\x02\r+\xca2\\\x97ԊM\x83b\xcc\xe2\x1f\x81Ԝr\x97\x91q\xf9;\xce\x16\x1c\vq\x8e\xb8\xd2qMӗ\x84\xeb\xa8J\n\v\x1a\xad\xb0\xc4<\x1c\xfa\x1a\x0e=)\x86e\x81\xb9\x8ad}\xeabS\xbc\xaf\xca\xd4\xddL\x1b\xec\xe3ɤ\xab\x1f\xdd D\x9e\xe8\xb1'\x19\xbe\xaf\xe9]\x92\xd7H.\x93y\xce\x18\x8f\xec`\xec=\x9d\x0e\xb6Mvsd\xa4V\xe3\xc0.\xcaJ\xca\x11\x84\x7f\x03\xd5J\xc2\x19\xe8u\xea\xfb\xf0\bB`sP\x00\xcf\fvm\xda\xe7\xcaN\x876\xf1\xef/,כM\x80ow\xe8\x15N\x19ͺ=\xea\xaf\xda~\x97Z\x1e\x7f\xadck\xa6\x91\xaf\xc7n\xff֔\x9b^6\xa0._\xf7\xd9a?g[\xeaM\x97\xfb\xfe\xe9\xf5\xb9\xebr\xb8 \xa2ȑɨ\xf0\xc2\xe4G\xb09Ţ\xa4\x8c\n\x96\xe3$g\x8b(T(`\x12\xe8O\xe1\xb0\xceG\xbd\x9d\x117\bHV\xaf\xdc!\xac\xd0}ն\x8eV\xe8\xdes\xdc\xe6r\x1bk\xf4\xc6\xfe\x8f#\x92\xc5\xc9\x1d\xc9\xe42\n\x8f'\x93\xbf\x99\x1d\xc6u\xeeaL,\xb62jեޜ\v\xc6\xfc2U\x0f\x109^\xe9I\xa4\x8cR\xdb$\xb3R\xed\tQ\xd4@\x12Ev\xbd.\xb0\xbbߧH`\bE\x81S\x82\xf2\xffM\x19\x9d\x93E\xf8\x93\xb7\x8d[!\x1b]\xf4L\x9d\xbf\x9ct\xa2\x16\x9c\x15Q(\x89\xccu\xe5{e\u0603R@k4'\x8b\x92#=\xa39\xc9q\xdc\xe63\xe3\x18}:\xe9Sr\x95\xa9\x83Я\xae\xe5j\x85h\x06J\xd4~\xfaq\xbc(s\xc4\x1d\xbd2<Ge.\xbb\x15\xf5\x0e.\xf6\xd72\xac\x8f\xb0\x1f\x02}\xc2^`\xccU]\x8b\x05L\xe1C\x18\xde\xe8\xd2\xe6\x89-m\xfa+\x9b\xe6\x90U\x87\xc3FQ\xa3\xf8\xea\xb2L\f\xd5G\x11\x0e\xc1\xabSޣ\xbbm\xa5\x8a\x02ו\xc2[\x8a\xebb\xa5\x1e\xacK\x94\xe6\xecG\x89SJ\xbd\xb0\xf5\x80\xce6\n\xd7V\x17M\xba0\x9aU\x99\xd7ʰX\xad\xdc[\x9fzW\xd9OUʪtg\xf3F\xb9)\x84%\xcd\xf0\x9cP\x9c9\xcf|f/R\xf3\xaf\n\xcb9c\xfa/W\x15\xa7\x02|.QNd\xddx\x0e'\xb6.o%\xf8\xc39\xcd\x19_!\xf9\x9b\x19\xd4m\x06e\x1a\xfb\xfd\xf9\xed\"v;\x83\xbd\x8c\xcb\xc2\xe7w\xbe\x96X\xd4\x06\xd3߮T3X\xd9sX\xd9#y\x8d\x85@\v\x03\xdaON\xc6\xee\xe8NI\xefq\x8a\xc9-\xcez\xa4U\xe0\xd8ݫ\x94\xb3\xd1,Ǡڸ\xaeû\xbd\xddR\xd3<\b\xe8\xc7\x00\xec\xf5^\x9c\x1eM\xeb$\xb8z\x98\xd9|bو\xa4\x8e\xb5W\xc7'\xa0[F2X\x964\xe38\x13\xc0\xe6@\xf1\x1d,\xe5*\xafֶ\xb0K1\x03B\x01\xc1璤\x9f@\x14\x88\x0e\x81H\xb8#y\x0e3\f9Y\x11\x89\xb3D\xb3\xa6\xf8N\xafں\xee\x983\x0e\x91>\x9aULt\x91\xe4\x14\x15\x98\xc3T\x0f~\xd0(7\x0e\xc0\xe8\x9d\x14\xa5P\x9b\x0e\xe6\xc9;;\x18\a~7\x02\x8e4\xfe\xd6\x1eP\xca(L\rڋz\xc3i\xf2Yk\xdb\xea`;'\xaaS\xf0\x88\x14@\xc2X\xefgN:\xac\xfb7=4\xb6B\xd4}\x11\x7f*\xf0\xa5\xd5H\xeb\x15\xab\xcc^\xc5oj7\xcb\xe7Y\xa6J\x86xO\x1eV\x8d\x96\x06\xe31\xfcS\x9d\xf0\xec\xc5$#\xc2n\xd8u\xfb\xc8\x1c\x0f\r[\x13\vv\xb0c%͐\t\xf4`\xf3\x19|\x97A+k\x18\r\xccޣ\xad\xab,\xd38\xf8\r\x93\xb8\xd5\xf6\xb7\x91\r\xd3}\xac\xed\xb6\\\xefH\xba4TK$FR[S\xc7lU\xe4\xc4'\xe0\xcf9\x91\x8c\xe5\x06\x11\x7f\x8e\x14}\x9c\xa8\xd5\x15u)y\x02\xc1\xc1v\xb0\x9e\xc0\x99ul\x87\x05\xf4^\xb9o\x94\xb5\xf9u\xb2:(\\\\\x8eu\xe4\xb6Y\x06\xfe\xa9\x8c\xbbHq\x06S{\xab)\x86/J\xf6\x1bl\xae\xf7\xa9\x14\xa8\xfeb\x9amv\xfe\x9c=\xc0\xf6\xfa\xb4>\x1bjv\xe4R\xaf\x13\xb4\xa7\x1f\xfc\xbd\xd1\xf3\x84\xb3#\xee\xeb\x85Mn\x1b\x8c:|\xa0\xee\x98u\xa9X_m\xf1w\xeb6\xcb8\x8e7;\xa8-Vn\xe5\x1a\xefB\xae\xab\xc7\x1dr{\f\xbf\xa7\xe5\x05\xae\xdb2\xb1\xbb\xdd\xc2\xef\xbf\xc3~T\x95\xa3\xeaBc_79LZ\xf4\a\xad\x10\xe1\\\xcdp\xcb\x12\x8f\xa7\xdeo\xbak\x9e\xfd\xa3\x94\xdb\xe2\xe5p{\xb5)=\x9b\xd55ўvk1\xeb\xe0s\x90\xfd\x1cv\xfd6\xacx\xfbvlUs\x87\xd8r\xc5T\xc6\xef̿\xad\x1aC\x1d\xb1\xbf\xd4:\xc9\xfdm\xd4\xcd~;烬\xb6)\xc0\xf6M\xb6H\xd80\xd2\xc6ɋS\xfb\xd5\x1f\x8f\xe0سi\r8\x85'\x13\x9b\xd3/\xe7\xc0n1\x87'\x13E\xa7\xd5\x15C`4_\x83\xba~\rO&\t\xfc\xb7*6\x17X\x02\xc7\xea\xee\"\xa1\v\xa0\xf8^B\x81\x84HڇT\xb66z\xc9\xd9\xea\x9a\x15\xd7\xfa椻\x91t\x9d&l\xee\x19{\x1d\xb3\u05f6\xddzʮ\xd1I\xd1\r\xd78\x04R\x95.\xd59\x0ȇ>\xbf\x01\xf3\xdc=8;\x1d\x93~BU\xa9\x80\xba\xf27\xb2\xe5F\xc5Ȗ) Y1\x00]\"M\a\x83\xb3W\fe\x84.\x92$9\x1d+ҭ\x87\xf7栩\x8a\x92\xc1n\\g\xef\xda\x03\xbb\x15\x85{P\xa8l9\x00]qN\a\xa3\xe3\xc9\x1e$U\x82؟\xac:Qkj\xddAe\xd3Y)%\xa3 \t]\x03\xca1\x97\x83\xb3\x8b\x1a\xab\xf7\x18\xad\xeb4l\xdb\x05\x90\xcdPDſ#\xf1ߑ\xf8\x95\"q\xb3\xecz\xf0\xdb\x1b/r\x8chY\xc0{VJBq\xf0\a\x9a\x18\xaa<\xf5\x9a\x18\xdd\x0f\xb6\xea\x91*\xcd\xcb\f\x8b(\xb4\xf1\x11\xba\x95\xa9bc\xaf\xf5\x8b\xa8i\x12\f\xa1\x9bw\xb5/\xc7^\xce\xdf\xd1L\xe9\xdb\xdd\xc8<\xdag\x06\xbaU\xd7\xc4v\x12\x1e\xd2\xc8i\xcb\x04\xd8C\xa4/ms\"A\xdd#z\x88\xc1\x9e,?\xcfT\xe7XHL1\x17 \x194!\x06&\xb4\xf4{\x1b6\xfd0\x1a\rV\xac\x14\xb8,\x06C\xc7\xef\xe0\xf6\x03\x9aSb\xbb\xc5zw\xd2\x1d<\x7fNn\x13!n\xb5pw\x1f5\xdb\xfbM\xcf\xf5\xbbO\xda\xf4\x19\xa6\xc4k\x91VU\x90»\xccz:\x19u\xaf;#B5\xf3\xb20>\x80ܸ\xe1\xc2J\x0e:]\xf9'\xd58D\x91\xe7R\xe2U!\x9b\xf7\xaa\x1e\xec\xa1S\x1c\x04\xea\x1euU\x10\x99\xb7\x8a\xba\x8b%\x03\x1b\x8fA\x11\x10\xba\xa8>\xc2l\r\x17\xf6l$\xa8\x92\xc0(\xb3#\xedX\x01'&\xf4{kQ\x98\b\xc3pDV\x8b\xee\xd7#\xc8<r\xb54\xaa\xb8\xaf\x95y\"G\x8a\x9fe\xb6\xed-\x92^\"\x13\x80\x82\xa7\xe10$\xabŸ,\x92\xa2z\x97\xac\xfd\xf2\xc7ו\xacZԍ\xec \x00@\x9c\xa35Lk6\xedl\xbb\xc0R\xa7\x8f[\x94?߁\xda[\xf9\x1b\x1e\x8e\xb0\x85J\n(W>\x88*\xb5/\xc5+,\xc4\xf5R\xb5~5ް\x96\xa9i7\xa5\x86\xb6مj\x9c\x9e@k|\xad\x03ԉ\xb3\xcbwM\x84\x91\xe2\x1b\xc6\x16)\x0e\xf2-)\xb6yug<\xfd\xa5ҾE\f5\xfb\xcf\xd6\xd8!ş\x8e\x9aV@\xa8\xceG\x13\x12\xb6u\xf2\xad\x82B\x89;\xc8Qm\x82\x83\x03\xe3/\x97\xf8-\x82\xc3zekd\xac\xc4\xe2O\x87\xc6\x1f\xc8'Uç\t!\xa7{\xf4\xad¨\x12y\x90c\xbb\x88\x0e\x0e\xa7\xaf&\xf9[\x84\x95\xe3\xa9\x7f\x99Ъ\x82\xc4S \xb7\xb2_Z\xa0\xabC\x89\x8d\x16\xd5u\x9e\xfepq!\xf5[ݞ[7\v9S\xe7\xe9\x99a\nSG`R\xdfS\x9a3n\x0fc\xa7091o\x06\xc3iEd\a\x8e\x8e*5\xe4\xaa\xf8'\xca=^\xeeA\xad\\\x150\x05\xe4\x0eWUy\xffԌ\x12\xaa\xa27\xd2Gp|\x02\x1f\xe1\fF\xc7\xf0\xf7\xbf\xc3wm\x03F\x8e\xec\x8f7\t\xa1\x14\xf3k|/\x87V\xbbf$>\x81\x8f\xa3Q#\a\\\xb5?\x1e\x1d\xdf\xf8\x13\xf9xS\xe3!\x17\x05\xf9Ї\xaez~\xeb\x14\xfeEg`|\xb3\xc9\xd0(\x11lp\x91\xab\xc2Ɣ\xb9W`\xa0\xdeu/o\xb5Eh\b\xb3:\xb6\xed\x05\x16\xa4\xdfW\x10\x92\xab\xa7\x11u\xcc`\xc7g\xeex5a+gbŒy\x84ڧ\x14\xb3\xae[\x15\x1e]\x00\x80\xae\x8a\x9cHe\x88D\xa8O\xeaJv\xac\xc6՛\x960\xb5pu\xf9\u0602\xc1\x80M\xac\xa7\x8c\xdeb\x15\xbd\xe6\x14A\x13}\x98\xdc\f\r\xf9\x87\xe3\x1b\x9dEf\x95\x8c\x99/cfe̺e\xcc:e\xccj\x193W\x862\x80\xc2?\xd5d\xad\xd9\x1e\xfbΙ\x04\x9egH\xf1\x15\x1c3\xaad\xd6v5\r\x87\x99\xfbU\x81M\x02BMޙ\x99\x91Y3\xa2\xe6\xa6\x06O5\xacsnU\xbe\xb2\xb9\nN5\xe3\x13 GG\xb63@\xe6ћr5\xc3<\x9a} 7\xa6\xf7\xf2\x06\xbd\tۗ\xab\xe0\xd8]\xc4\r\x15\xf2\xa9ZD\x13wݴ\x89N\xc1\x95|\x98\xc03\x9f\xb6Gl\xfbn\xe5泘\xe3Xt\x85S7\xae\xcc\xddW\x11!\xed\x9f\x1e\xe0,\x0e\xda\xf7V\xd1P\xb3\x1a\x86\xbf\x87\xc3\xd9PS\xda\xda\xc6H\x98\xaa\x1c\xa7\xd6a\xfd\xad/F*\x92ө\xe1\xd2\U000b0f84d\xb7-7\xb7VFP\xf0\x97\xd5\xc6\xe7\xd9ac\x1a\x92\xacp;\xbc\xd5\xd8>\x91l~JH\U000c1a66r\xd6\xeb\x89ai\xe1U\xe69\x85'\xdd\xdc\x02\xb0\xa7Yr\x899\x06\"\x00\xc1\x04V\x84\x8e\x97|\x9c\xa9\x1a\x80H\x10KV\xe6\x19\b\xa9\x0f\xb48F\x12sC(\x97\x88B\xce\xee0\x87\fS\xb6\"T\xbb;Q\xcd:u\xdeu\f\xa9:&\x13\x8a=L\xf4\xa5\xce\x00*\xe5?Ln\x8e\x8e<uU\xeai\xba\xa9\x02\xa7a\xdcR\xbb!Uw}\xbd\x1f\x8c\xe9\xe4\xb1\"t;\x8fg\x93\xddL\x96|;\x8f\xa7\xcf&{p\xc9\xd0z;\x9b\xff\xff\xec\x87ɤ?tLڥz\x15\x0e\xc1\xc4H\x1dB\xe6\xab#\xed\xd7\xf3\ra\x86\xd4ܑn\xe9ۦ~\xbd\x83z'\x83\x7f\xecǠ=S\xd3%_\xa2\xb5\x90(\xfd4\x04\x8aq\x96\xd7e\x98\n|\x02S\xa8\xe06\xbaO4\xf0nIr\f\x11\xf1j\x11u|[a\x7f 70\x9dN[<\xc1\xcbc\xaa\xe8;\t`\xf3H\xc1\xc2uY{\xe2i\xbd\xc0\xf2\xf2\x9d\xbe\\\xcb\xd7\x11\xb2\xb7۾\x040\xfe\x1e\x1e\xab\xba_\xf5\x80\xa3\xc1R\xca\xe2\xa7\xf1\x98\x14\x84\xceYB\xd8x\x00G`\xb1\xe1\b\x06\xee\xf3\x9b:\x8f\xb2\t\xd6Msj8I\x8d \xf7\xb7\xa7 \xbc\xbcz\xa7_\x12\xd0\x18\x8c/\xf4\xab\x1f\xf0\x96\x93\x05\xa1\r\xc0\x92j`\xa8\xbb\xabߏ\xab_BR\xafe\x82\xbcc\x90\xb3\x05\x11\x92\xa4\xb56\xa2\x99\xa9\x7f-\xe6\xb3{E\x88̫\xefp6u\xdf\x7f\xabT\xe4\x88~\x1a-\xf4\xef\vy\x81\xe3P\x8d~\xec\xa1by\x16\xf6\xa4\\\x83\xc1\xb1A\xf0\xfc\xe2ު\x98\xa9\x7f\x87\xb0\xb2\xb7(*\x9d\xc1\x00ԞP_TV\x1bE\x85\xe7\x01ںM \x9a\xc0\xaf3c\xca\x00`\x06\xd3z\x8b\xd4\\\xc7p\x8c\x8f\x9eƉd/\xd5O\x1fE\xc7q%\x14N]\x13)\u0099\xf2\n\xfcz\xee\x19\a\"\x97ӳx\x93lS\u07b3\x96<\x97\xfd\xeb\xf3\r3\xf6\xb1\xf9\x8f-l\xfeq^My\x05\xd3\xdaVvn+\xf3\xfac\xad\xe5\xaaa_a\xc2\xd8`\xb4%hn\xc6\x0e\xa1_'\xeaQ\x1d\xc83\x1b\xbd\x0f\xc1\xff\r\x00`\xc6D\x03\x9aO\x00\x00",
+		hash:  "0ffc1e6f1fb19673aaf0c5a160cc1e20423ac163e37bf6b6fc4ecf0544358829",
 		mime:  "application/javascript",
-		mtime: time.Unix(1542240436, 0),
-		size:  20306,
+		mtime: time.Unix(1792367711, 0),
+		size:  20378,
 	},
 	"js/factomd-ajax.js": {
 		data:  "\x1f\x8b\b\x00\x00\x00\x00\x00\x02\xff\xdcW]o\xdb6\x14}\u05ef\xb8e\x82\x85Be9[\x06\fh\xaa\x06\xe8ڭ\x18\xb2tk:`\xaf\xb4tm1\x96I\x85\xa4b\x1b\xab\xff\xfb@\x8a\xb2%[\xfe\xc8\n\xec\xa1\x0f\x05R\xf1\xf0~\x9e{\x0f=\xaeDj\xb8\x14\xf0X\xa1Z\xde\x1bf\x90r\x83\xb3\b\x9eXQa\x04\x16\x10\xc2?\x01\xc0\x13S\xa0\xf0\x11\x12\x108\x87\xbf\u007f\xbf\xfd`L\xf9\t\x1f+Ԇ\x86A\x00\xf64\x96B!˖\xdaZJs&&\b\t4^hm\t\x80\x8f\xa9\x05;\xa8s\nI\x02?6\xa7\x00\xc3a*\x85\x96\x05ƅ\x9c\xb8\x80\xe0%\x10\x18\x00\x81\x97P\xdfԥ\x14\x1aC\u007f\xc1z\xa0\xbb\a\xab\xa0\xfe\xe7\"+QP\xf2\xeb\xfb\xcf$\x02\x12\x0f\xc7,5r\x96\xddX\xe3\x895\xdbx\xf9\xcee\xee>\xf9\x1a\x18U9{֊F\x91\xd10X\x05\xc1\xbat#f\xd2\xfc\xcf\xed\xfa}\xeb\x85{k\xb3\xbeq\xb9\xaf˷\xafT甜\xd5\xd7\x06\x1a\x99Js\x12\xc6i\xc1\xd3)\xddJ\U0001c4b8\x03\x1c\xa0RR\x910\xd6\x05\xcf\xf0\xaf\x92\xc2\xd5\xe5%\x84\xc1*\xec\xb1:\xd0\xd5h\xc6\xcd>\xe35\xe8-S\xf7\x0eF\x9d\x95]\x8f\xa9\x14\x86q\x81\xd6\xeb\x14\x97\xa5B\xad7\xa6p\xd3\xd3).!\x01\x8c\xe79Os\xf8\xf2\x05\xd0\xe2\u007f\x96\x19^\a\xb6S@_P\x87I\xe0\xfb\xab\xb0\xe9\x91BS)\xe1\xcb\xdb\x1b҆Y;\xc7kߋ}l\x02X\x9cN\xa5\xc5~\"\xb5i\xb4\xd8a\x8d\x1c=@\x02\xbf\xdd\u007f\xbc\x8bK\xa64\xf6@l\xfer\xf4\x10\u007f^\x96\xce6\xc9F\x85L\xa7\x1f\x90OrC6\x8e\x00\xe6\\dr\x1e\x172e.\xeb\x04H\x9d\xf8\r\x17ee\x1c\xbb\xac\xa5\xf5\x80\x9ae\x89Im\x8ex++\xc0Bc\xd7\xe9\x8b\x04ȝ\x14\xf8lg}t}b\x05\r7ޛ\x98\xac\xa3\xc6\xf6p\xa80\xe3\nSC\xbf\xdaf\x04\xa4\x94ڐ\bZ\x95\x85\xe1\x10\xee\xe5\fM\xce\xc5\x04Ʋ\x12Y7\xfdM\x9aG\x06靜\vzuy\x19\xae/\x1c\xee\xf7\xaa\xb3\x14,\x01\xc7R\xcd\xde1\xc3<\x0f\u007f\xf1\xff\xa5\xa1\xe5~s\x18\xb3\xb2\xb4K\x80ؘef\xf7G\x93|\x1fʟE\xfb\x8b\xe5\xb6\xe5\xc2o\xa4?>\xde\xfb\x95\xe4JUs\xdf-\x9dƲ[>\xed=Q\xc8ɑ%\x01PO̭\x9cL\xb8\x98lO\xe4֡\xbfrd\"O\x9f\xc9\xe3syJ\xb7N\x9e\xd1\ue93e\xb7\fq\xa3J\xec6\xeb|\xaaD\x86c.0ێd\x8bl\xb6\xc0\r\xd3r\x9e!\r\x8f\xa1u\x95\xa6\xa8\xb5ef.\xe7\xfd\xf8\xb3\xac\x9a\x95?\xc1YZ)\x85¸{N\x9bI\x18\x1b\\\x18\xba\xddb4\x1bƴm\xed\xcc\xc9ѠNKb=\\\xbb)\xac\x82\xee_\xab5c\x0e\x8e\xd0\xc1!\xaa\tTȉ&a?֞\xa11\\L\xba\xe3\xb4S\x9d\x86\x9e\xfb&\xaa\u007f\xa6\xce)\x19\xc9lI\xc2X\nz1\x93\x95ƪ\xbc\x88\x88\xc6zL\xb6t\xb9\xe0bJ\xa2m\r5N\x19\xe0\xc1=\x9d\xa8ɹ\x0ecf\x8c\xa2Ğ8\xef9\xd3\xf96\xc45<\xfc\xbft\xf0\xb9J\xd7+:|L\x9bg\x82}\f\x84m\xfe\x9d\"H\xae\f\x1d\x9d0-\xddو_\xdb\xcb\x0f\xddI\xf5n\xea>\x0fO\xf4\xe0\x99إ\xf1A\x99\xeb\xb7\xf3\xdfԌ\x8f\xbb\x0f\b]b\xcaY1`\xae\u007f\x831K\xa7$|\x9e\xb2\x1f,\xe4\u05cbh\xf7\xf5\xfd\x1c\x19\xbd\xe5bzPJ-\xe049\xed גj3ߋ\x9a\n9\x17$\xaa{\xfe<\x89\xb5vj\x8d\x1c\x0e\xe1\x93'\x06̹\xc9\xc1^\xb1JeP\x98\x8d\x82\xae\xc9S\xa9\"\x82:\x93\xa8\x81m\x1e\xb8\xaeg\x90\xd8\x1e\xbcv\u007f\xbf!\x9d\xed\x10\x01\xc9y\x96\xa1\xf0\xab\xac1\xe01\x82\xcd\x1c\xc6\u007f&\xed}qN/^\xdb\xf0\xdf\\D\xebf\xd7q\xbcj\xe2\xf1_k\xa6\xbd\x82J\x15\xb6gu\xfa\xbeh.(_\x10\xff:\xbf\x0eV\xd7A\xeb\xb1 pa\xee\xa4\xd5\x0f\xe7ǲ\x01\x92\xf6/m\xd2 HDZ\xfb\xd1\x02=\xb1\xed\xeanTO\xc8\f\a\xa2\x9a\x8d\xdcO\x13\xb7\x06\x1d\xb2\x0em\x15\xfc\x1b\x00\x00\xff\xff\x10\xd9\xean\xcc\x0f\x00\x00",
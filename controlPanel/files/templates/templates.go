@@ -52,11 +52,11 @@ var staticFiles = map[string]*staticFilesFile{
 		size:  0,
 	},
 	"index/datadump.html": {
-		data:  "\x1f\x8b\b\x00\x00\x00\x00\x00\x02\xff\xe4Y]o\xdb6\x14}\xb6\u007f\x05\xc7b@\x02L6Ҥ-\x1aK\x02\xb6\xb4\x18\n\xa4]Q\x0f{\xa7\xc9+\x890Ej$\xe5\xc4\b\xf2\xdf\aQ\x92E\xdbR\x92\xban\x17\xa0y1\xcd\xcb\xfb\xc1{\xcea(\xf9\xee\x8eA\xc2% ̈%\xac\xcc\v|\u007f?\x0e\rP˕D\x9cE\xce\xf0\xae2 *\x881\x11\xce8\x03\x1c\x8f\x11B(d|\xd5Nku\x83\xe3\xf1hw\x9a*Q\xe6Ҵ&g\xce\xce\xe2\xbfA\xe7\\\x12\x81\xaa\xf0\x88\xe7\x85\xd26\x9cfg\xf1x4\x1a\x85\xa5h\xdd-Y\x18\xec\x16\x05\xd5\xd0U\x04\xb7$/\x04\xb8\t\xec\x1cF\xa1\xe0\xbeG`\xb9\x15\x80\xb8\t\b\xb5|\x058\x0e\t\xca4$\x11~Qm\xf2\f#\xa29\t\f\b\xa0\x16X\x84\xad.\x01\xc7\xf32ω^\x87S\x12\x87S\xc1\x1f\x88\xbd\x1b\xf1%\x8e?kE\xc1\x18t͍= \xc2y\x15\x81K\xfb\x91\x14\ax_\xe0x\x0ez\x05\xdat\xceh\xe7\xefI\x81^\xe3\xf8\xbd\xa8\xe17\a\xd4\xf1\n\xc7WJʽ\x00\a\xd5\xf2\x06\xc7\xd7*Es\xb0\x96\xcbt\xbb\x9cpZ\x8az\xe0\x91ͅ\xa2JZ\x90\xd6cM;\xd5O\x9d]\xff\x82H\x10\x1ewj\x158\xd6\xd4\x1e\xbb\xfcD\x951x\"SG\xa3\xf0\x97 \xf0\xb6\xdf:\xa3\xa7\x10w\x9e)m\x87ț9\r5=\n\x82M\xbe\x03s}!78\xfeBn\x86A\xec\x01\xb3'\xc3\xde\x1e֒r\x99\x0e\xed\xa2\xb6nS\xafC\xbb\xc1\xab\x85\xc4!K\xb8\x04ݵ\x97穳'\xa5\x10\x86j\x00\x19\xa8\xa2\xa2\xe3\xe6\x04#\v\xa3Di!\xe8Yb4\x8d0\xcf\xd3ig\x9b\x98U\x8a\xe3p\xca\xf3\xb4J\xe2\xef\xbc\x06\xd3\u00ad%\x1a\bbܐ\x85\x00\x86L\x01B\xd0\f\xe82\xc2\t\x11\x06\xf0\xd3(V\xe3\x1b\x87\xd36\xa4\x8f\xe3\xf1\xd28h\xbd$\xc3\xc8\x1e\x96\xd3\xdbP\x03\xf6V\xb6\x06R\xc6W\x8d\x04\xbda\xaf\x1a\xbbx/\xfd\u007f$5\x02\xdf&\xc6\xc3\xc5Q\x9d\xf7\x03$\xbe*\xb5\x06i\x8f\xad\x9c\xcf\x1aV\x03\x19+ӱ\xd3}\x82ۡ\xb3\xa62u\xe9\xc6\xcfO\xa4\xc7\x17\x8c\x83\xfbǈ\xc65\xfeǤr\x94:\x968ϟ\x8f8\ax\xbb\xf5\xef\xec' \xedѠ\xbd8\xd2\xdd\xe7@D\u007f/m6\x00ieR\x9a[\x0e;\xf7\xd5ǲ\xed\xe6\xf8\xc0\xaa[c\xec>\x8e\x10\xee\xe3\xfa\x93\xaa\x1e\x94>\xaeQ5x\xbe\xb4\xdb\x12\xec1\xf9\xe7@\xdb'\xe0\xe8\x9b\x0f\xad\x06\xa9\xef\x10\xb9\x05\xedH\xaay\xf5\xff\xaa\xe6Jɯ9\n\xbf\x92\xe0swU\xfd\xcbf\xa0\u007fBr7\xbd\xfd\x1e$\x9c\xef>\x03\fQp\xef6\xf0\b\x1d_\xe3\xfe\x9bġ\xec<\xec\x9a9\xcc\xd7͋\x87\x01ʶ\xf6\xa3?\x91\xf2\xbc\x14\xc4\x02{\xac\x80\u007f\x94\xddz6\x1d\xf7\xb7\xd3ѿ\xd72\xa8\x86\xc1\x8d|_}\x1c\xf9^\xd9'\x94\xae\xa7?\xe8\x89s\x1f\xccG\x13\x0f\xe9\xe90\x99\xbd\xc1\x0f\xa0\xbf\xf5\xb6t\xb0\t\xdeJ\x93\x13!\x02\x95$\x06lp\x86\uabef\x91\xf7^\xb59v\xb2\xf3\xf8*#2\x05$T\x8a\xcc\xe6\xa5Yvޭ\xf1\xe2rY\x946H\xb5*\v\x8c\x8c]\v\x88pNt\xcae  \xb1\x97\xe8կ\xb3.\xfa(4\x05\x91=\xbe\x81 \v\x108\x0e7\x82K\bJH\x90\xf0\xea\x9c\xe8\x9e 3\xce\x18\xc8VH\xe1\x94\xc7\xe1\xb4\n\xe9epQ\x91]\x17\x10a\x03D\xd3\f#Ir\x88\xf0\xbf\xb8/s\xc2A\xb0\xba\xed\t\xa1V\xe5\x81Pi`\xc0bT\bB!S\x82\x81\x8e\xf0\x1c,\xb2Y\xdd\x16\r)\xdcN&\x13\u007fg\xfdM\t\x16\xa5\xb5Jz\vw*,\x179\xb7\xfb\xe9\x9b\xe9&`\x1d\x041\xa2\x97\x18\xad\x88(!\xc2\u007f*?{w\xaa\x0f\x1f\xec[\xdc\xc8.\xda\xf7\v\xc8{;\x1aN\xb3\x8b.L\xa1\xa1Ŵ\x9d[\x10\xba\xac6&Y@\x95P\xfa\x12\xbd\x00\x80ك\xb9\x16J3З謸EF\t\xceЋ\xb7o\xdf>\xecø)\x04Y_\xa2\x85Pt\xf9\xf0ڂ0\xc6ez\x89^\x16\xb7\xb3Y[\xe9\x1e\v]\x8fi\xbdg\xd7d\xd7H\x1c\u007f\xeeP\x9eL&\x93pZhx\xa4y\x1e\xd6\x1eh\xa0\xb5҈\x12!Ti\x11\x11\xa0-\xaa\xb8\x1bP\x90\x16\xf4F\x1fj\x05:\x11\xea\xe6\xb2f\xf3\xacݫT\x12f8~\a\x92\x03{\n\x86\xfde\x98\x92\xba_\x10\xdaB\xda\xef\a\x94r\xed\x1d\x01\xd5`\x8bf\xfe\x97\x87\x8f>\u007fq;h>\xc3i\xf3+Q<\x1e\x87\x86j^\xd8:̊hT\v%BL\xd12\ai')\xd8\xf7\x02\xaa\xe1\x1f\xeb\x0f\xecdW\xb0\xa75O\x9cۄ0\xf6~\x05\xd2^scA\x82>\xc1KX\x97\x05\xfe\r%\xa5t)O\xa0\xb2\x9f\xa2\xbbM\xddnbRh\xf7\xf9\x0e\x12R\n{rڱ\x8f'\xa8v\x9a,a}\xa5\x18\xa0(\x8a\xd0ٹ\x1fñ\xf7)\x05\xd7\x12?\x9dP\xc1\xe9\xd2\xcfr\xefF\xf7\xa7\xb3\xaa;MK\xc6ww \xd9\xfd\xfd\u007f\x01\x00\x00\xff\xff\xc7×ik\x1b\x00\x00",
-		hash:  "d59a387a6b97ac8211d59ab0614b1e34df30e21f5d0729dd347f3c265095fb6f",
+		data:  "\x1f\x8b\b\x00\x00\x00\x00\x00\x02\xff\xe4Yao\xdb6\x13\xfel\xff\n\xbe,^ \x01&\x1bi\xd2\x16\x8d%\x01[Zl\x05ڮ\xa8\x87}\xa7ɓD\x98\"5\x92rb\x04\xf9\xef\x83(ˢm)I]5\vP}1\xcd#\xef9\xde\xf3\x1cEI\xb7\xb7\f\x12.\x01aF,ae^\u0efbqh\x80Z\xae$\xe2,r\x86w\x95\x01QA\x8c\x89p\xc6\x19\xe0x\x8c\x10B!㫦[\xabk\x1c\x8fG\xfb\xddT\x892\x97\xa619sv\x16\xff\x05:\xe7\x92\bT\xb9G</\x94\xb6\xe14;\x8bǣ\xd1(,E3ݒ\x85\xc1nPP5]DpC\xf2B\x80\xeb\xc0n\xc2(\x14ܟ\x11Xn\x05 n\x02B-_\x01\x8eC\x822\rI\x84_T\x8b<ÈhN\x02\x03\x02\xa8\x05\x16a\xabK\xc0\xf1\xbc\xccs\xa2\xd7\xe1\x94\xc4\xe1T\xf0{|\xef{|\x89\xe3/ZQ0\x06}\xe4\xc6\x1e\xe1\xe1\xbc\xf2\xc0\xa5\xfdD\x8a#f_\xe0x\x0ez\x05ڴ\x93\xd1\xde\xf5(G\xafq\xfc^\xd4\xf4\x9b#\xe2x\x85\xe3+%偃\xa3by\x83\xe3\x8f*Es\xb0\x96\xcbt7\x9cpZ\x8a\xba\xe1\x89\u0379\xa2JZ\x90\xd6SM\xd3\xd5-\x9d\xfd\xf9\x05\x91 <\xed\xd4U\xe0TS\xcf\xd8\xd7'\xaa\x8c\xc1#\x95:\x1a\x85\xff\v\x02o\xf9\xcdd\xf4\x18\xe1\xce3\xa5m\x9fx3WC\x9b\x1c\x05\xc1\x16\xefH\xac\xaf\xe4\x1a\xc7_\xc9u?\x89\x1ddv \x1c\xaca-)\x97i\xdf*j\xeb\xae\xf4Z\xb67|5\x948f\t\x97\xa0\xdb\xf4\xf2<u\xf6\xa4\x14\xc2P\r \x03UTr\xdc\xee`da\x94(-\x04\x1dC\x8c\xa6\x11\xe6y:mm\x13\xb3Jq\x1cNy\x9eV \xfe\xcak2-\xdcX\xa2\x81 \xc6\rY\b`\xc8\x14 \x04̀.#\x9c\x10a\x00?Nb5\xbfq8m\\\xfa<\x0e\a\xe3\xa8\xf5@\xfa\x99=\x0e\xd3[І\xec\x1d\xb4\r\xa5\x8c\xaf\xe2\xf1~\xb3\xb3\x1a[\x7f/\xfd\x1bI\xcd\xc0\xf7\x15\xe3\xf1\xc5Q\xed\xf7=\"\xbe*\xb5\x06i\x87\xae\x9c/\x1aV=\x88\x95ih\xb8\xcfpӷ\xd7T\xa6\x16n\xfc\xfc\x8at\xf8\x82qt?MѸ\xc4?\r\x94\x93\xd4P\xc5y\xfe|\x8a\xb3G\xb7;\xb7\xb3\x9f@\xb4\x83Q{1\xd0\xd9\xe7HF\x7f-m\xd6CieR\x9a[\x0e{\xe7Շ\xd0\xf61>\xb0\xea\xd4\x18\xbb\x9f\x01\xdc}Z\x7fVՃҧ5\xaa\x1a\xcfWv;\x05;\xa4\xfe\x1ci\x87\x02\x1c}\xf7\xa6\xb5a\xea\axnH\x1b\xa8j^\xfd\xb7Us\xa5\xe4\xb7l\x85\xdf(\xf0\xb9;\xaa\xfei3\xd0?\xa1\xb87\xb9\xfd\x11\"\x9c\xef?\x03\xf4I\xf0\xe04\xf0\x80\x1c_\xe3\xee\x93ı\xea<\xee\x98ٯ\xd7틇\x1e\xc96\xf6\xc1\x9fHy^\nb\x81=\x14\xc0\xdf\xca\xee<\x9b\x0e\x03ߠ\xfe\xc1\x8dUz݃\xbd\xb1\xfa\xa7\xeeNhW{\x9d\x96\xdeR\xec]Ə-\u0381\x0f\xb5]U\xda\x12\xfaD\x8f\xbb\x87Jz\x1a\xe0}\r=\x88ڷ\x85\x1c\xb7\xb3\xbc\xc1\xf7hn\xe7\x05qo\x06\xbc\x91&'B\x04*I\f\xd8\xe0\f\xd5\x7f_#\xefUr}\x85\xd9y|\x95\x11\x99\x02\x12*Ef\xfb\x9e0;o\xc7x~\xb9,J\x1b\xa4Z\x95\x05FƮ\x05D8':\xe52\x10\x90\xd8K\xf4\xea\xff\xb3\xd6\xfb(4\x05\x91\x1ds\x03A\x16 p\x1cn\x8b<!(!A«\xad\xb1}h\xce8c \x9b\xfa\r\xa7<\x0e\xa7\x95K\x0f\xc1yEv]@\x84\r\x10M3\x8c$\xc9!\xc2\xff\xe0.䄃`u\xda\x13B\xad\xca\x03\xa1\xd2\xc0\x80Ũ\x10\x84B\xa6\x04\x03\x1d\xe19Xd\xb3:-\x1aR\xb8\x99L&\xfeʺ\x93\x12,Jk\x95\xf4\x06\xeeEX.rn\x0f\xe17\xdd\x1b\x87\xb5\x13Ĉ^b\xb4\"\xa2\x84\b\xff\xae|\xf4\xf6F\xd6\x7f/\xf3\xaf0\xbbh^\xa9 \xef\x85p8\xcd.Z7\x85\x86\x86ӦoA\xe8\xb2Z\x98d\x01UB\xe9K\xf4\x02\x00f\xf7b-\x94f\xa0/\xd1Yq\x83\x8c\x12\x9c\xa1\x17o߾\xbd\x7f\x0e\xe3\xa6\x10d}\x89\x16B\xd1\xe5\xfdc\v\xc2\x18\x97\xe9%zY\xdc\xccfM\xa4\a*t9\xa6\xf5\x9a]\x92]\"q\xfc\xa5ey2\x99L\xc2i\xa1\xe1\x81\xe4y\\{\xa4\x81\xd6J#J\x84P\xa5ED\x80\xb6\xa8\xd2n@AZ\xd0\xdb\xfaP+ЉPח\xb5\x9ag\xcdZ\xa5\x920\xc3\xf1;\x90\x1c\xd8c8\xec\x0eÔ\xd4}4i\x02i\xfe\x1f\x11\xcaGo\v\xa8\x1a;2\xf3\xffܿ\xf5\xf9\x83\x9b\xc6\xe67\x9cn>\x8c\xc5\xe3qh\xa8慭ݬ\x88Fu\xa1D\x88)Z\xe6 \xed$\x05\xfb^@\xd5\xfcm\xfd\x81\x9d\xec\x17\xeci\xad\x137mB\x18{\xbf\x02i\xaboG A\x9f\xe0%\xac\xcb\x02\xff\x82\x92R:\xc8\x13\xa8\xec\xa7\xe8v\x1b\xb7\xeb\x98\x14\xda\xfd\xbe\x83\x84\x94\u009e\x9c\xb6\xea\xe3\t\xaa'M\x96\xb0\xbeR\fP\x14E\xe8\xec\xdc\xf7Q]\x8f\n\xb8.\xf1\xd3\t\x15\x9c.}\x94;\u05fa;\x9dU\xd9٤d|{\v\x92\xdd\xdd\xfd;\x00ϸs\x85^\x1c\x00\x00",
+		hash:  "b6cd8c18c84ba76cce43f1425be855619b790e312d8f9d6d009a8e89cec2ffd9",
 		mime:  "text/html; charset=utf-8",
-		mtime: time.Unix(1539243717, 0),
-		size:  7019,
+		mtime: time.Unix(1792367711, 0),
+		size:  7262,
 	},
 	"index/index.html": {
 		data:  "\x1f\x8b\b\x00\x00\x00\x00\x00\x02\xfft\x8fA\xaa\x021\f\x86\xd7\uf762v_O .\x04\xf7\x03z\x81\xd0d\xb4\xd0&\xa5͈C\xe9݅q!:\xcc6\xf9\xbe\xe4\xff[C\x1a\x03\x93\xb1\x81\x91\x9e\x03\xdc\xc8\xf6\xfe\xffךR\xca\x11\x94\x8c\xbd\x13 \x95e|\xd89gN\x82\xb3q\xee\xf8M->\xc3c\xa5G\xf1\x10\xaf\x92\xad\xd9\xff\xae\xb4\x00W\xf0\x1a\x84\xeb\x94\x12\x94ye#(\xe0\x94\xf2\xe7\xfd\x99q#B\xf5%d\xad\xab\x1b^X\x8b\xc4\x01\x98\xe2e\x83\x19E\xf4]\xb25b\xec\xfd\x15\x00\x00\xff\xff)\xb2x\xeb\x1a\x01\x00\x00",
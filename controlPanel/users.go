@@ -0,0 +1,124 @@
+package controlPanel
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Roles of control panel users.  They match the ControlPanelSetting of the state, a user never has
+// more access than the setting allows.
+const (
+	RoleNone      = 0
+	RoleReadOnly  = 1
+	RoleReadWrite = 2
+)
+
+// RoleName returns the name of a role as it is written in the config file
+func RoleName(role int) string {
+	switch role {
+	case RoleReadOnly:
+		return "readonly"
+	case RoleReadWrite:
+		return "readwrite"
+	}
+	return "none"
+}
+
+// ControlPanelUser is a user of the control panel from the ControlPanelUsers of the config file
+type ControlPanelUser struct {
+	Name     string
+	Role     int
+	passHash []byte
+}
+
+var (
+	users      map[string]*ControlPanelUser
+	usersMutex sync.RWMutex
+)
+
+// ParseControlPanelUser parses a name:password:role line of the config file.  The password is
+// everything between the first and the last ':', so it may hold a ':' itself.  Errors never
+// hold the password, nor the line when the name can't be told from it.
+func ParseControlPanelUser(line string) (*ControlPanelUser, error) {
+	first := strings.Index(line, ":")
+	last := strings.LastIndex(line, ":")
+	if first < 0 {
+		return nil, fmt.Errorf("control panel user must be name:password:role")
+	}
+	if first == 0 {
+		return nil, fmt.Errorf("control panel user has no name, expected name:password:role")
+	}
+	name := line[:first]
+	if first == last || last == first+1 {
+		return nil, fmt.Errorf("control panel user %q must be name:password:role", name)
+	}
+	password, role := line[first+1:last], line[last+1:]
+	u := new(ControlPanelUser)
+	u.Name = name
+	switch strings.ToLower(role) {
+	case "readonly":
+		u.Role = RoleReadOnly
+	case "readwrite":
+		u.Role = RoleReadWrite
+	default:
+		return nil, fmt.Errorf("control panel user %q has role %q, expected readonly or readwrite", u.Name, role)
+	}
+	h := sha256.Sum256([]byte(password))
+	u.passHash = h[:]
+	return u, nil
+}
+
+// SetControlPanelUsers replaces the users of the control panel.  Lines that don't parse are
+// skipped and returned as errors, numbered from 1.
+func SetControlPanelUsers(lines []string) []error {
+	var errs []error
+	newUsers := make(map[string]*ControlPanelUser)
+	for i, line := range lines {
+		u, err := ParseControlPanelUser(line)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %v", i+1, err))
+			continue
+		}
+		newUsers[u.Name] = u
+	}
+
+	usersMutex.Lock()
+	users = newUsers
+	usersMutex.Unlock()
+	return errs
+}
+
+// requestRole returns the role of the user making the request, false if the request is not authorized.
+// With no users and no RPC user the control panel is open to everyone.  The RPC user has the access
+// of the ControlPanelSetting, as it had before there were users.
+func requestRole(r *http.Request) (int, bool) {
+	setting := StatePointer.ControlPanelSetting
+
+	usersMutex.RLock()
+	defer usersMutex.RUnlock()
+
+	if len(users) == 0 && StatePointer.GetRpcUser() == "" {
+		return setting, true
+	}
+
+	if name, pass, ok := r.BasicAuth(); ok {
+		if u := users[name]; u != nil {
+			h := sha256.Sum256([]byte(pass))
+			if subtle.ConstantTimeCompare(h[:], u.passHash) == 1 {
+				if u.Role < setting {
+					return u.Role, true
+				}
+				return setting, true
+			}
+		}
+	}
+
+	if StatePointer.GetRpcUser() != "" && checkAuthHeader(r) {
+		return setting, true
+	}
+	return RoleNone, false
+}
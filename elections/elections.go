@@ -8,8 +8,10 @@ import (
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
+	llog "github.com/FactomProject/factomd/log"
 	"github.com/FactomProject/factomd/state"
 	"github.com/FactomProject/factomd/util/atomic"
+	"github.com/sirupsen/logrus"
 )
 
 var _ = fmt.Print
@@ -472,6 +474,9 @@ func (e *Elections) ProcessWaiting() {
 	}
 }
 
+// electionsLogger logs the messages the elections handle, at the level of the elections subsystem
+var electionsLogger = llog.SubsystemLogger(llog.SubsystemElections).WithField("package", "elections")
+
 // Runs the main loop for elections for this instance of factomd
 func Run(s *state.State) {
 	e := new(Elections)
@@ -491,6 +496,9 @@ func Run(s *state.State) {
 		e.LogMessage("election", fmt.Sprintf("exec %d", e.Electing), msg.(interfaces.IMsg))
 
 		valid := msg.ElectionValidate(e)
+		if llog.SubsystemEnabled(llog.SubsystemElections, logrus.DebugLevel) {
+			electionsLogger.WithField("node", e.Name).Debugf("electing %d valid %d %s", e.Electing, valid, msg.(interfaces.IMsg).String())
+		}
 		switch valid {
 		case -1:
 			// Do not process
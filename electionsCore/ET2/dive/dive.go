@@ -14,9 +14,13 @@ import (
 
 	"flag"
 	"math/rand"
+	"os"
+	"os/signal"
+	"runtime"
 
 	. "github.com/FactomProject/factomd/electionsCore/ET2/directedmessage"
 	. "github.com/FactomProject/factomd/electionsCore/ET2/mirrors"
+	"github.com/FactomProject/factomd/electionsCore/ET2/modelcheck"
 	"github.com/FactomProject/factomd/electionsCore/messages"
 	"github.com/FactomProject/factomd/electionsCore/primitives"
 	"github.com/dustin/go-humanize"
@@ -92,8 +96,25 @@ func Main() {
 	recursionsPtr := flag.Int("r", 1000, "Number of recursions allowed")
 	randomFactorPtr := flag.Int("p", 1, "Pick a starting prime")
 	globalPtr := flag.Int("g", 1000, "How many global nodes between prints")
+	modelCheck := flag.Bool("m", false, "Model check: visit every election state once instead of diving, with r as the depth limit")
+	workers := flag.Int("w", runtime.NumCPU(), "Number of model check workers")
+	checkpoint := flag.String("c", "", "Model check checkpoint file, resumed from if it exists")
+	output := flag.String("o", ".", "Directory model check violations are written to, for divefromfile")
 	flag.Parse()
 
+	if *modelCheck {
+		ModelCheck(modelcheck.Config{
+			Feds:            *feds,
+			Audits:          *audits,
+			Depth:           *recursionsPtr,
+			Workers:         *workers,
+			Checkpoint:      *checkpoint,
+			CheckpointEvery: time.Minute,
+			Output:          *output,
+		})
+		return
+	}
+
 	primeIdx = *randomFactorPtr
 	global = *globalPtr
 	recursions = *recursionsPtr
@@ -113,6 +134,46 @@ func Main() {
 	recurse(*audits, *feds, recursions)
 }
 
+// ModelCheck runs a model check, printing its progress, until every state is explored or it is
+// interrupted.  An interrupted check saves its checkpoint so the next run resumes it.
+func ModelCheck(config modelcheck.Config) {
+	fmt.Printf("Model checking %d feds %d audits to depth %d with %d workers\n", config.Feds, config.Audits, config.Depth, config.Workers)
+	checker := modelcheck.NewChecker(config)
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		fmt.Println("Stopping, saving the checkpoint")
+		checker.Stop()
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(10 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				fmt.Printf("%s remaining %s\n", checker.Stats(), humanize.Comma(int64(checker.Remaining())))
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	stats, err := checker.Run()
+	close(done)
+	fmt.Printf("%s remaining %s\n", stats, humanize.Comma(int64(checker.Remaining())))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if stats.Splits+stats.Stalls+stats.Limits > 0 {
+		os.Exit(1)
+	}
+}
+
 func SetGlobals(r int, rf int, p int, g int) {
 	recursions, randomFactor, primeIdx, global = r, rf, p, g
 }
@@ -18,17 +18,10 @@ func DiveFromFile(name string, listen string, connect string, load string, recur
 	fmt.Printf("DiveFromFile(name %s, listen <%s>, connect <%s>, load <%s>,  recursions %d, randomFactor %d, primeIdx %d, global %d)\n",
 		name, listen, connect, load, recursions, randomFactor, primeIdx, global)
 
-	con := controller.NewControllerInterpreter(1, 1)
-	file, err := os.Open(name)
-	if err != nil {
-		panic(err)
-	}
-	data, err := ioutil.ReadAll(file)
+	con, err := LoadFile(name)
 	if err != nil {
 		panic(err)
 	}
-	con.InitInterpreter()
-	con.Interpret(strings.NewReader(string(data)))
 
 	dive.MirrorMap.Init("dive")
 
@@ -48,6 +41,19 @@ func DiveFromFile(name string, listen string, connect string, load string, recur
 	dive.Dive(con.BufferedMessages, con.Elections, 0, recursions, []*DirectedMessage{})
 }
 
+// LoadFile runs a script of the controller interpreter, such as a violation written by the model
+// checker, and returns the election it sets up to dive from
+func LoadFile(name string) (*controller.ControllerInterpreter, error) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	con := controller.NewControllerInterpreter(1, 1)
+	con.InitInterpreter()
+	con.Interpret(strings.NewReader(string(data)))
+	return con, nil
+}
+
 func grabInput(in *bufio.Reader) string {
 	input, err := in.ReadString('\n')
 	if err != nil {
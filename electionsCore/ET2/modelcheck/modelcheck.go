@@ -0,0 +1,524 @@
+// Package modelcheck explores every order in which the messages of an election can be delivered,
+// like dive, but visits each canonical election state only once, runs the states across workers,
+// and can save the states it has explored and the ones still to explore so a long run can be
+// stopped and resumed.  Any election that splits, stalls or runs past the depth limit is written
+// out as a file that divefromfile can load to replay it.
+package modelcheck
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/FactomProject/factomd/electionsCore/ET2/directedmessage"
+	"github.com/FactomProject/factomd/electionsCore/controller"
+	"github.com/FactomProject/factomd/electionsCore/election"
+	"github.com/FactomProject/factomd/electionsCore/imessage"
+	"github.com/FactomProject/factomd/electionsCore/messages"
+)
+
+// The kinds of violation
+const (
+	Split = "split" // safety: leaders committed to different volunteers
+	Stall = "stall" // liveness: no pending message changes any leader, and no majority has committed
+	Limit = "limit" // liveness: no majority has committed after Depth deliveries, usually a loop
+)
+
+// Config is the election to check and how to run the check
+type Config struct {
+	Feds   int
+	Audits int
+	Depth  int // deliveries a path may have before it is reported as a Limit violation, no limit if 0
+
+	Workers         int           // states expanded in parallel, 1 if not set
+	Checkpoint      string        // file the explored states and frontier are loaded from and saved to
+	CheckpointEvery time.Duration // how often the checkpoint is saved while running, only at the end if 0
+	MaxStates       int           // states this run expands before it stops, no limit if 0
+
+	Output        string // directory the violations are written to, none are written if empty
+	MaxViolations int    // files written of each kind of violation, 10 if not set
+}
+
+// Stats counts what a check has found so far, over every run that was resumed
+type Stats struct {
+	States    int // states expanded
+	Mirrors   int // deliveries that led to a state already explored
+	Solutions int // states where a majority of leaders committed to the same volunteer
+	Splits    int
+	Stalls    int
+	Limits    int
+	MaxDepth  int
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf("states %d mirrors %d solutions %d splits %d stalls %d limits %d max depth %d",
+		s.States, s.Mirrors, s.Solutions, s.Splits, s.Stalls, s.Limits, s.MaxDepth)
+}
+
+// Checker explores the states of one election.  A state is identified by its path, the index into
+// the pending messages of each delivery from the start of the election, so the frontier can be
+// saved as plain paths and rebuilt by replaying them.
+type Checker struct {
+	Config
+
+	mutex    sync.Mutex
+	cond     *sync.Cond
+	stats    Stats
+	explored map[[32]byte]struct{}
+	frontier [][]int
+	inFlight map[int][]int
+	nextID   int
+	expanded int
+	stopped  bool
+	written  map[string]int
+}
+
+// checkpoint is what is saved to the checkpoint file
+type checkpoint struct {
+	Feds     int
+	Audits   int
+	Depth    int
+	Stats    Stats
+	Explored [][32]byte
+	Frontier [][]int
+	Written  map[string]int
+}
+
+func NewChecker(config Config) *Checker {
+	c := new(Checker)
+	c.Config = config
+	if c.Workers < 1 {
+		c.Workers = 1
+	}
+	if c.MaxViolations == 0 {
+		c.MaxViolations = 10
+	}
+	c.cond = sync.NewCond(&c.mutex)
+	c.explored = make(map[[32]byte]struct{})
+	c.inFlight = make(map[int][]int)
+	c.written = make(map[string]int)
+	return c
+}
+
+// Run explores states until there are none left, MaxStates have been expanded or Stop is called,
+// resuming from the checkpoint if there is one.  The checkpoint is saved before Run returns.
+func (c *Checker) Run() (Stats, error) {
+	resumed, err := c.load()
+	if err != nil {
+		return c.Stats(), err
+	}
+	if !resumed {
+		s, err := c.replay(nil)
+		if err != nil {
+			return c.Stats(), err
+		}
+		c.visit(nil, s)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < c.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.work()
+		}()
+	}
+
+	done := make(chan struct{})
+	if c.Checkpoint != "" && c.CheckpointEvery > 0 {
+		go func() {
+			ticker := time.NewTicker(c.CheckpointEvery)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					c.save()
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(done)
+
+	return c.Stats(), c.save()
+}
+
+// Stop makes the workers stop once the states they are expanding are done
+func (c *Checker) Stop() {
+	c.mutex.Lock()
+	c.stopped = true
+	c.cond.Broadcast()
+	c.mutex.Unlock()
+}
+
+// Stats returns what the check has found so far
+func (c *Checker) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.stats
+}
+
+// Remaining returns the number of states still to be expanded
+func (c *Checker) Remaining() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.frontier) + len(c.inFlight)
+}
+
+func (c *Checker) work() {
+	for {
+		id, path, ok := c.next()
+		if !ok {
+			return
+		}
+		err := c.expand(path)
+		c.mutex.Lock()
+		delete(c.inFlight, id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "modelcheck: %v\n", err)
+			c.stopped = true
+		}
+		c.cond.Broadcast()
+		c.mutex.Unlock()
+	}
+}
+
+// next takes the oldest state from the frontier, waiting while other workers may still add some
+func (c *Checker) next() (int, []int, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for len(c.frontier) == 0 && len(c.inFlight) > 0 && !c.stopped {
+		c.cond.Wait()
+	}
+	if c.MaxStates > 0 && c.expanded >= c.MaxStates {
+		c.stopped = true
+	}
+	if c.stopped || len(c.frontier) == 0 {
+		c.cond.Broadcast()
+		return 0, nil, false
+	}
+
+	path := c.frontier[0]
+	c.frontier[0] = nil
+	c.frontier = c.frontier[1:]
+	c.nextID++
+	c.inFlight[c.nextID] = path
+	c.expanded++
+	c.stats.States++
+	return c.nextID, path, true
+}
+
+// expand delivers each pending message of the state at path in turn, visiting the states the
+// deliveries lead to
+func (c *Checker) expand(path []int) error {
+	s, err := c.replay(path)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for i, dm := range s.pending {
+		leader := s.leaders[dm.LeaderIdx]
+		if leader.Committed {
+			continue // nothing changes a leader that has committed
+		}
+		saved := leader.Copy()
+		msg, change := leader.Execute(dm.Msg, len(path))
+		if change {
+			changed = true
+			child := &state{leaders: s.leaders, pending: s.deliver(i, dm, msg)}
+			c.visit(append(path[:len(path):len(path)], i), child)
+		}
+		s.leaders[dm.LeaderIdx] = saved
+	}
+
+	if !changed {
+		c.mutex.Lock()
+		c.stats.Stalls++
+		c.mutex.Unlock()
+		c.report(Stall, "no pending message changes any leader", path)
+	}
+	return nil
+}
+
+// visit adds the state at path to the frontier, unless it has been explored already or the
+// election has ended there
+func (c *Checker) visit(path []int, s *state) {
+	h := s.hash()
+	complete, err := nodesCompleted(s.leaders)
+
+	c.mutex.Lock()
+	if _, ok := c.explored[h]; ok {
+		c.stats.Mirrors++
+		c.mutex.Unlock()
+		return
+	}
+	c.explored[h] = struct{}{}
+	if len(path) > c.stats.MaxDepth {
+		c.stats.MaxDepth = len(path)
+	}
+
+	kind, why := "", ""
+	switch {
+	case err != nil:
+		c.stats.Splits++
+		kind, why = Split, err.Error()
+	case complete:
+		c.stats.Solutions++
+	case c.Depth > 0 && len(path) >= c.Depth:
+		c.stats.Limits++
+		kind, why = Limit, fmt.Sprintf("no majority has committed after %d deliveries", len(path))
+	default:
+		c.frontier = append(c.frontier, path)
+		c.cond.Signal()
+	}
+	c.mutex.Unlock()
+
+	if kind != "" {
+		c.report(kind, why, path)
+	}
+}
+
+// report writes the state at path as a divefromfile script: the deliveries that lead to it, then
+// the pending messages buffered for dive to explore
+func (c *Checker) report(kind string, why string, path []int) {
+	if c.Output == "" {
+		return
+	}
+	c.mutex.Lock()
+	if c.written[kind] >= c.MaxViolations {
+		c.mutex.Unlock()
+		return
+	}
+	c.written[kind]++
+	c.mutex.Unlock()
+
+	s, err := c.replay(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "modelcheck: %v\n", err)
+		return
+	}
+	h := s.hash()
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# %s: %s\n", kind, why)
+	fmt.Fprintf(&b, "%d %d setcon\n", c.Feds, c.Audits)
+	for i, dm := range s.delivered {
+		fmt.Fprintf(&b, "%s # %d\n", s.format(dm), i)
+	}
+	fmt.Fprintf(&b, "<b> # Pending:\n")
+	for _, dm := range s.pending {
+		fmt.Fprintf(&b, "%s\n", s.format(dm))
+	}
+
+	name := filepath.Join(c.Output, ViolationFile(kind, h))
+	if err := ioutil.WriteFile(name, b.Bytes(), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "modelcheck: %v\n", err)
+	}
+}
+
+// ViolationFile is the name of the file a violation of the kind at the state with hash h is written to
+func ViolationFile(kind string, h [32]byte) string {
+	return fmt.Sprintf("%s-%x.txt", kind, h[:6])
+}
+
+// load reads the checkpoint, if there is one
+func (c *Checker) load() (bool, error) {
+	if c.Checkpoint == "" {
+		return false, nil
+	}
+	f, err := os.Open(c.Checkpoint)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	var cp checkpoint
+	if err := gob.NewDecoder(f).Decode(&cp); err != nil {
+		return false, fmt.Errorf("checkpoint %s: %v", c.Checkpoint, err)
+	}
+	if cp.Feds != c.Feds || cp.Audits != c.Audits || cp.Depth != c.Depth {
+		return false, fmt.Errorf("checkpoint %s is of %d feds %d audits depth %d, not %d feds %d audits depth %d",
+			c.Checkpoint, cp.Feds, cp.Audits, cp.Depth, c.Feds, c.Audits, c.Depth)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.stats = cp.Stats
+	for _, h := range cp.Explored {
+		c.explored[h] = struct{}{}
+	}
+	c.frontier = cp.Frontier
+	for k, v := range cp.Written {
+		c.written[k] = v
+	}
+	return true, nil
+}
+
+// save writes the checkpoint.  States being expanded go back in the frontier, as the states they
+// lead to are either in the frontier already or not explored yet.
+func (c *Checker) save() error {
+	if c.Checkpoint == "" {
+		return nil
+	}
+
+	c.mutex.Lock()
+	cp := checkpoint{Feds: c.Feds, Audits: c.Audits, Depth: c.Depth, Stats: c.stats, Written: make(map[string]int)}
+	cp.Explored = make([][32]byte, 0, len(c.explored))
+	for h := range c.explored {
+		cp.Explored = append(cp.Explored, h)
+	}
+	for _, path := range c.inFlight {
+		cp.Frontier = append(cp.Frontier, path)
+		cp.Stats.States--
+	}
+	cp.Frontier = append(cp.Frontier, c.frontier...)
+	for k, v := range c.written {
+		cp.Written[k] = v
+	}
+	c.mutex.Unlock()
+
+	tmp := c.Checkpoint + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	err = gob.NewEncoder(f).Encode(&cp)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, c.Checkpoint)
+}
+
+// state is the leaders of an election and the messages still to be delivered to them
+type state struct {
+	leaders   []*election.Election
+	pending   []*DirectedMessage
+	delivered []*DirectedMessage
+}
+
+// replay rebuilds the state at path from the start of the election
+func (c *Checker) replay(path []int) (*state, error) {
+	con := controller.NewController(c.Feds, c.Audits)
+	for _, e := range con.Elections {
+		e.Display = nil
+	}
+	con.GlobalDisplay = nil
+
+	s := &state{leaders: con.Elections}
+	for _, v := range con.Volunteers {
+		for i := range con.Elections {
+			s.pending = append(s.pending, &DirectedMessage{LeaderIdx: i, Msg: v})
+		}
+	}
+	for depth, i := range path {
+		if i >= len(s.pending) {
+			return nil, fmt.Errorf("path %v does not fit the election, delivery %d has %d messages to pick from", path, depth, len(s.pending))
+		}
+		dm := s.pending[i]
+		msg, _ := s.leaders[dm.LeaderIdx].Execute(dm.Msg, depth)
+		s.pending = s.deliver(i, dm, msg)
+		s.delivered = append(s.delivered, dm)
+	}
+	return s, nil
+}
+
+// deliver returns the pending messages once the i'th has been delivered and the leader's response,
+// if any, is sent to every other leader
+func (s *state) deliver(i int, dm *DirectedMessage, resp imessage.IMessage) []*DirectedMessage {
+	pending := make([]*DirectedMessage, 0, len(s.pending)+len(s.leaders))
+	pending = append(pending, s.pending[:i]...)
+	pending = append(pending, s.pending[i+1:]...)
+	if resp != nil {
+		for l := range s.leaders {
+			if l != dm.LeaderIdx {
+				pending = append(pending, &DirectedMessage{LeaderIdx: l, Msg: resp})
+			}
+		}
+	}
+	return pending
+}
+
+func (s *state) hash() [32]byte {
+	return StateHash(s.leaders, s.pending)
+}
+
+// StateHash is the canonical state: the normalized state of each leader as dive uses for its
+// mirrors, with whether it has committed and the messages pending for it, sorted so the order of
+// the leaders and of their messages does not matter.  The files of the violations are named
+// after it.
+func StateHash(leaders []*election.Election, pending []*DirectedMessage) [32]byte {
+	inbox := make([][]string, len(leaders))
+	for _, dm := range pending {
+		inbox[dm.LeaderIdx] = append(inbox[dm.LeaderIdx], describe(dm.Msg))
+	}
+
+	hashes := make([]string, len(leaders))
+	for i, ldr := range leaders {
+		sort.Strings(inbox[i])
+		h := sha256.Sum256([]byte(fmt.Sprintf("%s%t\n%s", ldr.NormalizedString(), ldr.Committed, strings.Join(inbox[i], "\n"))))
+		hashes[i] = string(h[:])
+	}
+	sort.Strings(hashes)
+	return sha256.Sum256([]byte(strings.Join(hashes, "")))
+}
+
+func describe(msg imessage.IMessage) string {
+	switch m := msg.(type) {
+	case *messages.LeaderLevelMessage:
+		return fmt.Sprintf("l %x %d %d %d", m.Signer, m.Level, m.Rank, m.VolunteerPriority)
+	case *messages.VolunteerMessage:
+		return fmt.Sprintf("v %x", m.Signer)
+	case *messages.VoteMessage:
+		return fmt.Sprintf("o %x %x", m.Signer, m.Volunteer.Signer)
+	}
+	return fmt.Sprintf("%v", msg)
+}
+
+// format writes a delivery the way the controller interpreter reads it
+func (s *state) format(dm *DirectedMessage) string {
+	a := s.leaders[0].AuthSet
+	switch m := dm.Msg.(type) {
+	case *messages.LeaderLevelMessage:
+		return fmt.Sprintf("{ %d } %d { %d } <-l", a.FedIDtoIndex(m.Signer), m.Level, dm.LeaderIdx)
+	case *messages.VolunteerMessage:
+		return fmt.Sprintf("%d { %d } <-v", a.GetVolunteerPriority(m.Signer), dm.LeaderIdx)
+	case *messages.VoteMessage:
+		return fmt.Sprintf("{ %d } %d { %d } <-o", a.FedIDtoIndex(m.Signer), a.GetVolunteerPriority(m.Volunteer.Signer), dm.LeaderIdx)
+	}
+	return "NA"
+}
+
+// nodesCompleted is true once a majority of the leaders has committed, and an error if any two
+// committed to different volunteers
+func nodesCompleted(nodes []*election.Election) (bool, error) {
+	done := 0
+	prev := -1
+	for _, n := range nodes {
+		if n.Committed {
+			done++
+			if prev != -1 && n.CurrentVote.VolunteerPriority != prev {
+				return false, fmt.Errorf("2 nodes committed on different results. %d and %d", prev, n.CurrentVote.VolunteerPriority)
+			}
+			prev = n.CurrentVote.VolunteerPriority
+		}
+	}
+	return done >= len(nodes)/2+1, nil
+}
@@ -0,0 +1,128 @@
+package modelcheck_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/factomd/electionsCore/ET2/directedmessage"
+	"github.com/FactomProject/factomd/electionsCore/ET2/dive"
+	"github.com/FactomProject/factomd/electionsCore/ET2/divefromfile"
+	. "github.com/FactomProject/factomd/electionsCore/ET2/modelcheck"
+)
+
+func TestModelCheck(t *testing.T) {
+	one, err := NewChecker(Config{Feds: 2, Audits: 1}).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if one.Splits != 0 || one.Stalls != 0 || one.Limits != 0 {
+		t.Errorf("Expected no violations, found %s", one)
+	}
+	if one.Solutions == 0 {
+		t.Errorf("Expected some solutions, found %s", one)
+	}
+
+	// The workers take the states in a different order, but without a depth limit they explore the same ones
+	four, err := NewChecker(Config{Feds: 2, Audits: 1, Workers: 4}).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if four.States != one.States || four.Solutions != one.Solutions {
+		t.Errorf("Expected %s with 4 workers, found %s", one, four)
+	}
+}
+
+func TestCheckpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "modelcheck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	full, err := NewChecker(Config{Feds: 2, Audits: 1}).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := Config{Feds: 2, Audits: 1, Workers: 2, Checkpoint: filepath.Join(dir, "checkpoint"), MaxStates: 10}
+	c := NewChecker(config)
+	partial, err := c.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if partial.States != 10 || c.Remaining() == 0 {
+		t.Fatalf("Expected the run to stop after 10 states, found %s with %d remaining", partial, c.Remaining())
+	}
+
+	config.MaxStates = 0
+	resumed, err := NewChecker(config).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed.States != full.States || resumed.Solutions != full.Solutions {
+		t.Errorf("Expected %s once resumed, found %s", full, resumed)
+	}
+
+	config.Feds = 3
+	if _, err := NewChecker(config).Run(); err == nil {
+		t.Errorf("Expected an error resuming a checkpoint of another election")
+	}
+}
+
+func TestViolationReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "modelcheck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	stats, err := NewChecker(Config{Feds: 2, Audits: 1, Depth: 4, Output: dir, MaxViolations: 2}).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Limits == 0 {
+		t.Fatalf("Expected a depth of 4 to be too short, found %s", stats)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, Limit+"-*.txt"))
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 files, found %v", files)
+	}
+
+	// divefromfile sets up the election from the file, a comment, the election, 4 deliveries, then
+	// the pending messages once buffering is on, and it is the state that was reported
+	data, err := ioutil.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	con, err := divefromfile.LoadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending := strings.Count(string(data), "\n") - 3 - 4
+	if len(con.Elections) != 2 || len(con.BufferedMessages) != pending {
+		t.Errorf("Expected 2 leaders and %d buffered messages, found %d and %d", pending, len(con.Elections), len(con.BufferedMessages))
+	}
+	if name := ViolationFile(Limit, StateHash(con.Elections, con.BufferedMessages)); name != filepath.Base(files[0]) {
+		t.Errorf("The file sets up the state of %s, not %s", name, filepath.Base(files[0]))
+	}
+
+	// and diving from it runs past the depth limit without a majority committing
+	committed := 0
+	for _, e := range con.Elections {
+		if e.Committed {
+			committed++
+		}
+	}
+	if committed > len(con.Elections)/2 {
+		t.Errorf("%d of %d leaders have committed in the file", committed, len(con.Elections))
+	}
+	dive.SetGlobals(4, 1, 1, 1000)
+	limitHit, _, success := dive.Dive(con.BufferedMessages, con.Elections, 4, 4, []*directedmessage.DirectedMessage{})
+	if !limitHit || success {
+		t.Errorf("Expected the dive from the file to hit the limit, found limit %t success %t", limitHit, success)
+	}
+}
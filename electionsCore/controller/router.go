@@ -19,6 +19,8 @@ type Router struct {
 	ElectionQueues []chan imessage.IMessage
 	RepeatedFilter []map[imessage.IMessage]struct{}
 	Elections      []*election.RoutingElection
+	// Links that lose every message, from node to node
+	Drops map[[2]int]struct{}
 
 	Printing bool
 }
@@ -30,6 +32,7 @@ func NewRouter(elections []*election.RoutingElection) *Router {
 	r.Consumed = make([]int, len(elections))
 	r.Generated = make([]int, len(elections))
 	r.RepeatedFilter = make([]map[imessage.IMessage]struct{}, len(elections))
+	r.Drops = make(map[[2]int]struct{})
 
 	for i := range r.ElectionQueues {
 		r.ElectionQueues[i] = make(chan imessage.IMessage, 10000)
@@ -59,6 +62,16 @@ func center(str string) string {
 	return fmt.Sprintf("%-4s", fmt.Sprintf("%4s", str))
 }
 
+// DropLink makes the router lose every message it would route from one node to another
+func (r *Router) DropLink(from int, to int) {
+	r.Drops[[2]int{from, to}] = struct{}{}
+}
+
+func (r *Router) dropped(from int, to int) bool {
+	_, ok := r.Drops[[2]int{from, to}]
+	return ok
+}
+
 func (r *Router) PrintMode(active bool) {
 	r.Printing = active
 }
@@ -154,7 +167,9 @@ func (r *Router) routeIncoming(from int, msg imessage.IMessage) {
 	}
 	// TODO: Adjust routing behavior
 	for i := range r.ElectionQueues {
-		r.acceptIncoming(i, msg)
+		if !r.dropped(from, i) {
+			r.acceptIncoming(i, msg)
+		}
 	}
 }
 
@@ -180,6 +195,8 @@ func (r *Router) route(from int, msg imessage.IMessage) {
 	}
 	// TODO: Adust routing behavior
 	for i := range r.ElectionQueues {
-		r.acceptIncoming(i, msg)
+		if !r.dropped(from, i) {
+			r.acceptIncoming(i, msg)
+		}
 	}
 }
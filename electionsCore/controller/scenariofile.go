@@ -0,0 +1,278 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// ScenarioFile is a declarative election scenario.  It sets up an authority set, delivers messages
+// by hand in the order of its steps, optionally lets the router gossip the rest, and says what the
+// election should end with.
+//
+//	{
+//	  "name": "all agree",
+//	  "feds": 3, "audits": 3,
+//	  "router": true,
+//	  "drops": [ { "from": 0, "to": 2 } ],
+//	  "steps": [
+//	    { "volunteer": 0, "to": [0, 1, 2] },
+//	    { "vote": 0, "from": [0, 1, 2], "to": [0, 1, 2] },
+//	    { "level": 1, "from": [0, 1, 2], "to": [0, 1, 2] }
+//	  ],
+//	  "expect": { "complete": true, "volunteer": 0 }
+//	}
+type ScenarioFile struct {
+	Name   string         `json:"name"`
+	Feds   int            `json:"feds"`
+	Audits int            `json:"audits"`
+	Router bool           `json:"router"` // the outputs of the nodes are gossiped by the router
+	Steps  []ScenarioStep `json:"steps"`  // messages delivered by hand, in order
+	Drops  []ScenarioDrop `json:"drops"`  // links the router never delivers over
+	Limit  int            `json:"limit"`  // router steps to run after the scenario steps, 100 if not set
+	Expect ScenarioExpect `json:"expect"`
+}
+
+// ScenarioStep delivers one kind of message to the nodes in To.  Exactly one of Volunteer, Vote
+// and Level is set.  Vote and Level are the messages that the leaders in From have generated.
+type ScenarioStep struct {
+	Volunteer *int  `json:"volunteer,omitempty"` // the volunteer message of this audit server
+	Vote      *int  `json:"vote,omitempty"`      // votes for this audit server
+	Level     *int  `json:"level,omitempty"`     // leader level messages of this level
+	From      []int `json:"from,omitempty"`
+	To        []int `json:"to"`
+}
+
+// ScenarioDrop is a link from one node to another that loses every message
+type ScenarioDrop struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// ScenarioExpect is the outcome a scenario should reach.  Nodes that commit to different
+// volunteers always fail the scenario.
+type ScenarioExpect struct {
+	Complete  bool `json:"complete"`            // every leader commits
+	Volunteer *int `json:"volunteer,omitempty"` // the audit server the committed leaders chose, at least one must commit
+}
+
+// ScenarioOutcome is what a scenario ended with
+type ScenarioOutcome struct {
+	Committed  []bool // by leader
+	Volunteers []int  // the audit server each leader committed to, -1 if it did not
+	StepError  string // a step that could not be delivered, as the message does not exist yet
+}
+
+// Divergence is why a scenario did not reach its expected outcome.  It is empty if it did.
+func (s *ScenarioFile) Divergence(o *ScenarioOutcome) string {
+	if o.StepError != "" {
+		return "step: " + o.StepError
+	}
+
+	chosen := -1
+	complete := true
+	for i, committed := range o.Committed {
+		if !committed {
+			complete = false
+			continue
+		}
+		if chosen != -1 && o.Volunteers[i] != chosen {
+			return fmt.Sprintf("split: leaders committed to volunteers %v", o.Volunteers)
+		}
+		chosen = o.Volunteers[i]
+	}
+	if complete != s.Expect.Complete {
+		return fmt.Sprintf("complete: expected %t, committed %v", s.Expect.Complete, o.Committed)
+	}
+	if s.Expect.Volunteer != nil && chosen != *s.Expect.Volunteer {
+		if chosen == -1 {
+			return fmt.Sprintf("volunteer: expected %d, leaders committed to none", *s.Expect.Volunteer)
+		}
+		return fmt.Sprintf("volunteer: expected %d, leaders committed to %v", *s.Expect.Volunteer, o.Volunteers)
+	}
+	return ""
+}
+
+// LoadScenarioFile reads a scenario file
+func LoadScenarioFile(filename string) (*ScenarioFile, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseScenario(data)
+}
+
+// ParseScenario decodes a scenario and checks it refers only to nodes and audit servers that exist
+func ParseScenario(data []byte) (*ScenarioFile, error) {
+	s := new(ScenarioFile)
+	err := json.Unmarshal(data, s)
+	if err != nil {
+		return nil, err
+	}
+	if s.Feds < 1 || s.Audits < 1 {
+		return nil, fmt.Errorf("scenario %q needs at least one fed and one audit server", s.Name)
+	}
+
+	nodes := func(list []int) error {
+		for _, n := range list {
+			if n < 0 || n >= s.Feds {
+				return fmt.Errorf("scenario %q has no leader %d", s.Name, n)
+			}
+		}
+		return nil
+	}
+	for i, step := range s.Steps {
+		kinds := 0
+		for _, k := range []*int{step.Volunteer, step.Vote, step.Level} {
+			if k != nil {
+				kinds++
+			}
+		}
+		if kinds != 1 {
+			return nil, fmt.Errorf("scenario %q step %d must have one of volunteer, vote or level", s.Name, i)
+		}
+		if step.Volunteer != nil && (*step.Volunteer < 0 || *step.Volunteer >= s.Audits) ||
+			step.Vote != nil && (*step.Vote < 0 || *step.Vote >= s.Audits) {
+			return nil, fmt.Errorf("scenario %q step %d has no such audit server", s.Name, i)
+		}
+		if err := nodes(step.From); err != nil {
+			return nil, err
+		}
+		if err := nodes(step.To); err != nil {
+			return nil, err
+		}
+	}
+	for _, d := range s.Drops {
+		if err := nodes([]int{d.From, d.To}); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Run plays the scenario against a new Controller and returns what it ended with
+func (s *ScenarioFile) Run() *ScenarioOutcome {
+	return s.run(s.Steps, nil)
+}
+
+func (s *ScenarioFile) run(steps []ScenarioStep, trace *[]string) *ScenarioOutcome {
+	c := NewController(s.Feds, s.Audits)
+	c.SendOutputsToRouter(s.Router)
+	for _, d := range s.Drops {
+		c.Router.DropLink(d.From, d.To)
+	}
+
+	o := new(ScenarioOutcome)
+	for i, step := range steps {
+		ok := true
+		switch {
+		case step.Volunteer != nil:
+			c.RouteVolunteerMessage(*step.Volunteer, step.To)
+		case step.Vote != nil:
+			ok = c.RouteLeaderSetVoteMessage(step.From, *step.Vote, step.To)
+		case step.Level != nil:
+			ok = c.RouteLeaderSetLevelMessage(step.From, *step.Level, step.To)
+		}
+		if trace != nil {
+			*trace = append(*trace, fmt.Sprintf("%3d: %s", i, step.String()))
+		}
+		if !ok {
+			o.StepError = fmt.Sprintf("%d: %s, the message does not exist", i, step.String())
+			break
+		}
+	}
+
+	if s.Router && o.StepError == "" {
+		limit := s.Limit
+		if limit == 0 {
+			limit = 100
+		}
+		for i := 0; i < limit; i++ {
+			if c.Router.Step() {
+				break
+			}
+		}
+		if trace != nil {
+			*trace = append(*trace, "router:\n"+c.Router.Status())
+		}
+	}
+
+	for _, e := range c.Elections {
+		o.Committed = append(o.Committed, e.Committed)
+		if e.Committed {
+			o.Volunteers = append(o.Volunteers, e.CurrentVote.VolunteerPriority)
+		} else {
+			o.Volunteers = append(o.Volunteers, -1)
+		}
+	}
+	if trace != nil {
+		*trace = append(*trace, c.ElectionStatus(-1))
+	}
+	return o
+}
+
+// Minimize removes steps from a scenario that diverges, one at a time, as long as it still diverges
+// the same way.  It returns the scenario with the fewest steps that reproduces the divergence.
+func (s *ScenarioFile) Minimize() *ScenarioFile {
+	kind := func(steps []ScenarioStep) string {
+		d := s.Divergence(s.run(steps, nil))
+		return strings.SplitN(d, ":", 2)[0]
+	}
+
+	want := kind(s.Steps)
+	if want == "" {
+		return s
+	}
+	steps := append([]ScenarioStep{}, s.Steps...)
+	for removed := true; removed; {
+		removed = false
+		for i := len(steps) - 1; i >= 0; i-- {
+			shorter := append(append([]ScenarioStep{}, steps[:i]...), steps[i+1:]...)
+			if kind(shorter) == want {
+				steps = shorter
+				removed = true
+			}
+		}
+	}
+
+	m := *s
+	m.Steps = steps
+	return &m
+}
+
+// Trace plays the scenario and returns every step it delivered, the router status and the final
+// state of the election
+func (s *ScenarioFile) Trace() string {
+	var trace []string
+	o := s.run(s.Steps, &trace)
+	if d := s.Divergence(o); d != "" {
+		trace = append(trace, "diverged: "+d)
+	}
+	return strings.Join(trace, "\n")
+}
+
+// Check runs the scenario and, if it diverges from what it expects, returns an error with the
+// divergence and a trace of the smallest scenario that reproduces it
+func (s *ScenarioFile) Check() error {
+	d := s.Divergence(s.Run())
+	if d == "" {
+		return nil
+	}
+	m := s.Minimize()
+	data, _ := json.MarshalIndent(m, "", "  ")
+	return fmt.Errorf("scenario %q diverged: %s\nminimal scenario, %d of %d steps:\n%s\n%s",
+		s.Name, d, len(m.Steps), len(s.Steps), string(data), m.Trace())
+}
+
+func (step ScenarioStep) String() string {
+	switch {
+	case step.Volunteer != nil:
+		return fmt.Sprintf("volunteer %d to %v", *step.Volunteer, step.To)
+	case step.Vote != nil:
+		return fmt.Sprintf("votes for %d from %v to %v", *step.Vote, step.From, step.To)
+	case step.Level != nil:
+		return fmt.Sprintf("level %d from %v to %v", *step.Level, step.From, step.To)
+	}
+	return "empty step"
+}
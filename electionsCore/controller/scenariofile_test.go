@@ -0,0 +1,100 @@
+package controller_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/FactomProject/factomd/electionsCore/controller"
+)
+
+func TestScenarioFiles(t *testing.T) {
+	runScenarioFiles(t, "testdata/*.json")
+}
+
+// runScenarioFiles checks every scenario file matching the pattern as a subtest, failing the ones
+// that diverge
+func runScenarioFiles(t *testing.T, pattern string) {
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("No scenario files match %s", pattern)
+	}
+	for _, file := range files {
+		file := file
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			s, err := LoadScenarioFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Check(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestParseScenario(t *testing.T) {
+	bad := []string{
+		`{"feds": 0, "audits": 1}`,
+		`{"feds": 3, "audits": 3, "steps": [{"to": [0]}]}`,
+		`{"feds": 3, "audits": 3, "steps": [{"volunteer": 1, "vote": 1, "to": [0]}]}`,
+		`{"feds": 3, "audits": 3, "steps": [{"volunteer": 3, "to": [0]}]}`,
+		`{"feds": 3, "audits": 3, "steps": [{"volunteer": 1, "to": [3]}]}`,
+		`{"feds": 3, "audits": 3, "drops": [{"from": 0, "to": -1}]}`,
+	}
+	for _, data := range bad {
+		if _, err := ParseScenario([]byte(data)); err == nil {
+			t.Errorf("Expected an error parsing %s", data)
+		}
+	}
+}
+
+func TestScenarioDivergence(t *testing.T) {
+	// Everyone agrees, but the scenario expects the election to stall.  The volunteer alone does
+	// not finish the election, so it is the only step left in the minimal scenario.
+	s, err := ParseScenario([]byte(`{
+		"name": "wrong expectation",
+		"feds": 3, "audits": 3,
+		"router": true,
+		"steps": [
+			{ "volunteer": 1, "to": [0, 1, 2] },
+			{ "vote": 1, "from": [0, 1, 2], "to": [0, 1, 2] },
+			{ "level": 1, "from": [0, 1, 2], "to": [0, 1, 2] }
+		],
+		"expect": { "complete": false }
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := s.Divergence(s.Run()); !strings.HasPrefix(d, "complete:") {
+		t.Errorf("Expected the scenario to diverge on completion, found %q", d)
+	}
+	if err := s.Check(); err == nil {
+		t.Errorf("Expected the check to fail")
+	}
+
+	m := s.Minimize()
+	if len(m.Steps) >= len(s.Steps) {
+		t.Errorf("Expected fewer than %d steps, found %d", len(s.Steps), len(m.Steps))
+	}
+	if d := m.Divergence(m.Run()); !strings.HasPrefix(d, "complete:") {
+		t.Errorf("Expected the minimal scenario to diverge the same way, found %q", d)
+	}
+
+	// No leader commits to the volunteer the scenario expects
+	steps := s.Steps
+	s.Expect.Complete, s.Expect.Volunteer = false, new(int)
+	s.Steps = steps[:1]
+	if d := s.Divergence(s.Run()); d != "volunteer: expected 0, leaders committed to none" {
+		t.Errorf("Expected the scenario to diverge on the volunteer, found %q", d)
+	}
+
+	// A vote cannot be delivered before the volunteer it is for
+	s.Steps = steps[1:]
+	if d := s.Divergence(s.Run()); !strings.HasPrefix(d, "step:") {
+		t.Errorf("Expected a step error, found %q", d)
+	}
+}
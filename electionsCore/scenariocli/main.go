@@ -0,0 +1,46 @@
+// scenariocli runs declarative election scenario files against the election controller and
+// reports the ones that do not reach the outcome they expect, with the smallest trace that
+// reproduces the divergence.
+//
+//	scenariocli [-trace] scenario.json...
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/FactomProject/factomd/electionsCore/controller"
+)
+
+func main() {
+	trace := flag.Bool("trace", false, "print the trace of every scenario, not only the ones that diverge")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: scenariocli [-trace] scenario.json...")
+		os.Exit(2)
+	}
+
+	failed := 0
+	for _, file := range flag.Args() {
+		s, err := controller.LoadScenarioFile(file)
+		if err != nil {
+			fmt.Printf("FAIL %s: %v\n", file, err)
+			failed++
+			continue
+		}
+		if *trace {
+			fmt.Println(s.Trace())
+		}
+		if err := s.Check(); err != nil {
+			fmt.Printf("FAIL %s: %v\n", file, err)
+			failed++
+			continue
+		}
+		fmt.Printf("ok   %s\n", file)
+	}
+	if failed > 0 {
+		fmt.Printf("%d of %d scenarios failed\n", failed, flag.NArg())
+		os.Exit(1)
+	}
+}
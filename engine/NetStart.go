@@ -11,10 +11,12 @@ import (
 	"fmt"
 	"io/ioutil"
 	"math"
+	"math/rand"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/FactomProject/factomd/activations"
 	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/constants/runstate"
 	. "github.com/FactomProject/factomd/common/globals"
@@ -250,6 +252,20 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 
 	go StartProfiler(p.MemProfileRate, p.ExposeProfiling)
 
+	if p.Journaling || p.Journal != "" {
+		// Seed the random number generators so a replay of the journal makes the same choices
+		seed := time.Now().UnixNano()
+		if p.Journal != "" {
+			if js, ok := ReadJournalSeed(p.Journal); ok {
+				seed = js
+				journalTicks = true
+			}
+		}
+		rand.Seed(seed)
+		p2p.RandomSeed = seed
+		s.JournalSeed = seed
+	}
+
 	s.AddPrefix(p.Prefix)
 	s.SetOut(false)
 	s.Init()
@@ -289,6 +305,7 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 	os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "net spec", pnet))
 	os.Stderr.WriteString(fmt.Sprintf("%20s %d\n", "Msgs droped", p.DropRate))
 	os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "journal", p.Journal))
+	os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "journalstop", p.JournalStop))
 	os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "database", p.Db))
 	os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "database for clones", p.CloneDB))
 	os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "peers", p.Peers))
@@ -387,6 +404,13 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 		panic("Invalid Network choice in Config File or command line. Choose MAIN, TEST, LOCAL, or CUSTOM")
 	}
 
+	if err := loadCustomActivations(s.Cfg.(*util.FactomdConfig), s.Network); err != nil {
+		panic(err.Error())
+	}
+	if err := loadGrantFile(s.Cfg.(*util.FactomdConfig), s.GetNetworkID()); err != nil {
+		panic(err.Error())
+	}
+
 	connectionMetricsChannel := make(chan interface{}, p2p.StandardChannelSize)
 	p2p.NetworkDeadline = time.Duration(p.Deadline) * time.Millisecond
 
@@ -446,6 +470,11 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 				AddSimPeer(fnodes, a, b)
 			}
 		}
+	case "memory":
+		StartMemoryNetwork(networkID, networkPort, s.JournalSeed)
+		for i := range fnodes {
+			AddMemoryNetworkPeer(fnodes, i)
+		}
 	case "square":
 		side := int(math.Sqrt(float64(p.Cnt)))
 
@@ -529,7 +558,7 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 			}
 		}
 	default:
-		fmt.Println("Didn't understand network type. Known types: mesh, long, circles, tree, loops.  Using a Long Network")
+		fmt.Println("Didn't understand network type. Known types: mesh, long, circles, tree, loops, memory.  Using a Long Network")
 		for i := 1; i < p.Cnt; i++ {
 			AddSimPeer(fnodes, i-1, i)
 		}
@@ -602,6 +631,10 @@ func NetStart(s *state.State, p *FactomParams, listenToStdin bool) {
 
 	go controlPanel.ServeControlPanel(fnodes[0].State.ControlPanelChannel, fnodes[0].State, connectionMetricsChannel, p2pNetwork, Build, p.NodeName)
 
+	// Only a simulation can be controlled through the debug API
+	if p.Cnt > 1 || p.SimAPI {
+		wsapi.SetSimController(simController{})
+	}
 	go SimControl(p.ListenTo, listenToStdin)
 
 }
@@ -659,7 +692,9 @@ func startServer(i int, fnode *FactomNode, load bool) {
 		go state.LoadDatabase(fnode.State)
 	}
 	go fnode.State.GoSyncEntries()
-	go Timer(fnode.State)
+	if i > 0 || !journalTicks { // a replayed journal provides the ticks
+		go Timer(fnode.State)
+	}
 	go elections.Run(fnode.State)
 	go fnode.State.ValidatorLoop()
 
@@ -697,6 +732,68 @@ func AddNode() {
 	fnodes = GetFnodes()
 	fnodes[i].State.IntiateNetworkSkeletonIdentity()
 	fnodes[i].State.InitiateNetworkIdentityRegistration()
-	AddSimPeer(fnodes, i, i-1) // KLUDGE peer w/ only last node
+	if networkpattern == "memory" {
+		AddMemoryNetworkPeer(fnodes, i)
+	} else {
+		AddSimPeer(fnodes, i, i-1) // KLUDGE peer w/ only last node
+	}
 	startServer(i, fnodes[i], true)
 }
+
+// loadCustomActivations schedules the activations given in the config file.  Only custom
+// networks may schedule their own activations.
+func loadCustomActivations(config *util.FactomdConfig, network string) error {
+	list, file := config.App.CustomActivations, config.App.CustomActivationsFile
+	if list == "" && file == "" {
+		return nil
+	}
+	if strings.ToUpper(network) != "CUSTOM" {
+		return fmt.Errorf("CustomActivations can only be used on a CUSTOM network, not %s", network)
+	}
+
+	heights := make(map[activations.ActivationType]int)
+	if file != "" {
+		fromFile, err := activations.LoadActivationHeights(file)
+		if err != nil {
+			return err
+		}
+		for id, h := range fromFile {
+			heights[id] = h
+		}
+	}
+	if list != "" { // The list in the config file overrides the file
+		fromList, err := activations.ParseActivationHeights(list)
+		if err != nil {
+			return err
+		}
+		for id, h := range fromList {
+			heights[id] = h
+		}
+	}
+
+	netName := activations.NetworkName()
+	if err := activations.SetActivationHeights(netName, heights); err != nil {
+		return err
+	}
+	for id, h := range heights {
+		fmt.Printf("Activation %s scheduled at height %d on %s\n", id.String(), h, netName)
+	}
+	return nil
+}
+
+// loadGrantFile adds the grants of the grant file in the config file, signed for the network, to
+// the hard coded grants
+func loadGrantFile(config *util.FactomdConfig, networkID uint32) error {
+	if config.App.GrantFile == "" {
+		return nil
+	}
+	grants, err := state.ReadGrantFile(config.App.GrantFile, config.App.GrantFilePublicKeys, networkID)
+	if err != nil {
+		return err
+	}
+	if err := state.SetFileGrants(grants); err != nil {
+		return err
+	}
+	fmt.Printf("Loaded %d grants from %s\n", len(grants), config.App.GrantFile)
+	return nil
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/state"
 )
 
 var _ = fmt.Print
@@ -349,18 +350,21 @@ func sendToExecute(msg interfaces.IMsg, fnode *FactomNode, source string) {
 func Q1(fnode *FactomNode, source string, msg interfaces.IMsg) {
 	fnode.State.LogMessage("NetworkInputs", source+", enqueue", msg)
 	fnode.State.LogMessage("InMsgQueue", source+", enqueue", msg)
+	fnode.State.JournalArrival(state.JournalInMsgQueue, msg)
 	fnode.State.InMsgQueue().Enqueue(msg)
 }
 
 func Q2(fnode *FactomNode, source string, msg interfaces.IMsg) {
 	fnode.State.LogMessage("NetworkInputs", source+", enqueue2", msg)
 	fnode.State.LogMessage("InMsgQueue2", source+", enqueue2", msg)
+	fnode.State.JournalArrival(state.JournalInMsgQueue2, msg)
 	fnode.State.InMsgQueue2().Enqueue(msg)
 }
 
 func DataQ(fnode *FactomNode, source string, msg interfaces.IMsg) {
 	q := fnode.State.DataMsgQueue()
 	fnode.State.LogMessage("DataQueue", fmt.Sprintf(source+", enqueue %v", len(q)), msg)
+	fnode.State.JournalArrival(state.JournalDataMsgQueue, msg)
 	q <- msg
 }
 
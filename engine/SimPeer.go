@@ -7,6 +7,7 @@ package engine
 import (
 	"bytes"
 	"fmt"
+	"sync"
 	"time"
 
 	"math/rand"
@@ -37,6 +38,11 @@ type SimPeer struct {
 	// Were we hold delayed packets
 	Delayed *SimPacket
 
+	// Injected faults, see simFaults.go
+	faultMutex  sync.Mutex
+	Faults      LinkFaults // Latency, reordering and duplication on this link
+	Partitioned bool       // Messages on this link are lost
+
 	bytesOut int // Bytes sent out
 	bytesIn  int // Bytes received
 
@@ -48,6 +54,9 @@ type SimPeer struct {
 
 var _ interfaces.IPeer = (*SimPeer)(nil)
 
+// MaxReorderHold is how long the reorder fault holds a packet back waiting for the next one
+const MaxReorderHold = 200 * time.Millisecond
+
 // Bytes sent out per second from this peer
 func (f *SimPeer) BytesOut() int {
 	return f.RateOut
@@ -123,13 +132,58 @@ func (f *SimPeer) Send(msg interfaces.IMsg) error {
 		return err
 	}
 
+	f.faultMutex.Lock()
+	faults := f.Faults
+	if f.Partitioned {
+		f.faultMutex.Unlock()
+		return nil // Lost, the other side is unreachable
+	}
+	packet := &SimPacket{data: data}
+	if faults.Reorder > 0 && f.Delayed == nil && rand.Intn(1000) < faults.Reorder {
+		// Hold this packet back, it goes out after the next one, or on its own if nothing
+		// follows it in time
+		f.Delayed = packet
+		f.faultMutex.Unlock()
+		time.AfterFunc(MaxReorderHold+time.Duration(faults.Latency)*time.Millisecond, func() {
+			f.faultMutex.Lock()
+			flush := f.Delayed == packet
+			if flush {
+				f.Delayed = nil
+			}
+			f.faultMutex.Unlock()
+			if flush {
+				packet.sent = time.Now().UnixNano() / 1000000
+				f.BroadcastOut <- packet
+			}
+		})
+		return nil
+	}
+	held := f.Delayed
+	f.Delayed = nil
+	f.faultMutex.Unlock()
+
+	copies := 1
+	if faults.Duplicate > 0 && rand.Intn(1000) < faults.Duplicate {
+		copies = 2
+	}
+
 	go func() {
+		delay := faults.Latency
 		if f.Delay > 0 {
 			// Sleep some random number of milliseconds, then send the packet
-			time.Sleep(time.Duration(rand.Intn(int(f.Delay))) * time.Millisecond)
+			delay += int64(rand.Intn(int(f.Delay)))
+		}
+		if faults.Jitter > 0 {
+			delay += rand.Int63n(faults.Jitter)
+		}
+		time.Sleep(time.Duration(delay) * time.Millisecond)
+		for i := 0; i < copies; i++ {
+			f.BroadcastOut <- &SimPacket{data: packet.data, sent: time.Now().UnixNano() / 1000000}
+		}
+		if held != nil {
+			held.sent = time.Now().UnixNano() / 1000000
+			f.BroadcastOut <- held
 		}
-		packet := SimPacket{data: data, sent: time.Now().UnixNano() / 1000000}
-		f.BroadcastOut <- &packet
 	}()
 
 	return nil
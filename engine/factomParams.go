@@ -30,6 +30,7 @@ func init() {
 	flag.IntVar(&p.FaultTimeout, "faulttimeout", 120, "Seconds before considering Federated servers at-fault. Default is 120.")
 	flag.IntVar(&p.RoundTimeout, "roundtimeout", 30, "Seconds before audit servers will increment rounds and volunteer.")
 	flag.IntVar(&p2p.NumberPeersToBroadcast, "broadcastnum", 16, "Number of peers to broadcast to in the peer to peer networking")
+	flag.BoolVar(&p2p.CompressionEnabled, "p2pcompression", true, "Compress large messages sent to peers that support it")
 	flag.IntVar(&p.P2PIncoming, "p2pIncoming", 0, "Override the maximum number of other peers dialing into this node that will be accepted; default 200")
 	flag.IntVar(&p.P2POutgoing, "p2pOutgoing", 0, "Override the maximum number of peers this node will attempt to dial into; default 32")
 	flag.StringVar(&p.ConfigPath, "config", "", "Override the config file location (factomd.conf)")
@@ -45,6 +46,7 @@ func init() {
 	flag.IntVar(&p.DropRate, "drop", 0, "Number of messages to drop out of every thousand")
 	flag.StringVar(&p.Journal, "journal", "", "Rerun a Journal of messages")
 	flag.BoolVar(&p.Journaling, "journaling", false, "Write a journal of all messages received. Default is off.")
+	flag.StringVar(&p.JournalStop, "journalstop", "", "Stop replaying a journal at height:minute and write the state next to the journal")
 	flag.BoolVar(&p.Follower, "follower", false, "If true, force node to be a follower.  Only used when replaying a journal.")
 	flag.BoolVar(&p.Leader, "leader", true, "If true, force node to be a leader.  Only used when replaying a journal.")
 	flag.StringVar(&p.Db, "db", "", "Override the Database in the Config file and use this Database implementation. Options Map, LDB, or Bolt")
@@ -75,8 +77,9 @@ func init() {
 	flag.IntVar(&p.FastSaveRate, "fastsaverate", 1000, "Save a fastboot file every so many blocks. Should be > 1000 for live systems.")
 	flag.StringVar(&p.FastLocation, "fastlocation", "", "Directory to put the Fast-boot file in.")
 	flag.StringVar(&p.Loglvl, "loglvl", "none", "Set log level to either: none, debug, info, warning, error, fatal or panic")
-	flag.BoolVar(&p.Logjson, "logjson", false, "Use to set logging to use a json formatting")
+	flag.BoolVar(&p.Logjson, "logjson", false, "Use to set logging to use a json formatting, for the -debuglog logs too")
 	flag.BoolVar(&p.Sim_Stdin, "sim_stdin", true, "If true, sim control reads from stdin.")
+	flag.BoolVar(&p.SimAPI, "simapi", false, "If true, serve the sim and network debug API methods even with a single node. Always on with -count > 1.")
 	// Plugins
 	flag.StringVar(&p.PluginPath, "plugin", "", "Input the path to any plugin binaries")
 	// 	Torrent Plugin
@@ -7,16 +7,58 @@ package engine
 import (
 	"bufio"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/globals"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages/msgsupport"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/state"
 )
 
+// journalTicks is set when the first node replays a journal of records, which holds the ticks of its Timer
+var journalTicks bool
+
+// ReadJournalSeed returns the random seed a journal was recorded with.  It is false if the journal
+// does not start with a seed record, as journals of MsgHex lines don't.
+func ReadJournalSeed(journal string) (int64, bool) {
+	f, err := os.Open(journal)
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+
+	line, _ := bufio.NewReader(f).ReadBytes('\n')
+	rec := new(state.JournalRecord)
+	if err := json.Unmarshal(line, rec); err != nil || rec.Kind != state.JournalKindSeed {
+		return 0, false
+	}
+	return rec.Seed, true
+}
+
+// ParseJournalStop parses the height:minute to stop a replay at.  The minute is optional.
+func ParseJournalStop(stop string) (height uint32, minute int, err error) {
+	parts := strings.SplitN(stop, ":", 2)
+	h, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("bad journal stop %q, expected height:minute", stop)
+	}
+	if len(parts) == 2 {
+		minute, err = strconv.Atoi(parts[1])
+		if err != nil || minute < 0 || minute > 10 {
+			return 0, 0, fmt.Errorf("bad journal stop %q, the minute must be 0 to 10", stop)
+		}
+	}
+	return uint32(h), minute, nil
+}
+
 func LoadJournal(s interfaces.IState, journal string) {
 	f, err := os.Open(journal)
 	if err != nil {
@@ -39,6 +81,18 @@ func LoadJournalFromReader(s interfaces.IState, r *bufio.Reader) {
 	s.SetIsReplaying()
 	defer s.SetIsDoneReplaying()
 
+	stop := globals.Params.JournalStop
+	var stopHeight uint32
+	var stopMinute int
+	if stop != "" {
+		var err error
+		stopHeight, stopMinute, err = ParseJournalStop(stop)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+
 	fmt.Println("Replaying Journal")
 	time.Sleep(time.Second * 5)
 	fmt.Println("GO!")
@@ -54,6 +108,31 @@ func LoadJournalFromReader(s interfaces.IState, r *bufio.Reader) {
 			break
 		}
 
+		// Records of the journal are replayed one at a time, each waits for the state to take the last
+		if line[0] == '{' {
+			if !s.GetNetStateOff() {
+				s.SetNetStateOff(true) // the journal holds all the node hears, the network must not add to it
+			}
+			rec := new(state.JournalRecord)
+			if err := json.Unmarshal(line, rec); err != nil {
+				fmt.Println(err)
+				return
+			}
+			if rec.Kind == "" {
+				continue // a message older versions journaled as JSON, which was never replayed
+			}
+			if err := ReplayJournalRecord(s, rec); err != nil {
+				fmt.Println(err)
+				return
+			}
+			p++
+			if stop != "" && journalStopReached(s, stopHeight, stopMinute) {
+				stopJournal(s, stopHeight, stopMinute)
+				return
+			}
+			continue
+		}
+
 		// Get the next word.  If not MsgHex:, then go to next line.
 		adv, word, err := bufio.ScanWords(line, true)
 		if string(word) != "MsgHex:" {
@@ -99,3 +178,74 @@ func LoadJournalFromReader(s interfaces.IState, r *bufio.Reader) {
 		time.Sleep(time.Millisecond * 100)
 	}
 }
+
+// ReplayJournalRecord feeds one record of a journal to a replaying state and waits until the state has
+// dealt with it, so the next record arrives after it as it did when it was recorded.  A state that
+// journals writes the record to its own journal again.
+func ReplayJournalRecord(s interfaces.IState, rec *state.JournalRecord) error {
+	fs := s.(*state.State)
+	if !fs.IsReplaying {
+		return fmt.Errorf("journal record %d: the node is not replaying a journal", rec.Seq)
+	}
+
+	switch rec.Kind {
+	case state.JournalKindSeed:
+		if fs.JournalSeed != rec.Seed {
+			fmt.Printf("Journal was recorded with the random seed %d, this run uses %d\n", rec.Seed, fs.JournalSeed)
+		}
+		return nil
+	case state.JournalKindTick:
+		fs.ReplayTimestamp = primitives.NewTimestampFromMilliseconds(uint64(rec.Time))
+		fs.TickerQueue() <- rec.Tick
+	case state.JournalKindMsg:
+		msg, err := rec.Message()
+		if err != nil {
+			return fmt.Errorf("journal record %d: %v", rec.Seq, err)
+		}
+		fs.ReplayTimestamp = primitives.NewTimestampFromMilliseconds(uint64(rec.Time))
+		fs.JournalArrival(rec.Queue, msg)
+		switch rec.Queue {
+		case state.JournalInMsgQueue:
+			fs.LogMessage("InMsgQueue", "journal, enqueue", msg)
+			fs.InMsgQueue().Enqueue(msg)
+		case state.JournalInMsgQueue2:
+			fs.LogMessage("InMsgQueue2", "journal, enqueue2", msg)
+			fs.InMsgQueue2().Enqueue(msg)
+		case state.JournalDataMsgQueue:
+			fs.LogMessage("DataQueue", "journal, enqueue", msg)
+			fs.DataMsgQueue() <- msg
+		default:
+			return fmt.Errorf("journal record %d has an unknown queue %d", rec.Seq, rec.Queue)
+		}
+		if constants.NeedsAck(msg.Type()) {
+			fs.RecentMessage.NewMsgs <- msg // as the network processor does, so MMR does not ask for it
+		}
+	default:
+		return fmt.Errorf("journal record %d has an unknown kind %q", rec.Seq, rec.Kind)
+	}
+
+	if !fs.WaitReplayIdle() {
+		return fmt.Errorf("journal record %d: the node stopped", rec.Seq)
+	}
+	return nil
+}
+
+func journalStopReached(s interfaces.IState, height uint32, minute int) bool {
+	h := s.GetLLeaderHeight()
+	return h > height || (h == height && s.GetCurrentMinute() >= minute)
+}
+
+// stopJournal writes the state where the replay stopped next to the journal
+func stopJournal(s interfaces.IState, height uint32, minute int) {
+	journal := globals.Params.Journal
+	if journal == "" {
+		journal = "journal"
+	}
+	filename := fmt.Sprintf("%s.%d.%d.state", journal, height, minute)
+	dump := state.DumpState(s.(*state.State)) + "\n" + s.(*state.State).ProcessLists.String()
+	if err := ioutil.WriteFile(filename, []byte(dump), 0666); err != nil {
+		fmt.Println("Could not write the state:", err)
+		return
+	}
+	fmt.Printf("Replay stopped at %d-:-%d, the state is in %s\n", s.GetLLeaderHeight(), s.GetCurrentMinute(), filename)
+}
@@ -0,0 +1,186 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factom"
+	. "github.com/FactomProject/factomd/engine"
+	"github.com/FactomProject/factomd/state"
+	. "github.com/FactomProject/factomd/testHelper"
+)
+
+func TestReadJournalSeed(t *testing.T) {
+	f, err := ioutil.TempFile("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString(`{"seq":1,"time":1500000000000,"kind":"seed","seed":42,"tick":0}` + "\n")
+	f.WriteString(`{"seq":2,"time":1500000000100,"kind":"tick","tick":-1}` + "\n")
+	f.Close()
+
+	seed, ok := ReadJournalSeed(f.Name())
+	if !ok || seed != 42 {
+		t.Errorf("expected seed 42, got %d %v", seed, ok)
+	}
+
+	if _, ok := ReadJournalSeed(f.Name() + ".missing"); ok {
+		t.Error("found a seed in a missing journal")
+	}
+}
+
+func TestParseJournalStop(t *testing.T) {
+	for _, tc := range []struct {
+		stop   string
+		height uint32
+		minute int
+		ok     bool
+	}{
+		{"10", 10, 0, true},
+		{"10:4", 10, 4, true},
+		{"10:11", 0, 0, false},
+		{"ten", 0, 0, false},
+		{"10:x", 0, 0, false},
+	} {
+		h, m, err := ParseJournalStop(tc.stop)
+		if (err == nil) != tc.ok || h != tc.height || m != tc.minute {
+			t.Errorf("%q: got %d:%d %v", tc.stop, h, m, err)
+		}
+	}
+}
+
+// journalingTestState starts a test node that journals to a new file
+func journalingTestState(t *testing.T) *state.State {
+	f, err := ioutil.TempFile("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	s := CreateAndPopulateTestStateAndStartValidator()
+	s.DBFinished = true
+	s.JournalFile = f.Name()
+	s.Journaling = true
+	return s
+}
+
+// waitForHeld waits for the API view of the messages a node holds, in the holding queue or waiting
+// on something like the balance of an account, to have count messages
+func waitForHeld(s *state.State, count int) map[[32]byte]bool {
+	held := make(map[[32]byte]bool)
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		held = make(map[[32]byte]bool)
+		for k := range s.LoadHoldingMap() {
+			held[k] = true
+		}
+		for k := range s.LoadDependentHoldingMap() {
+			held[k] = true
+		}
+		if len(held) >= count {
+			break
+		}
+	}
+	return held
+}
+
+func readJournal(t *testing.T, s *state.State) []*state.JournalRecord {
+	var records []*state.JournalRecord
+	for _, line := range s.GetJournalMessages() {
+		rec := new(state.JournalRecord)
+		if err := json.Unmarshal(line, rec); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestJournalRecordAndReplay(t *testing.T) {
+	recorded := journalingTestState(t)
+	defer os.Remove(recorded.JournalFile)
+
+	// Record messages as they arrive from the network, and ticks as the node takes them.  A node
+	// schedules retries of a tick by itself, a replay takes them from the journal instead.
+	a := AccountFromFctSecret("Fs2zQ3egq2j99j37aYzaCddPq9AF3mgh64uG9gRaDAnrkjRx3eHs")
+	for i := 0; i < 3; i++ {
+		e := factom.Entry{ChainID: "92475004e70f41b94750f4a77bf7b430551113b25d3d57169eadca5692bb043d", Content: []byte(fmt.Sprintf("replay %d", i))}
+		m, err := ComposeCommitEntryMsg(a.Priv, e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		recorded.JournalArrival(state.JournalInMsgQueue, m)
+		recorded.InMsgQueue().Enqueue(m)
+		recorded.TickerQueue() <- 0 // the last retry of a minute, which does not schedule another
+		for len(recorded.TickerQueue()) > 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	held := waitForHeld(recorded, 3)
+	if len(held) != 3 {
+		t.Fatalf("expected the recording node to hold the 3 unpaid commits, it holds %d", len(held))
+	}
+
+	// Replay the journal into a node that journals too
+	replayed := journalingTestState(t)
+	defer os.Remove(replayed.JournalFile)
+	replayed.SetIsReplaying()
+	records := readJournal(t, recorded)
+	if len(records) != 6 {
+		t.Fatalf("expected 6 journal records, got %d", len(records))
+	}
+	for _, rec := range records {
+		if err := ReplayJournalRecord(replayed, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if now := replayed.GetTimestamp().GetTimeMilli(); now != records[len(records)-1].Time {
+		t.Errorf("replaying node is at time %d, expected the time of the last record %d", now, records[len(records)-1].Time)
+	}
+
+	replayedHeld := waitForHeld(replayed, 3)
+	if len(replayedHeld) != len(held) {
+		t.Errorf("replaying node holds %d messages, the recording node %d", len(replayedHeld), len(held))
+	}
+	for k := range held {
+		if !replayedHeld[k] {
+			t.Errorf("replaying node does not hold %x", k)
+		}
+	}
+
+	again := readJournal(t, replayed)
+	if len(again) != len(records) {
+		t.Fatalf("replay journaled %d records, expected %d", len(again), len(records))
+	}
+	for i, rec := range records {
+		r := again[i]
+		if r.Seq != rec.Seq || r.Time != rec.Time || r.Kind != rec.Kind || r.Tick != rec.Tick || r.Queue != rec.Queue || r.MsgHex != rec.MsgHex {
+			t.Errorf("record %d replayed as %+v, recorded as %+v", i, r, rec)
+		}
+	}
+	replayed.SetIsDoneReplaying()
+}
+
+func TestLoadJournalSkipsOldJSON(t *testing.T) {
+	s := journalingTestState(t)
+	defer os.Remove(s.JournalFile)
+	s.Journaling = false
+
+	// A message older versions journaled as JSON, then a record
+	journal := `{"Type":3,"Message":{"timestamp":1500000000000}}` + "\n" +
+		`{"seq":2,"time":1500000000100,"kind":"tick","tick":-1}` + "\n"
+	LoadJournalFromString(s, journal)
+	if s.IsReplaying {
+		t.Error("node is still replaying")
+	}
+	if len(s.TickerQueue()) != 0 {
+		t.Error("the tick after the old line was not replayed")
+	}
+}
@@ -0,0 +1,458 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/FactomProject/factomd/common/constants/runstate"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/controlPanel"
+	"github.com/FactomProject/factomd/p2p"
+	"github.com/FactomProject/factomd/wsapi"
+)
+
+// The simulator control API.  The single letter commands of SimControl that change the simulation
+// call these, and the debug API reaches them through simController.  They return what they did
+// rather than printing it.
+
+var simMutex sync.Mutex // one simulator operation at a time
+
+// identityMutex is held by AddIdentities.  Writing identities to the blockchain goes through the
+// API of a node and takes a while, so it only holds simMutex while it reads or changes the pool.
+var identityMutex sync.Mutex
+
+// killedNodes are the nodes KillNode took off the network for good
+var killedNodes = make(map[int]bool)
+
+var initChainCost = 11 // entry credits to create the identity registration chain, only paid once
+
+// The logging options of SetSimLogging
+const (
+	SimLogConsensus   = "consensus"   // trace the consensus process on every node
+	SimLogTally       = "tally"       // message tallies of the node with the focus
+	SimLogFaults      = "faults"      // verbose fault output in the status
+	SimLogAuthorities = "authorities" // every authority set in the status
+	SimLogDeltas      = "deltas"      // authority set changes in the status
+)
+
+type simController struct{}
+
+var _ interfaces.ISimController = simController{}
+
+func (simController) Nodes() []interfaces.SimNode { return SimNodes() }
+func (simController) SelectNode(node int) (*interfaces.SimResult, error) {
+	return SelectNode(node)
+}
+func (simController) PromoteLeader(node int, newIdentity bool) (*interfaces.SimResult, error) {
+	return PromoteLeader(node, newIdentity)
+}
+func (simController) PromoteAudit(node int, newIdentity bool) (*interfaces.SimResult, error) {
+	return PromoteAudit(node, newIdentity)
+}
+func (simController) RemoveServer(node int, audit bool) (*interfaces.SimResult, error) {
+	return RemoveServer(node, audit)
+}
+func (simController) SetOffline(node int, offline bool) (*interfaces.SimResult, error) {
+	return SetNodeOffline(node, offline)
+}
+func (simController) KillNode(node int) (*interfaces.SimResult, error) {
+	return KillNode(node)
+}
+func (simController) AddIdentities(count int) (*interfaces.SimResult, error) {
+	return AddIdentities(count)
+}
+func (simController) SetDropRate(node int, rate int) (*interfaces.SimResult, error) {
+	return SetDropRate(node, rate)
+}
+func (simController) SetLogging(option string, on bool) (*interfaces.SimResult, error) {
+	return SetSimLogging(option, on)
+}
+func (simController) PartitionNodes(groups [][]int) (*interfaces.SimResult, error) {
+	if err := PartitionNodes(groups); err != nil {
+		return nil, err
+	}
+	return &interfaces.SimResult{Message: fmt.Sprintf("Partitioned the network into %v", groups)}, nil
+}
+func (simController) HealPartitions(height *uint32, minute int) (*interfaces.SimResult, error) {
+	if height == nil {
+		HealPartitions()
+		return &interfaces.SimResult{Message: "Healed all partitions"}, nil
+	}
+	if minute < 0 || minute > 9 {
+		return nil, fmt.Errorf("minute %d is not between 0 and 9", minute)
+	}
+	ScheduleHeal(*height, minute)
+	return &interfaces.SimResult{Message: fmt.Sprintf("Healing all partitions at %d-:-%d", *height, minute)}, nil
+}
+func (simController) SetLinkFaults(from int, to int, latency int64, jitter int64, reorder int, duplicate int) (*interfaces.SimResult, error) {
+	faults := LinkFaults{Latency: latency, Jitter: jitter, Reorder: reorder, Duplicate: duplicate}
+	if err := SetLinkFaults(from, to, faults); err != nil {
+		return nil, err
+	}
+	return &interfaces.SimResult{Message: fmt.Sprintf("Set %s between nodes %d and %d", faults, from, to)}, nil
+}
+func (simController) ClearNetworkFaults() (*interfaces.SimResult, error) {
+	ClearNetworkFaults()
+	return &interfaces.SimResult{Message: "Cleared all network faults"}, nil
+}
+
+func simNode(node int) (*FactomNode, error) {
+	if node < 0 || node >= len(fnodes) {
+		return nil, fmt.Errorf("there is no node %d", node)
+	}
+	return fnodes[node], nil
+}
+
+func simNodeStatus(node int) interfaces.SimNode {
+	s := fnodes[node].State
+	n := interfaces.SimNode{
+		Index:    node,
+		Name:     s.FactomNodeName,
+		Identity: s.IdentityChainID.String(),
+		Role:     "Follower",
+		Focus:    node == ListenTo,
+		Offline:  s.GetNetStateOff(),
+		Stopped:  killedNodes[node] || s.GetRunState() >= runstate.Stopping,
+		DropRate: s.DropRate,
+		Height:   s.LLeaderHeight,
+	}
+	if s.Leader {
+		n.Role = "Leader"
+	} else if pl := s.ProcessLists.Get(s.LLeaderHeight); pl != nil {
+		if audit, _ := pl.GetAuditServerIndexHash(s.IdentityChainID); audit {
+			n.Role = "Audit"
+		}
+	}
+	return n
+}
+
+func simResult(message string, nodes ...int) *interfaces.SimResult {
+	r := &interfaces.SimResult{Message: message}
+	for _, i := range nodes {
+		r.Nodes = append(r.Nodes, simNodeStatus(i))
+	}
+	return r
+}
+
+// SimNodes returns the state of every node in the simulation
+func SimNodes() []interfaces.SimNode {
+	simMutex.Lock()
+	defer simMutex.Unlock()
+	nodes := make([]interfaces.SimNode, 0, len(fnodes))
+	for i := range fnodes {
+		nodes = append(nodes, simNodeStatus(i))
+	}
+	return nodes
+}
+
+// SelectNode gives a node the focus.  The simulator commands act on it, and the control panel shows it.
+func SelectNode(node int) (*interfaces.SimResult, error) {
+	simMutex.Lock()
+	defer simMutex.Unlock()
+	f, err := simNode(node)
+	if err != nil {
+		return nil, err
+	}
+	ListenTo = node
+	connectionMetricsChannel := make(chan interface{}, p2p.StandardChannelSize)
+	go controlPanel.ServeControlPanel(f.State.ControlPanelChannel, f.State, connectionMetricsChannel, p2pNetwork, Build, "")
+	return simResult(fmt.Sprintf("Switching to Node %d", node), node), nil
+}
+
+// attachNextIdentity gives a node the next identity from the identity pool that is not taken
+func attachNextIdentity(f *FactomNode) (string, error) {
+	for i := range authKeyLibrary {
+		if authKeyLibrary[i].Taken {
+			continue
+		}
+		authKeyLibrary[i].Taken = true
+		f.State.IdentityChainID = authKeyLibrary[i].ChainID
+		key, pKey, _ := authKeyLookup(f.State.IdentityChainID)
+		f.State.LocalServerPrivKey = key
+		f.State.SimSetNewKeys(pKey)
+		return fmt.Sprintf("Identity of %s changed to [%s]\n", f.State.GetFactomNodeName(), authKeyLibrary[i].ChainID.String()[:10]), nil
+	}
+	return "", fmt.Errorf("ran out of identities, add more identities first")
+}
+
+// signAndEnqueue signs an add or remove server message with the key of the local network and
+// gives it to node f
+func signAndEnqueue(f *FactomNode, msg interfaces.IMsg) error {
+	priv, err := primitives.NewPrivateKeyFromHex(LOCAL_NET_PRIV_KEY)
+	if err != nil {
+		return err
+	}
+	err = msg.(interface{ Sign(interfaces.Signer) error }).Sign(priv)
+	if err != nil {
+		return err
+	}
+	f.State.InMsgQueue().Enqueue(msg)
+	return nil
+}
+
+// PromoteLeader asks the network to make a node a federated server.  With newIdentity a node that
+// is not already a federated server and does not have one of the preset identities first gets the
+// next free identity from the pool.
+func PromoteLeader(node int, newIdentity bool) (*interfaces.SimResult, error) {
+	return promoteLeader(node, node, newIdentity)
+}
+
+// promoteLeader is PromoteLeader with the message given to node via.  The l command gives it to
+// the node the simulator started with the focus.
+func promoteLeader(node int, via int, newIdentity bool) (*interfaces.SimResult, error) {
+	simMutex.Lock()
+	defer simMutex.Unlock()
+	f, err := simNode(node)
+	if err != nil {
+		return nil, err
+	}
+	v, err := simNode(via)
+	if err != nil {
+		return nil, err
+	}
+
+	message := ""
+	if newIdentity && f.State.IdentityChainID.String()[:6] != "888888" {
+		exists := false
+		for _, fed := range f.State.LeaderPL.FedServers {
+			if fed.GetChainID().IsSameAs(f.State.IdentityChainID) {
+				exists = true
+			}
+		}
+		if !exists {
+			message, err = attachNextIdentity(f)
+			if err != nil {
+				return nil, fmt.Errorf("did not make a leader, %v", err)
+			}
+		}
+	}
+
+	err = signAndEnqueue(v, messages.NewAddServerMsg(f.State, 0))
+	if err != nil {
+		return nil, fmt.Errorf("could not make a leader, %v", err)
+	}
+	message += fmt.Sprintf("Attempting to make %s a Leader", f.State.GetFactomNodeName())
+	return simResult(message, node), nil
+}
+
+// PromoteAudit asks the network to make a node an audit server.  With newIdentity the node first
+// gets the next free identity from the pool.
+func PromoteAudit(node int, newIdentity bool) (*interfaces.SimResult, error) {
+	simMutex.Lock()
+	defer simMutex.Unlock()
+	f, err := simNode(node)
+	if err != nil {
+		return nil, err
+	}
+
+	message := ""
+	if newIdentity {
+		message, err = attachNextIdentity(f)
+		if err != nil {
+			return nil, fmt.Errorf("did not make an audit server, %v", err)
+		}
+	}
+
+	err = signAndEnqueue(f, messages.NewAddServerMsg(f.State, 1))
+	if err != nil {
+		return nil, fmt.Errorf("could not make an audit server, %v", err)
+	}
+	message += fmt.Sprintf("Attempting to make %s a Audit Server", f.State.GetFactomNodeName())
+	return simResult(message, node), nil
+}
+
+// RemoveServer asks the network to remove a node as a federated server, or as an audit server
+func RemoveServer(node int, audit bool) (*interfaces.SimResult, error) {
+	return removeServer(node, node, audit)
+}
+
+// removeServer is RemoveServer with the message given to node via.  The z command gives it to the
+// node the simulator started with the focus.
+func removeServer(node int, via int, audit bool) (*interfaces.SimResult, error) {
+	simMutex.Lock()
+	defer simMutex.Unlock()
+	f, err := simNode(node)
+	if err != nil {
+		return nil, err
+	}
+	v, err := simNode(via)
+	if err != nil {
+		return nil, err
+	}
+
+	serverType := 0
+	if audit {
+		serverType = 1
+	}
+	err = signAndEnqueue(v, messages.NewRemoveServerMsg(f.State, f.State.IdentityChainID, serverType))
+	if err != nil {
+		return nil, fmt.Errorf("could not remove server, %v", err)
+	}
+	return simResult(fmt.Sprintf("Attempting to remove %s as a server", f.State.GetFactomNodeName()), node), nil
+}
+
+// SetNodeOffline turns the network of a node off, or back on.  The node keeps running, so it
+// catches up when it is back.
+func SetNodeOffline(node int, offline bool) (*interfaces.SimResult, error) {
+	simMutex.Lock()
+	defer simMutex.Unlock()
+	f, err := simNode(node)
+	if err != nil {
+		return nil, err
+	}
+
+	if killedNodes[node] {
+		return nil, fmt.Errorf("%s was killed", f.State.FactomNodeName)
+	}
+
+	f.State.SetNetStateOff(offline)
+	if offline {
+		return simResult("Take  "+f.State.FactomNodeName+" off the network", node), nil
+	}
+	return simResult("Bring "+f.State.FactomNodeName+" Back onto the network", node), nil
+}
+
+// KillNode takes a node off the network for good.  The node is not shut down, as closing its
+// database would pull it out from under its elections and network goroutines, which have no way
+// to be stopped.  Off the network the node is as good as dead.
+func KillNode(node int) (*interfaces.SimResult, error) {
+	simMutex.Lock()
+	defer simMutex.Unlock()
+	f, err := simNode(node)
+	if err != nil {
+		return nil, err
+	}
+	if killedNodes[node] || f.State.GetRunState() >= runstate.Stopping {
+		return nil, fmt.Errorf("%s is already stopped", f.State.FactomNodeName)
+	}
+
+	f.State.SetNetStateOff(true)
+	killedNodes[node] = true
+	return simResult("Killed "+f.State.FactomNodeName, node), nil
+}
+
+// AddIdentities moves count identities from the identity stack to the pool of this simulation,
+// writing their chains to the blockchain through the node with the focus.  A count of 0 only
+// readies the stack.
+func AddIdentities(count int) (*interfaces.SimResult, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("cannot add %d identities", count)
+	}
+	identityMutex.Lock()
+	defer identityMutex.Unlock()
+
+	simMutex.Lock()
+	f, err := simNode(ListenTo)
+	if err != nil {
+		simMutex.Unlock()
+		return nil, err
+	}
+	limitBuys = false
+	wsapiNode = ListenTo
+	wsapi.SetState(f.State)
+	setUp := nextAuthority == -1
+	if setUp {
+		authKeyLibrary = make([]hardCodedAuthority, 0)
+	}
+	simMutex.Unlock()
+
+	r := new(interfaces.SimResult)
+	if setUp {
+		setUpAuthorities(f.State, true)
+		r.Message = fmt.Sprintf("%d Authorities added to the stack and funds are in wallet\n", len(authStack))
+	}
+	if count == 0 {
+		r.Message += "Authorities are ready to be made"
+		return r, nil
+	}
+	if count > 100 {
+		r.Message += "You can only pop a max of 100 off the stack at a time\n"
+		count = 100
+	}
+
+	// Perfectly fund the identities
+	idcost := 13 + 15 + 1 // Cost for 1 ID : Root + Management + Register
+	need := (idcost * count) + initChainCost
+	FundWalletTOFF(f.State, 0, uint64(need)*f.State.GetFactoshisPerEC())
+	initChainCost = 0 // Init only happens once
+
+	auths, known, skipped, err := authorityToBlockchain(count, f.State)
+	simMutex.Lock()
+	authKeyLibrary = append(authKeyLibrary, known...)
+	simMutex.Unlock()
+	for _, a := range auths {
+		r.Identities = append(r.Identities, a.ChainID.String())
+	}
+	r.Message += fmt.Sprintf("=== %d Identities added to blockchain, %d remain in stack, %d skipped (Added by someone else) ===", len(auths), len(authStack), skipped)
+	if err != nil {
+		return r, fmt.Errorf("error making authorities, %v", err)
+	}
+	return r, nil
+}
+
+// SetDropRate sets how many out of every 1000 messages a node drops, or every node if node is -1
+func SetDropRate(node int, rate int) (*interfaces.SimResult, error) {
+	simMutex.Lock()
+	defer simMutex.Unlock()
+	if rate < 0 || rate > 999 {
+		return nil, fmt.Errorf("specify a drop rate between 0 and 1000")
+	}
+
+	nodes := []int{node}
+	if node == -1 {
+		nodes = nodes[:0]
+		for i := range fnodes {
+			nodes = append(nodes, i)
+		}
+	} else if _, err := simNode(node); err != nil {
+		return nil, err
+	}
+
+	r := new(interfaces.SimResult)
+	for _, i := range nodes {
+		fnodes[i].State.DropRate = rate
+		r.Message += fmt.Sprintf("Setting drop rate of %10s to %2d.%01d percent\n", fnodes[i].State.FactomNodeName, rate/10, rate%10)
+		r.Nodes = append(r.Nodes, simNodeStatus(i))
+	}
+	return r, nil
+}
+
+// SetSimLogging turns one of the logging options of the simulator on or off
+func SetSimLogging(option string, on bool) (*interfaces.SimResult, error) {
+	simMutex.Lock()
+	defer simMutex.Unlock()
+
+	state := "Off"
+	if on {
+		state = "On"
+	}
+	switch option {
+	case SimLogConsensus:
+		for _, f := range fnodes {
+			f.State.DebugConsensus = on
+		}
+	case SimLogTally:
+		f, err := simNode(ListenTo)
+		if err != nil {
+			return nil, err
+		}
+		f.State.MessageTally = on
+		return simResult(fmt.Sprintf("--Print Message Tallies %s--", state), ListenTo), nil
+	case SimLogFaults:
+		VerboseFaultOutput = on
+	case SimLogAuthorities:
+		VerboseAuthoritySet = on
+	case SimLogDeltas:
+		VerboseAuthorityDeltas = on
+	default:
+		return nil, fmt.Errorf("no logging option %q, use one of %s, %s, %s, %s or %s", option,
+			SimLogConsensus, SimLogTally, SimLogFaults, SimLogAuthorities, SimLogDeltas)
+	}
+	return simResult(fmt.Sprintf("--Logging of %s %s--", option, state)), nil
+}
@@ -118,7 +118,6 @@ func copyOver(st *state.State) {
 
 func setUpAuthorities(st *state.State, buildMain bool) []hardCodedAuthority {
 	authStack = make([]hardCodedAuthority, 0)
-	authKeyLibrary = make([]hardCodedAuthority, 0)
 	list := buildMessages()
 	if buildMain {
 		blank, _ := primitives.HexToHash("888888001750ede0eff4b05f0c3f557890b256450cabbb84cada937f9c258327")
@@ -168,15 +167,19 @@ func buildMainChain(port int) {
 	_, _ = v2Request(j, port)*/
 }
 
-func authorityToBlockchain(total int, st *state.State) ([]hardCodedAuthority, int, error) {
+// authorityToBlockchain writes total identities from the stack to the blockchain.  It returns the
+// identities it wrote, and those and the ones it found already written, in the order they belong
+// in the key library.
+func authorityToBlockchain(total int, st *state.State) ([]hardCodedAuthority, []hardCodedAuthority, int, error) {
 	madeAuths := make([]hardCodedAuthority, 0)
+	known := make([]hardCodedAuthority, 0)
 	skipped := 0
 	sec, _ := hex.DecodeString(ecSec)
 	ec, _ := factom.MakeECAddress(sec[:32])
 	//for index, ele := range list {
 	for count := 0; count < total; count++ {
 		if len(authStack) == 0 {
-			return madeAuths, skipped, errors.New("No hardcoded authorities remain")
+			return madeAuths, known, skipped, errors.New("No hardcoded authorities remain")
 		}
 		ele := authStack[0]
 		authStack = authStack[1:]
@@ -184,7 +187,7 @@ func authorityToBlockchain(total int, st *state.State) ([]hardCodedAuthority, in
 		if existsEB != nil {
 			skipped++
 			count--
-			authKeyLibrary = append(authKeyLibrary, ele)
+			known = append(known, ele)
 			continue
 		}
 
@@ -192,7 +195,7 @@ func authorityToBlockchain(total int, st *state.State) ([]hardCodedAuthority, in
 		if existsEB != nil {
 			skipped++
 			count--
-			authKeyLibrary = append(authKeyLibrary, ele)
+			known = append(known, ele)
 			continue
 		}
 
@@ -200,7 +203,7 @@ func authorityToBlockchain(total int, st *state.State) ([]hardCodedAuthority, in
 		if exists != nil {
 			skipped++
 			count--
-			authKeyLibrary = append(authKeyLibrary, ele)
+			known = append(known, ele)
 			continue
 		}
 
@@ -365,9 +368,9 @@ func authorityToBlockchain(total int, st *state.State) ([]hardCodedAuthority, in
 		//_, _ = wsapi.HandleV2Request(st, j)
 
 		madeAuths = append(madeAuths, ele)
-		authKeyLibrary = append(authKeyLibrary, ele)
+		known = append(known, ele)
 	}
-	return madeAuths, skipped, nil
+	return madeAuths, known, skipped, nil
 }
 
 func makeBlockKey(ele hardCodedAuthority, ec *factom.ECAddress, random bool) (string, string, string, *factom.Entry) {
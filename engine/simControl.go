@@ -24,9 +24,7 @@ import (
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
-	"github.com/FactomProject/factomd/controlPanel"
 	elections2 "github.com/FactomProject/factomd/elections"
-	"github.com/FactomProject/factomd/p2p"
 	"github.com/FactomProject/factomd/wsapi"
 )
 
@@ -100,7 +98,6 @@ func SimControl(listenTo int, listenStdin bool) {
 	var faulting bool
 	var cancelheight int = -1
 	var cancelindex int = -1
-	var initchainCost = 11
 
 	ListenTo = listenTo
 
@@ -133,11 +130,7 @@ func SimControl(listenTo int, listenStdin bool) {
 
 		v, err := strconv.Atoi(string(b))
 		if err == nil && v >= 0 && v < len(fnodes) && fnodes[ListenTo].State != nil {
-			ListenTo = v
-			os.Stderr.WriteString(fmt.Sprintf("Switching to Node %d\n", ListenTo))
-			// Update which node will be displayed on the controlPanel page
-			connectionMetricsChannel := make(chan interface{}, p2p.StandardChannelSize)
-			go controlPanel.ServeControlPanel(fnodes[ListenTo].State.ControlPanelChannel, fnodes[ListenTo].State, connectionMetricsChannel, p2pNetwork, Build, "")
+			printSimResult(SelectNode(v))
 		} else {
 			switch {
 			case '!' == b[0]:
@@ -172,53 +165,19 @@ func SimControl(listenTo int, listenStdin bool) {
 						break
 					}
 				}
-				if ListenTo < 0 || ListenTo > len(fnodes) {
-					break
-				}
-				wsapiNode = ListenTo
-				wsapi.SetState(fnodes[wsapiNode].State)
-
-				if nextAuthority == -1 {
-					//err, _ := FundWallet(fnodes[wsapiNode].State, 2e7)
-					//if err != nil {
-					//	os.Stderr.WriteString(fmt.Sprintf("Error in funding the wallet, %s\n", err.Error()))
-					//	break
-					//}
-					setUpAuthorities(fnodes[wsapiNode].State, true)
-					os.Stderr.WriteString(fmt.Sprintf("%d Authorities added to the stack and funds are in wallet\n", len(authStack)))
-				}
-				if len(b) == 1 {
-					os.Stderr.WriteString(fmt.Sprint("Authorities are ready to be made. 'gN' where N is the number to be made.\n"))
-				}
+				count := 0
 				if len(b) > 1 {
-					count, err := strconv.Atoi(b[1:])
+					count, err = strconv.Atoi(b[1:])
 					if err != nil {
 						os.Stderr.WriteString(fmt.Sprintf("Error in input bN, %s\n", err.Error()))
-					} else {
-						if count > 100 {
-							os.Stderr.WriteString(fmt.Sprint("You can only pop a max of 100 off the stack at a time."))
-							count = 100
-						}
-						//err := fundWallet(fnodes[wsapiNode].State, uint64(count*5e7))
-						//if err != nil {
-						//	os.Stderr.WriteString(fmt.Sprintf("Error in funding the wallet, %s\n", err.Error()))
-						//	break
-						//}
-
-						// Perfectly fund the g command
-						idcost := 13 + 15 + 1 // Cost for 1 ID : Root + Management + Register
-						need := (idcost * count) + initchainCost
-						FundWalletTOFF(fnodes[wsapiNode].State, 0, uint64(need)*fnodes[wsapiNode].State.GetFactoshisPerEC())
-
-						initchainCost = 0 // Init only happens once. We set to 0 to not count it again
-						auths, skipped, err := authorityToBlockchain(count, fnodes[wsapiNode].State)
-						if err != nil {
-							os.Stderr.WriteString(fmt.Sprintf("Error making authorities, %s\n", err.Error()))
-						}
-						os.Stderr.WriteString(fmt.Sprintf("=== %d Identities added to blockchain, %d remain in stack, %d skipped (Added by someone else) ===\n", len(auths), len(authStack), skipped))
-						for _, ele := range auths {
-							fmt.Println(ele.ChainID.String())
-						}
+						break
+					}
+				}
+				r, err := AddIdentities(count)
+				printSimResult(r, err)
+				if r != nil {
+					for _, id := range r.Identities {
+						fmt.Println(id)
 					}
 				}
 			case '/' == b[0]:
@@ -486,13 +445,7 @@ func SimControl(listenTo int, listenStdin bool) {
 						os.Stderr.WriteString(fmt.Sprintf("Setting FaultWait of %10s to %d\n", fn.State.FactomNodeName, nnn))
 					}
 				} else {
-					if VerboseFaultOutput {
-						VerboseFaultOutput = false
-						os.Stderr.WriteString("Vnnn          Set full fault timeout to the given number of seconds. Helps debugging.\n")
-					} else {
-						VerboseFaultOutput = true
-						os.Stderr.WriteString("--VerboseFaultOutput On--\n")
-					}
+					printSimResult(SetSimLogging(SimLogFaults, !VerboseFaultOutput))
 				}
 			case 'V' == b[0]:
 				if len(b) == 1 {
@@ -524,24 +477,12 @@ func SimControl(listenTo int, listenStdin bool) {
 				}
 
 				if b[1] == 'l' || b[1] == 'L' {
-					if VerboseAuthoritySet {
-						VerboseAuthoritySet = false
-						os.Stderr.WriteString("--VerboseAuthoritySet Off--\n")
-					} else {
-						VerboseAuthoritySet = true
-						os.Stderr.WriteString("--VerboseAuthoritySet On--\n")
-					}
+					printSimResult(SetSimLogging(SimLogAuthorities, !VerboseAuthoritySet))
 					break
 				}
 
 				if b[1] == 'd' || b[1] == 'D' {
-					if VerboseAuthorityDeltas {
-						VerboseAuthorityDeltas = false
-						os.Stderr.WriteString("--VerboseAuthorityDeltas Off--\n")
-					} else {
-						VerboseAuthorityDeltas = true
-						os.Stderr.WriteString("--VerboseAuthorityDeltas On--\n")
-					}
+					printSimResult(SetSimLogging(SimLogDeltas, !VerboseAuthorityDeltas))
 					break
 				}
 
@@ -640,14 +581,8 @@ func SimControl(listenTo int, listenStdin bool) {
 			case 'x' == b[0]:
 
 				if ListenTo >= 0 && ListenTo < len(fnodes) {
-					f := fnodes[ListenTo]
-					v := f.State.GetNetStateOff() // Toggle his network on/off state
-					if v {
-						os.Stderr.WriteString("Bring " + f.State.FactomNodeName + " Back onto the network\n")
-					} else {
-						os.Stderr.WriteString("Take  " + f.State.FactomNodeName + " off the network\n")
-					}
-					f.State.SetNetStateOff(!v)
+					// Toggle his network on/off state
+					printSimResult(SetNodeOffline(ListenTo, !fnodes[ListenTo].State.GetNetStateOff()))
 
 					// Advance to the next node. Makes taking a number of nodes off or on line easier
 					fnodes[ListenTo].State.SetOut(false)
@@ -707,111 +642,30 @@ func SimControl(listenTo int, listenStdin bool) {
 					os.Stderr.WriteString("--Print Messages Off--\n")
 				}
 			case 'M' == b[0]:
-				if !fnodes[ListenTo].State.MessageTally {
-					os.Stderr.WriteString("--Print Message Tallies On--\n")
-					fnodes[ListenTo].State.MessageTally = true
-				} else {
-					os.Stderr.WriteString("--Print Message Tallies Off--\n")
-					fnodes[ListenTo].State.MessageTally = false
-				}
+				printSimResult(SetSimLogging(SimLogTally, !fnodes[ListenTo].State.MessageTally))
 			case 'z' == b[0]: // Add Audit server, Remove server, and Add Leader fall through to 'n', switch to next node.
-				var msg interfaces.IMsg
-				if len(b) > 1 && b[1] == 'a' {
-					msg = messages.NewRemoveServerMsg(fnodes[ListenTo].State, fnodes[ListenTo].State.IdentityChainID, 1)
-				} else {
-					msg = messages.NewRemoveServerMsg(fnodes[ListenTo].State, fnodes[ListenTo].State.IdentityChainID, 0)
-				}
-
-				priv, err := primitives.NewPrivateKeyFromHex(LOCAL_NET_PRIV_KEY)
-				if err != nil {
-					os.Stderr.WriteString(fmt.Sprintln("Could not remove server,", err.Error()))
-					break
-				}
-				err = msg.(*messages.RemoveServerMsg).Sign(priv)
+				r, err := removeServer(ListenTo, listenTo, len(b) > 1 && b[1] == 'a')
+				printSimResult(r, err)
 				if err != nil {
-					os.Stderr.WriteString(fmt.Sprintln("Could not remove server,", err.Error()))
 					break
 				}
-
-				fnodes[listenTo].State.InMsgQueue().Enqueue(msg)
-				os.Stderr.WriteString(fmt.Sprintln("Attempting to remove", fnodes[ListenTo].State.GetFactomNodeName(), "as a server"))
-
 				fallthrough
 			case 'o' == b[0]: // Add Audit server and Add Leader fall through to 'n', switch to next node.
 				if b[0] == 'o' { // (Don't do anything if just passing along the remove server)
-					if len(b) > 1 && b[1] == 'n' {
-						index := 0
-						for index < len(authKeyLibrary) {
-							if authKeyLibrary[index].Taken == false {
-								authKeyLibrary[index].Taken = true
-								fnodes[ListenTo].State.IdentityChainID = authKeyLibrary[index].ChainID
-								key, pKey, _ := authKeyLookup(fnodes[ListenTo].State.IdentityChainID)
-								fnodes[ListenTo].State.LocalServerPrivKey = key
-								fnodes[ListenTo].State.SimSetNewKeys(pKey)
-								os.Stderr.WriteString(fmt.Sprintf("Identity of " + fnodes[ListenTo].State.GetFactomNodeName() + " changed to [" + authKeyLibrary[index].ChainID.String()[:10] + "]\n"))
-								break
-							}
-							index++
-						}
-					}
-
-					msg := messages.NewAddServerMsg(fnodes[ListenTo].State, 1)
-					priv, err := primitives.NewPrivateKeyFromHex(LOCAL_NET_PRIV_KEY)
+					r, err := PromoteAudit(ListenTo, len(b) > 1 && b[1] == 'n')
+					printSimResult(r, err)
 					if err != nil {
-						os.Stderr.WriteString(fmt.Sprintln("Could not make an audit server,", err.Error()))
 						break
 					}
-					err = msg.(*messages.AddServerMsg).Sign(priv)
-					if err != nil {
-						os.Stderr.WriteString(fmt.Sprintln("Could not make an audit server,", err.Error()))
-						break
-					}
-					fnodes[ListenTo].State.InMsgQueue().Enqueue(msg)
-					os.Stderr.WriteString(fmt.Sprintln("Attempting to make", fnodes[ListenTo].State.GetFactomNodeName(), "a Audit Server"))
 				}
 				fallthrough
 			case 'l' == b[0]: // Add Audit server, Remove server, and Add Leader fall through to 'n', switch to next node.
 				if b[0] == 'l' { // (Don't do anything if just passing along the audit server)
-					feds := fnodes[ListenTo].State.LeaderPL.FedServers
-					exists := false
-					for _, fed := range feds {
-						if fed.GetChainID().IsSameAs(fnodes[ListenTo].State.IdentityChainID) {
-							exists = true
-						}
-					}
-					if len(b) > 1 && b[1] == 't' && fnodes[ListenTo].State.IdentityChainID.String()[:6] != "888888" && !exists {
-						index := 0
-						for index < len(authKeyLibrary) {
-							if authKeyLibrary[index].Taken == false {
-								authKeyLibrary[index].Taken = true
-								fnodes[ListenTo].State.IdentityChainID = authKeyLibrary[index].ChainID
-								key, pKey, _ := authKeyLookup(fnodes[ListenTo].State.IdentityChainID)
-								fnodes[ListenTo].State.LocalServerPrivKey = key
-								fnodes[ListenTo].State.SimSetNewKeys(pKey)
-								os.Stderr.WriteString(fmt.Sprintf("Identity of " + fnodes[ListenTo].State.GetFactomNodeName() + " changed to [" + authKeyLibrary[index].ChainID.String()[:10] + "]\n"))
-								break
-							}
-							index++
-						}
-						if index >= len(authKeyLibrary) {
-							os.Stderr.WriteString(fmt.Sprint("Did not make a leader, ran out of identities. Type 'g1' for one more identity.\n"))
-							break
-						}
-					}
-
-					msg := messages.NewAddServerMsg(fnodes[ListenTo].State, 0)
-					priv, err := primitives.NewPrivateKeyFromHex(LOCAL_NET_PRIV_KEY)
-					if err != nil {
-						os.Stderr.WriteString(fmt.Sprintln("Could not make a leader,", err.Error()))
-						break
-					}
-					err = msg.(*messages.AddServerMsg).Sign(priv)
+					r, err := promoteLeader(ListenTo, listenTo, len(b) > 1 && b[1] == 't')
+					printSimResult(r, err)
 					if err != nil {
-						os.Stderr.WriteString(fmt.Sprintln("Could not make a leader,", err.Error()))
 						break
 					}
-					fnodes[listenTo].State.InMsgQueue().Enqueue(msg)
-					os.Stderr.WriteString(fmt.Sprintln("Attempting to make", fnodes[ListenTo].State.GetFactomNodeName(), "a Leader"))
 				}
 				fallthrough
 			case 'n' == b[0]:
@@ -823,19 +677,7 @@ func SimControl(listenTo int, listenStdin bool) {
 				fnodes[ListenTo].State.SetOut(true)
 				os.Stderr.WriteString(fmt.Sprint("\r\nSwitching to Node ", ListenTo, "\r\n"))
 			case 'c' == b[0]:
-				c := !fnodes[0].State.DebugConsensus
-				for _, n := range fnodes {
-					n.State.DebugConsensus = fnodes[0].State.DebugConsensus
-				}
-				if c {
-					os.Stderr.WriteString(fmt.Sprint("\r\nTrace Consensus\n"))
-				} else {
-					os.Stderr.WriteString(fmt.Sprint("\r\nTurn off Consensus Trace \n"))
-				}
-
-				for _, f := range fnodes {
-					f.State.DebugConsensus = c
-				}
+				printSimResult(SetSimLogging(SimLogConsensus, !fnodes[0].State.DebugConsensus))
 			case 'i' == b[0]:
 				show := 0
 				amt := -1
@@ -1086,14 +928,11 @@ func SimControl(listenTo int, listenStdin bool) {
 				fmt.Println(fpl)
 			case 'S' == b[0]:
 				nnn, err := strconv.Atoi(string(b[1:]))
-				if err != nil || nnn < 0 || nnn > 999 {
+				if err != nil {
 					os.Stderr.WriteString("Specify a drop amount between 0 and 1000\n")
 					break
 				}
-				for _, fn := range fnodes {
-					fn.State.DropRate = nnn
-					os.Stderr.WriteString(fmt.Sprintf("Setting drop rate of %10s to %2d.%01d\n", fn.State.FactomNodeName, nnn/10, nnn%10))
-				}
+				printSimResult(SetDropRate(-1, nnn))
 
 			case 'O' == b[0]:
 				if ListenTo < 0 || ListenTo > len(fnodes) {
@@ -1101,14 +940,11 @@ func SimControl(listenTo int, listenStdin bool) {
 					break
 				}
 				nnn, err := strconv.Atoi(string(b[1:]))
-				if err != nil || nnn < 0 || nnn > 999 {
+				if err != nil {
 					os.Stderr.WriteString("Specify a drop amount between 0 and 1000\n")
 					break
 				}
-
-				fnodes[ListenTo].State.DropRate = nnn
-				os.Stderr.WriteString(fmt.Sprintf("Setting drop rate of %10s to %2d.%01d percent\n",
-					fnodes[ListenTo].State.FactomNodeName, nnn/10, nnn%10))
+				printSimResult(SetDropRate(ListenTo, nnn))
 
 				// modify the blocktime or modify the effective clocks on leaders in a simulation
 			case 'T' == b[0]:
@@ -1144,6 +980,10 @@ func SimControl(listenTo int, listenStdin bool) {
 						"other 's' specifier for spreading clocks over the simulator.\n")
 				}
 
+			case 'N' == b[0]:
+				if err := networkFaultCommand(b); err != nil {
+					os.Stderr.WriteString(fmt.Sprintf("%v\n", err))
+				}
 			case 'F' == b[0]:
 				nn, err := strconv.Atoi(string(b[1:]))
 				nnn := int64(nn)
@@ -1453,6 +1293,12 @@ func SimControl(listenTo int, listenStdin bool) {
 				os.Stderr.WriteString("Onnn          Set Drop Rate to nnn on this node\n")
 				os.Stderr.WriteString("Dnnn          Set the Delay on messages from the current node to nnn milliseconds\n")
 				os.Stderr.WriteString("Fnnn          Set the Delay on messages from all nodes to nnn milliseconds\n")
+				os.Stderr.WriteString("Np0,1/2,3     Partition nodes 0,1 from nodes 2,3 and from all other nodes\n")
+				os.Stderr.WriteString("Nh            Heal all partitions.  Nh10.5 heals once a node reaches block 10 minute 5\n")
+				os.Stderr.WriteString("Nl0,1,L,J     Set L milliseconds latency and J milliseconds jitter between nodes 0 and 1\n")
+				os.Stderr.WriteString("Nr0,1,nnn     Reorder nnn out of 1000 messages between nodes 0 and 1\n")
+				os.Stderr.WriteString("Nd0,1,nnn     Duplicate nnn out of 1000 messages between nodes 0 and 1\n")
+				os.Stderr.WriteString("Nc            Clear all network faults.  Ns shows them\n")
 				os.Stderr.WriteString("/             Toggle the sort order between ChainID and Factom Node Name\n")
 				os.Stderr.WriteString("Pnnn          Set's the efficiency of the given node to nnn\n")
 				os.Stderr.WriteString("B             Set's the coinbase address to a random one. Tyoe BFA... for a specific\n")
@@ -1473,6 +1319,17 @@ func SimControl(listenTo int, listenStdin bool) {
 		}
 	}
 }
+
+// printSimResult prints what a simulator operation did, or why it failed
+func printSimResult(r *interfaces.SimResult, err error) {
+	if r != nil && r.Message != "" {
+		os.Stderr.WriteString(strings.TrimRight(r.Message, "\n") + "\n")
+	}
+	if err != nil {
+		os.Stderr.WriteString(fmt.Sprintf("%v\n", err))
+	}
+}
+
 func returnStatString(i uint8) string {
 	var stat string
 	switch i {
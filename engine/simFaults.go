@@ -0,0 +1,280 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FactomProject/factomd/p2p"
+)
+
+// Fault injection for the simulated network.  Faults are set on the SimPeers connecting the
+// simulated nodes, and on the links of the memory network of -net=memory, so the connection
+// to a real p2p network is never affected.  A memory network link carries a stream, so only
+// latency and jitter apply to it.  These are
+// driven by the N commands of the simulator control (see simControl.go), which testHelper
+// uses, and by the network methods of the debug API through simController.
+
+// LinkFaults are the faults injected on one direction of a simulated link
+type LinkFaults struct {
+	Latency   int64 // Milliseconds every message is delayed
+	Jitter    int64 // Up to this many milliseconds of random extra delay, which reorders messages
+	Reorder   int   // Out of every 1000 messages, how many are held back until after the next one
+	Duplicate int   // Out of every 1000 messages, how many are delivered twice
+}
+
+func (f LinkFaults) String() string {
+	return fmt.Sprintf("latency %dms jitter %dms reorder %d.%01d%% duplicate %d.%01d%%",
+		f.Latency, f.Jitter, f.Reorder/10, f.Reorder%10, f.Duplicate/10, f.Duplicate%10)
+}
+
+// simPeerBetween returns the SimPeer node i uses to send to node j, if they are connected
+func simPeerBetween(i int, j int) *SimPeer {
+	for _, p := range fnodes[i].Peers {
+		sim, ok := p.(*SimPeer)
+		if ok && sim.ToName == fnodes[j].State.FactomNodeName {
+			return sim
+		}
+	}
+	return nil
+}
+
+// SetLinkFaults sets the faults on the link between two nodes, in both directions
+func SetLinkFaults(i int, j int, faults LinkFaults) error {
+	if i < 0 || j < 0 || i >= len(fnodes) || j >= len(fnodes) {
+		return fmt.Errorf("no link between nodes %d and %d", i, j)
+	}
+	found := false
+	for _, sim := range []*SimPeer{simPeerBetween(i, j), simPeerBetween(j, i)} {
+		if sim == nil {
+			continue
+		}
+		found = true
+		sim.faultMutex.Lock()
+		sim.Faults = faults
+		sim.faultMutex.Unlock()
+	}
+	if network := memoryNetwork(); network != nil && i != j {
+		found = true
+		network.SetLinkConditions(memoryNetworkHost(i), memoryNetworkHost(j), p2p.LinkConditions{
+			Latency: time.Duration(faults.Latency) * time.Millisecond,
+			Jitter:  time.Duration(faults.Jitter) * time.Millisecond,
+		})
+	}
+	if !found {
+		return fmt.Errorf("no link between nodes %d and %d", i, j)
+	}
+	return nil
+}
+
+// PartitionNodes splits the simulated network into the given groups of node indexes.  Nodes
+// not named in any group form one more group.  Messages between groups are lost until healed.
+func PartitionNodes(groups [][]int) error {
+	group := make([]int, len(fnodes))
+	for g, nodes := range groups {
+		for _, i := range nodes {
+			if i < 0 || i >= len(fnodes) {
+				return fmt.Errorf("there is no node %d", i)
+			}
+			group[i] = g + 1
+		}
+	}
+	setPartitions(func(i int, j int) bool { return group[i] != group[j] })
+	if network := memoryNetwork(); network != nil {
+		hosts := make([][]string, len(groups)+1)
+		for i, g := range group {
+			hosts[g] = append(hosts[g], memoryNetworkHost(i))
+		}
+		network.Partition(hosts...)
+	}
+	return nil
+}
+
+// HealPartitions reconnects all partitioned nodes
+func HealPartitions() {
+	setPartitions(func(i int, j int) bool { return false })
+	if network := memoryNetwork(); network != nil {
+		network.Heal()
+	}
+}
+
+func setPartitions(partitioned func(i int, j int) bool) {
+	index := make(map[string]int)
+	for i, fnode := range fnodes {
+		index[fnode.State.FactomNodeName] = i
+	}
+	for i, fnode := range fnodes {
+		for _, p := range fnode.Peers {
+			sim, ok := p.(*SimPeer)
+			if !ok {
+				continue
+			}
+			sim.faultMutex.Lock()
+			sim.Partitioned = partitioned(i, index[sim.ToName])
+			sim.faultMutex.Unlock()
+		}
+	}
+}
+
+// ScheduleHeal heals all partitions once any node reaches the given block height and minute
+func ScheduleHeal(height uint32, minute int) {
+	go func() {
+		for {
+			for _, fnode := range fnodes {
+				h, m := fnode.State.GetLLeaderHeight(), fnode.State.GetCurrentMinute()
+				if h > height || (h == height && m >= minute) {
+					os.Stderr.WriteString(fmt.Sprintf("Healing partitions at %d-:-%d\n", h, m))
+					HealPartitions()
+					return
+				}
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}()
+}
+
+// ClearNetworkFaults removes all partitions and link faults
+func ClearNetworkFaults() {
+	HealPartitions()
+	for _, fnode := range fnodes {
+		for _, p := range fnode.Peers {
+			if sim, ok := p.(*SimPeer); ok {
+				sim.faultMutex.Lock()
+				sim.Faults = LinkFaults{}
+				sim.faultMutex.Unlock()
+			}
+		}
+	}
+	if network := memoryNetwork(); network != nil {
+		for i := range fnodes {
+			for j := i + 1; j < len(fnodes); j++ {
+				network.SetLinkConditions(memoryNetworkHost(i), memoryNetworkHost(j), p2p.LinkConditions{})
+			}
+		}
+	}
+}
+
+// NetworkFaultsString lists the links that have faults injected
+func NetworkFaultsString() string {
+	var out strings.Builder
+	for _, fnode := range fnodes {
+		for _, p := range fnode.Peers {
+			sim, ok := p.(*SimPeer)
+			if !ok {
+				continue
+			}
+			sim.faultMutex.Lock()
+			faults, partitioned := sim.Faults, sim.Partitioned
+			sim.faultMutex.Unlock()
+			switch {
+			case partitioned:
+				fmt.Fprintf(&out, "%10s -> %-10s partitioned\n", sim.FromName, sim.ToName)
+			case faults != LinkFaults{}:
+				fmt.Fprintf(&out, "%10s -> %-10s %s\n", sim.FromName, sim.ToName, faults)
+			}
+		}
+	}
+	return out.String()
+}
+
+// parseNodeList parses node indexes separated by commas, eg "0,2,3"
+func parseNodeList(list string) ([]int, error) {
+	var nodes []int
+	for _, s := range strings.Split(list, ",") {
+		i, err := strconv.Atoi(s)
+		if err != nil || i < 0 || i >= len(fnodes) {
+			return nil, fmt.Errorf("%q is not a node", s)
+		}
+		nodes = append(nodes, i)
+	}
+	return nodes, nil
+}
+
+// networkFaultCommand runs the N commands of the simulator control
+//
+//	Np0,1/2,3     partition nodes 0,1 from nodes 2,3 (and from everyone else)
+//	Nh            heal all partitions
+//	Nh10.5        heal all partitions once a node reaches block 10 minute 5
+//	Nl0,1,500,100 set latency 500ms and jitter 100ms on the link between nodes 0 and 1
+//	Nr0,1,nnn     reorder nnn out of 1000 messages on the link between nodes 0 and 1
+//	Nd0,1,nnn     duplicate nnn out of 1000 messages on the link between nodes 0 and 1
+//	Nc            clear all network faults
+//	Ns            show the network faults
+func networkFaultCommand(b string) error {
+	if len(b) < 2 {
+		return fmt.Errorf("missing N subcommand")
+	}
+	args := b[2:]
+	switch b[1] {
+	case 'p':
+		var groups [][]int
+		for _, list := range strings.Split(args, "/") {
+			nodes, err := parseNodeList(list)
+			if err != nil {
+				return err
+			}
+			groups = append(groups, nodes)
+		}
+		return PartitionNodes(groups)
+	case 'h':
+		if args == "" {
+			HealPartitions()
+			return nil
+		}
+		var height uint32
+		var minute int
+		if _, err := fmt.Sscanf(args, "%d.%d", &height, &minute); err != nil || minute < 0 || minute > 9 {
+			return fmt.Errorf("specify when to heal as block.minute, eg Nh10.5")
+		}
+		ScheduleHeal(height, minute)
+		return nil
+	case 'l', 'r', 'd':
+		values := strings.Split(args, ",")
+		var n []int64
+		for _, v := range values {
+			i, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || i < 0 {
+				return fmt.Errorf("%q is not a valid value", v)
+			}
+			n = append(n, i)
+		}
+		if len(n) < 3 || (b[1] == 'l' && len(n) != 4) || (b[1] != 'l' && len(n) != 3) {
+			return fmt.Errorf("wrong number of values for N%c", b[1])
+		}
+		i, j := int(n[0]), int(n[1])
+		if i >= len(fnodes) || j >= len(fnodes) {
+			return fmt.Errorf("no link between nodes %d and %d", i, j)
+		}
+		var faults LinkFaults
+		if sim := simPeerBetween(i, j); sim != nil {
+			sim.faultMutex.Lock()
+			faults = sim.Faults
+			sim.faultMutex.Unlock()
+		}
+		switch b[1] {
+		case 'l':
+			faults.Latency, faults.Jitter = n[2], n[3]
+		case 'r':
+			faults.Reorder = int(n[2])
+		case 'd':
+			faults.Duplicate = int(n[2])
+		}
+		if faults.Reorder > 1000 || faults.Duplicate > 1000 {
+			return fmt.Errorf("specify a rate between 0 and 1000")
+		}
+		return SetLinkFaults(i, j, faults)
+	case 'c':
+		ClearNetworkFaults()
+		return nil
+	case 's':
+		os.Stderr.WriteString(NetworkFaultsString())
+		return nil
+	}
+	return fmt.Errorf("unknown N subcommand %q", b[1:2])
+}
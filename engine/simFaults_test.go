@@ -0,0 +1,79 @@
+package engine_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/engine"
+)
+
+func newLinkedSimPeers() (*SimPeer, *SimPeer) {
+	a := new(SimPeer).Init("a", "b").(*SimPeer)
+	b := new(SimPeer).Init("b", "a").(*SimPeer)
+	a.BroadcastIn = b.BroadcastOut
+	b.BroadcastIn = a.BroadcastOut
+	return a, b
+}
+
+func sendBounce(t *testing.T, peer *SimPeer, number int32) {
+	msg := new(messages.Bounce)
+	msg.Name = "faults"
+	msg.Number = number
+	msg.Timestamp = primitives.NewTimestampNow()
+	if err := peer.Send(msg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// receiveBounces collects the numbers of the bounce messages the peer receives within the wait,
+// returning early once it has want of them.  With a want of 0 it waits the whole time.
+func receiveBounces(peer *SimPeer, want int, wait time.Duration) (numbers []int32) {
+	for end := time.Now().Add(wait); time.Now().Before(end) && (want == 0 || len(numbers) < want); {
+		msg, err := peer.Receive()
+		if err != nil || msg == nil {
+			time.Sleep(time.Millisecond)
+			continue
+		}
+		numbers = append(numbers, msg.(*messages.Bounce).Number)
+	}
+	return
+}
+
+func TestSimPeerFaults(t *testing.T) {
+	a, b := newLinkedSimPeers()
+
+	a.Partitioned = true
+	sendBounce(t, a, 1)
+	if got := receiveBounces(b, 0, 50*time.Millisecond); len(got) != 0 {
+		t.Errorf("received %v across a partition", got)
+	}
+	a.Partitioned = false
+
+	a.Faults = LinkFaults{Duplicate: 1000}
+	sendBounce(t, a, 2)
+	if got := receiveBounces(b, 2, time.Second); len(got) != 2 || got[0] != 2 || got[1] != 2 {
+		t.Errorf("expected the message twice, got %v", got)
+	}
+
+	a.Faults = LinkFaults{Reorder: 1000}
+	sendBounce(t, a, 3)
+	sendBounce(t, a, 4)
+	if got := receiveBounces(b, 2, time.Second); len(got) != 2 || got[0] != 4 || got[1] != 3 {
+		t.Errorf("expected the messages out of order, got %v", got)
+	}
+	sendBounce(t, a, 5) // held back, with nothing after it
+	if got := receiveBounces(b, 1, MaxReorderHold+time.Second); len(got) != 1 || got[0] != 5 {
+		t.Errorf("expected the held message once the hold expired, got %v", got)
+	}
+
+	a.Faults = LinkFaults{Latency: 200}
+	sendBounce(t, a, 6)
+	if got := receiveBounces(b, 0, 50*time.Millisecond); len(got) != 0 {
+		t.Errorf("received %v before the latency passed", got)
+	}
+	if got := receiveBounces(b, 1, time.Second); len(got) != 1 || got[0] != 6 {
+		t.Errorf("expected the delayed message, got %v", got)
+	}
+}
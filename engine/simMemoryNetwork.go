@@ -0,0 +1,101 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/p2p"
+)
+
+// With -net=memory the simulated nodes talk to each other through the real p2p stack, each node
+// running its own p2p.Controller on an in-process p2p.MemoryNetwork, instead of through
+// SimPeers.  Node i is the host 10.0.<i/250>.<i%250+1> and has every node before it as a
+// config peer, so the nodes form a mesh.  The network faults of simFaults.go are applied to
+// the MemoryNetwork as well.
+
+var simMemoryNetwork struct {
+	sync.Mutex
+	network     *p2p.MemoryNetwork
+	networkID   p2p.NetworkID
+	port        string
+	proxies     []*P2PProxy
+	controllers []*p2p.Controller
+}
+
+// memoryNetworkHost returns the host of node i on the memory network
+func memoryNetworkHost(i int) string {
+	return fmt.Sprintf("10.0.%d.%d", i/250, i%250+1)
+}
+
+// StartMemoryNetwork creates the memory network the nodes added by AddMemoryNetworkPeer use
+func StartMemoryNetwork(networkID p2p.NetworkID, port string, seed int64) {
+	simMemoryNetwork.Lock()
+	defer simMemoryNetwork.Unlock()
+	simMemoryNetwork.network = p2p.NewMemoryNetwork(seed)
+	simMemoryNetwork.networkID = networkID
+	simMemoryNetwork.port = port
+	go memoryNetworkHousekeeping()
+}
+
+// AddMemoryNetworkPeer starts a p2p controller for node i on the memory network, connected to
+// all the nodes before it
+func AddMemoryNetworkPeer(fnodes []*FactomNode, i int) {
+	simMemoryNetwork.Lock()
+	defer simMemoryNetwork.Unlock()
+	if simMemoryNetwork.network == nil {
+		panic("AddMemoryNetworkPeer() called before StartMemoryNetwork()")
+	}
+
+	port := simMemoryNetwork.port
+	var peers []string
+	for j := 0; j < i; j++ {
+		peers = append(peers, memoryNetworkHost(j)+":"+port)
+	}
+	host := memoryNetworkHost(i)
+	name := fnodes[i].State.FactomNodeName
+	controller := new(p2p.Controller).Init(p2p.ControllerInit{
+		NodeName:                 name,
+		Port:                     port,
+		Network:                  simMemoryNetwork.networkID,
+		ConfigPeers:              strings.Join(peers, " "),
+		ConnectionMetricsChannel: make(chan interface{}, p2p.StandardChannelSize),
+		Transport:                simMemoryNetwork.network.Transport(host),
+	})
+	fnodes[i].State.NetworkController = controller
+	controller.StartNetwork()
+
+	proxy := new(P2PProxy).Init(name, "Memory Network "+host).(*P2PProxy)
+	proxy.FromNetwork = controller.FromNetwork
+	proxy.ToNetwork = controller.ToNetwork
+	fnodes[i].Peers = append(fnodes[i].Peers, proxy)
+	proxy.StartProxy()
+
+	simMemoryNetwork.proxies = append(simMemoryNetwork.proxies, proxy)
+	simMemoryNetwork.controllers = append(simMemoryNetwork.controllers, controller)
+}
+
+// memoryNetworkHousekeeping keeps the weight of the memory network proxies up to date, like
+// networkHousekeeping does for the real network
+func memoryNetworkHousekeeping() {
+	for {
+		time.Sleep(1 * time.Second)
+		simMemoryNetwork.Lock()
+		for i, proxy := range simMemoryNetwork.proxies {
+			proxy.SetWeight(simMemoryNetwork.controllers[i].GetNumberOfConnections())
+		}
+		simMemoryNetwork.Unlock()
+	}
+}
+
+// memoryNetwork returns the memory network of the simulation, nil if it does not use one
+func memoryNetwork() *p2p.MemoryNetwork {
+	simMemoryNetwork.Lock()
+	defer simMemoryNetwork.Unlock()
+	return simMemoryNetwork.network
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/FactomProject/factomd/common/globals"
 	"github.com/FactomProject/factomd/events/eventconfig"
 	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
+	llog "github.com/FactomProject/factomd/log"
 	"github.com/FactomProject/factomd/p2p"
 	"github.com/FactomProject/factomd/util"
 	"github.com/gogo/protobuf/proto"
@@ -22,6 +23,9 @@ import (
 
 var eventSenderInstance *eventSender
 
+// eventsLogger logs at the level of the events subsystem
+var eventsLogger = llog.SubsystemLogger(llog.SubsystemEvents).WithFields(log.Fields{"package": "events"})
+
 const (
 	defaultProtocol       = "tcp"
 	defaultConnectionHost = "127.0.0.1"
@@ -96,7 +100,7 @@ func (eventSender *eventSender) processEventsChannel() {
 func (eventSender *eventSender) sendEvent(event *eventmessages.FactomEvent) {
 	data, err := eventSender.marshallMessage(event)
 	if err != nil {
-		log.Errorf("An error occurred while serializing factom event of type %s: %v", reflect.TypeOf(event), err)
+		eventsLogger.Errorf("An error occurred while serializing factom event of type %s: %v", reflect.TypeOf(event), err)
 		eventSender.notSentCounter.Inc()
 		return
 	}
@@ -105,7 +109,7 @@ func (eventSender *eventSender) sendEvent(event *eventmessages.FactomEvent) {
 	sendSuccessful := false
 	for retry := 0; (eventSender.params.PersistentReconnect || retry < sendRetries) && !sendSuccessful; retry++ {
 		if err = eventSender.connect(); err != nil {
-			log.Errorf("An error occurred while connecting to receiver %s: %v, retry %d", eventSender.params.Address, err, retry)
+			eventsLogger.Errorf("An error occurred while connecting to receiver %s: %v, retry %d", eventSender.params.Address, err, retry)
 			time.Sleep(redialSleepDuration)
 			continue
 		}
@@ -114,7 +118,7 @@ func (eventSender *eventSender) sendEvent(event *eventmessages.FactomEvent) {
 		if err = eventSender.writeEvent(data); err == nil {
 			sendSuccessful = true
 		} else {
-			log.Errorf("An error occurred while sending a message to receiver %s: %v, retry %d", eventSender.params.Address, err, retry)
+			eventsLogger.Errorf("An error occurred while sending a message to receiver %s: %v, retry %d", eventSender.params.Address, err, retry)
 
 			// reset connection and retry
 			eventSender.disconnect()
@@ -147,7 +151,7 @@ func (eventSender *eventSender) connect() error {
 	defer catchConnectPanics()
 
 	if eventSender.connection == nil {
-		log.Infoln("Connecting to ", eventSender.params.Address)
+		eventsLogger.Infoln("Connecting to ", eventSender.params.Address)
 		var conn net.Conn
 		var err error
 		if len(eventSender.params.ClientPort) > 0 {
@@ -176,10 +180,10 @@ func catchConnectPanics() error {
 
 func (eventSender *eventSender) disconnect() {
 	if eventSender.connection != nil {
-		log.Infoln("Closing connection to receiver", eventSender.params.Address)
+		eventsLogger.Infoln("Closing connection to receiver", eventSender.params.Address)
 		err := eventSender.connection.Close()
 		if err != nil {
-			log.Warnln("An error occurred while closing connection to receiver", eventSender.params.Address)
+			eventsLogger.Warnln("An error occurred while closing connection to receiver", eventSender.params.Address)
 		}
 	}
 }
@@ -244,7 +248,7 @@ func (eventSender *eventSender) IncreaseDroppedFromQueueCounter() {
 }
 
 func (eventSender *eventSender) Shutdown() {
-	log.Infoln("Waiting until queued event messages have been dispatched.")
+	eventsLogger.Infoln("Waiting until queued event messages have been dispatched.")
 	for len(eventSender.eventsOutQueue) > 0 {
 		time.Sleep(25 * time.Millisecond)
 	}
@@ -0,0 +1,35 @@
+package log
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReadTail(t *testing.T) {
+	f, err := ioutil.TempFile("", "tail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("first line\nsecond line\nthird line\n")
+	f.Close()
+
+	for size, expected := range map[int64]string{
+		1000: "first line\nsecond line\nthird line\n",
+		20:   "third line\n", // starts in the middle of the second line
+		5:    "",
+	} {
+		data, err := readTail(f.Name(), size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != expected {
+			t.Errorf("the last %d bytes are %q, expected %q", size, data, expected)
+		}
+	}
+	if _, err := readTail(f.Name()+"missing", 10); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("read a missing file: %v", err)
+	}
+}
@@ -0,0 +1,280 @@
+package log
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/FactomProject/factomd/common/globals"
+	"github.com/sirupsen/logrus"
+)
+
+// The subsystems with a log level of their own
+const (
+	SubsystemState     = "state"
+	SubsystemP2P       = "p2p"
+	SubsystemWSAPI     = "wsapi"
+	SubsystemElections = "elections"
+	SubsystemEvents    = "events"
+)
+
+// A subsystem has logrus loggers of its own so its level can be changed at runtime without
+// changing the others.  Until its level is set it follows the level of the standard logger, as
+// logrus.SetLevel sets it at any time: its loggers are at the debug level and what the standard
+// logger would not write is dropped.  Logs that are costly to build are guarded with
+// SubsystemEnabled, which follows the standard logger as well.
+type subsystem struct {
+	loggers []*logrus.Logger
+	own     int32       // 1 once the level was set for the subsystem, read atomically
+	revert  *time.Timer // puts the level back when a set level times out
+}
+
+var (
+	subsystemsMutex sync.Mutex
+	subsystems      = make(map[string]*subsystem)
+
+	regexRevert *time.Timer
+)
+
+// drops returns true for an entry the subsystem should not write, because it follows the
+// standard logger and the standard logger is not at that level
+func (s *subsystem) drops(e *logrus.Entry) bool {
+	return atomic.LoadInt32(&s.own) == 0 && !logrus.IsLevelEnabled(e.Level)
+}
+
+// setLevel sets the level of the loggers, following the standard logger if own is false
+func (s *subsystem) setLevel(lvl logrus.Level, own bool) {
+	if own {
+		atomic.StoreInt32(&s.own, 1)
+	} else {
+		atomic.StoreInt32(&s.own, 0)
+		lvl = logrus.DebugLevel
+	}
+	for _, l := range s.loggers {
+		l.SetLevel(lvl)
+	}
+}
+
+// level returns the level the subsystem writes at
+func (s *subsystem) level() logrus.Level {
+	if atomic.LoadInt32(&s.own) == 0 || len(s.loggers) == 0 {
+		return logrus.GetLevel()
+	}
+	return s.loggers[0].GetLevel()
+}
+
+// standardWriter writes where the standard logger writes, so -loglvl none still discards
+type standardWriter struct{}
+
+func (standardWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 { // dropped by the formatter
+		return 0, nil
+	}
+	return logrus.StandardLogger().Out.Write(p)
+}
+
+// standardFormatter formats as the standard logger does, so -logjson still applies
+type standardFormatter struct {
+	s *subsystem
+}
+
+func (f standardFormatter) Format(e *logrus.Entry) ([]byte, error) {
+	if f.s.drops(e) {
+		return nil, nil
+	}
+	return logrus.StandardLogger().Formatter.Format(e)
+}
+
+// standardHooks fires the hooks of the standard logger, so a logstash hook gets every subsystem
+type standardHooks struct {
+	s *subsystem
+}
+
+func (standardHooks) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h standardHooks) Fire(e *logrus.Entry) error {
+	if h.s.drops(e) {
+		return nil
+	}
+	return logrus.StandardLogger().Hooks.Fire(e.Level, e)
+}
+
+func getSubsystem(name string) *subsystem {
+	s := subsystems[name]
+	if s == nil {
+		s = new(subsystem)
+		subsystems[name] = s
+	}
+	return s
+}
+
+// SubsystemLogger returns a new logger of a subsystem.  It writes where the standard logger
+// writes, in its format and with its hooks.
+func SubsystemLogger(name string) *logrus.Logger {
+	subsystemsMutex.Lock()
+	defer subsystemsMutex.Unlock()
+	s := getSubsystem(name)
+
+	l := logrus.New()
+	l.Out = standardWriter{}
+	l.Formatter = standardFormatter{s}
+	l.Hooks.Add(standardHooks{s})
+	if atomic.LoadInt32(&s.own) == 1 {
+		l.SetLevel(s.level())
+	} else {
+		l.SetLevel(logrus.DebugLevel)
+	}
+	s.loggers = append(s.loggers, l)
+	return l
+}
+
+// RegisterSubsystemLoggers replaces the loggers of a subsystem with loggers that have an output
+// and level of their own, as the wsapi loggers do
+func RegisterSubsystemLoggers(name string, loggers ...*logrus.Logger) {
+	subsystemsMutex.Lock()
+	defer subsystemsMutex.Unlock()
+	s := getSubsystem(name)
+	atomic.StoreInt32(&s.own, 1)
+	s.loggers = loggers
+}
+
+// SubsystemEnabled returns true if the subsystem writes at the level, so a log that is costly to
+// build can be skipped.  A subsystem without a level of its own follows the standard logger.
+func SubsystemEnabled(name string, lvl logrus.Level) bool {
+	subsystemsMutex.Lock()
+	defer subsystemsMutex.Unlock()
+	s := subsystems[name]
+	if s == nil || len(s.loggers) == 0 {
+		return logrus.IsLevelEnabled(lvl)
+	}
+	return s.level() >= lvl
+}
+
+// SubsystemLevels returns the level of each subsystem
+func SubsystemLevels() map[string]string {
+	subsystemsMutex.Lock()
+	defer subsystemsMutex.Unlock()
+	levels := make(map[string]string)
+	for name, s := range subsystems {
+		if len(s.loggers) > 0 {
+			levels[name] = s.level().String()
+		}
+	}
+	return levels
+}
+
+// Subsystems returns the names of the subsystems, sorted
+func Subsystems() []string {
+	subsystemsMutex.Lock()
+	defer subsystemsMutex.Unlock()
+	var names []string
+	for name := range subsystems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetSubsystemLevel sets the level of a subsystem.  If revertAfter is not 0 the level goes back
+// to what it was after that long, so a verbose level can't be left on by accident.
+func SetSubsystemLevel(name string, level string, revertAfter time.Duration) error {
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	subsystemsMutex.Lock()
+	defer subsystemsMutex.Unlock()
+	s := subsystems[name]
+	if s == nil || len(s.loggers) == 0 {
+		return fmt.Errorf("unknown log subsystem %q", name)
+	}
+
+	if s.revert != nil {
+		s.revert.Stop()
+		s.revert = nil
+	}
+	if revertAfter > 0 {
+		old, oldOwn := s.level(), atomic.LoadInt32(&s.own) == 1
+		var t *time.Timer
+		t = time.AfterFunc(revertAfter, func() {
+			subsystemsMutex.Lock()
+			defer subsystemsMutex.Unlock()
+			if s.revert != t { // set again since
+				return
+			}
+			s.revert = nil
+			s.setLevel(old, oldOwn)
+		})
+		s.revert = t
+	}
+
+	s.setLevel(lvl, true)
+	return nil
+}
+
+// DebugLogRegEx returns the regex that picks the debug logs to write, "" when none are written
+func DebugLogRegEx() string {
+	traceMutex.Lock()
+	defer traceMutex.Unlock()
+	if globals.Params.DebugLogRegEx == "" {
+		return ""
+	}
+	return globals.Params.DebugLogLocation + globals.Params.DebugLogRegEx
+}
+
+// SetDebugLogRegEx sets the regex that picks the debug logs to write, as -debuglog does.  The
+// logs are still written to the directory -debuglog gave, so the regex may only name that
+// directory.  If revertAfter is not 0 the regex goes back to what it was after that long.
+func SetDebugLogRegEx(regex string, revertAfter time.Duration) error {
+	regex = strings.Trim(regex, `"'`)
+	dir, r := SplitUpDebugLogRegEx(regex)
+	if _, err := regexp.Compile("(?i)" + r); err != nil {
+		return err
+	}
+
+	traceMutex.Lock()
+	defer traceMutex.Unlock()
+	checkForChangesInDebugRegex() // split up the regex -debuglog gave if nothing was logged yet
+	if dir != "" && dir != globals.Params.DebugLogLocation {
+		return fmt.Errorf("the debug logs are written to %q, not %q", globals.Params.DebugLogLocation, dir)
+	}
+
+	if regexRevert != nil {
+		regexRevert.Stop()
+		regexRevert = nil
+	}
+	if revertAfter > 0 {
+		old := globals.Params.DebugLogRegEx
+		var t *time.Timer
+		t = time.AfterFunc(revertAfter, func() {
+			traceMutex.Lock()
+			defer traceMutex.Unlock()
+			if regexRevert != t {
+				return
+			}
+			regexRevert = nil
+			setDebugLogRegEx(old)
+		})
+		regexRevert = t
+	}
+	setDebugLogRegEx(r)
+	return nil
+}
+
+// setDebugLogRegEx sets the regex, keeping the directory, and closes the debug logs if it is "".
+// assumes traceMutex is locked already
+func setDebugLogRegEx(regex string) {
+	globals.Params.DebugLogRegEx = regex
+	globals.LastDebugLogRegEx = regex
+	TestRegex = nil // compiled again by the next log
+	if regex == "" {
+		closeTraceFiles()
+	}
+}
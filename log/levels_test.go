@@ -0,0 +1,130 @@
+package log_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/log"
+	"github.com/sirupsen/logrus"
+)
+
+func TestSubsystemLevel(t *testing.T) {
+	defer logrus.SetLevel(logrus.GetLevel())
+	logrus.SetLevel(logrus.InfoLevel)
+
+	out := logrus.StandardLogger().Out
+	defer logrus.SetOutput(out)
+	buf := new(bytes.Buffer)
+	logrus.SetOutput(buf)
+
+	l := log.SubsystemLogger("leveltest")
+	if lvl := log.SubsystemLevels()["leveltest"]; lvl != "info" {
+		t.Errorf("expected a new subsystem at the standard level, got %s", lvl)
+	}
+
+	logrus.SetLevel(logrus.WarnLevel)
+	if log.SubsystemEnabled("leveltest", logrus.InfoLevel) || !log.SubsystemEnabled("leveltest", logrus.WarnLevel) {
+		t.Error("expected the subsystem to be enabled at the standard level")
+	}
+	l.Info("dropped")
+	l.Warn("written")
+	if strings.Contains(buf.String(), "dropped") || !strings.Contains(buf.String(), "written") {
+		t.Errorf("expected the subsystem to follow the standard level, got %q", buf.String())
+	}
+	if lvl := log.SubsystemLevels()["leveltest"]; lvl != "warning" {
+		t.Errorf("expected the subsystem to follow the standard level, got %s", lvl)
+	}
+
+	logrus.SetLevel(logrus.DebugLevel)
+	l.Debug("verbose")
+	if !strings.Contains(buf.String(), "verbose") || !log.SubsystemEnabled("leveltest", logrus.DebugLevel) {
+		t.Errorf("expected the subsystem to follow the standard level to debug, got %q", buf.String())
+	}
+
+	if err := log.SetSubsystemLevel("leveltest", "debug", 0); err != nil {
+		t.Fatal(err)
+	}
+	if l.GetLevel() != logrus.DebugLevel || log.SubsystemLevels()["leveltest"] != "debug" {
+		t.Errorf("expected debug, got %s", l.GetLevel())
+	}
+
+	// A subsystem with a level of its own keeps it
+	logrus.SetLevel(logrus.ErrorLevel)
+	l.Debug("kept")
+	if !strings.Contains(buf.String(), "kept") {
+		t.Errorf("expected the subsystem to keep debug, got %q", buf.String())
+	}
+
+	if err := log.SetSubsystemLevel("leveltest", "verbose", 0); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+	if err := log.SetSubsystemLevel("nosuchsubsystem", "debug", 0); err == nil {
+		t.Error("expected an error for an unknown subsystem")
+	}
+}
+
+func TestSubsystemLevelRevert(t *testing.T) {
+	l := log.SubsystemLogger("reverttest")
+	if err := log.SetSubsystemLevel("reverttest", "warn", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := log.SetSubsystemLevel("reverttest", "debug", 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if l.GetLevel() != logrus.DebugLevel {
+		t.Errorf("expected debug, got %s", l.GetLevel())
+	}
+
+	for i := 0; i < 100 && l.GetLevel() != logrus.WarnLevel; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if l.GetLevel() != logrus.WarnLevel {
+		t.Errorf("expected the level to go back to warning, got %s", l.GetLevel())
+	}
+}
+
+func TestSetDebugLogRegEx(t *testing.T) {
+	dir, restore := debugLogsToTempDir(t, "nothing")
+	defer restore()
+
+	if err := log.SetDebugLogRegEx("(", 0); err == nil {
+		t.Error("expected an error for a regex that does not compile")
+	}
+	if err := log.SetDebugLogRegEx("../fnode0_.*", 0); err == nil {
+		t.Error("expected an error for a regex in another directory")
+	}
+
+	if err := log.SetDebugLogRegEx(dir+"fnode0_.*", 0); err != nil {
+		t.Fatal(err)
+	}
+	log.LogPrintf("fnode0_regextest.txt", "first")
+	if err := log.SetDebugLogRegEx("", 0); err != nil {
+		t.Fatal(err)
+	}
+	log.LogPrintf("fnode0_regextest.txt", "dropped")
+	if err := log.SetDebugLogRegEx("fnode0_.*", 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if r := log.DebugLogRegEx(); r != dir+"fnode0_.*" {
+		t.Errorf("expected the regex in %s, got %q", dir, r)
+	}
+	log.LogPrintf("fnode0_regextest.txt", "second")
+
+	data, err := ioutil.ReadFile(dir + "fnode0_regextest.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "first") || !strings.Contains(string(data), "second") || strings.Contains(string(data), "dropped") {
+		t.Errorf("expected the log to be opened again and appended to, got %q", data)
+	}
+
+	for i := 0; i < 100 && log.DebugLogRegEx() != ""; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if r := log.DebugLogRegEx(); r != "" {
+		t.Errorf("expected the regex to go back to \"\", got %q", r)
+	}
+}
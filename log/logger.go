@@ -5,10 +5,14 @@ Adding a wrapper around messages to allow eventual refactor here
 */
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -22,6 +26,7 @@ import (
 var (
 	traceMutex sync.Mutex
 	files      map[string]*os.File
+	created    map[string]bool // the files created by this run, appended to when opened again
 	enabled    map[string]bool
 	TestRegex  *regexp.Regexp
 	sequence   int
@@ -30,6 +35,70 @@ var (
 	msgmap     map[[32]byte]interfaces.IMsg
 )
 
+// Fields place a record in the consensus of a node.  VM is -1 when it is not known.
+type Fields struct {
+	Node     string
+	DBHeight int
+	Minute   int
+	VM       int
+}
+
+// Record is a log record as the JSON sink writes it, one per line, when -logjson is set.  The time
+// and msg keys are the ones logrus writes with -logjson, so one pipeline can read both.  Level is
+// always debug, as -debuglog selects debug traces, and is there for pipelines that filter on it.
+type Record struct {
+	Time       string `json:"time"`
+	Level      string `json:"level"`
+	Msg        string `json:"msg"`
+	Log        string `json:"log"` // the name of the log, the file it is written to
+	Seq        int    `json:"seq"`
+	Node       string `json:"node,omitempty"`
+	DBHeight   *int   `json:"dbheight,omitempty"`
+	Minute     *int   `json:"minute,omitempty"`
+	VM         *int   `json:"vm,omitempty"`
+	MsgType    string `json:"msgtype,omitempty"`
+	MsgHash    string `json:"msghash,omitempty"`
+	Hash       string `json:"hash,omitempty"`
+	RepeatHash string `json:"repeathash,omitempty"`
+	Peer       string `json:"peer,omitempty"`     // the peer a message came from, or the node it is sent to
+	Message    string `json:"message,omitempty"`  // the message as text
+	Embedded   bool   `json:"embedded,omitempty"` // the message is the one an ack or volunteer of the last record refers to
+}
+
+func newRecord(name string, f *Fields, text string) *Record {
+	r := new(Record)
+	r.Time = time.Now().Format(time.RFC3339Nano)
+	r.Level = "debug"
+	r.Msg = text
+	r.Log = strings.TrimSuffix(name, ".txt")
+	r.Seq = sequence
+	if f != nil {
+		r.Node = f.Node
+		r.DBHeight, r.Minute = &f.DBHeight, &f.Minute
+		if f.VM >= 0 {
+			r.VM = &f.VM
+		}
+	}
+	return r
+}
+
+// assumes traceMutex is locked already
+func writeRecord(file *os.File, r *Record) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return
+	}
+	file.Write(append(data, '\n'))
+}
+
+// statePrefix is the height and minute a text record of a node starts with
+func statePrefix(f *Fields) string {
+	if f == nil {
+		return ""
+	}
+	return fmt.Sprintf("%7d-:-%d ", f.DBHeight, f.Minute)
+}
+
 // Check a filename and see if logging is on for that filename
 // If it never ben see then check with the regex. If it has been seen then just look it up in the map
 // assumes traceMutex is locked already
@@ -93,6 +162,7 @@ func getTraceFile(name string) (f *os.File) {
 	}
 	if files == nil {
 		files = make(map[string]*os.File)
+		created = make(map[string]bool)
 	}
 	f, _ = files[name]
 	if f != nil {
@@ -104,14 +174,22 @@ func getTraceFile(name string) (f *os.File) {
 		}
 	}
 	if f == nil {
-		fmt.Println("Creating " + (name))
+		flags := os.O_RDWR | os.O_CREATE | os.O_TRUNC
+		if created[name] { // closed since, so keep what was written before
+			flags = os.O_RDWR | os.O_CREATE | os.O_APPEND
+		} else {
+			fmt.Println("Creating " + (name))
+		}
 		var err error
-		f, err = os.Create(name)
+		f, err = os.OpenFile(name, flags, 0666)
 		if err != nil {
 			panic(err)
 		}
 		files[name] = f
-		f.WriteString(time.Now().String() + "\n")
+		created[name] = true
+		if !globals.Params.Logjson {
+			f.WriteString(time.Now().String() + "\n")
+		}
 	}
 	return f
 }
@@ -147,13 +225,13 @@ func getmsg(hash [32]byte) interfaces.IMsg {
 func LogMessage(name string, note string, msg interfaces.IMsg) {
 	traceMutex.Lock()
 	defer traceMutex.Unlock()
-	logMessage(name, note, msg)
+	logMessage(name, nil, note, msg, false)
 }
 
 var logWhere bool = false // log GoID() of the caller.
 
 // Assumes called managed the locks so we can recurse for multi part messages
-func logMessage(name string, note string, msg interfaces.IMsg) {
+func logMessage(name string, fields *Fields, note string, msg interfaces.IMsg, embedded bool) {
 	myfile := getTraceFile(name)
 	if myfile == nil {
 		return
@@ -220,6 +298,34 @@ func logMessage(name string, note string, msg interfaces.IMsg) {
 		messageType = constants.MessageName(byte(t))
 	}
 
+	if globals.Params.Logjson {
+		r := newRecord(name, fields, note)
+		r.Embedded = embedded
+		if msg != nil {
+			r.MsgType = messageType
+			r.Message = msgString
+			if mh := msg.GetMsgHash(); mh != nil && !reflect.ValueOf(mh).IsNil() {
+				r.MsgHash = mh.String()
+			}
+			if h := msg.GetHash(); h != nil && !reflect.ValueOf(h).IsNil() {
+				r.Hash = h.String()
+			}
+			if rh := msg.GetRepeatHash(); rh != nil && !reflect.ValueOf(rh).IsNil() {
+				r.RepeatHash = rh.String()
+			}
+			r.Peer = msg.GetNetworkOrigin()
+			if r.Peer == "" {
+				r.Peer = to
+			}
+		}
+		writeRecord(myfile, r)
+		if embeddedMsg != nil {
+			logMessage(name, fields, note, embeddedMsg, true)
+		}
+		return
+	}
+	note = statePrefix(fields) + note
+
 	// handle multi-line printf's
 	lines := strings.Split(msgString, "\n")
 
@@ -242,7 +348,7 @@ func logMessage(name string, note string, msg interfaces.IMsg) {
 	}
 
 	if embeddedMsg != nil {
-		logMessage(name, note+" EmbeddedMsg:", embeddedMsg)
+		logMessage(name, nil, note+" EmbeddedMsg:", embeddedMsg, true)
 	}
 }
 
@@ -291,6 +397,10 @@ func addNodeNames(s string) (rval string) {
 }
 
 func LogPrintf(name string, format string, more ...interface{}) {
+	logPrintf(name, nil, format, more...)
+}
+
+func logPrintf(name string, fields *Fields, format string, more ...interface{}) {
 	traceMutex.Lock()
 	defer traceMutex.Unlock()
 	myfile := getTraceFile(name)
@@ -305,8 +415,12 @@ func LogPrintf(name string, format string, more ...interface{}) {
 	}
 
 	sequence++
+	if globals.Params.Logjson {
+		writeRecord(myfile, newRecord(name, fields, fmt.Sprintf(format, more...)))
+		return
+	}
 	// handle multi-line printf's
-	lines := strings.Split(fmt.Sprintf(format, more...), "\n")
+	lines := strings.Split(statePrefix(fields)+fmt.Sprintf(format, more...), "\n")
 	now := time.Now().Local()
 	for i, text := range lines {
 		var s string
@@ -321,8 +435,9 @@ func LogPrintf(name string, format string, more ...interface{}) {
 	}
 }
 
-// stringify it in the caller to avoid having to deal with the import loop
-func LogParcel(name string, note string, msg string) {
+// stringify it in the caller to avoid having to deal with the import loop.  peer is the peer the
+// parcel came from or is sent to.
+func LogParcel(name string, note string, peer string, msg string) {
 	traceMutex.Lock()
 	defer traceMutex.Unlock()
 	myfile := getTraceFile(name)
@@ -332,27 +447,145 @@ func LogParcel(name string, note string, msg string) {
 	sequence++
 	seq := sequence
 
-	myfile.WriteString(fmt.Sprintf("%5v %26s %s\n", seq, note, msg))
+	if globals.Params.Logjson {
+		r := newRecord(name, nil, note)
+		r.Peer = peer
+		r.Message = msg
+		writeRecord(myfile, r)
+		return
+	}
+	myfile.WriteString(fmt.Sprintf("%5v %26s %s %s\n", seq, note, peer, msg))
 }
 
 // Log a message with a state timestamp
 func StateLogMessage(FactomNodeName string, DBHeight int, CurrentMinute int, logName string, comment string, msg interfaces.IMsg) {
-	logFileName := FactomNodeName + "_" + logName + ".txt"
-	t := fmt.Sprintf("%7d-:-%d ", DBHeight, CurrentMinute)
-	LogMessage(logFileName, t+comment, msg)
+	NodeLogMessage(Fields{Node: FactomNodeName, DBHeight: DBHeight, Minute: CurrentMinute, VM: -1}, logName, comment, msg)
 }
 
 // Log a printf with a state timestamp
 func StateLogPrintf(FactomNodeName string, DBHeight int, CurrentMinute int, logName string, format string, more ...interface{}) {
-	logFileName := FactomNodeName + "_" + logName + ".txt"
-	t := fmt.Sprintf("%7d-:-%d ", DBHeight, CurrentMinute)
-	LogPrintf(logFileName, t+format, more...)
+	NodeLogPrintf(Fields{Node: FactomNodeName, DBHeight: DBHeight, Minute: CurrentMinute, VM: -1}, logName, format, more...)
+}
+
+// NodeLogMessage logs a message to the node's log with the fields of the node
+func NodeLogMessage(f Fields, logName string, comment string, msg interfaces.IMsg) {
+	logFileName := f.Node + "_" + logName + ".txt"
+	traceMutex.Lock()
+	defer traceMutex.Unlock()
+	logMessage(logFileName, &f, comment, msg, false)
+}
+
+// NodeLogPrintf logs a printf to the node's log with the fields of the node
+func NodeLogPrintf(f Fields, logName string, format string, more ...interface{}) {
+	logFileName := f.Node + "_" + logName + ".txt"
+	logPrintf(logFileName, &f, format, more...)
+}
+
+// historyTail is how much of the end of each debug log MessageHistory searches
+const historyTail = 1 << 20
+
+// readTail returns the complete lines in the last size bytes of a file
+func readTail(name string, size int64) ([]byte, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := info.Size() - size
+	if offset < 0 {
+		offset = 0
+	}
+	data := make([]byte, info.Size()-offset)
+	n, err := f.ReadAt(data, offset)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	data = data[:n]
+	if offset > 0 {
+		// drop the partial line the tail starts in
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		} else {
+			data = nil
+		}
+	}
+	return data, nil
+}
+
+// MessageHistory returns the last max lines of a node's debug logs that mention a message, as
+// the log name followed by the line, in the order they were logged.  JSON records are matched on
+// their hashes and returned as they are.  Only the logs enabled by the debug log regex have
+// anything to search, and only the last historyTail bytes of each log are searched.
+func MessageHistory(FactomNodeName string, msgHash string, max int) []string {
+	if len(msgHash) < 6 {
+		return nil
+	}
+	tags := []string{"M-" + msgHash[:6], "H-" + msgHash[:6]}
+	prefix := globals.Params.DebugLogLocation + strings.ToLower(FactomNodeName) + "_"
+
+	traceMutex.Lock()
+	var names []string
+	for name := range files {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	traceMutex.Unlock()
+
+	type line struct {
+		sequence int
+		text     string
+	}
+	var lines []line
+	for _, name := range names {
+		data, err := readTail(name, historyTail)
+		if err != nil {
+			continue
+		}
+		logName := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".txt")
+		for _, text := range strings.Split(string(data), "\n") {
+			if strings.HasPrefix(text, "{") {
+				r := new(Record)
+				if json.Unmarshal([]byte(text), r) != nil ||
+					!strings.HasPrefix(r.MsgHash, msgHash[:6]) && !strings.HasPrefix(r.Hash, msgHash[:6]) {
+					continue
+				}
+				lines = append(lines, line{r.Seq, logName + " " + text})
+				continue
+			}
+			if !strings.Contains(text, tags[0]) && !strings.Contains(text, tags[1]) {
+				continue
+			}
+			var seq int
+			fmt.Sscanf(text, "%d", &seq)
+			lines = append(lines, line{seq, logName + " " + strings.TrimSpace(text)})
+		}
+	}
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].sequence < lines[j].sequence })
+	if len(lines) > max {
+		lines = lines[len(lines)-max:]
+	}
+
+	history := make([]string, 0, len(lines))
+	for _, l := range lines {
+		history = append(history, l.text)
+	}
+	return history
 }
 
 // unused -- of.File is written by direct calls to write and not buffered and the os closes the files on exit.
 func Cleanup() {
 	traceMutex.Lock()
 	defer traceMutex.Unlock()
+	closeTraceFiles()
+}
+
+// assumes traceMutex is locked already
+func closeTraceFiles() {
 	for name, f := range files {
 		delete(files, name)
 		f.Close()
@@ -1,11 +1,103 @@
 package log_test
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/FactomProject/factomd/common/globals"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/FactomProject/factomd/log"
 )
 
+// debugLogsToTempDir writes the debug logs that match regex to a new temporary directory, as
+// -debuglog does.  The returned function closes the logs, removes the directory and puts the
+// debug log settings back as they were.
+func debugLogsToTempDir(t *testing.T, regex string) (dir string, restore func()) {
+	dir, err := ioutil.TempDir("", "debuglog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir += string(os.PathSeparator)
+
+	old := globals.Params.DebugLogLocation + globals.Params.DebugLogRegEx
+	oldJSON := globals.Params.Logjson
+	log.Cleanup()
+	globals.Params.DebugLogRegEx, globals.Params.DebugLogLocation = dir+regex, ""
+
+	return dir, func() {
+		log.Cleanup()
+		os.RemoveAll(dir)
+		// split up and compiled again by the next log
+		globals.Params.DebugLogRegEx, globals.Params.DebugLogLocation = old, ""
+		globals.LastDebugLogRegEx = ""
+		globals.Params.Logjson = oldJSON
+	}
+}
+
 func TestLogPrintf(t *testing.T) {
 	log.LogPrintf("testing", "unittest %v", "FOO")
 }
+
+func TestLogJSON(t *testing.T) {
+	dir, restore := debugLogsToTempDir(t, "jsontest")
+	defer restore()
+	globals.Params.Logjson = true
+
+	eom := new(messages.EOM)
+	eom.Timestamp = primitives.NewTimestampNow()
+	eom.ChainID = primitives.NewHash([]byte("logjson"))
+	eom.SetNetworkOrigin("peer1")
+
+	f := log.Fields{Node: "jsontest", DBHeight: 12, Minute: 3, VM: 1}
+	log.NodeLogPrintf(f, "consensus", "line one\nline two %d", 2)
+	log.NodeLogMessage(f, "consensus", "enqueue", eom)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "jsontest_consensus.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 records, got %d:\n%s", len(lines), data)
+	}
+
+	printf := new(log.Record)
+	if err := json.Unmarshal([]byte(lines[0]), printf); err != nil {
+		t.Fatal(err)
+	}
+	if printf.Msg != "line one\nline two 2" || printf.Node != "jsontest" || printf.Level != "debug" ||
+		printf.DBHeight == nil || *printf.DBHeight != 12 || printf.Minute == nil || *printf.Minute != 3 || printf.VM == nil || *printf.VM != 1 {
+		t.Errorf("bad printf record %s", lines[0])
+	}
+
+	msg := new(log.Record)
+	if err := json.Unmarshal([]byte(lines[1]), msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Msg != "enqueue" || msg.MsgType != "EOM" || msg.Peer != "peer1" || msg.Hash != eom.GetHash().String() || msg.Seq <= printf.Seq {
+		t.Errorf("bad message record %s", lines[1])
+	}
+
+	history := log.MessageHistory("jsontest", eom.GetHash().String(), 10)
+	if len(history) != 1 || !strings.HasPrefix(history[0], "consensus {") {
+		t.Errorf("expected the message record in the history, got %v", history)
+	}
+
+	log.LogParcel("jsontest_parcels.txt", "send", "peer2", "parcel")
+	data, err = ioutil.ReadFile(filepath.Join(dir, "jsontest_parcels.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parcel := new(log.Record)
+	if err := json.Unmarshal(data, parcel); err != nil {
+		t.Fatal(err)
+	}
+	if parcel.Msg != "send" || parcel.Peer != "peer2" || parcel.Message != "parcel" {
+		t.Errorf("bad parcel record %s", data)
+	}
+}
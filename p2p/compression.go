@@ -0,0 +1,96 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// Payload compression is negotiated per connection. Every parcel we send carries
+// FlagCompressionCapable in its header (when CompressionEnabled is set).  Once a
+// peer has sent us a parcel with that flag we know it can decode compressed
+// payloads, and application messages above CompressionThreshold are deflated
+// before being written to that connection.  Peers running older versions never
+// set the flag, so they only ever see uncompressed payloads.
+
+var (
+	CompressionEnabled   = true // advertise and use payload compression with peers that support it
+	CompressionThreshold = 1024 // only compress application payloads of at least this many bytes
+	CompressionLevel     = flate.BestSpeed
+)
+
+// isCompressible returns true if the parcel is an application message large enough to be worth compressing
+func (p *Parcel) isCompressible() bool {
+	if p.Header.Flags&FlagPayloadCompressed != 0 {
+		return false
+	}
+	if p.Header.Type != TypeMessage && p.Header.Type != TypeMessagePart {
+		return false
+	}
+	return len(p.Payload) >= CompressionThreshold
+}
+
+// compress deflates the payload of the parcel and updates the header.  If the compressed
+// payload would not be smaller than the original, the parcel is left untouched and false
+// is returned.
+func (p *Parcel) compress() bool {
+	compressed, err := compressPayload(p.Payload)
+	if err != nil || len(compressed) >= len(p.Payload) {
+		return false
+	}
+	p.Payload = compressed
+	p.Header.Flags |= FlagPayloadCompressed
+	p.UpdateHeader()
+	return true
+}
+
+// decompress inflates a compressed payload in place and updates the header to describe
+// the original payload.
+func (p *Parcel) decompress() error {
+	if p.Header.Flags&FlagPayloadCompressed == 0 {
+		return nil
+	}
+	payload, err := decompressPayload(p.Payload)
+	if err != nil {
+		return err
+	}
+	p.Payload = payload
+	p.Header.Flags &^= FlagPayloadCompressed
+	p.UpdateHeader()
+	return nil
+}
+
+func compressPayload(payload []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, CompressionLevel)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(payload); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decompressPayload(payload []byte) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(payload))
+	defer r.Close()
+	// Read one byte past the limit so we can tell an oversized payload from one exactly at the limit
+	data, err := ioutil.ReadAll(io.LimitReader(r, MaxPayloadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxPayloadSize {
+		return nil, fmt.Errorf("decompressed payload exceeds %d bytes", MaxPayloadSize)
+	}
+	return data, nil
+}
@@ -0,0 +1,81 @@
+package p2p
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestParcelCompressionRoundTrip(t *testing.T) {
+	payload := bytes.Repeat([]byte("factom entry data "), 500)
+	parcel := NewParcel(TestNet, payload)
+
+	if !parcel.isCompressible() {
+		t.Fatalf("expected a %d byte message parcel to be compressible", len(payload))
+	}
+	if !parcel.compress() {
+		t.Fatal("failed to compress a highly redundant payload")
+	}
+	if parcel.Header.Flags&FlagPayloadCompressed == 0 {
+		t.Error("compressed parcel is missing FlagPayloadCompressed")
+	}
+	if int(parcel.Header.Length) >= len(payload) || parcel.Header.Length != uint32(len(parcel.Payload)) {
+		t.Errorf("unexpected header length %d for compressed payload of %d bytes", parcel.Header.Length, len(parcel.Payload))
+	}
+	if parcel.isCompressible() {
+		t.Error("an already compressed parcel should not be compressed again")
+	}
+
+	if err := parcel.decompress(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(parcel.Payload, payload) {
+		t.Error("decompressed payload does not match the original")
+	}
+	if parcel.Header.Flags&FlagPayloadCompressed != 0 {
+		t.Error("decompressed parcel still has FlagPayloadCompressed set")
+	}
+	if parcel.Header.Length != uint32(len(payload)) {
+		t.Errorf("expected header length %d, got %d", len(payload), parcel.Header.Length)
+	}
+}
+
+func TestParcelCompressionSkipped(t *testing.T) {
+	small := NewParcel(TestNet, []byte("Ping"))
+	small.Header.Type = TypeMessage
+	if small.isCompressible() {
+		t.Error("payloads below CompressionThreshold should not be compressed")
+	}
+
+	ping := NewParcel(TestNet, bytes.Repeat([]byte{0}, CompressionThreshold*2))
+	ping.Header.Type = TypePing
+	if ping.isCompressible() {
+		t.Error("only application messages should be compressed")
+	}
+
+	// random data does not shrink, so it is sent as is
+	noise := make([]byte, CompressionThreshold*2)
+	rand.New(rand.NewSource(1)).Read(noise)
+	parcel := NewParcel(TestNet, noise)
+	header := parcel.Header
+	if parcel.compress() {
+		t.Fatal("compress() accepted a payload that did not shrink")
+	}
+	if !bytes.Equal(parcel.Payload, noise) {
+		t.Error("payload changed for a parcel that was left uncompressed")
+	}
+	if parcel.Header != header {
+		t.Errorf("header changed for a parcel that was left uncompressed: %+v, want %+v", parcel.Header, header)
+	}
+	if parcel.Header.Flags&FlagPayloadCompressed != 0 {
+		t.Error("uncompressed parcel is flagged as compressed")
+	}
+}
+
+func TestDecompressGarbage(t *testing.T) {
+	parcel := NewParcel(TestNet, []byte{0xde, 0xad, 0xbe, 0xef, 0x01, 0x02})
+	parcel.Header.Flags |= FlagPayloadCompressed
+	if err := parcel.decompress(); err == nil {
+		t.Error("expected an error decompressing garbage")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"hash/crc32"
 	"net"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/FactomProject/factomd/common/messages"
@@ -29,6 +30,9 @@ var conLogger = packageLogger.WithField("subpack", "connection")
 // (defined below).
 type Connection struct {
 	conn           net.Conn
+	transport      Transport               // how we dial the peer
+	nodeID         uint64                  // NodeID of the node that owns the connection, for loopback protection
+	listenPort     string                  // port the node that owns the connection listens on, sent to the peer
 	Errors         chan error              // handle errors from connections.
 	Commands       chan *ConnectionCommand // handle connection commands
 	SendChannel    chan interface{}        // Send means "towards the network" Channel sends Parcels and ConnectionCommands
@@ -50,6 +54,9 @@ type Connection struct {
 	isPersistent    bool              // Persistent connections we always redail.
 	notes           string            // Notes about the connection, for debugging (eg: error)
 	metrics         ConnectionMetrics // Metrics about this connection
+	peerCompression atomic.AtomicBool // The peer advertised it can decode compressed payloads
+	peerSignedPeers atomic.AtomicBool // The peer advertised it understands signed peer exchanges
+	mutex           sync.Mutex        // guards state, conn, encoder, decoder and metrics, shared by the runLoop, processSends, processReceives and the controller
 
 	// logging
 	logger *log.Entry
@@ -100,14 +107,17 @@ func (e *ConnectionParcel) String() string {
 
 // ConnectionMetrics is used to encapsulate various metrics about the connection.
 type ConnectionMetrics struct {
-	MomentConnected  time.Time // when the connection started.
-	BytesSent        uint32    // Keeping track of the data sent/received for console
-	BytesReceived    uint32    // Keeping track of the data sent/received for console
-	MessagesSent     uint32    // Keeping track of the data sent/received for console
-	MessagesReceived uint32    // Keeping track of the data sent/received for console
-	PeerAddress      string    // Peer IP Address
-	PeerQuality      int32     // Quality of the connection.
-	PeerType         string    // Type of the peer (regular, special_config, ...)
+	MomentConnected   time.Time // when the connection started.
+	BytesSent         uint32    // Keeping track of the data sent/received for console
+	BytesReceived     uint32    // Keeping track of the data sent/received for console
+	MessagesSent      uint32    // Keeping track of the data sent/received for console
+	MessagesReceived  uint32    // Keeping track of the data sent/received for console
+	PeerAddress       string    // Peer IP Address
+	PeerQuality       int32     // Quality of the connection.
+	PeerType          string    // Type of the peer (regular, special_config, ...)
+	Compression       bool      // Payload compression negotiated with the peer
+	BytesUncompressed uint32    // Size of the application payloads we compressed, before compression
+	BytesCompressed   uint32    // Size of the application payloads we compressed, after compression
 	// Red: Below -50
 	// Yellow: -50 - 100
 	// Green: > 100
@@ -176,11 +186,11 @@ func (c *Connection) IsOutGoing() bool {
 }
 
 func (c *Connection) IsOnline() bool {
-	return ConnectionOnline == c.state
+	return ConnectionOnline == c.getState()
 }
 
 func (c *Connection) StatusString() string {
-	return connectionStateStrings[c.state]
+	return connectionStateStrings[c.getState()]
 }
 
 func (c *Connection) IsPersistent() bool {
@@ -197,10 +207,44 @@ func (c *Connection) Notes() string {
 //
 //////////////////////////////
 
+func (c *Connection) getState() uint8 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.state
+}
+
+func (c *Connection) setState(state uint8) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.state = state
+}
+
+// getConn returns the network connection and its encoder and decoder, which are nil when we are not online
+func (c *Connection) getConn() (net.Conn, *gob.Encoder, *gob.Decoder) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.conn, c.encoder, c.decoder
+}
+
+func (c *Connection) bytesReceived() uint32 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.metrics.BytesReceived
+}
+
 func (c *Connection) commonInit(peer Peer) {
 	p2pConnectionCommonInit.Inc() // Prometheus
 	c.state = ConnectionInitialized
 	c.peer = peer
+	if c.transport == nil {
+		c.transport = TCPTransport
+	}
+	if c.nodeID == 0 {
+		c.nodeID = NodeID
+	}
+	if c.listenPort == "" {
+		c.listenPort = NetworkListenPort
+	}
 	c.logger = conLogger.WithFields(c.peer.PeerLogFields())
 	c.logger.Debug("Initializing connection")
 	c.Errors = make(chan error, StandardChannelSize)
@@ -223,8 +267,13 @@ func (c *Connection) Start() {
 func (c *Connection) CopyMetricsFrom(another *Connection) {
 	// perform a shallow copy of the metrics, but update the state with the
 	// current value
-	c.metrics = another.metrics
+	another.mutex.Lock()
+	metrics := another.metrics
+	another.mutex.Unlock()
+	c.mutex.Lock()
+	c.metrics = metrics
 	c.metrics.ConnectionState = connectionStateStrings[c.state]
+	c.mutex.Unlock()
 }
 
 // runloop OWNs the connection.  It is the only goroutine that can change values in the connection struct
@@ -236,13 +285,14 @@ func (c *Connection) runLoop() {
 	defer p2pConnectionsRunLoop.Dec()
 	var pstate uint8 = 255
 
-	for ConnectionClosed != c.state { // loop exits when we hit shutdown state
+	for ConnectionClosed != c.getState() { // loop exits when we hit shutdown state
 		time.Sleep(100 * time.Millisecond) // This can be a tight loop, don't want to starve the application
 		c.updateStats()                    // Update controller with metrics
 		c.handleNetErrors(false)
 		c.handleCommand()
 
-		var stateLogger = c.logger.WithField("current_state", connectionStateStrings[c.state])
+		state := c.getState()
+		var stateLogger = c.logger.WithField("current_state", connectionStateStrings[state])
 
 	parcelloop:
 		for {
@@ -257,8 +307,8 @@ func (c *Connection) runLoop() {
 		}
 
 		// log state changes on peers
-		if pstate != c.state {
-			switch c.state {
+		if pstate != state {
+			switch state {
 			case ConnectionInitialized:
 				messages.LogPrintf("fnode0_peers.txt", "ConnectionInitialized(%s)", c.peer.Hash)
 			case ConnectionOnline:
@@ -270,10 +320,10 @@ func (c *Connection) runLoop() {
 			default:
 				messages.LogPrintf("fnode0_peers.txt", "ConnectionUnknownState(%s)", c.peer.Hash)
 			}
-			pstate = c.state
+			pstate = state
 		}
 
-		switch c.state {
+		switch state {
 		case ConnectionInitialized:
 			p2pConnectionRunLoopInitialized.Inc()
 			if MinumumQualityScore > c.peer.QualityScore && !c.isPersistent {
@@ -281,8 +331,8 @@ func (c *Connection) runLoop() {
 				c.updatePeer() // every PeerSaveInterval * 0.90 we send an update peer to the controller.
 				c.goShutdown()
 			} else {
-				c.state = ConnectionOffline // We now view this as an offline connection
-				c.dialLoop()                // dialLoop dials until it connects or shuts down.
+				c.setState(ConnectionOffline) // We now view this as an offline connection
+				c.dialLoop()                  // dialLoop dials until it connects or shuts down.
 			}
 		case ConnectionOnline:
 			p2pConnectionRunLoopOnline.Inc()
@@ -309,7 +359,7 @@ func (c *Connection) runLoop() {
 		case ConnectionShuttingDown:
 			p2pConnectionRunLoopShutdown.Inc()
 			stateLogger.Debug("Connection is shutting down")
-			c.state = ConnectionClosed
+			c.setState(ConnectionClosed)
 			BlockFreeChannelSend(c.ReceiveChannel, ConnectionCommand{Command: ConnectionIsClosed})
 			return // ending runloop() goroutine
 		default:
@@ -336,7 +386,7 @@ func (c *Connection) dialLoop() {
 		}
 		switch {
 		case c.isPersistent:
-		case ConnectionOffline == c.state: // We were online with the peer at one point.
+		case ConnectionOffline == c.getState(): // We were online with the peer at one point.
 			c.attempts++
 			if MaxNumberOfRedialAttempts < c.attempts {
 				c.logger.Info("Cannot contact peer, shutting down")
@@ -357,9 +407,11 @@ func (c *Connection) dialLoop() {
 func (c *Connection) dial() bool {
 	address := c.peer.AddressPort()
 	// conn, err := net.Dial("tcp", c.peer.Address)
-	conn, err := net.DialTimeout("tcp", address, time.Second*10)
+	conn, err := c.transport.DialTimeout(address, time.Second*10)
 	if nil == err {
+		c.mutex.Lock()
 		c.conn = conn
+		c.mutex.Unlock()
 		return true
 	}
 	return false
@@ -371,15 +423,17 @@ func (c *Connection) goOnline() {
 	c.logger.Info("Connected to a remote peer")
 	p2pConnectionOnlineCall.Inc()
 	now := time.Now()
-	c.encoder = gob.NewEncoder(c.conn)
-	c.decoder = gob.NewDecoder(c.conn)
 	c.attempts = 0
 	c.timeLastPing = now
 	c.timeLastAttempt = now
 	c.timeLastUpdate = now
 	c.peer.LastContact = now
 
+	c.mutex.Lock()
+	c.encoder = gob.NewEncoder(c.conn)
+	c.decoder = gob.NewDecoder(c.conn)
 	c.state = ConnectionOnline
+	c.mutex.Unlock()
 
 	// Drain the handleNetErrors to avoid immediate disconnect
 	c.handleNetErrors(true)
@@ -395,12 +449,14 @@ func (c *Connection) goOffline() {
 	messages.LogPrintf("fnode0_peers.txt", "goOffline(%s) %s", c.peer.Hash, atomic.WhereAmIString(1))
 	c.logger.Debug("Going offline")
 	p2pConnectionOfflineCall.Inc()
+	c.mutex.Lock()
 	if nil != c.conn {
 		defer c.conn.Close()
 	}
 	c.decoder = nil
 	c.encoder = nil
 	c.state = ConnectionOffline
+	c.mutex.Unlock()
 	c.attempts = 0
 	c.peer.demerit()
 }
@@ -410,12 +466,14 @@ func (c *Connection) goShutdown() {
 	c.logger.Debug("Connection shutting down")
 	c.goOffline()
 	c.updatePeer()
+	c.mutex.Lock()
 	if nil != c.conn {
 		defer c.conn.Close()
 	}
 	c.decoder = nil
 	c.encoder = nil
 	c.state = ConnectionShuttingDown
+	c.mutex.Unlock()
 }
 
 // processSends gets all the messages from the application and sends them out over the network
@@ -432,10 +490,10 @@ func (c *Connection) processSends() {
 		}
 	}()
 
-	for ConnectionClosed != c.state && c.state != ConnectionShuttingDown {
+	for state := c.getState(); ConnectionClosed != state && state != ConnectionShuttingDown; state = c.getState() {
 		// note(c.peer.PeerIdent(), "Connection.processSends() called. Items in send channel: %d State: %s", len(c.SendChannel), c.ConnectionState())
 	conloop:
-		for ConnectionOnline == c.state && len(c.SendChannel) > 0 {
+		for ConnectionOnline == c.getState() && len(c.SendChannel) > 0 {
 			// This was blocking. By checking the length of the channel before entering, this does not block.
 			// The problem was this routine was blocked on a closed connection. Idealling we do want to block
 			// on a 0 length channel, and this is still possible if use a select and close the channel when we
@@ -443,11 +501,12 @@ func (c *Connection) processSends() {
 			message := <-c.SendChannel
 			switch message.(type) {
 			case ConnectionParcel:
-				if nil == c.decoder || nil == c.conn {
+				conn, encoder, _ := c.getConn()
+				if nil == encoder || nil == conn {
 					break conloop
 				}
 				parameters := message.(ConnectionParcel)
-				c.sendParcel(parameters.Parcel)
+				c.sendParcel(conn, encoder, parameters.Parcel)
 			case ConnectionCommand:
 				parameters := message.(ConnectionCommand)
 				c.Commands <- &parameters
@@ -492,20 +551,39 @@ func (c *Connection) handleCommand() {
 	}
 }
 
-func (c *Connection) sendParcel(parcel Parcel) {
+func (c *Connection) sendParcel(conn net.Conn, encoder *gob.Encoder, parcel Parcel) {
 	if parcel.Header.Type == TypeMessagePart {
 		messages.LogPrintf("fnode0_peers.txt", "sendParcel(%s) M-%s %d of %d", c.peer.Hash, parcel.Header.AppHash[:6], parcel.Header.PartNo+1, parcel.Header.PartsTotal)
 	} else {
 		messages.LogPrintf("fnode0_peers.txt", "sendParcel(%s) type %s", c.peer.Hash, parcel.MessageType())
 	}
 
-	parcel.Header.NodeID = NodeID // Send it out with our ID for loopback.
-	c.conn.SetWriteDeadline(time.Now().Add(NetworkDeadline))
-	err := c.encoder.Encode(parcel)
+	parcel.Header.NodeID = c.nodeID // Send it out with our ID for loopback.
+	parcel.Header.PeerPort = c.listenPort
+	parcel.Header.Flags |= FlagSignedPeerExchange
+	if CompressionEnabled {
+		parcel.Header.Flags |= FlagCompressionCapable
+		if c.peerCompression.Load() && parcel.isCompressible() {
+			originalLength := parcel.Header.Length
+			if parcel.compress() {
+				c.mutex.Lock()
+				c.metrics.BytesUncompressed += originalLength
+				c.metrics.BytesCompressed += parcel.Header.Length
+				c.mutex.Unlock()
+				p2pCompressionBytesUncompressed.Add(float64(originalLength))
+				p2pCompressionBytesCompressed.Add(float64(parcel.Header.Length))
+				p2pCompressionRatio.Observe(float64(originalLength) / float64(parcel.Header.Length))
+			}
+		}
+	}
+	conn.SetWriteDeadline(time.Now().Add(NetworkDeadline))
+	err := encoder.Encode(parcel)
 	switch {
 	case nil == err:
+		c.mutex.Lock()
 		c.metrics.BytesSent += parcel.Header.Length
 		c.metrics.MessagesSent += 1
+		c.mutex.Unlock()
 	default:
 		messages.LogPrintf("fnode0_peers.txt", "sendParcel(%s) M-%s", c.peer.Hash, parcel.Header.AppHash[:6], err.Error())
 		c.Errors <- err
@@ -529,16 +607,20 @@ func (c *Connection) processReceives() {
 		}
 	}()
 
-	for ConnectionClosed != c.state && c.state != ConnectionShuttingDown {
-		for c.state == ConnectionOnline {
+	for state := c.getState(); ConnectionClosed != state && state != ConnectionShuttingDown; state = c.getState() {
+		for c.getState() == ConnectionOnline {
 			var parcel Parcel
 
-			c.conn.SetReadDeadline(time.Now().Add(NetworkDeadline))
-			err := c.decoder.Decode(&parcel)
+			conn, _, decoder := c.getConn()
+			if nil == decoder || nil == conn {
+				break
+			}
+			conn.SetReadDeadline(time.Now().Add(NetworkDeadline))
+			err := decoder.Decode(&parcel)
 			switch err {
 			case nil: // successfully decoded
 				if messages.CheckFileName("peers.txt") { // Debug only if log file enabled
-					if parcel.Header.Type == TypeMessagePart {
+					if parcel.Header.Type == TypeMessagePart && parcel.Header.Flags&FlagPayloadCompressed == 0 {
 						msg, err := messages.Unmarshal_Message(parcel.Payload)
 						if err == nil {
 							parcel.Header.AppHash = fmt.Sprintf("%x", msg.GetMsgHash().Bytes())
@@ -550,11 +632,12 @@ func (c *Connection) processReceives() {
 					}
 				}
 
+				c.mutex.Lock()
 				c.metrics.BytesReceived += parcel.Header.Length
 				c.metrics.MessagesReceived += 1
+				c.mutex.Unlock()
 				parcel.Header.PeerAddress = c.peer.Address
-				c.ReceiveParcel <- &parcel
-				c.TimeLastpacket = time.Now()
+				c.ReceiveParcel <- &parcel // the runLoop sets TimeLastpacket when it handles the parcel
 			default: // error
 				messages.LogPrintf("fnode0_peers.txt", "processReceives(%s) %s", c.peer.Hash, err.Error())
 				c.Errors <- err
@@ -619,6 +702,19 @@ func (c *Connection) handleParcel(parcel Parcel) {
 		c.peer.LastContact = time.Now() // We only update for valid messages (including pings and heartbeats)
 		c.attempts = 0                  // reset since we are clearly in touch now.
 		c.peer.merit()                  // Increase peer quality score.
+		if CompressionEnabled && parcel.Header.Flags&FlagCompressionCapable != 0 && !c.peerCompression.Load() {
+			c.logger.Debug("Peer supports payload compression")
+			c.peerCompression.Store(true)
+		}
+		if parcel.Header.Flags&FlagSignedPeerExchange != 0 && !c.peerSignedPeers.Load() {
+			c.peerSignedPeers.Store(true)
+		}
+		if err := parcel.decompress(); err != nil {
+			c.logger.Warnf("Connection.handleParcel() failed to decompress payload: %s", err.Error())
+			p2pCompressionFailures.Inc()
+			c.peer.demerit()
+			return
+		}
 		c.logger.Debugf("Connection.handleParcel() got ParcelValid %s", parcel.MessageType())
 		c.handleParcelTypes(parcel) // handles both network commands and application messages
 		return
@@ -640,7 +736,7 @@ func (c *Connection) parcelValidity(parcel Parcel) uint8 {
 	c.logger.Debugf("Connection.isValidParcel(%s)", parcel.MessageType())
 	crc := crc32.Checksum(parcel.Payload, CRCKoopmanTable)
 	switch {
-	case parcel.Header.NodeID == NodeID: // We are talking to ourselves!
+	case parcel.Header.NodeID == c.nodeID: // We are talking to ourselves!
 		parcel.LogEntry().Debug("Connection.isValidParcel()-loopback")
 		c.logger.Warnf("Connection.isValidParcel(), failed due to loopback!: %+v", parcel.Header)
 		c.peer.QualityScore = MinumumQualityScore - 50 // Ban ourselves for a week
@@ -681,17 +777,19 @@ func (c *Connection) handleParcelTypes(parcel Parcel) {
 		BlockFreeChannelSend(c.ReceiveChannel, ConnectionParcel{Parcel: parcel}) // Controller handles these.
 	case TypePeerResponse:
 		BlockFreeChannelSend(c.ReceiveChannel, ConnectionParcel{Parcel: parcel}) // Controller handles these.
+	case TypeSignedPeerResponse:
+		BlockFreeChannelSend(c.ReceiveChannel, ConnectionParcel{Parcel: parcel}) // Controller handles these.
 	case TypeMessage:
 		c.peer.QualityScore = c.peer.QualityScore + 1
 		// Store our connection ID so the controller can direct response to us.
 		parcel.Header.TargetPeer = c.peer.Hash
-		parcel.Header.NodeID = NodeID
+		parcel.Header.NodeID = c.nodeID
 		BlockFreeChannelSend(c.ReceiveChannel, ConnectionParcel{Parcel: parcel}) // Controller handles these.
 	case TypeMessagePart:
 		c.peer.QualityScore = c.peer.QualityScore + 1
 		// Store our connection ID so the controller can direct response to us.
 		parcel.Header.TargetPeer = c.peer.Hash
-		parcel.Header.NodeID = NodeID
+		parcel.Header.NodeID = c.nodeID
 		BlockFreeChannelSend(c.ReceiveChannel, ConnectionParcel{Parcel: parcel}) // Controller handles these.
 	default:
 		c.logger.Warn("Got message of unknown type?")
@@ -724,16 +822,20 @@ func (c *Connection) updatePeer() {
 func (c *Connection) updateStats() {
 	if time.Second < time.Since(c.timeLastMetrics) {
 		c.timeLastMetrics = time.Now()
+		c.mutex.Lock()
 		c.metrics.PeerAddress = c.peer.Address
 		c.metrics.PeerQuality = c.peer.QualityScore
 		c.metrics.PeerType = c.peer.PeerTypeString()
 		c.metrics.ConnectionState = connectionStateStrings[c.state]
 		c.metrics.ConnectionNotes = c.notes
-		c.logger.Debugf("updatePeer() SENDING ConnectionUpdateMetrics - Bytes Sent: %d Bytes Received: %d", c.metrics.BytesSent, c.metrics.BytesReceived)
-		BlockFreeChannelSend(c.ReceiveChannel, ConnectionCommand{Command: ConnectionUpdateMetrics, Metrics: c.metrics})
+		c.metrics.Compression = CompressionEnabled && c.peerCompression.Load()
+		metrics := c.metrics
+		c.mutex.Unlock()
+		c.logger.Debugf("updatePeer() SENDING ConnectionUpdateMetrics - Bytes Sent: %d Bytes Received: %d", metrics.BytesSent, metrics.BytesReceived)
+		BlockFreeChannelSend(c.ReceiveChannel, ConnectionCommand{Command: ConnectionUpdateMetrics, Metrics: metrics})
 	}
 }
 
 func (c *Connection) ConnectionState() string {
-	return connectionStateStrings[c.state]
+	return connectionStateStrings[c.getState()]
 }
@@ -106,7 +106,7 @@ func (cm *ConnectionManager) UpdatePrometheusMetrics() {
 // returns nil if none are found.
 func (cm *ConnectionManager) GetRandom() *Connection {
 	onlineActive := cm.getMatching(func(c *Connection) bool {
-		return c.IsOnline() && c.metrics.BytesReceived > 0
+		return c.IsOnline() && c.bytesReceived() > 0
 	})
 
 	if len(onlineActive) == 0 {
@@ -123,7 +123,7 @@ func (cm *ConnectionManager) GetAllRegular(msgHash [32]byte) []*Connection {
 
 	selection := cm.getMatching(func(c *Connection) bool {
 
-		b := c.IsOnline() && !c.peer.IsSpecial() && c.metrics.BytesReceived > 0 && !c.peer.PrevMsgs.Get(msgHash)
+		b := c.IsOnline() && !c.peer.IsSpecial() && c.bytesReceived() > 0 && !c.peer.PrevMsgs.Get(msgHash)
 		return b
 	})
 
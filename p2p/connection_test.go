@@ -13,7 +13,7 @@ func TestParcelString(t *testing.T) {
 	c := new(ConnectionParcel)
 	c.Parcel = *p
 
-	correct := `{"Parcel":{"Header":{"Network":0,"Version":9,"Type":6,"Length":1,"TargetPeer":"","Crc32":4278190080,"PartNo":0,"PartsTotal":0,"NodeID":0,"PeerAddress":"","PeerPort":"8108","AppHash":"NetworkMessage","AppType":"Network"},"Payload":"/w=="}}`
+	correct := `{"Parcel":{"Header":{"Network":0,"Version":9,"Type":6,"Length":1,"TargetPeer":"","Crc32":4278190080,"PartNo":0,"PartsTotal":0,"NodeID":0,"PeerAddress":"","PeerPort":"8108","AppHash":"NetworkMessage","AppType":"Network","Flags":0},"Payload":"/w=="}}`
 	data, err := c.JSONByte()
 	if err != nil {
 		t.Error(err)
@@ -43,7 +43,7 @@ func TestConnectionCommandString(t *testing.T) {
 	c.Command = 4
 	c.Delta = 2
 
-	correct := `{"Command":4,"Peer":{"QualityScore":0,"Address":"","Port":"","NodeID":0,"Hash":"","Location":0,"Network":0,"Type":0,"Connections":0,"LastContact":"0001-01-01T00:00:00Z","Source":null},"Delta":2,"Metrics":{"MomentConnected":"0001-01-01T00:00:00Z","BytesSent":0,"BytesReceived":0,"MessagesSent":0,"MessagesReceived":0,"PeerAddress":"","PeerQuality":0,"PeerType":"","ConnectionState":"","ConnectionNotes":""}}`
+	correct := `{"Command":4,"Peer":{"QualityScore":0,"Address":"","Port":"","NodeID":0,"Hash":"","Location":0,"Network":0,"Type":0,"Connections":0,"LastContact":"0001-01-01T00:00:00Z","Source":null,"Trust":0},"Delta":2,"Metrics":{"MomentConnected":"0001-01-01T00:00:00Z","BytesSent":0,"BytesReceived":0,"MessagesSent":0,"MessagesReceived":0,"PeerAddress":"","PeerQuality":0,"PeerType":"","Compression":false,"BytesUncompressed":0,"BytesCompressed":0,"ConnectionState":"","ConnectionNotes":""}}`
 
 	data, err := c.JSONByte()
 	if err != nil {
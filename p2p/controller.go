@@ -14,18 +14,21 @@ import (
 	"fmt"
 	"math/rand"
 	"net"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 
 	"github.com/FactomProject/factomd/common/primitives"
+	llog "github.com/FactomProject/factomd/log"
 
 	log "github.com/sirupsen/logrus"
 )
 
 // packageLogger is the general logger for all p2p related logs. You can add additional fields,
 // or create more context loggers off of this
-var packageLogger = log.WithFields(log.Fields{
+var packageLogger = llog.SubsystemLogger(llog.SubsystemP2P).WithFields(log.Fields{
 	"package":   "p2p",
 	"component": "networking"})
 
@@ -36,6 +39,7 @@ type Controller struct {
 	keepRunning bool // Indicates its time to shut down when false.
 
 	listenPort  string             // port we listen on for new connections
+	transport   Transport          // how we listen for and dial connections
 	connections *ConnectionManager // current connections
 
 	// After launching the network, the management is done via these channels.
@@ -48,6 +52,8 @@ type Controller struct {
 
 	connectionMetrics           map[string]ConnectionMetrics // map of the metrics indexed by peer hash
 	lastConnectionMetricsUpdate time.Time                    // update once a second.
+	metricsSnapshot             map[string]ConnectionMetrics // copy of the metrics published by updateMetrics, safe to read from other goroutines
+	metricsSnapshotMutex        sync.RWMutex
 
 	discovery Discovery // Our discovery structure
 
@@ -57,6 +63,7 @@ type Controller struct {
 	lastStatusReport     time.Time
 	lastPeerRequest      time.Time        // Last time we asked peers about the peers they know about.
 	specialPeers         map[string]*Peer // special peers (from config file and from the command line params) by peer address
+	specialPeersMutex    sync.RWMutex     // the special peers are reloaded from the API and config goroutines
 	partsAssembler       *PartsAssembler  // a data structure that assembles full messages from received message parts
 
 	// logging
@@ -76,6 +83,7 @@ type ControllerInit struct {
 	ConnectionMetricsChannel chan interface{} // Channel on which we put the connection metrics map, periodically.
 	LogPath                  string           // Path for logs
 	LogLevel                 string           // Logging level
+	Transport                Transport        // How to listen and dial, TCPTransport if nil
 }
 
 // CommandDialPeer is used to instruct the Controller to dial a peer address
@@ -132,6 +140,24 @@ func (e *CommandBan) String() string {
 	return str
 }
 
+// CommandUnban is used to instruct the Controller to lift a ban on a peer address
+type CommandUnban struct {
+	Address string
+}
+
+func (e *CommandUnban) JSONByte() ([]byte, error) {
+	return primitives.EncodeJSON(e)
+}
+
+func (e *CommandUnban) JSONString() (string, error) {
+	return primitives.EncodeJSONString(e)
+}
+
+func (e *CommandUnban) String() string {
+	str, _ := e.JSONString()
+	return str
+}
+
 // CommandDisconnect is used to instruct the Controller to disconnect from a peer
 type CommandDisconnect struct {
 	PeerHash string
@@ -165,17 +191,24 @@ func (c *Controller) Init(ci ControllerInit) *Controller {
 		"port":    ci.Port,
 		"network": fmt.Sprintf("%#x", ci.Network)})
 	c.logger.WithField("controller_init", ci).Debugf("Initializing network controller")
-	RandomGenerator = rand.New(rand.NewSource(time.Now().UnixNano()))
-	NodeID = uint64(RandomGenerator.Int63()) // This is a global used by all connections
+	seed := RandomSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	c.NodeID = uint64(rand.New(rand.NewSource(seed)).Int63()) // Used by our connections for loopback protection
 	c.keepRunning = true
 	c.commandChannel = make(chan interface{}, StandardChannelSize) // Commands from App
 	c.FromNetwork = make(chan interface{}, StandardChannelSize)    // Channel to the app for network data
 	c.ToNetwork = make(chan interface{}, StandardChannelSize)      // Parcels from the app for the network
 	c.connections = new(ConnectionManager).Init()
 	c.connectionMetrics = make(map[string]ConnectionMetrics)
+	c.metricsSnapshot = make(map[string]ConnectionMetrics)
 	c.connectionMetricsChannel = ci.ConnectionMetricsChannel
 	c.listenPort = ci.Port
-	NetworkListenPort = ci.Port
+	c.transport = ci.Transport
+	if c.transport == nil {
+		c.transport = TCPTransport
+	}
 	// Set this to the past so we will do peer management almost right away after starting up.
 	c.lastPeerManagement = time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
 	c.lastPeerRequest = time.Now()
@@ -186,7 +219,7 @@ func (c *Controller) Init(ci ControllerInit) *Controller {
 	c.lastDiscoveryRequest = time.Now() // Discovery does its own on startup.
 	c.lastConnectionMetricsUpdate = time.Now()
 	c.partsAssembler = new(PartsAssembler).Init()
-	discovery := new(Discovery).Init(ci.PeersFile, ci.SeedURL)
+	discovery := new(Discovery).Init(ci.PeersFile, ci.SeedURL, ci.Port)
 	c.discovery = *discovery
 	return c
 }
@@ -225,6 +258,12 @@ func (c *Controller) Ban(peerHash string) {
 	BlockFreeChannelSend(c.commandChannel, CommandBan{PeerHash: peerHash})
 }
 
+// Unban resets the quality score of a previously banned peer address so it may be dialed
+// and shared again.
+func (c *Controller) Unban(address string) {
+	BlockFreeChannelSend(c.commandChannel, CommandUnban{Address: address})
+}
+
 func (c *Controller) Disconnect(peerHash string) {
 	BlockFreeChannelSend(c.commandChannel, CommandDisconnect{PeerHash: peerHash})
 }
@@ -233,6 +272,41 @@ func (c *Controller) GetNumberOfConnections() int {
 	return c.connections.Count()
 }
 
+// GetConnectionMetrics returns a copy of the most recently published metrics of the
+// current connections, indexed by peer hash.  Safe to call from any goroutine.
+func (c *Controller) GetConnectionMetrics() map[string]ConnectionMetrics {
+	c.metricsSnapshotMutex.RLock()
+	defer c.metricsSnapshotMutex.RUnlock()
+	metrics := make(map[string]ConnectionMetrics, len(c.metricsSnapshot))
+	for hash, m := range c.metricsSnapshot {
+		metrics[hash] = m
+	}
+	return metrics
+}
+
+// GetSpecialPeers returns the addresses (address:port) of the special peers
+func (c *Controller) GetSpecialPeers() []string {
+	c.specialPeersMutex.RLock()
+	defer c.specialPeersMutex.RUnlock()
+	peers := make([]string, 0, len(c.specialPeers))
+	for _, peer := range c.specialPeers {
+		peers = append(peers, peer.AddressPort())
+	}
+	sort.Strings(peers)
+	return peers
+}
+
+// getSpecialPeers returns a copy of the special peers, to use without holding the lock
+func (c *Controller) getSpecialPeers() []*Peer {
+	c.specialPeersMutex.RLock()
+	defer c.specialPeersMutex.RUnlock()
+	peers := make([]*Peer, 0, len(c.specialPeers))
+	for _, peer := range c.specialPeers {
+		peers = append(peers, peer)
+	}
+	return peers
+}
+
 func (c *Controller) ReloadSpecialPeers(newPeersConfig string) {
 	c.logger.Info("Reloading special peers after config file change")
 	newPeers := make(map[string]*Peer)
@@ -240,6 +314,7 @@ func (c *Controller) ReloadSpecialPeers(newPeersConfig string) {
 		newPeers[newPeer.Address] = newPeer
 	}
 
+	c.specialPeersMutex.Lock()
 	toBeAdded := make([]*Peer, 0, len(newPeers))
 	toBeRemoved := make([]*Peer, 0, len(c.specialPeers))
 
@@ -253,7 +328,7 @@ func (c *Controller) ReloadSpecialPeers(newPeersConfig string) {
 
 	for address, oldPeer := range c.specialPeers {
 		_, exists := newPeers[address]
-		if exists {
+		if !exists {
 			if oldPeer.Type == SpecialPeerCmdLine {
 				c.logger.Warnf(
 					"Detected a peer removed from the config file,"+
@@ -269,11 +344,16 @@ func (c *Controller) ReloadSpecialPeers(newPeersConfig string) {
 
 	for _, peer := range toBeRemoved {
 		delete(c.specialPeers, peer.Address)
-		c.Disconnect(peer.Hash)
 	}
-
 	for _, peer := range toBeAdded {
 		c.specialPeers[peer.Address] = peer
+	}
+	c.specialPeersMutex.Unlock()
+
+	for _, peer := range toBeRemoved {
+		c.Disconnect(peer.Hash)
+	}
+	for _, peer := range toBeAdded {
 		c.DialPeer(*peer, true)
 	}
 }
@@ -292,7 +372,7 @@ func (c *Controller) ReloadSpecialPeers(newPeersConfig string) {
 //////////////////////////////////////////////////////////////////////
 
 func (c *Controller) dialSpecialPeers() {
-	for _, peer := range c.specialPeers {
+	for _, peer := range c.getSpecialPeers() {
 		c.DialPeer(*peer, true) // these are persistent connections
 	}
 }
@@ -300,7 +380,7 @@ func (c *Controller) dialSpecialPeers() {
 func (c *Controller) listen() {
 	address := fmt.Sprintf(":%s", c.listenPort)
 	c.logger.WithFields(log.Fields{"address": address, "port": c.listenPort}).Infof("Listening for new connections")
-	listener, err := net.Listen("tcp", address)
+	listener, err := c.transport.Listen(address)
 	listener = LimitListenerSources(listener)
 	if nil != err {
 		c.logger.Errorf("Controller.listen() Error: %+v", err)
@@ -346,7 +426,7 @@ func (c *Controller) canConnectTo(conn net.Conn) (bool, string) {
 }
 
 func (c *Controller) isSpecialPeer(conn net.Conn) bool {
-	for _, peer := range c.specialPeers {
+	for _, peer := range c.getSpecialPeers() {
 		if peer.IsSamePeerAs(conn.RemoteAddr()) {
 			return true
 		}
@@ -487,14 +567,22 @@ func (c *Controller) handleParcelReceive(message interface{}, peerHash string, c
 			BlockFreeChannelSend(c.FromNetwork, *assembled)
 		}
 	case TypePeerRequest: // send a response to the connection over its connection.SendChannel
-		// Get selection of peers from discovery
-		response := NewParcel(CurrentNetwork, c.discovery.SharePeers())
-		response.Header.Type = TypePeerResponse
+		// Get selection of peers from discovery, signed if the peer understands signed records
+		var response *Parcel
+		if connection.peerSignedPeers.Load() {
+			response = NewParcel(CurrentNetwork, c.discovery.ShareSignedPeers(connection.peer.Address))
+			response.Header.Type = TypeSignedPeerResponse
+		} else {
+			response = NewParcel(CurrentNetwork, c.discovery.SharePeers())
+			response.Header.Type = TypePeerResponse
+		}
 		// Send them out to the network - on the connection that requested it!
 		BlockFreeChannelSend(connection.SendChannel, ConnectionParcel{Parcel: *response})
 	case TypePeerResponse:
 		// Add these peers to our known peers
 		c.discovery.LearnPeers(parcel)
+	case TypeSignedPeerResponse:
+		c.discovery.LearnSignedPeers(parcel)
 	default:
 		c.logger.Warnf("handleParcelReceive() unknown parcel.Header.Type?: %+v ", parcel)
 	}
@@ -516,11 +604,17 @@ func (c *Controller) handleConnectionCommand(command ConnectionCommand, connecti
 	}
 }
 
+// newConnection returns a connection that dials with our transport and identifies as our node,
+// listening on our port
+func (c *Controller) newConnection() *Connection {
+	return &Connection{transport: c.transport, nodeID: c.NodeID, listenPort: c.listenPort}
+}
+
 func (c *Controller) handleCommand(command interface{}) {
 	switch commandType := command.(type) {
 	case CommandDialPeer: // parameter is the peer address
 		parameters := command.(CommandDialPeer)
-		conn := new(Connection).Init(parameters.peer, parameters.persistent)
+		conn := c.newConnection().Init(parameters.peer, parameters.persistent)
 		c.handleNewConnection(conn)
 	case CommandAddPeer: // parameter is a Connection. This message is sent by the accept loop which is in a different goroutine
 
@@ -530,7 +624,7 @@ func (c *Controller) handleCommand(command interface{}) {
 		// Port initially stored will be the connection port (not the listen port), but peer will update it on first message.
 		peer := new(Peer).Init(addPort[0], addPort[1], 0, RegularPeer, 0)
 		peer.Source["Accept()"] = time.Now()
-		connection := new(Connection).InitWithConn(conn, *peer)
+		connection := c.newConnection().InitWithConn(conn, *peer)
 		c.handleNewConnection(connection)
 	case CommandShutdown:
 		c.shutdown()
@@ -542,6 +636,9 @@ func (c *Controller) handleCommand(command interface{}) {
 		parameters := command.(CommandBan)
 		peerHash := parameters.PeerHash
 		c.applicationPeerUpdate(BannedQualityScore, peerHash)
+	case CommandUnban:
+		parameters := command.(CommandUnban)
+		c.discovery.unbanPeer(parameters.Address)
 	case CommandDisconnect:
 		parameters := command.(CommandDisconnect)
 		connection, present := c.connections.GetByHash(parameters.PeerHash)
@@ -634,19 +731,25 @@ func (c *Controller) updateMetrics() {
 			metrics, present := c.connectionMetrics[value.peer.Hash]
 			if present {
 				newMetrics[key] = ConnectionMetrics{
-					MomentConnected:  metrics.MomentConnected,
-					BytesSent:        metrics.BytesSent,
-					BytesReceived:    metrics.BytesReceived,
-					MessagesSent:     metrics.MessagesSent,
-					MessagesReceived: metrics.MessagesReceived,
-					PeerAddress:      metrics.PeerAddress,
-					PeerQuality:      metrics.PeerQuality,
-					PeerType:         metrics.PeerType,
-					ConnectionState:  metrics.ConnectionState,
-					ConnectionNotes:  metrics.ConnectionNotes,
+					MomentConnected:   metrics.MomentConnected,
+					BytesSent:         metrics.BytesSent,
+					BytesReceived:     metrics.BytesReceived,
+					MessagesSent:      metrics.MessagesSent,
+					MessagesReceived:  metrics.MessagesReceived,
+					PeerAddress:       metrics.PeerAddress,
+					PeerQuality:       metrics.PeerQuality,
+					PeerType:          metrics.PeerType,
+					ConnectionState:   metrics.ConnectionState,
+					ConnectionNotes:   metrics.ConnectionNotes,
+					Compression:       metrics.Compression,
+					BytesUncompressed: metrics.BytesUncompressed,
+					BytesCompressed:   metrics.BytesCompressed,
 				}
 			}
 		}
+		c.metricsSnapshotMutex.Lock()
+		c.metricsSnapshot = newMetrics
+		c.metricsSnapshotMutex.Unlock()
 		BlockFreeChannelSend(c.connectionMetricsChannel, newMetrics)
 	}
 }
@@ -664,7 +767,8 @@ func (c *Controller) broadcast(parcel Parcel, full bool) {
 	msgHash := parcel.msg.GetMsgHash().Fixed()
 
 	// always broadcast to special peers
-	for _, peer := range c.specialPeers {
+	specialPeers := c.getSpecialPeers()
+	for _, peer := range specialPeers {
 		connection, connected := c.connections.GetByHash(peer.Hash)
 		if !connected {
 			continue
@@ -679,7 +783,7 @@ func (c *Controller) broadcast(parcel Parcel, full bool) {
 		randomSelection = c.connections.GetAllRegular(msgHash)
 	} else {
 		// todo: Do we really want to discount broadcast with by the special peer count?
-		numToSendTo := NumberPeersToBroadcast - len(c.specialPeers)
+		numToSendTo := NumberPeersToBroadcast - len(specialPeers)
 		randomSelection = c.connections.GetRandomRegular(numToSendTo, msgHash)
 	}
 
@@ -17,6 +17,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/FactomProject/factomd/common/primitives"
+
 	log "github.com/sirupsen/logrus"
 )
 
@@ -29,6 +31,12 @@ type Discovery struct {
 	lastPeerSave  time.Time  // Last time we saved known peers.
 	rng           *rand.Rand // RNG = random number generator
 	seedURL       string     // URL to the source of a list of peers
+	listenPort    string     // port we listen on, announced in our signed record
+
+	selfKey      *primitives.PrivateKey          // key we sign our announcements with, kept next to the peers file
+	learnedFrom  map[string]learnedCount         // source -> number of new peers it taught us
+	selfAddress  string                          // our address as observed by our peers, "" until confirmed
+	addressVotes map[string]map[string]time.Time // observed address -> subnets of the peers that observed it -> when
 
 	// logging
 	logger *log.Entry
@@ -42,7 +50,7 @@ var UpdateKnownPeers sync.Mutex
 // Controller and its routines are called from the Controllers runloop()
 // This ensures that all shared memory is accessed from that goroutine.
 
-func (d *Discovery) Init(peersFile string, seed string) *Discovery {
+func (d *Discovery) Init(peersFile string, seed string, listenPort string) *Discovery {
 	d.logger = discoLogger
 	UpdateKnownPeers.Lock()
 	d.knownPeers = map[string]Peer{}
@@ -50,6 +58,10 @@ func (d *Discovery) Init(peersFile string, seed string) *Discovery {
 	d.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
 	d.peersFilePath = peersFile
 	d.seedURL = seed
+	d.listenPort = listenPort
+	d.selfKey = d.loadIdentityKey()
+	d.learnedFrom = map[string]learnedCount{}
+	d.addressVotes = map[string]map[string]time.Time{}
 	//d.LoadPeers()
 	d.DiscoverPeersFromSeed()
 	return d
@@ -82,6 +94,19 @@ func (d *Discovery) getPeer(address string) Peer {
 	return thePeer
 }
 
+// unbanPeer resets the quality score of a known peer that is below the minimum quality score
+func (d *Discovery) unbanPeer(address string) {
+	UpdateKnownPeers.Lock()
+	defer UpdateKnownPeers.Unlock()
+	peer, ok := d.knownPeers[address]
+	if !ok || peer.QualityScore >= MinumumQualityScore {
+		return
+	}
+	d.logger.WithField("address", address).Info("Lifting ban on peer")
+	peer.QualityScore = 0
+	d.knownPeers[address] = peer
+}
+
 // UpdatePeer updates the values in our known peers. Creates peer if its not in there.
 func (d *Discovery) isPeerPresent(peer Peer) bool {
 	UpdateKnownPeers.Lock()
@@ -115,6 +140,9 @@ func (d *Discovery) LoadPeers() {
 func (d *Discovery) SavePeers() {
 	// save known peers to peers.json
 	d.lastPeerSave = time.Now()
+	if d.peersFilePath == "" { // a controller without a peers file, like those of a memory network
+		return
+	}
 	file, err := os.Create(d.peersFilePath)
 	if nil != err {
 		d.logger.Errorf("Discover.SavePeers() File write error on file: %s, Error: %+v", d.peersFilePath, err)
@@ -150,6 +178,8 @@ func (d *Discovery) SavePeers() {
 // LearnPeers receives a set of peers from other hosts
 // The unique peers are added to our peer list.
 // The peers are in a json encoded string as a byte slice
+// This is the legacy, unsigned peer list, so peers learned here get the lowest trust
+// and each source can add at most MaxPeersLearnedPerSource new peers.
 func (d *Discovery) LearnPeers(parcel Parcel) {
 	dec := json.NewDecoder(bytes.NewReader(parcel.Payload))
 	var peerArray []Peer
@@ -159,14 +189,19 @@ func (d *Discovery) LearnPeers(parcel Parcel) {
 		return
 	}
 	filteredArray := d.filterPeersFromOtherNetworks(peerArray)
+	source := parcel.Header.PeerAddress
 	for _, value := range filteredArray {
 		value.QualityScore = 0
-		switch d.isPeerPresent(value) {
-		case true:
+		value.Trust = PeerTrustLegacy
+		value.Record = nil
+		if d.isPeerPresent(value) {
 			alreadyKnownPeer := d.getPeer(value.Address)
-			d.updatePeer(d.updatePeerSource(alreadyKnownPeer, parcel.Header.PeerAddress))
-		default:
-			value.Source = map[string]time.Time{parcel.Header.PeerAddress: time.Now()}
+			d.updatePeer(d.updatePeerSource(alreadyKnownPeer, source))
+		} else if !d.canLearnFrom(source, time.Now()) {
+			continue
+		} else {
+			d.countLearned(source, time.Now())
+			value.Source = map[string]time.Time{source: time.Now()}
 			d.updatePeer(value)
 			d.logger.Debugf("Discovery.LearnPeers !!!!!!!!!!!!! Discovered new PEER!   %+v ", value)
 		}
@@ -212,6 +247,7 @@ func (d *Discovery) filterForUniqueIPAdresses(peers []Peer) (filtered []Peer) {
 // We want peers from diverse networks.  So,method is this:
 //	-- generate list of candidates (if exclusive, only special peers)
 //	-- sort candidates by distance
+//  -- keep at most MaxPeersPerSubnet candidates per subnet, preferring trusted peers
 //  -- if num canddiates is less than desired set, return all candidates
 //  -- Otherwise,repeatedly take candidates at the 0%, %25, %50, %75, %100 points in the list
 //  -- remove each candidate from the list.
@@ -231,7 +267,7 @@ func (d *Discovery) GetOutgoingPeers() []Peer {
 	}
 	UpdateKnownPeers.Unlock()
 	secondPass := d.filterPeersFromOtherNetworks(firstPassPeers)
-	peerPool := d.filterForUniqueIPAdresses(secondPass)
+	peerPool := d.filterForSubnetDiversity(d.filterForUniqueIPAdresses(secondPass))
 	sort.Sort(PeerDistanceSort(peerPool))
 	// Get four times as many as who knows how many will be online
 	desiredQuantity := NumberPeersToConnect * 4
@@ -322,6 +358,9 @@ func (d *Discovery) getPeerSelection() []byte {
 
 // DiscoverPeers gets a set of peers from a DNS Seed
 func (d *Discovery) DiscoverPeersFromSeed() {
+	if d.seedURL == "" {
+		return
+	}
 	d.logger.Info("Contacting seed URL to get peers")
 	resp, err := http.Get(d.seedURL)
 	if nil != err {
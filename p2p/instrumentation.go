@@ -91,6 +91,29 @@ var (
 		Name: "factomd_p2p_goOffline_total",
 		Help: "Number of times we call goOffline()",
 	})
+
+	//
+	// Compression
+	p2pCompressionBytesUncompressed = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_p2p_compression_bytes_uncompressed_total",
+		Help: "Bytes of application payloads sent compressed, measured before compression",
+	})
+
+	p2pCompressionBytesCompressed = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_p2p_compression_bytes_compressed_total",
+		Help: "Bytes of application payloads sent compressed, measured after compression",
+	})
+
+	p2pCompressionRatio = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "factomd_p2p_compression_ratio",
+		Help:    "Ratio of uncompressed to compressed size of payloads sent compressed",
+		Buckets: prometheus.LinearBuckets(1, 0.5, 10),
+	})
+
+	p2pCompressionFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_p2p_compression_failures_total",
+		Help: "Number of received payloads that failed to decompress",
+	})
 )
 
 var registered = false
@@ -127,4 +150,10 @@ func RegisterPrometheus() {
 	// Connections
 	prometheus.MustRegister(p2pConnectionCommonInit)
 
+	// Compression
+	prometheus.MustRegister(p2pCompressionBytesUncompressed)
+	prometheus.MustRegister(p2pCompressionBytesCompressed)
+	prometheus.MustRegister(p2pCompressionRatio)
+	prometheus.MustRegister(p2pCompressionFailures)
+
 }
@@ -0,0 +1,477 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MemoryNetwork is an in-process network that lets several controllers run the real p2p
+// stack (handshakes, parcel parts, peer discovery) inside one process.  Each controller gets
+// the Transport of its own host, which should be an IP address since peers are identified
+// by IP.  Data written on a connection becomes readable on the other end once the latency
+// of the link has passed.  Links can be slowed down, made to fail randomly, or partitioned
+// away from each other.  Random faults are drawn from a generator seeded at creation, so a
+// scenario sees the same faults for the same sequence of writes.
+type MemoryNetwork struct {
+	mutex     sync.Mutex
+	rng       *rand.Rand
+	defaults  LinkConditions            // conditions of links without their own
+	links     map[string]LinkConditions // conditions by linkKey()
+	groups    map[string]int            // partition group of each host, nil when not partitioned
+	listeners map[string]*memoryListener
+	conns     map[*memoryConn]bool // both ends of every open connection
+	nextPort  int                  // next port handed out to the dialing end of a connection
+}
+
+// LinkConditions describes how a link between two hosts behaves
+type LinkConditions struct {
+	Latency  time.Duration // delay before written data can be read on the other end
+	Jitter   time.Duration // random extra delay of up to this much, data still arrives in order
+	DropRate float64       // probability that a write breaks the connection, like a failing link would
+}
+
+// Size of the accept backlog of a listener, dials beyond this are refused
+const memoryListenBacklog = 64
+
+var (
+	errMemoryConnClosed = errors.New("use of closed network connection")
+	errMemoryConnReset  = errors.New("connection reset by peer")
+	errMemoryRefused    = errors.New("connection refused")
+	errMemoryInUse      = errors.New("address already in use")
+)
+
+// memoryTimeout is returned when a deadline passes, or a dial goes into a partition
+type memoryTimeout struct{}
+
+func (memoryTimeout) Error() string   { return "i/o timeout" }
+func (memoryTimeout) Timeout() bool   { return true }
+func (memoryTimeout) Temporary() bool { return true }
+
+func NewMemoryNetwork(seed int64) *MemoryNetwork {
+	n := new(MemoryNetwork)
+	n.rng = rand.New(rand.NewSource(seed))
+	n.links = make(map[string]LinkConditions)
+	n.listeners = make(map[string]*memoryListener)
+	n.conns = make(map[*memoryConn]bool)
+	n.nextPort = 40000
+	return n
+}
+
+// Transport returns the transport for a controller running on the given host
+func (n *MemoryNetwork) Transport(host string) Transport {
+	return &memoryTransport{network: n, host: host}
+}
+
+// SetDefaultConditions sets the conditions of all links that have not been given their own
+func (n *MemoryNetwork) SetDefaultConditions(conditions LinkConditions) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.defaults = conditions
+}
+
+// SetLinkConditions sets the conditions of the link between two hosts, in both directions
+func (n *MemoryNetwork) SetLinkConditions(a string, b string, conditions LinkConditions) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.links[linkKey(a, b)] = conditions
+}
+
+// Partition splits the network into the given groups of hosts.  Hosts not named in any group
+// form one more group.  Data between groups is held back like TCP would, until the partition
+// heals or the connection times out, and dials between groups time out.
+func (n *MemoryNetwork) Partition(groups ...[]string) {
+	n.mutex.Lock()
+	n.groups = make(map[string]int)
+	for i, group := range groups {
+		for _, host := range group {
+			n.groups[host] = i + 1
+		}
+	}
+	n.mutex.Unlock()
+	n.wakeAll()
+}
+
+// Heal removes all partitions
+func (n *MemoryNetwork) Heal() {
+	n.mutex.Lock()
+	n.groups = nil
+	n.mutex.Unlock()
+	n.wakeAll()
+}
+
+// BreakConnections resets all open connections between two hosts
+func (n *MemoryNetwork) BreakConnections(a string, b string) {
+	n.mutex.Lock()
+	var broken []*memoryConn
+	for conn := range n.conns {
+		if conn.local.host == a && conn.remote.host == b {
+			broken = append(broken, conn)
+		}
+	}
+	n.mutex.Unlock()
+	for _, conn := range broken {
+		conn.reset()
+	}
+}
+
+func linkKey(a string, b string) string {
+	if b < a {
+		a, b = b, a
+	}
+	return a + "|" + b
+}
+
+func (n *MemoryNetwork) reachable(from string, to string) bool {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.reachableLocked(from, to)
+}
+
+func (n *MemoryNetwork) reachableLocked(from string, to string) bool {
+	return n.groups == nil || n.groups[from] == n.groups[to]
+}
+
+// sample draws the delay of a write between two hosts, and whether it breaks the connection
+func (n *MemoryNetwork) sample(from string, to string) (delay time.Duration, drop bool) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	conditions, ok := n.links[linkKey(from, to)]
+	if !ok {
+		conditions = n.defaults
+	}
+	delay = conditions.Latency
+	if conditions.Jitter > 0 {
+		delay += time.Duration(n.rng.Int63n(int64(conditions.Jitter)))
+	}
+	drop = conditions.DropRate > 0 && n.rng.Float64() < conditions.DropRate
+	return
+}
+
+// wakeAll makes every reader look at its pipe again, after the partitions changed
+func (n *MemoryNetwork) wakeAll() {
+	n.mutex.Lock()
+	pipes := make([]*memoryPipe, 0, len(n.conns))
+	for conn := range n.conns {
+		pipes = append(pipes, conn.in)
+	}
+	n.mutex.Unlock()
+	for _, pipe := range pipes {
+		pipe.signal()
+	}
+}
+
+func (n *MemoryNetwork) forget(conn *memoryConn) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	delete(n.conns, conn)
+}
+
+func (n *MemoryNetwork) listen(address memoryAddr) (net.Listener, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if _, exists := n.listeners[address.String()]; exists {
+		return nil, &net.OpError{Op: "listen", Net: address.Network(), Addr: address, Err: errMemoryInUse}
+	}
+	l := &memoryListener{
+		network: n,
+		addr:    address,
+		accept:  make(chan *memoryConn, memoryListenBacklog),
+		closed:  make(chan struct{}),
+	}
+	n.listeners[address.String()] = l
+	return l, nil
+}
+
+func (n *MemoryNetwork) dial(from string, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	remote := memoryAddr{host: host, port: port}
+
+	n.mutex.Lock()
+	l, listening := n.listeners[remote.String()]
+	switch {
+	case !n.reachableLocked(from, host):
+		n.mutex.Unlock()
+		return nil, &net.OpError{Op: "dial", Net: remote.Network(), Addr: remote, Err: memoryTimeout{}}
+	case !listening:
+		n.mutex.Unlock()
+		return nil, &net.OpError{Op: "dial", Net: remote.Network(), Addr: remote, Err: errMemoryRefused}
+	}
+	n.nextPort++
+	local := memoryAddr{host: from, port: strconv.Itoa(n.nextPort)}
+	toServer, toClient := newMemoryPipe(), newMemoryPipe()
+	client := &memoryConn{network: n, local: local, remote: remote, in: toClient, out: toServer}
+	server := &memoryConn{network: n, local: remote, remote: local, in: toServer, out: toClient}
+	client.peer, server.peer = server, client
+	n.conns[client] = true
+	n.conns[server] = true
+	n.mutex.Unlock()
+
+	select {
+	case <-l.closed:
+	case l.accept <- server:
+		return client, nil
+	default: // backlog is full
+	}
+	client.reset()
+	return nil, &net.OpError{Op: "dial", Net: remote.Network(), Addr: remote, Err: errMemoryRefused}
+}
+
+// memoryTransport is the Transport of one host on a MemoryNetwork
+type memoryTransport struct {
+	network *MemoryNetwork
+	host    string
+}
+
+func (t *memoryTransport) Listen(address string) (net.Listener, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	if host != "" && host != t.host {
+		return nil, &net.OpError{Op: "listen", Net: "memory", Err: errors.New("cannot assign requested address")}
+	}
+	return t.network.listen(memoryAddr{host: t.host, port: port})
+}
+
+func (t *memoryTransport) DialTimeout(address string, timeout time.Duration) (net.Conn, error) {
+	return t.network.dial(t.host, address)
+}
+
+type memoryAddr struct {
+	host string
+	port string
+}
+
+func (a memoryAddr) Network() string { return "memory" }
+func (a memoryAddr) String() string  { return net.JoinHostPort(a.host, a.port) }
+
+type memoryListener struct {
+	network   *MemoryNetwork
+	addr      memoryAddr
+	accept    chan *memoryConn
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (l *memoryListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.accept:
+		return conn, nil
+	case <-l.closed:
+		return nil, &net.OpError{Op: "accept", Net: l.addr.Network(), Addr: l.addr, Err: errMemoryConnClosed}
+	}
+}
+
+func (l *memoryListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.closed)
+		l.network.mutex.Lock()
+		delete(l.network.listeners, l.addr.String())
+		l.network.mutex.Unlock()
+	})
+	return nil
+}
+
+func (l *memoryListener) Addr() net.Addr {
+	return l.addr
+}
+
+// memoryChunk is the data of one write, readable from deliverAt on
+type memoryChunk struct {
+	data      []byte
+	deliverAt time.Time
+}
+
+// memoryPipe carries the data of one direction of a connection
+type memoryPipe struct {
+	mutex  sync.Mutex
+	wake   chan struct{} // closed and replaced whenever the pipe changes
+	chunks []memoryChunk
+	eof    bool // the writing end was closed, reads return io.EOF once the data is drained
+	broken bool // the connection was reset, or the reading end was closed
+}
+
+func newMemoryPipe() *memoryPipe {
+	return &memoryPipe{wake: make(chan struct{})}
+}
+
+func (p *memoryPipe) signal() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.signalLocked()
+}
+
+func (p *memoryPipe) signalLocked() {
+	close(p.wake)
+	p.wake = make(chan struct{})
+}
+
+func (p *memoryPipe) closeWrite() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.eof = true
+	p.signalLocked()
+}
+
+func (p *memoryPipe) breakPipe() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.broken = true
+	p.chunks = nil
+	p.signalLocked()
+}
+
+// memoryConn is one end of a connection on a MemoryNetwork
+type memoryConn struct {
+	network *MemoryNetwork
+	peer    *memoryConn // the other end of the connection
+	local   memoryAddr
+	remote  memoryAddr
+	in      *memoryPipe // data written by the other end
+	out     *memoryPipe // data we write
+
+	deadlineMutex sync.Mutex
+	readDeadline  time.Time
+	writeDeadline time.Time
+}
+
+func (c *memoryConn) Read(b []byte) (int, error) {
+	for {
+		c.deadlineMutex.Lock()
+		deadline := c.readDeadline
+		c.deadlineMutex.Unlock()
+
+		now := time.Now()
+		var wait time.Duration // how long to sleep, 0 means until the pipe changes
+		c.in.mutex.Lock()
+		switch {
+		case c.in.broken:
+			c.in.mutex.Unlock()
+			return 0, errMemoryConnClosed
+		case len(c.in.chunks) > 0:
+			head := &c.in.chunks[0]
+			if !c.network.reachable(c.remote.host, c.local.host) {
+				break // held back until the partition heals
+			}
+			if now.Before(head.deliverAt) {
+				wait = head.deliverAt.Sub(now)
+				break
+			}
+			n := copy(b, head.data)
+			head.data = head.data[n:]
+			if len(head.data) == 0 {
+				c.in.chunks = c.in.chunks[1:]
+			}
+			c.in.mutex.Unlock()
+			return n, nil
+		case c.in.eof:
+			c.in.mutex.Unlock()
+			return 0, io.EOF
+		}
+		wake := c.in.wake
+		c.in.mutex.Unlock()
+
+		if !deadline.IsZero() {
+			if !now.Before(deadline) {
+				return 0, memoryTimeout{}
+			}
+			if untilDeadline := deadline.Sub(now); wait == 0 || untilDeadline < wait {
+				wait = untilDeadline
+			}
+		}
+		if wait == 0 {
+			<-wake
+			continue
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-wake:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+func (c *memoryConn) Write(b []byte) (int, error) {
+	c.deadlineMutex.Lock()
+	deadline := c.writeDeadline
+	c.deadlineMutex.Unlock()
+	if !deadline.IsZero() && !time.Now().Before(deadline) {
+		return 0, memoryTimeout{}
+	}
+
+	delay, drop := c.network.sample(c.local.host, c.remote.host)
+	if drop {
+		c.reset()
+		return 0, errMemoryConnReset
+	}
+
+	c.out.mutex.Lock()
+	defer c.out.mutex.Unlock()
+	switch {
+	case c.out.broken:
+		return 0, errMemoryConnReset
+	case c.out.eof:
+		return 0, errMemoryConnClosed
+	}
+	deliverAt := time.Now().Add(delay)
+	if last := len(c.out.chunks) - 1; last >= 0 && deliverAt.Before(c.out.chunks[last].deliverAt) {
+		deliverAt = c.out.chunks[last].deliverAt // keep the data in order
+	}
+	data := make([]byte, len(b))
+	copy(data, b)
+	c.out.chunks = append(c.out.chunks, memoryChunk{data: data, deliverAt: deliverAt})
+	c.out.signalLocked()
+	return len(b), nil
+}
+
+// Close closes our end.  The other end can still read what we wrote before it gets io.EOF.
+func (c *memoryConn) Close() error {
+	c.in.breakPipe()
+	c.out.closeWrite()
+	c.network.forget(c)
+	return nil
+}
+
+// reset breaks the connection for both ends
+func (c *memoryConn) reset() {
+	c.in.breakPipe()
+	c.out.breakPipe()
+	c.network.forget(c)
+	c.network.forget(c.peer)
+}
+
+func (c *memoryConn) LocalAddr() net.Addr  { return c.local }
+func (c *memoryConn) RemoteAddr() net.Addr { return c.remote }
+
+func (c *memoryConn) SetDeadline(t time.Time) error {
+	c.SetWriteDeadline(t)
+	return c.SetReadDeadline(t)
+}
+
+func (c *memoryConn) SetReadDeadline(t time.Time) error {
+	c.deadlineMutex.Lock()
+	c.readDeadline = t
+	c.deadlineMutex.Unlock()
+	c.in.signal()
+	return nil
+}
+
+func (c *memoryConn) SetWriteDeadline(t time.Time) error {
+	c.deadlineMutex.Lock()
+	c.writeDeadline = t
+	c.deadlineMutex.Unlock()
+	return nil
+}
@@ -0,0 +1,228 @@
+package p2p_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/FactomProject/factomd/p2p"
+)
+
+func dialPair(t *testing.T, network *MemoryNetwork) (client net.Conn, server net.Conn) {
+	listener, err := network.Transport("10.0.0.1").Listen(":8108")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	client, err = network.Transport("10.0.0.2").DialTimeout("10.0.0.1:8108", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err = listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+func readWithin(conn net.Conn, size int, timeout time.Duration) ([]byte, error) {
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	data := make([]byte, size)
+	_, err := io.ReadFull(conn, data)
+	return data, err
+}
+
+func TestMemoryNetworkConn(t *testing.T) {
+	network := NewMemoryNetwork(1)
+	network.SetDefaultConditions(LinkConditions{Latency: 50 * time.Millisecond})
+	client, server := dialPair(t, network)
+
+	if server.RemoteAddr().String() == "" || client.RemoteAddr().String() != "10.0.0.1:8108" {
+		t.Errorf("unexpected addresses %s -> %s", client.LocalAddr(), client.RemoteAddr())
+	}
+
+	start := time.Now()
+	client.Write([]byte("hello"))
+	data, err := readWithin(server, 5, time.Second)
+	if err != nil || string(data) != "hello" {
+		t.Fatalf("read %q, %v", data, err)
+	}
+	if time.Since(start) < 50*time.Millisecond {
+		t.Error("data arrived before the latency of the link passed")
+	}
+
+	if _, err := readWithin(server, 1, 10*time.Millisecond); err == nil {
+		t.Error("expected a timeout reading from an idle connection")
+	} else if ne, ok := err.(net.Error); !ok || !ne.Timeout() {
+		t.Errorf("expected a timeout error, got %v", err)
+	}
+
+	client.Write([]byte("bye"))
+	client.Close()
+	data, err = readWithin(server, 3, time.Second)
+	if err != nil || string(data) != "bye" {
+		t.Errorf("data written before close was lost: %q, %v", data, err)
+	}
+	if _, err := readWithin(server, 1, time.Second); err != io.EOF {
+		t.Errorf("expected EOF after the other end closed, got %v", err)
+	}
+}
+
+func TestMemoryNetworkFaults(t *testing.T) {
+	network := NewMemoryNetwork(1)
+	client, server := dialPair(t, network)
+
+	network.Partition([]string{"10.0.0.1"}, []string{"10.0.0.2"})
+	client.Write([]byte("held"))
+	if _, err := readWithin(server, 4, 100*time.Millisecond); err == nil {
+		t.Error("data crossed a partition")
+	}
+	if _, err := network.Transport("10.0.0.2").DialTimeout("10.0.0.1:8108", time.Second); err == nil {
+		t.Error("dialed across a partition")
+	}
+	network.Heal()
+	if data, err := readWithin(server, 4, time.Second); err != nil || string(data) != "held" {
+		t.Errorf("data held by the partition was not delivered after healing: %q, %v", data, err)
+	}
+
+	network.BreakConnections("10.0.0.2", "10.0.0.1")
+	if _, err := client.Write([]byte("x")); err == nil {
+		t.Error("wrote to a broken connection")
+	}
+	if _, err := readWithin(server, 1, time.Second); err == nil || err == io.EOF {
+		t.Errorf("expected a reset reading from a broken connection, got %v", err)
+	}
+
+	client, server = dialPair(t, network)
+	network.SetLinkConditions("10.0.0.1", "10.0.0.2", LinkConditions{DropRate: 1})
+	if _, err := client.Write([]byte("x")); err == nil {
+		t.Error("write survived a link that drops everything")
+	}
+	if _, err := server.Write([]byte("x")); err == nil {
+		t.Error("the other end of a dropped connection is still usable")
+	}
+}
+
+func startMemoryController(t *testing.T, network *MemoryNetwork, host string, peers string) *Controller {
+	controller := new(Controller).Init(ControllerInit{
+		NodeName:                 host,
+		Port:                     "8108",
+		Network:                  LocalNet,
+		ConfigPeers:              peers,
+		ConnectionMetricsChannel: make(chan interface{}, StandardChannelSize),
+		Transport:                network.Transport(host),
+	})
+	controller.StartNetwork()
+	return controller
+}
+
+// sendUntilReceived keeps sending the payload to a random peer of the sender until the
+// receiver gets it
+func sendUntilReceived(sender *Controller, receiver *Controller, payload []byte, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		parcel := NewParcel(LocalNet, payload)
+		parcel.Header.Type = TypeMessage
+		parcel.Header.TargetPeer = RandomPeerFlag
+		BlockFreeChannelSend(sender.ToNetwork, *parcel)
+
+		select {
+		case message := <-receiver.FromNetwork:
+			if received, ok := message.(Parcel); ok && bytes.Equal(received.Payload, payload) {
+				return true
+			}
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+	return false
+}
+
+func TestControllersOverMemoryNetwork(t *testing.T) {
+	network := NewMemoryNetwork(1)
+	network.SetDefaultConditions(LinkConditions{Latency: 5 * time.Millisecond, Jitter: 5 * time.Millisecond})
+
+	a := startMemoryController(t, network, "10.1.0.1", "")
+	b := startMemoryController(t, network, "10.2.0.1", "10.1.0.1:8108")
+	defer a.NetworkStop()
+	defer b.NetworkStop()
+	if a.NodeID == b.NodeID || NodeID != 0 || NetworkListenPort != "8108" {
+		t.Errorf("the controllers share state, NodeIDs %d %d, global NodeID %d", a.NodeID, b.NodeID, NodeID)
+	}
+
+	payload := bytes.Repeat([]byte("factom "), 10000)
+	if !sendUntilReceived(b, a, payload, 15*time.Second) {
+		t.Fatal("message was not delivered between controllers")
+	}
+	// drop the copies sent while the connection was coming up
+	for drained := false; !drained; {
+		select {
+		case <-a.FromNetwork:
+		case <-time.After(500 * time.Millisecond):
+			drained = true
+		}
+	}
+
+	network.Partition([]string{"10.1.0.1"}, []string{"10.2.0.1"})
+	parcel := NewParcel(LocalNet, []byte("partitioned"))
+	parcel.Header.Type = TypeMessage
+	parcel.Header.TargetPeer = RandomPeerFlag
+	BlockFreeChannelSend(b.ToNetwork, *parcel)
+	select {
+	case <-a.FromNetwork:
+		t.Error("message crossed a partition")
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	network.Heal()
+	select {
+	case message := <-a.FromNetwork:
+		if received := message.(Parcel); string(received.Payload) != "partitioned" {
+			t.Errorf("unexpected message %q after healing", received.Payload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("message held by the partition was not delivered after healing")
+	}
+}
+
+func TestReloadSpecialPeers(t *testing.T) {
+	network := NewMemoryNetwork(1)
+	file, err := ioutil.TempFile(os.TempDir(), "PeersTesting")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+	defer os.Remove(file.Name() + ".key")
+
+	controller := new(Controller).Init(ControllerInit{
+		NodeName:                 "10.0.0.9",
+		Port:                     "8108",
+		PeersFile:                file.Name(),
+		Network:                  LocalNet,
+		ConfigPeers:              "10.0.0.1:8108 10.0.0.2:8108",
+		CmdLinePeers:             "10.0.0.3:8108",
+		ConnectionMetricsChannel: make(chan interface{}, StandardChannelSize),
+		Transport:                network.Transport("10.0.0.9"),
+	})
+
+	// read the list while it is reloaded, for the race detector
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			controller.GetSpecialPeers()
+		}
+	}()
+	controller.ReloadSpecialPeers("10.0.0.2:8108 10.0.0.4:8108")
+	<-done
+
+	peers := strings.Join(controller.GetSpecialPeers(), " ")
+	if peers != "10.0.0.2:8108 10.0.0.3:8108 10.0.0.4:8108" {
+		t.Errorf("expected the config peers to be replaced and the command line peer kept, got %s", peers)
+	}
+}
@@ -36,36 +36,50 @@ type ParcelHeader struct {
 	PartNo      uint16            // 2 bytes - in case of multipart parcels, indicates which part this corresponds to, otherwise should be 0
 	PartsTotal  uint16            // 2 bytes - in case of multipart parcels, indicates the total number of parts that the receiver should expect
 	NodeID      uint64
-	PeerAddress string // address of the peer set by connection to know who sent message (for tracking source of other peers)
-	PeerPort    string // port of the peer , or we are listening on
-	AppHash     string // Application specific message hash, for tracing
-	AppType     string // Application specific message type, for tracing
+	PeerAddress string      // address of the peer set by connection to know who sent message (for tracking source of other peers)
+	PeerPort    string      // port of the peer , or we are listening on
+	AppHash     string      // Application specific message hash, for tracing
+	AppType     string      // Application specific message type, for tracing
+	Flags       ParcelFlags // Capabilities of the sender and encoding of the payload
 }
 
+// ParcelFlags is a bit field in the parcel header describing capabilities of the
+// sender and the encoding of the payload.
+type ParcelFlags uint16
+
+// Parcel flags -- all new flags should be added to the *end* of the list!
+const (
+	FlagCompressionCapable ParcelFlags = 1 << iota // the sender can decode compressed payloads, see compression.go
+	FlagPayloadCompressed                          // the payload of this parcel is compressed
+	FlagSignedPeerExchange                         // the sender understands TypeSignedPeerResponse, see peerRecord.go
+)
+
 type ParcelCommandType uint16
 
 // Parcel commands -- all new commands should be added to the *end* of the list!
 const ( // iota is reset to 0
-	TypeHeartbeat    ParcelCommandType = iota // "Note, I'm still alive"
-	TypePing                                  // "Are you there?"
-	TypePong                                  // "yes, I'm here"
-	TypePeerRequest                           // "Please share some peers"
-	TypePeerResponse                          // "Here's some peers I know about."
-	TypeAlert                                 // network wide alerts (used in bitcoin to indicate criticalities)
-	TypeMessage                               // Application level message
-	TypeMessagePart                           // Application level message that was split into multiple parts
+	TypeHeartbeat          ParcelCommandType = iota // "Note, I'm still alive"
+	TypePing                                        // "Are you there?"
+	TypePong                                        // "yes, I'm here"
+	TypePeerRequest                                 // "Please share some peers"
+	TypePeerResponse                                // "Here's some peers I know about."
+	TypeAlert                                       // network wide alerts (used in bitcoin to indicate criticalities)
+	TypeMessage                                     // Application level message
+	TypeMessagePart                                 // Application level message that was split into multiple parts
+	TypeSignedPeerResponse                          // "Here's some signed peer records, and who I am."
 )
 
 // CommandStrings is a Map of command ids to strings for easy printing of network comands
 var CommandStrings = map[ParcelCommandType]string{
-	TypeHeartbeat:    "Heartbeat",     // "Note, I'm still alive"
-	TypePing:         "Ping",          // "Are you there?"
-	TypePong:         "Pong",          // "yes, I'm here"
-	TypePeerRequest:  "Peer-Request",  // "Please share some peers"
-	TypePeerResponse: "Peer-Response", // "Here's some peers I know about."
-	TypeAlert:        "Alert",         // network wide alerts (used in bitcoin to indicate criticalities)
-	TypeMessage:      "Message",       // Application level message
-	TypeMessagePart:  "MessagePart",   // Application level message that was split into multiple parts
+	TypeHeartbeat:          "Heartbeat",            // "Note, I'm still alive"
+	TypePing:               "Ping",                 // "Are you there?"
+	TypePong:               "Pong",                 // "yes, I'm here"
+	TypePeerRequest:        "Peer-Request",         // "Please share some peers"
+	TypePeerResponse:       "Peer-Response",        // "Here's some peers I know about."
+	TypeAlert:              "Alert",                // network wide alerts (used in bitcoin to indicate criticalities)
+	TypeMessage:            "Message",              // Application level message
+	TypeMessagePart:        "MessagePart",          // Application level message that was split into multiple parts
+	TypeSignedPeerResponse: "Signed-Peer-Response", // "Here's some signed peer records, and who I am."
 }
 
 // MaxPayloadSize is the maximum bytes a message can be at the networking level.
@@ -167,6 +181,7 @@ func (p *Parcel) LogEntry() *log.Entry {
 		"node_id":     p.Header.NodeID,
 		"part_no":     p.Header.PartNo + 1,
 		"parts_total": p.Header.PartsTotal,
+		"flags":       p.Header.Flags,
 	})
 }
 
@@ -53,6 +53,8 @@ type Peer struct {
 	Connections  int                  // Number of successful connections.
 	LastContact  time.Time            // Keep track of how long ago we talked to the peer.
 	Source       map[string]time.Time // source where we heard from the peer.
+	Trust        uint8                // How much we trust the information about this peer, see peerRecord.go
+	Record       *SignedPeerRecord    `json:",omitempty"` // Latest signed announcement of the peer, if any
 
 	// logging
 	logger *log.Entry
@@ -0,0 +1,397 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// Signed peer exchange
+//
+// Every node holds an ed25519 key, kept in a file next to its PeersFile so it survives
+// restarts, and signs a timestamped announcement of its own address and listen port.
+// Announcements are only accepted first hand when the address in the announcement matches
+// the address the sender is connected from, which makes the record "verified" and pins the
+// key of the sender.  Nodes relay only verified records, so a peer can no longer inject
+// arbitrary addresses into our PeersFile on behalf of others.  Records learned second hand
+// are kept at a lower trust and can only add new peers or refresh the record of a peer whose
+// pinned key signed them; they never change seed or configured peers.  Records from legacy
+// peer lists get the lowest trust.  Every source is limited in how many peers it can teach
+// us a day, and GetOutgoingPeers limits the number of peers dialed per subnet.
+
+// Trust levels of the information we have about a peer
+const (
+	PeerTrustLegacy   uint8 = iota // learned from an unsigned (legacy) peer list
+	PeerTrustSigned                // learned from a signed record relayed by another peer
+	PeerTrustVerified              // signed record received from the peer itself, over a connection from that address
+)
+
+var (
+	SignedPeerRecordMaxAge    = time.Hour * 24   // records older than this are ignored
+	SignedPeerRecordMaxFuture = time.Minute * 10 // records timestamped further in the future are ignored
+	MaxPeersLearnedPerSource  = 64               // peers a single source may add to our known peers within LearnedFromMaxAge
+	LearnedFromMaxAge         = time.Hour * 24   // how long we count the peers a source added
+	MaxLearnedFromSources     = 4096             // sources we count the added peers of, the oldest are forgotten first
+	MaxPeersPerSubnet         = 2                // peers per subnet (/16 for IPv4, /32 for IPv6) GetOutgoingPeers will select
+	SelfAddressConfirmations  = 3                // peers in distinct subnets that must observe the same address before we announce it
+	SelfAddressVoteMaxAge     = time.Hour        // observations of our address older than this are forgotten
+	MaxSelfAddressCandidates  = 16               // observed addresses we keep votes for
+)
+
+// learnedCount is the number of new peers a source taught us since the first of them
+type learnedCount struct {
+	count int
+	since time.Time
+}
+
+// SignedPeerRecord is a signed, timestamped self-announcement of a node
+type SignedPeerRecord struct {
+	Address   string    // IP address of the node
+	Port      string    // port the node listens on
+	Network   NetworkID // network the node is on
+	Timestamp int64     // unix time the record was signed
+	PublicKey []byte    // ed25519 key the record is signed with
+	Signature []byte    // signature over the fields above
+}
+
+// PeerExchange is the payload of a TypeSignedPeerResponse parcel
+type PeerExchange struct {
+	Self     *SignedPeerRecord  // the sender's own announcement, nil if it does not know its address yet
+	Observed string             // the address the sender sees the recipient connected from
+	Peers    []SignedPeerRecord // verified records the sender is sharing
+}
+
+// signedData returns the bytes covered by the signature of the record
+func (r *SignedPeerRecord) signedData() []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, uint32(r.Network))
+	binary.Write(&buf, binary.BigEndian, r.Timestamp)
+	binary.Write(&buf, binary.BigEndian, uint16(len(r.Address)))
+	buf.WriteString(r.Address)
+	binary.Write(&buf, binary.BigEndian, uint16(len(r.Port)))
+	buf.WriteString(r.Port)
+	buf.Write(r.PublicKey)
+	return buf.Bytes()
+}
+
+// Sign sets the public key and signature of the record
+func (r *SignedPeerRecord) Sign(key *primitives.PrivateKey) {
+	r.PublicKey = key.Public()
+	r.Signature = primitives.Sign(key.Key[:], r.signedData())
+}
+
+// Verify checks the signature, network and age of the record
+func (r *SignedPeerRecord) Verify(now time.Time) error {
+	if r.Network != CurrentNetwork {
+		return fmt.Errorf("record is for network %x", uint32(r.Network))
+	}
+	if net.ParseIP(r.Address) == nil {
+		return fmt.Errorf("record has an invalid address %q", r.Address)
+	}
+	signed := time.Unix(r.Timestamp, 0)
+	if now.Sub(signed) > SignedPeerRecordMaxAge {
+		return fmt.Errorf("record is stale, signed %s", signed)
+	}
+	if signed.Sub(now) > SignedPeerRecordMaxFuture {
+		return fmt.Errorf("record is from the future, signed %s", signed)
+	}
+	return primitives.VerifySignature(r.signedData(), r.PublicKey, r.Signature)
+}
+
+// loadIdentityKey reads the key we sign our announcements with from the file next to the
+// peers file, creating it on first use.  Peers pin the key, so it has to survive restarts.
+func (d *Discovery) loadIdentityKey() *primitives.PrivateKey {
+	if d.peersFilePath == "" {
+		return primitives.RandomPrivateKey()
+	}
+	path := d.peersFilePath + ".key"
+	if data, err := ioutil.ReadFile(path); err == nil {
+		key, err := primitives.NewPrivateKeyFromHex(strings.TrimSpace(string(data)))
+		if err == nil {
+			return key
+		}
+		d.logger.Errorf("Discovery.loadIdentityKey ignoring the invalid key in %s: %s", path, err)
+	}
+	key := primitives.RandomPrivateKey()
+	if err := ioutil.WriteFile(path, []byte(key.PrivateKeyString()+"\n"), 0600); err != nil {
+		d.logger.Errorf("Discovery.loadIdentityKey failed to save the key to %s, peers will see a new key on restart: %s", path, err)
+	}
+	return key
+}
+
+// newSelfRecord returns a freshly signed announcement of our own address, or nil if we
+// don't know our address yet
+func (d *Discovery) newSelfRecord() *SignedPeerRecord {
+	if d.selfKey == nil || d.selfAddress == "" {
+		return nil
+	}
+	record := &SignedPeerRecord{
+		Address:   d.selfAddress,
+		Port:      d.listenPort,
+		Network:   CurrentNetwork,
+		Timestamp: time.Now().Unix(),
+	}
+	record.Sign(d.selfKey)
+	return record
+}
+
+// observeSelfAddress records the address a peer sees us at.  Once peers in enough distinct
+// subnets agree on an address we start announcing it, so a few colluding peers cannot pick it.
+func (d *Discovery) observeSelfAddress(observed string, reporter string) {
+	if net.ParseIP(observed) == nil || reporter == "" || observed == d.selfAddress {
+		return
+	}
+	now := time.Now()
+	d.pruneAddressVotes(now)
+	votes, ok := d.addressVotes[observed]
+	if !ok {
+		if len(d.addressVotes) >= MaxSelfAddressCandidates {
+			d.dropWeakestAddressCandidate()
+		}
+		votes = map[string]time.Time{}
+		d.addressVotes[observed] = votes
+	}
+	votes[subnetOf(reporter)] = now
+	if len(votes) >= SelfAddressConfirmations {
+		d.logger.WithField("address", observed).Info("Announcing our address to peers")
+		d.selfAddress = observed
+		d.addressVotes = map[string]map[string]time.Time{}
+	}
+}
+
+// pruneAddressVotes forgets the observations of our address older than SelfAddressVoteMaxAge
+func (d *Discovery) pruneAddressVotes(now time.Time) {
+	for address, votes := range d.addressVotes {
+		for subnet, observed := range votes {
+			if now.Sub(observed) > SelfAddressVoteMaxAge {
+				delete(votes, subnet)
+			}
+		}
+		if len(votes) == 0 {
+			delete(d.addressVotes, address)
+		}
+	}
+}
+
+// dropWeakestAddressCandidate forgets the observed address with the fewest votes, the least
+// recently observed of them if there is a tie
+func (d *Discovery) dropWeakestAddressCandidate() {
+	weakest, weakestLast := "", time.Time{}
+	for address, votes := range d.addressVotes {
+		var last time.Time
+		for _, observed := range votes {
+			if observed.After(last) {
+				last = observed
+			}
+		}
+		if weakest == "" || len(votes) < len(d.addressVotes[weakest]) ||
+			(len(votes) == len(d.addressVotes[weakest]) && last.Before(weakestLast)) {
+			weakest, weakestLast = address, last
+		}
+	}
+	delete(d.addressVotes, weakest)
+}
+
+// canLearnFrom returns true if the source has not added MaxPeersLearnedPerSource new peers within
+// LearnedFromMaxAge
+func (d *Discovery) canLearnFrom(source string, now time.Time) bool {
+	learned, ok := d.learnedFrom[source]
+	return !ok || now.Sub(learned.since) > LearnedFromMaxAge || learned.count < MaxPeersLearnedPerSource
+}
+
+// countLearned counts a new peer the source added
+func (d *Discovery) countLearned(source string, now time.Time) {
+	learned, ok := d.learnedFrom[source]
+	if !ok && len(d.learnedFrom) >= MaxLearnedFromSources {
+		d.pruneLearnedFrom(now)
+	}
+	if !ok || now.Sub(learned.since) > LearnedFromMaxAge {
+		learned = learnedCount{since: now}
+	}
+	learned.count++
+	d.learnedFrom[source] = learned
+}
+
+// pruneLearnedFrom forgets the sources counted for longer than LearnedFromMaxAge, or the one
+// counted for the longest if none are that old
+func (d *Discovery) pruneLearnedFrom(now time.Time) {
+	oldest := ""
+	for source, learned := range d.learnedFrom {
+		if now.Sub(learned.since) > LearnedFromMaxAge {
+			delete(d.learnedFrom, source)
+		} else if oldest == "" || learned.since.Before(d.learnedFrom[oldest].since) {
+			oldest = source
+		}
+	}
+	if len(d.learnedFrom) >= MaxLearnedFromSources {
+		delete(d.learnedFrom, oldest)
+	}
+}
+
+// ShareSignedPeers gets a signed peer exchange to send to the peer at the given address
+func (d *Discovery) ShareSignedPeers(recipient string) []byte {
+	exchange := PeerExchange{Self: d.newSelfRecord(), Observed: recipient}
+	now := time.Now()
+	UpdateKnownPeers.Lock()
+	for _, peer := range d.knownPeers {
+		if peer.Trust != PeerTrustVerified || peer.Record == nil || peer.Address == recipient {
+			continue
+		}
+		if MinumumQualityScore > peer.QualityScore || peer.Record.Verify(now) != nil {
+			continue
+		}
+		exchange.Peers = append(exchange.Peers, *peer.Record)
+		if 4*NumberPeersToConnect <= len(exchange.Peers) {
+			break
+		}
+	}
+	UpdateKnownPeers.Unlock()
+
+	data, err := json.Marshal(exchange)
+	if nil != err {
+		d.logger.Errorf("Discovery.ShareSignedPeers got an error marshalling json. error: %+v", err)
+	}
+	return data
+}
+
+// LearnSignedPeers receives a signed peer exchange from another host
+func (d *Discovery) LearnSignedPeers(parcel Parcel) {
+	var exchange PeerExchange
+	err := json.Unmarshal(parcel.Payload, &exchange)
+	if nil != err {
+		d.logger.Errorf("Discovery.LearnSignedPeers got an error unmarshalling json. error: %+v", err)
+		return
+	}
+	source := parcel.Header.PeerAddress
+	now := time.Now()
+
+	d.observeSelfAddress(exchange.Observed, source)
+
+	if exchange.Self != nil {
+		switch err := exchange.Self.Verify(now); {
+		case err != nil:
+			d.logger.Debugf("Discovery.LearnSignedPeers rejected self announcement from %s: %s", source, err)
+		case exchange.Self.Address != source:
+			d.logger.Debugf("Discovery.LearnSignedPeers rejected self announcement for %s received from %s", exchange.Self.Address, source)
+		default:
+			d.learnRecord(*exchange.Self, source, PeerTrustVerified)
+		}
+	}
+
+	for _, record := range exchange.Peers {
+		if !d.canLearnFrom(source, now) {
+			d.logger.Debugf("Discovery.LearnSignedPeers ignoring the rest of %d peers from %s", len(exchange.Peers), source)
+			break
+		}
+		if err := record.Verify(now); err != nil {
+			d.logger.Debugf("Discovery.LearnSignedPeers rejected record from %s: %s", source, err)
+			continue
+		}
+		if d.learnRecord(record, source, PeerTrustSigned) {
+			d.countLearned(source, now)
+		}
+	}
+	d.SavePeers()
+}
+
+// learnRecord adds or updates the known peer described by a verified record.  Returns true
+// if the peer was not known before.  A verified record, received from the peer itself, is
+// authoritative and pins the key of the peer.  A relayed record can add a new peer, but for a
+// known peer it only refreshes the record when the pinned key signed it, and it never changes
+// seed or configured peers.
+func (d *Discovery) learnRecord(record SignedPeerRecord, source string, trust uint8) bool {
+	UpdateKnownPeers.Lock()
+	peer, known := d.knownPeers[record.Address]
+	UpdateKnownPeers.Unlock()
+
+	if !known {
+		peer = *new(Peer).Init(record.Address, record.Port, 0, RegularPeer, 0)
+	}
+	peer = d.updatePeerSource(peer, source)
+	switch {
+	case peer.Record != nil && record.Timestamp < peer.Record.Timestamp:
+	case trust == PeerTrustVerified:
+		if peer.Record != nil && !bytes.Equal(peer.Record.PublicKey, record.PublicKey) {
+			d.logger.Infof("Discovery.learnRecord peer %s changed its key", record.Address)
+		}
+		if !peer.IsSpecial() {
+			peer.Port = record.Port
+		}
+		peer.Trust = trust
+		peer.Record = &record
+	case !known:
+		peer.Trust = trust
+		peer.Record = &record
+	case peer.IsSpecial() || isSeeded(peer):
+	case peer.Record != nil && bytes.Equal(peer.Record.PublicKey, record.PublicKey) && peer.Port == record.Port:
+		peer.Record = &record
+	default:
+		d.logger.Debugf("Discovery.learnRecord ignoring a record relayed by %s for known peer %s", source, record.Address)
+	}
+	d.updatePeer(peer)
+	return !known
+}
+
+// isSeeded returns true if we learned the peer from the seed URL
+func isSeeded(peer Peer) bool {
+	_, seeded := peer.Source["DNS-Seed"]
+	return seeded
+}
+
+// subnetOf returns the subnet the address belongs to for diversity purposes
+func subnetOf(address string) string {
+	ip := net.ParseIP(address)
+	switch {
+	case ip == nil:
+		return address
+	case ip.To4() != nil:
+		return ip.Mask(net.CIDRMask(16, 32)).String()
+	default:
+		return ip.Mask(net.CIDRMask(32, 128)).String()
+	}
+}
+
+// filterForSubnetDiversity keeps at most MaxPeersPerSubnet peers of each subnet, preferring
+// special peers, then seed peers and peers that verified themselves to us, then peers of
+// higher quality.  Relayed records are not preferred over legacy peers, anyone can sign one.
+func (d *Discovery) filterForSubnetDiversity(peers []Peer) (filtered []Peer) {
+	sorted := make([]Peer, len(peers))
+	copy(sorted, peers)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].IsSpecial() != sorted[j].IsSpecial() {
+			return sorted[i].IsSpecial()
+		}
+		if vouched(sorted[i]) != vouched(sorted[j]) {
+			return vouched(sorted[i])
+		}
+		return sorted[i].QualityScore > sorted[j].QualityScore
+	})
+
+	perSubnet := map[string]int{}
+	for _, peer := range sorted {
+		subnet := subnetOf(peer.Address)
+		if !peer.IsSpecial() && perSubnet[subnet] >= MaxPeersPerSubnet {
+			continue
+		}
+		perSubnet[subnet]++
+		filtered = append(filtered, peer)
+	}
+	return
+}
+
+// vouched returns true if the peer came from the seed or verified its own record to us
+func vouched(peer Peer) bool {
+	return peer.Trust == PeerTrustVerified || isSeeded(peer)
+}
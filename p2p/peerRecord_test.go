@@ -0,0 +1,350 @@
+package p2p
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+func newTestDiscovery(t *testing.T) *Discovery {
+	file, err := ioutil.TempFile(os.TempDir(), "PeersTesting")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	d := new(Discovery)
+	d.knownPeers = map[string]Peer{}
+	d.peersFilePath = file.Name()
+	d.listenPort = "8108"
+	d.selfKey = primitives.RandomPrivateKey()
+	d.learnedFrom = map[string]learnedCount{}
+	d.addressVotes = map[string]map[string]time.Time{}
+	d.logger = discoLogger
+	return d
+}
+
+func newSignedRecord(address string, timestamp time.Time) SignedPeerRecord {
+	return newSignedRecordWithKey(address, "8108", timestamp, primitives.RandomPrivateKey())
+}
+
+func newSignedRecordWithKey(address string, port string, timestamp time.Time, key *primitives.PrivateKey) SignedPeerRecord {
+	record := SignedPeerRecord{Address: address, Port: port, Network: CurrentNetwork, Timestamp: timestamp.Unix()}
+	record.Sign(key)
+	return record
+}
+
+func signedExchangeParcel(source string, exchange PeerExchange) Parcel {
+	payload, _ := json.Marshal(exchange)
+	parcel := NewParcel(CurrentNetwork, payload)
+	parcel.Header.PeerAddress = source
+	return *parcel
+}
+
+func TestSignedPeerRecordVerify(t *testing.T) {
+	now := time.Now()
+	record := newSignedRecord("10.1.2.3", now)
+	if err := record.Verify(now); err != nil {
+		t.Fatalf("fresh record failed to verify: %s", err)
+	}
+
+	tampered := record
+	tampered.Address = "10.1.2.4"
+	if tampered.Verify(now) == nil {
+		t.Error("record with a changed address verified")
+	}
+
+	tampered = record
+	tampered.Port = "9999"
+	if tampered.Verify(now) == nil {
+		t.Error("record with a changed port verified")
+	}
+
+	if record.Verify(now.Add(SignedPeerRecordMaxAge+time.Minute)) == nil {
+		t.Error("stale record verified")
+	}
+	if record.Verify(now.Add(-SignedPeerRecordMaxFuture-time.Minute)) == nil {
+		t.Error("record from the future verified")
+	}
+
+	other := newSignedRecord("10.1.2.3", now)
+	other.Network = CurrentNetwork + 1
+	if other.Verify(now) == nil {
+		t.Error("record for another network verified")
+	}
+}
+
+func TestLearnSignedPeersRequiresMatchingSender(t *testing.T) {
+	d := newTestDiscovery(t)
+	defer os.Remove(d.peersFilePath)
+	self := newSignedRecord("10.1.2.3", time.Now())
+	payload, _ := json.Marshal(PeerExchange{Self: &self})
+
+	parcel := NewParcel(CurrentNetwork, payload)
+	parcel.Header.PeerAddress = "10.9.9.9"
+	d.LearnSignedPeers(*parcel)
+	if _, known := d.knownPeers["10.1.2.3"]; known {
+		t.Error("learned a self announcement relayed from a different address")
+	}
+
+	parcel.Header.PeerAddress = "10.1.2.3"
+	d.LearnSignedPeers(*parcel)
+	peer, known := d.knownPeers["10.1.2.3"]
+	if !known {
+		t.Fatal("did not learn the self announcement of the sender")
+	}
+	if peer.Trust != PeerTrustVerified || peer.Record == nil {
+		t.Errorf("sender should be verified, got trust %d", peer.Trust)
+	}
+
+	shared := PeerExchange{}
+	if err := json.Unmarshal(d.ShareSignedPeers("10.4.4.4"), &shared); err != nil {
+		t.Fatal(err)
+	}
+	if len(shared.Peers) != 1 || shared.Peers[0].Address != "10.1.2.3" || shared.Observed != "10.4.4.4" {
+		t.Errorf("unexpected peer exchange %+v", shared)
+	}
+}
+
+func TestLearnSignedPeersLimitPerSource(t *testing.T) {
+	d := newTestDiscovery(t)
+	defer os.Remove(d.peersFilePath)
+	var first, second PeerExchange
+	for i := 0; i < MaxPeersLearnedPerSource+10; i++ {
+		record := newSignedRecord(fmt.Sprintf("10.%d.%d.1", i/250, i%250), time.Now())
+		if i < MaxPeersLearnedPerSource/2 {
+			first.Peers = append(first.Peers, record)
+		} else {
+			second.Peers = append(second.Peers, record)
+		}
+	}
+	// the limit holds for the source, not for a single exchange
+	d.LearnSignedPeers(signedExchangeParcel("10.200.0.1", first))
+	d.LearnSignedPeers(signedExchangeParcel("10.200.0.1", second))
+
+	if len(d.knownPeers) != MaxPeersLearnedPerSource {
+		t.Errorf("learned %d peers from a single source, expected %d", len(d.knownPeers), MaxPeersLearnedPerSource)
+	}
+	for _, peer := range d.knownPeers {
+		if peer.Trust != PeerTrustSigned {
+			t.Errorf("relayed peer %s should have signed trust, got %d", peer.Address, peer.Trust)
+		}
+	}
+
+	d.LearnSignedPeers(signedExchangeParcel("10.201.0.1", PeerExchange{Peers: []SignedPeerRecord{newSignedRecord("10.250.0.1", time.Now())}}))
+	if _, known := d.knownPeers["10.250.0.1"]; !known {
+		t.Error("the limit of one source blocked another source")
+	}
+
+	// the count of a source is forgotten after LearnedFromMaxAge
+	learned := d.learnedFrom["10.200.0.1"]
+	learned.since = learned.since.Add(-LearnedFromMaxAge - time.Minute)
+	d.learnedFrom["10.200.0.1"] = learned
+	d.LearnSignedPeers(signedExchangeParcel("10.200.0.1", PeerExchange{Peers: []SignedPeerRecord{newSignedRecord("10.250.0.2", time.Now())}}))
+	if _, known := d.knownPeers["10.250.0.2"]; !known {
+		t.Error("the limit of a source still holds after LearnedFromMaxAge")
+	}
+}
+
+func TestLearnedFromIsCapped(t *testing.T) {
+	d := newTestDiscovery(t)
+	defer os.Remove(d.peersFilePath)
+	now := time.Now()
+	for i := 0; i < MaxLearnedFromSources; i++ {
+		d.countLearned(fmt.Sprintf("source%d", i), now.Add(time.Duration(i)*time.Millisecond))
+	}
+	d.countLearned("expired", now.Add(-LearnedFromMaxAge-time.Minute))
+	if len(d.learnedFrom) != MaxLearnedFromSources {
+		t.Fatalf("counting %d sources, expected at most %d", len(d.learnedFrom), MaxLearnedFromSources)
+	}
+	if _, ok := d.learnedFrom["source0"]; ok {
+		t.Error("the oldest source was not forgotten")
+	}
+
+	d.countLearned("new", now)
+	if _, ok := d.learnedFrom["expired"]; ok || len(d.learnedFrom) != MaxLearnedFromSources {
+		t.Error("an expired source was not forgotten first")
+	}
+}
+
+func TestLearnPeersLegacy(t *testing.T) {
+	d := newTestDiscovery(t)
+	defer os.Remove(d.peersFilePath)
+	legacyParcel := func(source string, addresses ...string) Parcel {
+		var peers []Peer
+		for _, address := range addresses {
+			peer := *new(Peer).Init(address, "8108", 0, RegularPeer, 0)
+			peer.Network = CurrentNetwork
+			peers = append(peers, peer)
+		}
+		payload, _ := json.Marshal(peers)
+		parcel := NewParcel(CurrentNetwork, payload)
+		parcel.Header.PeerAddress = source
+		return *parcel
+	}
+
+	d.LearnPeers(legacyParcel("10.200.0.1", "10.1.0.1"))
+	peer, known := d.knownPeers["10.1.0.1"]
+	if !known {
+		t.Fatal("did not learn a new legacy peer")
+	}
+	if peer.Trust != PeerTrustLegacy || peer.Source["10.200.0.1"].IsZero() {
+		t.Errorf("bad legacy peer %+v", peer)
+	}
+
+	var addresses []string
+	for i := 0; i < MaxPeersLearnedPerSource+10; i++ {
+		addresses = append(addresses, fmt.Sprintf("10.2.%d.1", i))
+	}
+	d.LearnPeers(legacyParcel("10.200.0.1", addresses[:10]...))
+	d.LearnPeers(legacyParcel("10.200.0.1", addresses[10:]...))
+	if len(d.knownPeers) != MaxPeersLearnedPerSource {
+		t.Errorf("learned %d peers from a single source, expected %d", len(d.knownPeers), MaxPeersLearnedPerSource)
+	}
+
+	// known peers still get the source added once the limit is reached
+	d.LearnPeers(legacyParcel("10.200.0.1", "10.1.0.1"))
+	d.LearnPeers(legacyParcel("10.201.0.1", "10.1.0.1"))
+	if peer := d.knownPeers["10.1.0.1"]; len(peer.Source) != 2 {
+		t.Errorf("expected 2 sources for a known peer, got %v", peer.Source)
+	}
+}
+
+func TestRelayedRecordsDoNotOverrideKnownPeers(t *testing.T) {
+	d := newTestDiscovery(t)
+	defer os.Remove(d.peersFilePath)
+	now := time.Now()
+
+	seed := *new(Peer).Init("10.1.0.1", "8108", 0, RegularPeer, 0)
+	d.updatePeer(d.updatePeerSource(seed, "DNS-Seed"))
+	d.updatePeer(*new(Peer).Init("10.1.0.2", "8108", 0, SpecialPeerConfig, 0))
+	key := primitives.RandomPrivateKey()
+	d.LearnSignedPeers(signedExchangeParcel("10.1.0.3", PeerExchange{Self: newRecordPointer(newSignedRecordWithKey("10.1.0.3", "8108", now, key))}))
+
+	var forged PeerExchange
+	for _, address := range []string{"10.1.0.1", "10.1.0.2", "10.1.0.3"} {
+		forged.Peers = append(forged.Peers, newSignedRecordWithKey(address, "6666", now.Add(time.Minute), primitives.RandomPrivateKey()))
+	}
+	d.LearnSignedPeers(signedExchangeParcel("10.9.9.9", forged))
+	for _, address := range []string{"10.1.0.1", "10.1.0.2", "10.1.0.3"} {
+		peer := d.knownPeers[address]
+		if peer.Port != "8108" || peer.Trust == PeerTrustSigned {
+			t.Errorf("a relayed record overrode %s: port %s trust %d", address, peer.Port, peer.Trust)
+		}
+	}
+	if record := d.knownPeers["10.1.0.3"].Record; record == nil || !bytes.Equal(record.PublicKey, key.Public()) {
+		t.Error("a relayed record replaced the pinned key of a verified peer")
+	}
+
+	// a relayed record signed by the pinned key refreshes the record
+	fresh := newSignedRecordWithKey("10.1.0.3", "8108", now.Add(2*time.Minute), key)
+	d.LearnSignedPeers(signedExchangeParcel("10.9.9.9", PeerExchange{Peers: []SignedPeerRecord{fresh}}))
+	if peer := d.knownPeers["10.1.0.3"]; peer.Trust != PeerTrustVerified || peer.Record.Timestamp != fresh.Timestamp {
+		t.Errorf("the pinned key failed to refresh the record, trust %d", peer.Trust)
+	}
+
+	// relayed records are not preferred over seed peers
+	relayed := newSignedRecord("10.1.0.4", now)
+	d.LearnSignedPeers(signedExchangeParcel("10.9.9.9", PeerExchange{Peers: []SignedPeerRecord{relayed}}))
+	filtered := d.filterForSubnetDiversity([]Peer{d.knownPeers["10.1.0.4"], d.knownPeers["10.1.0.1"], d.knownPeers["10.1.0.3"]})
+	if len(filtered) != MaxPeersPerSubnet || filtered[0].Address != "10.1.0.1" && filtered[1].Address != "10.1.0.1" {
+		t.Errorf("the seed peer was not selected over the relayed one: %v", filtered)
+	}
+}
+
+func newRecordPointer(record SignedPeerRecord) *SignedPeerRecord {
+	return &record
+}
+
+func TestIdentityKeyPersists(t *testing.T) {
+	d := newTestDiscovery(t)
+	defer os.Remove(d.peersFilePath)
+	defer os.Remove(d.peersFilePath + ".key")
+
+	key := d.loadIdentityKey()
+	again := d.loadIdentityKey()
+	if !bytes.Equal(key.Public(), again.Public()) {
+		t.Error("the identity key changed between loads")
+	}
+}
+
+func TestObserveSelfAddress(t *testing.T) {
+	d := newTestDiscovery(t)
+	defer os.Remove(d.peersFilePath)
+	if d.newSelfRecord() != nil {
+		t.Error("announced an address before it was observed")
+	}
+	d.observeSelfAddress("10.5.5.5", "10.1.1.1")
+	d.observeSelfAddress("10.5.5.5", "10.1.1.1")
+	d.observeSelfAddress("10.5.5.5", "10.1.2.2")
+	if d.selfAddress != "" {
+		t.Error("accepted an address observed by peers in a single subnet")
+	}
+	d.observeSelfAddress("10.5.5.5", "10.2.2.2")
+	if d.selfAddress != "" {
+		t.Errorf("accepted an address observed in %d subnets", len(d.addressVotes["10.5.5.5"]))
+	}
+
+	// old votes are forgotten
+	for subnet := range d.addressVotes["10.5.5.5"] {
+		d.addressVotes["10.5.5.5"][subnet] = time.Now().Add(-SelfAddressVoteMaxAge - time.Minute)
+	}
+	d.observeSelfAddress("10.5.5.5", "10.3.3.3")
+	if d.selfAddress != "" || len(d.addressVotes["10.5.5.5"]) != 1 {
+		t.Errorf("counted votes older than %s", SelfAddressVoteMaxAge)
+	}
+
+	// the number of observed addresses is capped, dropping the weakest
+	for i := 0; i < MaxSelfAddressCandidates; i++ {
+		d.observeSelfAddress(fmt.Sprintf("10.6.6.%d", i), "10.9.9.9")
+	}
+	if len(d.addressVotes) != MaxSelfAddressCandidates {
+		t.Errorf("keeping votes for %d addresses, expected at most %d", len(d.addressVotes), MaxSelfAddressCandidates)
+	}
+	if _, ok := d.addressVotes["10.5.5.5"]; ok {
+		t.Error("kept the least recently observed address")
+	}
+
+	d.observeSelfAddress("10.6.6.0", "10.4.4.4")
+	d.observeSelfAddress("10.6.6.0", "10.5.4.4")
+	if d.selfAddress != "10.6.6.0" {
+		t.Fatalf("expected the address confirmed in %d subnets, got %q", SelfAddressConfirmations, d.selfAddress)
+	}
+	record := d.newSelfRecord()
+	if record == nil || record.Verify(time.Now()) != nil {
+		t.Error("failed to sign our own announcement")
+	}
+}
+
+func TestFilterForSubnetDiversity(t *testing.T) {
+	d := newTestDiscovery(t)
+	defer os.Remove(d.peersFilePath)
+	var peers []Peer
+	for i := 1; i <= 5; i++ {
+		peers = append(peers, *new(Peer).Init(fmt.Sprintf("10.1.0.%d", i), "8108", 0, RegularPeer, 0))
+	}
+	peers[4].Trust = PeerTrustVerified
+	peers = append(peers, *new(Peer).Init("10.1.0.9", "8108", 0, SpecialPeerConfig, 0))
+	peers = append(peers, *new(Peer).Init("10.2.0.1", "8108", 0, RegularPeer, 0))
+
+	filtered := d.filterForSubnetDiversity(peers)
+	addresses := map[string]bool{}
+	for _, peer := range filtered {
+		addresses[peer.Address] = true
+	}
+	// The special peer counts towards the limit of its subnet
+	if len(filtered) != MaxPeersPerSubnet+1 {
+		t.Errorf("expected %d peers, got %d", MaxPeersPerSubnet+1, len(filtered))
+	}
+	for _, address := range []string{"10.1.0.5", "10.1.0.9", "10.2.0.1"} {
+		if !addresses[address] {
+			t.Errorf("expected %s to be selected", address)
+		}
+	}
+}
@@ -7,7 +7,6 @@ package p2p
 import (
 	"fmt"
 	"hash/crc32"
-	"math/rand"
 	"time"
 
 	"github.com/FactomProject/factomd/common/primitives"
@@ -49,7 +48,7 @@ var (
 	BroadcastFlag                       = "<BROADCAST>"
 	FullBroadcastFlag                   = "<FULLBORADCAST>"
 	RandomPeerFlag                      = "<RANDOMPEER>"
-	NodeID                       uint64 = 0           // Random number used for loopback protection
+	NodeID                       uint64 = 0           // loopback protection ID of connections made outside a controller, each Controller has its own
 	MinumumQualityScore          int32  = -200        // if a peer's score is less than this we ignore them.
 	BannedQualityScore           int32  = -2147000000 // Used to ban a peer
 	MinumumSharingQualityScore   int32  = 20          // if a peer's score is less than this we don't share them.
@@ -75,7 +74,7 @@ var (
 	ApplicationMessagesReceived uint64
 
 	CRCKoopmanTable = crc32.MakeTable(crc32.Koopman)
-	RandomGenerator *rand.Rand // seeded pseudo-random number generator
+	RandomSeed      int64 // seed the controller derives its NodeID from, the time if 0, so a journal replay can repeat it
 
 )
 
@@ -0,0 +1,31 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"net"
+	"time"
+)
+
+// Transport is how the controller listens for incoming connections and dials out to peers.
+// The network uses TCPTransport, tests can use the in-process transport of a MemoryNetwork
+// to run several controllers in one process.
+type Transport interface {
+	Listen(address string) (net.Listener, error)
+	DialTimeout(address string, timeout time.Duration) (net.Conn, error)
+}
+
+// TCPTransport is the transport used when ControllerInit does not specify one
+var TCPTransport Transport = tcpTransport{}
+
+type tcpTransport struct{}
+
+func (tcpTransport) Listen(address string) (net.Listener, error) {
+	return net.Listen("tcp", address)
+}
+
+func (tcpTransport) DialTimeout(address string, timeout time.Duration) (net.Conn, error) {
+	return net.DialTimeout("tcp", address, timeout)
+}
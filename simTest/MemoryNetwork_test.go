@@ -0,0 +1,43 @@
+package simtest
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/engine"
+
+	. "github.com/FactomProject/factomd/testHelper"
+)
+
+// TestMemoryNetwork runs the nodes over the real p2p stack, on the in-process network of
+// -net=memory, and checks that a follower falls behind while it is partitioned away and
+// catches up once the partition heals
+func TestMemoryNetwork(t *testing.T) {
+	state0 := SetupSim("LFF", map[string]string{"--net": "memory"}, 14, 0, 0, t)
+	WaitBlocks(state0, 1)
+
+	for _, fnode := range engine.GetFnodes() {
+		controller := fnode.State.NetworkController
+		if controller == nil {
+			t.Fatalf("%s has no p2p controller", fnode.State.FactomNodeName)
+		}
+		if n := controller.GetNumberOfConnections(); n != 2 {
+			t.Errorf("%s has %d connections, expected 2", fnode.State.FactomNodeName, n)
+		}
+	}
+
+	follower := engine.GetFnodes()[2].State
+	if err := engine.PartitionNodes([][]int{{2}}); err != nil {
+		t.Fatal(err)
+	}
+	WaitBlocks(state0, 2)
+	if follower.GetLLeaderHeight() >= state0.GetLLeaderHeight() {
+		t.Errorf("the partitioned follower kept up, at %d with the leader at %d", follower.GetLLeaderHeight(), state0.GetLLeaderHeight())
+	}
+
+	engine.HealPartitions()
+	WaitBlocks(state0, 3)
+	if follower.GetLLeaderHeight()+1 < state0.GetLLeaderHeight() {
+		t.Errorf("the follower did not catch up, at %d with the leader at %d", follower.GetLLeaderHeight(), state0.GetLLeaderHeight())
+	}
+	ShutDownEverything(t)
+}
@@ -0,0 +1,12 @@
+package simtest
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/testHelper"
+)
+
+// TestFundAndEntriesScenario runs scenarios/FundAndEntries.yaml, see testHelper.SimScenario for the format
+func TestFundAndEntriesScenario(t *testing.T) {
+	RunSimScenario(t, "scenarios/FundAndEntries.yaml")
+}
@@ -31,6 +31,15 @@ func (l *HoldingList) Messages() map[[32]byte][]interfaces.IMsg {
 	return l.holding
 }
 
+// Snapshot copies dependent holding for the APIs, keyed by message hash
+func (l *HoldingList) Snapshot() map[[32]byte]dependentMsg {
+	snapshot := make(map[[32]byte]dependentMsg, len(l.dependents))
+	for h, d := range l.dependents {
+		snapshot[h] = dependentMsg{d.dependentHash, l.holding[d.dependentHash][d.offset]}
+	}
+	return snapshot
+}
+
 func (l *HoldingList) GetSize() int {
 	return len(l.dependents)
 }
@@ -129,6 +138,18 @@ func (l *HoldingList) Review() {
 }
 
 func (l *HoldingList) isMsgStale(msg interfaces.IMsg) (res bool) {
+	res = l.staleReason(msg) != ""
+	if res {
+		l.s.LogMessage("DependentHolding", "EXPIRE", msg)
+	} else {
+		//		l.s.LogMessage("DependentHolding", "NOT_EXPIRED", msg)
+	}
+
+	return res
+}
+
+// staleReason returns why a message has expired from holding, or "" if it has not
+func (l *HoldingList) staleReason(msg interfaces.IMsg) (reason string) {
 
 	/*
 		REVIEW:
@@ -141,31 +162,25 @@ func (l *HoldingList) isMsgStale(msg interfaces.IMsg) (res bool) {
 	switch msg.Type() {
 	case constants.EOM_MSG:
 		if uint32(msg.(*messages.EOM).DBHeight)*10+uint32(msg.(*messages.EOM).Minute) < l.s.GetLLeaderHeight()*10+uint32(l.s.CurrentMinute) {
-			res = true
+			reason = "EOM for a past minute"
 		}
 	case constants.ACK_MSG:
 		if msg.(*messages.Ack).DBHeight < l.s.GetLLeaderHeight() {
-			res = true
+			reason = "ack for a past block"
 		}
 	case constants.DIRECTORY_BLOCK_SIGNATURE_MSG:
 		if msg.(*messages.DirectoryBlockSignature).DBHeight < l.s.GetLLeaderHeight() {
-			res = true
+			reason = "DBSig for a past block"
 		}
 	default:
 		//		l.s.LogMessage("DependentHolding", "SKIP_DBHT_REVIEW", msg)
 	}
 
 	if msg.GetTimestamp().GetTime().UnixNano() < l.s.GetMessageFilterTimestamp().GetTime().UnixNano() {
-		res = true
+		reason = "older than the message filter timestamp"
 	}
 
-	if res {
-		l.s.LogMessage("DependentHolding", "EXPIRE", msg)
-	} else {
-		//		l.s.LogMessage("DependentHolding", "NOT_EXPIRED", msg)
-	}
-
-	return res
+	return reason
 }
 
 func (s *State) HoldForHeight(ht uint32, minute int, msg interfaces.IMsg) int {
@@ -0,0 +1,228 @@
+package state
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/FactomProject/factomd/common/adminBlock"
+	"github.com/FactomProject/factomd/common/directoryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/globals"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// CustomGenesis defines the genesis blocks of a custom network, so a new network can be started
+// without changing the code.  The genesis factoid block pays the initial balances, and the genesis
+// admin block adds the initial federated and audit servers along with their signing keys.  The
+// identity chains of the servers and of the skeleton identity are created in the genesis block
+// too, see customGenesisIdentity.go.  Utilities/CustomNetwork generates one along with the
+// factomd.conf of every node.
+type CustomGenesis struct {
+	Network          string           `json:"network"` // the -customnet name of the network
+	ExchangeRate     uint64           `json:"exchangerate"`
+	Balances         []GenesisBalance `json:"balances"`
+	FederatedServers []GenesisServer  `json:"federatedservers"` // the first one is the bootstrap identity
+	AuditServers     []GenesisServer  `json:"auditservers"`
+	SkeletonIdentity string           `json:"skeletonidentity"` // its block signing key signs the messages adding and removing servers
+	IdentityEntries  []string         `json:"identityentries"`  // hex entries creating the identity chains
+}
+
+// GenesisBalance is a factoid balance paid in the genesis block
+type GenesisBalance struct {
+	Address string `json:"address"`
+	Amount  uint64 `json:"amount"` // in factoshis
+}
+
+// GenesisServer is a server identity in the genesis block
+type GenesisServer struct {
+	IdentityChainID string `json:"identitychainid"`
+	PublicKey       string `json:"publickey"` // hex ed25519 signing key
+}
+
+// ReadCustomGenesis reads and validates a custom network genesis definition
+func ReadCustomGenesis(filename string) (*CustomGenesis, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	g := new(CustomGenesis)
+	if err := json.Unmarshal(data, g); err != nil {
+		return nil, fmt.Errorf("genesis file %s: %v", filename, err)
+	}
+	if err := g.Validate(); err != nil {
+		return nil, fmt.Errorf("genesis file %s: %v", filename, err)
+	}
+	return g, nil
+}
+
+// Validate checks every address, identity and key of the genesis definition
+func (g *CustomGenesis) Validate() error {
+	if g.Network == "" {
+		return fmt.Errorf("no network name")
+	}
+	if g.ExchangeRate == 0 {
+		return fmt.Errorf("no exchange rate")
+	}
+	for i, b := range g.Balances {
+		if !primitives.ValidateFUserStr(b.Address) {
+			return fmt.Errorf("bad address %q in balance[%d]", b.Address, i)
+		}
+	}
+	if len(g.FederatedServers) == 0 {
+		return fmt.Errorf("there must be at least one federated server")
+	}
+	seen := make(map[string]bool)
+	for _, s := range append(append([]GenesisServer{}, g.FederatedServers...), g.AuditServers...) {
+		if _, err := primitives.HexToHash(s.IdentityChainID); err != nil {
+			return fmt.Errorf("bad identity chain id %q: %v", s.IdentityChainID, err)
+		}
+		if seen[s.IdentityChainID] {
+			return fmt.Errorf("identity %s is listed twice", s.IdentityChainID)
+		}
+		seen[s.IdentityChainID] = true
+		if key, err := hex.DecodeString(s.PublicKey); err != nil || len(key) != 32 {
+			return fmt.Errorf("bad public key %q for identity %s", s.PublicKey, s.IdentityChainID)
+		}
+	}
+	if g.SkeletonIdentity != "" {
+		if _, err := primitives.HexToHash(g.SkeletonIdentity); err != nil {
+			return fmt.Errorf("bad skeleton identity %q: %v", g.SkeletonIdentity, err)
+		}
+	}
+	if _, _, err := g.identityBlocks(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// loadCustomGenesis boots a custom network from the genesis definition named in the config file.
+// A relative path is relative to the directory of the config file.
+func (s *State) loadCustomGenesis(configFile string, genesisFile string) {
+	if !filepath.IsAbs(genesisFile) {
+		genesisFile = filepath.Join(filepath.Dir(configFile), genesisFile)
+	}
+	g, err := ReadCustomGenesis(genesisFile)
+	if err != nil {
+		panic(err.Error())
+	}
+	if g.Network != globals.Params.CustomNetName {
+		panic(fmt.Sprintf("The genesis file %s is for the custom network %q, not %q", genesisFile, g.Network, globals.Params.CustomNetName))
+	}
+	s.CustomGenesis = g
+	s.CustomBootstrapIdentity, s.CustomBootstrapKey = g.BootstrapIdentity()
+	fmt.Printf("Using the genesis of custom network %q from %s\n", g.Network, genesisFile)
+}
+
+// addServers adds the genesis servers to the first process list.  The genesis admin block only
+// adds the bootstrap identity to block 1, so the other servers have to be there from the start.
+func (g *CustomGenesis) addServers(pl *ProcessList) {
+	for _, s := range g.FederatedServers {
+		id, _ := primitives.HexToHash(s.IdentityChainID)
+		pl.AddFedServer(id)
+	}
+	for _, s := range g.AuditServers {
+		id, _ := primitives.HexToHash(s.IdentityChainID)
+		pl.AddAuditServer(id)
+	}
+}
+
+// BootstrapIdentity returns the identity and key that sign the first blocks of the network
+func (g *CustomGenesis) BootstrapIdentity() (identity string, key string) {
+	return g.FederatedServers[0].IdentityChainID, g.FederatedServers[0].PublicKey
+}
+
+// FBlock returns the genesis factoid block paying the initial balances
+func (g *CustomGenesis) FBlock() interfaces.IFBlock {
+	fblk := factoid.NewFBlock(nil)
+	fblk.SetExchRate(g.ExchangeRate)
+
+	coinbase := new(factoid.Transaction)
+	coinbase.SetTimestamp(primitives.NewTimestampFromMinutes(24018960))
+	for _, b := range g.Balances {
+		coinbase.AddOutput(factoid.NewAddress(primitives.ConvertUserStrToAddress(b.Address)), b.Amount)
+	}
+	if err := fblk.AddCoinbase(coinbase); err != nil {
+		panic(err)
+	}
+	for i := 1; i <= 10; i++ {
+		fblk.EndOfPeriod(i)
+	}
+
+	// Round trip the block so it is identical to the genesis block read back from the database
+	data, err := fblk.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	block := new(factoid.FBlock)
+	if err := block.UnmarshalBinary(data); err != nil {
+		panic(err)
+	}
+	block.GetBodyMR()
+	return block
+}
+
+// GenerateCustomGenesisBlocks generates the genesis blocks of a custom network from its definition
+func GenerateCustomGenesisBlocks(networkID uint32, g *CustomGenesis) (interfaces.IDirectoryBlock, interfaces.IAdminBlock, interfaces.IFBlock, interfaces.IEntryCreditBlock) {
+	dblk := directoryBlock.NewDirectoryBlock(nil)
+	ablk := adminBlock.NewAdminBlock(nil)
+	fblk := g.FBlock()
+	ecblk := entryCreditBlock.NewECBlock()
+
+	for _, s := range g.FederatedServers {
+		addGenesisServer(ablk, s, true)
+	}
+	for _, s := range g.AuditServers {
+		addGenesisServer(ablk, s, false)
+	}
+	if err := ablk.InsertIdentityABEntries(); err != nil {
+		panic(err)
+	}
+
+	dblk.SetABlockHash(ablk)
+	dblk.SetECBlockHash(ecblk)
+	dblk.SetFBlockHash(fblk)
+	dblk.GetHeader().SetNetworkID(networkID)
+
+	eblocks, _, err := g.identityBlocks()
+	if err != nil {
+		panic(err)
+	}
+	for _, eb := range eblocks {
+		keymr, err := eb.KeyMR()
+		if err != nil {
+			panic(err)
+		}
+		dblk.AddEntry(eb.GetChainID(), keymr)
+	}
+
+	dblk.GetHeader().SetTimestamp(primitives.NewTimestampFromMinutes(24018960))
+	dblk.BuildBodyMR()
+
+	return dblk, ablk, fblk, ecblk
+}
+
+// addGenesisServer adds a server and its signing key to the genesis admin block
+func addGenesisServer(ablk interfaces.IAdminBlock, s GenesisServer, federated bool) {
+	id, _ := primitives.HexToHash(s.IdentityChainID)
+	var key [32]byte
+	k, _ := hex.DecodeString(s.PublicKey)
+	copy(key[:], k)
+
+	var err error
+	if federated {
+		err = ablk.AddFedServer(id)
+	} else {
+		err = ablk.AddAuditServer(id)
+	}
+	if err == nil {
+		err = ablk.AddFederatedServerSigningKey(id, key)
+	}
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,187 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The identities of a custom network are created in its genesis block, so the servers and the
+// skeleton identity have their identity chains from the start.  Each identity gets a root chain,
+// a server management chain holding its block signing key, and an entry in the identity
+// registration chain.  The entries are signed when the network is generated and stored in the
+// genesis definition, as every node must build the same genesis block.
+
+// genesisSeconds is the timestamp of the genesis blocks, in seconds
+const genesisSeconds = 24018960 * 60
+
+// GenesisIdentity is an identity created in the genesis block, along with its private keys
+type GenesisIdentity struct {
+	IdentityChainID   interfaces.IHash
+	ManagementChainID interfaces.IHash
+	IdentityKeys      [4]*primitives.PrivateKey // identity keys of levels 1 to 4, level 1 signs the entries
+	SigningKey        *primitives.PrivateKey    // block signing key
+	Entries           []interfaces.IEBEntry     // the entries of the root and management chains
+	Registration      interfaces.IEBEntry       // the entry in the identity registration chain
+}
+
+// identityRegistrationChain returns the first entry of the chain every identity registers in
+func identityRegistrationChain() interfaces.IEBEntry {
+	return newIdentityEntry(nil, []byte("Factom Identity Registration Chain"), []byte("44079090249"))
+}
+
+// newIdentityEntry returns an entry with the given ExtIDs, in the chain they create when chainID
+// is nil
+func newIdentityEntry(chainID interfaces.IHash, extIDs ...[]byte) *entryBlock.Entry {
+	e := entryBlock.NewEntry()
+	for _, extID := range extIDs {
+		e.ExtIDs = append(e.ExtIDs, primitives.ByteSlice{Bytes: extID})
+	}
+	if chainID == nil {
+		chainID = entryBlock.ExternalIDsToChainID(extIDs)
+	}
+	e.ChainID = chainID
+	return e
+}
+
+// identityNonce finds the nonce that, appended to the ExtIDs, makes the chain ID start with 888888
+func identityNonce(extIDs [][]byte) []byte {
+	data := make([]byte, 0, (len(extIDs)+1)*sha256.Size)
+	for _, extID := range extIDs {
+		h := sha256.Sum256(extID)
+		data = append(data, h[:]...)
+	}
+	data = data[:cap(data)]
+	nonce := make([]byte, 8)
+	for n := uint64(0); ; n++ {
+		binary.BigEndian.PutUint64(nonce, n)
+		h := sha256.Sum256(nonce)
+		copy(data[len(extIDs)*sha256.Size:], h[:])
+		if id := sha256.Sum256(data); id[0] == 0x88 && id[1] == 0x88 && id[2] == 0x88 {
+			return nonce
+		}
+	}
+}
+
+// NewGenesisIdentity generates the keys of a new identity and the signed entries creating its chains
+func NewGenesisIdentity() *GenesisIdentity {
+	id := new(GenesisIdentity)
+	var keyHashes [][]byte
+	for i := range id.IdentityKeys {
+		id.IdentityKeys[i] = primitives.RandomPrivateKey()
+		keyHashes = append(keyHashes, primitives.Shad(id.identityKeyPreimage(i)).Bytes())
+	}
+	id.SigningKey = primitives.RandomPrivateKey()
+	timestamp := make([]byte, 8)
+	binary.BigEndian.PutUint64(timestamp, genesisSeconds)
+
+	rootExtIDs := append([][]byte{{0}, []byte("Identity Chain")}, keyHashes...)
+	rootExtIDs = append(rootExtIDs, identityNonce(rootExtIDs))
+	root := newIdentityEntry(nil, rootExtIDs...)
+	id.IdentityChainID = root.ChainID
+
+	manageExtIDs := [][]byte{{0}, []byte("Server Management"), id.IdentityChainID.Bytes()}
+	manageExtIDs = append(manageExtIDs, identityNonce(manageExtIDs))
+	manage := newIdentityEntry(nil, manageExtIDs...)
+	id.ManagementChainID = manage.ChainID
+
+	id.Entries = []interfaces.IEBEntry{
+		root,
+		id.signedEntry(id.IdentityChainID, []byte("Register Server Management"), id.ManagementChainID.Bytes()),
+		manage,
+		id.signedEntry(id.ManagementChainID, []byte("New Block Signing Key"), id.IdentityChainID.Bytes(), id.SigningKey.Pub[:], timestamp),
+	}
+	registration := identityRegistrationChain().GetChainID()
+	id.Registration = id.signedEntry(registration, []byte("Register Factom Identity"), id.IdentityChainID.Bytes())
+	return id
+}
+
+// identityKeyPreimage returns the preimage of an identity key, its type prefix and public key
+func (id *GenesisIdentity) identityKeyPreimage(level int) []byte {
+	return append([]byte{1}, id.IdentityKeys[level].Pub[:]...)
+}
+
+// signedEntry returns an identity entry with the ExtIDs [0] [function] [fields...] [identity key
+// preimage] [signature of the ExtIDs before the preimage]
+func (id *GenesisIdentity) signedEntry(chainID interfaces.IHash, function []byte, fields ...[]byte) interfaces.IEBEntry {
+	extIDs := append([][]byte{{0}, function}, fields...)
+	var signed []byte
+	for _, extID := range extIDs {
+		signed = append(signed, extID...)
+	}
+	signature := id.IdentityKeys[0].Sign(signed).Bytes()
+	return newIdentityEntry(chainID, append(extIDs, id.identityKeyPreimage(0), signature)...)
+}
+
+// GenesisServer returns the identity as a server of the genesis block
+func (id *GenesisIdentity) GenesisServer() GenesisServer {
+	return GenesisServer{IdentityChainID: id.IdentityChainID.String(), PublicKey: id.SigningKey.PublicKeyString()}
+}
+
+// NewCustomNetwork generates the genesis definition of a new custom network, with a new skeleton
+// identity and new identities for its federated and audit servers
+func NewCustomNetwork(name string, rate uint64, balances []GenesisBalance, feds int, audits int) (g *CustomGenesis, skeleton *GenesisIdentity, servers []*GenesisIdentity) {
+	g = &CustomGenesis{Network: name, ExchangeRate: rate, Balances: balances}
+	skeleton = NewGenesisIdentity()
+	g.SkeletonIdentity = skeleton.IdentityChainID.String()
+
+	entries := []interfaces.IEBEntry{identityRegistrationChain(), skeleton.Registration}
+	entries = append(entries, skeleton.Entries...)
+	for i := 0; i < feds+audits; i++ {
+		id := NewGenesisIdentity()
+		servers = append(servers, id)
+		if i < feds {
+			g.FederatedServers = append(g.FederatedServers, id.GenesisServer())
+		} else {
+			g.AuditServers = append(g.AuditServers, id.GenesisServer())
+		}
+		entries = append(append(entries, id.Registration), id.Entries...)
+	}
+	for _, e := range entries {
+		data, err := e.MarshalBinary()
+		if err != nil {
+			panic(err)
+		}
+		g.IdentityEntries = append(g.IdentityEntries, hex.EncodeToString(data))
+	}
+	return g, skeleton, servers
+}
+
+// identityBlocks returns the entry blocks of the identity chains created in the genesis block,
+// with their entries.  The entries of a chain keep their order in the genesis definition.
+func (g *CustomGenesis) identityBlocks() ([]interfaces.IEntryBlock, []interfaces.IEBEntry, error) {
+	var eblocks []interfaces.IEntryBlock
+	var entries []interfaces.IEBEntry
+	chains := make(map[[32]byte]*entryBlock.EBlock)
+	for i, s := range g.IdentityEntries {
+		data, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, nil, fmt.Errorf("identity entry %d: %v", i, err)
+		}
+		e := entryBlock.NewEntry()
+		if err := e.UnmarshalBinary(data); err != nil {
+			return nil, nil, fmt.Errorf("identity entry %d: %v", i, err)
+		}
+		if e.ChainID.String()[:6] != "888888" {
+			return nil, nil, fmt.Errorf("identity entry %d is in chain %s, which is not an identity chain", i, e.ChainID)
+		}
+		eb := chains[e.ChainID.Fixed()]
+		if eb == nil {
+			eb = entryBlock.NewEBlock()
+			eb.GetHeader().SetChainID(e.ChainID)
+			eb.GetHeader().SetDBHeight(0)
+			chains[e.ChainID.Fixed()] = eb
+			eblocks = append(eblocks, eb)
+		}
+		if err := eb.AddEBEntry(e); err != nil {
+			return nil, nil, err
+		}
+		entries = append(entries, e)
+	}
+	return eblocks, entries, nil
+}
@@ -0,0 +1,89 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/adminBlock"
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+func testCustomGenesis() *CustomGenesis {
+	g := new(CustomGenesis)
+	g.Network = "genesis_test"
+	g.ExchangeRate = 1000
+	g.Balances = []GenesisBalance{
+		{"FA2hvRaci9Kks9cLNkEUFcxzUJuUFaaAE1eWYLqa2qk1k9pVFVBp", 5e8},
+		{"FA3AEL2H9XZy3n199USs2poCEJBkK1Egy6JXhLehfLJjUYMKh1zS", 7e8},
+	}
+	for i := 0; i < 3; i++ {
+		s := GenesisServer{primitives.RandomHash().String(), primitives.RandomPrivateKey().PublicKeyString()}
+		if i < 2 {
+			g.FederatedServers = append(g.FederatedServers, s)
+		} else {
+			g.AuditServers = append(g.AuditServers, s)
+		}
+	}
+	return g
+}
+
+func TestCustomGenesisValidate(t *testing.T) {
+	if err := testCustomGenesis().Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	g := testCustomGenesis()
+	g.Balances[0].Address = "FA2hvRaci9Kks9cLNkEUFcxzUJuUFaaAE1eWYLqa2qk1k9pVFVBq"
+	if g.Validate() == nil {
+		t.Error("accepted a bad address")
+	}
+	g = testCustomGenesis()
+	g.AuditServers = append(g.AuditServers, g.FederatedServers[0])
+	if g.Validate() == nil {
+		t.Error("accepted a server listed twice")
+	}
+	g = testCustomGenesis()
+	g.FederatedServers[1].PublicKey = "abcd"
+	if g.Validate() == nil {
+		t.Error("accepted a bad key")
+	}
+	g = testCustomGenesis()
+	g.FederatedServers = nil
+	if g.Validate() == nil {
+		t.Error("accepted a network without federated servers")
+	}
+}
+
+func TestGenerateCustomGenesisBlocks(t *testing.T) {
+	g := testCustomGenesis()
+	dblk, ablk, fblk, _ := GenerateCustomGenesisBlocks(constants.LOCAL_NETWORK_ID, g)
+
+	if fblk.GetExchRate() != 1000 || len(fblk.GetTransactions()) != 1 {
+		t.Fatalf("wrong genesis factoid block %v", fblk)
+	}
+	outputs := fblk.GetTransactions()[0].GetOutputs()
+	if len(outputs) != 2 || outputs[0].GetUserAddress() != g.Balances[0].Address || outputs[1].GetAmount() != 7e8 {
+		t.Errorf("wrong genesis balances %v", outputs)
+	}
+
+	feds, audits, keys := 0, 0, 0
+	for _, e := range ablk.GetABEntries() {
+		switch e.(type) {
+		case *adminBlock.AddFederatedServer:
+			feds++
+		case *adminBlock.AddAuditServer:
+			audits++
+		case *adminBlock.AddFederatedServerSigningKey:
+			keys++
+		}
+	}
+	if feds != 2 || audits != 1 || keys != 3 {
+		t.Errorf("genesis admin block has %d federated servers, %d audit servers and %d keys", feds, audits, keys)
+	}
+
+	// Every node must generate the same genesis
+	dblk2, _, _, _ := GenerateCustomGenesisBlocks(constants.LOCAL_NETWORK_ID, g)
+	if !dblk.GetKeyMR().IsSameAs(dblk2.GetKeyMR()) {
+		t.Error("genesis block is not deterministic")
+	}
+}
@@ -0,0 +1,201 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/FactomProject/factomd/activations"
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The codes of the reasons a transaction or commit is rejected or held
+const (
+	RejectMalformed           = "malformed"            // the transaction is not well formed
+	RejectRCDMismatch         = "rcd-mismatch"         // an input does not have a matching RCD
+	RejectRCDNotActive        = "rcd-not-active"       // an input uses an RCD type that is not activated yet
+	RejectInvalidSignature    = "invalid-signature"    // a signature is missing or wrong
+	RejectInsufficientFee     = "insufficient-fee"     // the inputs do not cover the outputs and the fee
+	RejectInsufficientBalance = "insufficient-balance" // an address does not have the balance to pay, so it is held
+	RejectInvalidCredits      = "invalid-credits"      // the commit pays the wrong number of entry credits
+	RejectReplay              = "replay"               // the same transaction or commit was already submitted
+	RejectOutsideTimeWindow   = "outside-time-window"  // the timestamp is too old or too far in the future
+	RejectAlreadyCommitted    = "already-committed"    // the entry was already revealed
+	RejectRepeatCommit        = "repeat-commit"        // a commit paying as much or more is already pending
+)
+
+// ValidateWithoutSubmit runs every check a factoid transaction or commit goes through when it is
+// submitted, against the current state, and reports each one that fails.  The message is not
+// submitted and no state is changed, so the filters do not learn about it.
+func (s *State) ValidateWithoutSubmit(msg interfaces.IMsg) *interfaces.IValidationReport {
+	report := new(interfaces.IValidationReport)
+	report.Rejections = make([]interfaces.IValidationRejection, 0)
+	reject := func(code string, format string, args ...interface{}) {
+		report.Rejections = append(report.Rejections, interfaces.IValidationRejection{Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch m := msg.(type) {
+	case *messages.FactoidTransaction:
+		report.TransactionID = m.Transaction.GetSigHash()
+		s.validateTransaction(m.Transaction, reject)
+	case *messages.CommitChainMsg:
+		report.TransactionID = m.CommitChain.GetSigHash()
+		s.validateCommit(m, m.CommitChain.ECPubKey, m.CommitChain.Credits, m.CommitChain.Version, m.CommitChain.IsValid(), m.CommitChain.ValidateSignatures(), reject)
+	case *messages.CommitEntryMsg:
+		report.TransactionID = m.CommitEntry.GetSigHash()
+		s.validateCommit(m, m.CommitEntry.ECPubKey, m.CommitEntry.Credits, m.CommitEntry.Version, m.CommitEntry.IsValid(), m.CommitEntry.ValidateSignatures(), reject)
+	default:
+		reject(RejectMalformed, "%s is not a factoid transaction or commit", constants.MessageName(msg.Type()))
+		return report
+	}
+	if s.validateTime(msg, reject) {
+		s.validateReplay(msg, reject)
+	}
+
+	report.Valid = len(report.Rejections) == 0
+	return report
+}
+
+func (s *State) validateTransaction(tx interfaces.ITransaction, reject func(string, string, ...interface{})) {
+	inputs, rcds := tx.GetInputs(), tx.GetRCDs()
+	rcdsMatch := len(inputs) == len(rcds)
+	if !rcdsMatch {
+		reject(RejectRCDMismatch, "%d inputs have %d RCDs", len(inputs), len(rcds))
+	}
+	for i := 0; i < len(inputs) && i < len(rcds); i++ {
+		address, err := rcds[i].GetAddress()
+		if err != nil || !inputs[i].GetAddress().IsSameAs(address) {
+			rcdsMatch = false
+			reject(RejectRCDMismatch, "input %d %s does not match its RCD", i, primitives.ConvertFctAddressToUserStr(inputs[i].GetAddress()))
+		}
+	}
+
+	if factoid.UsesRCD_2(tx) && !s.IsActive(activations.RCD_2_MULTISIG) {
+		reject(RejectRCDNotActive, "multisig (RCD type 2) addresses are not activated at height %d", s.GetLLeaderHeight())
+	}
+
+	// The RCD problems are already reported, so only report what else is wrong with the structure
+	wellFormed := rcdsMatch
+	if rcdsMatch {
+		if err := tx.Validate(1); err != nil {
+			wellFormed = false
+			reject(RejectMalformed, "%v", err)
+		}
+		if err := tx.ValidateSignatures(); err != nil {
+			reject(RejectInvalidSignature, "%v", err)
+		}
+	}
+
+	if wellFormed {
+		rate := s.GetFactoshisPerEC()
+		fee, err := tx.CalculateFee(rate)
+		if err != nil {
+			reject(RejectMalformed, "%v", err)
+		} else {
+			in, _ := tx.TotalInputs()
+			out, _ := tx.TotalOutputs()
+			ecs, _ := tx.TotalECs()
+			if sum, err := factoid.ValidateAmounts(out, ecs, fee); err != nil {
+				reject(RejectMalformed, "%v", err)
+			} else if in < sum {
+				reject(RejectInsufficientFee, "inputs %s do not cover the outputs %s, the entry credit outputs %s and the fee %s at %d factoshis per EC",
+					primitives.ConvertDecimalToString(in), primitives.ConvertDecimalToString(out),
+					primitives.ConvertDecimalToString(ecs), primitives.ConvertDecimalToString(fee), rate)
+			}
+		}
+	}
+
+	// An address can be an input more than once, so check its balance against all it spends
+	spends := make(map[[32]byte]uint64)
+	var order []interfaces.IAddress
+	for _, input := range inputs {
+		adr := input.GetAddress()
+		if _, ok := spends[adr.Fixed()]; !ok {
+			order = append(order, adr)
+		}
+		sum, err := factoid.ValidateAmounts(spends[adr.Fixed()], input.GetAmount())
+		if err != nil {
+			reject(RejectMalformed, "%v", err)
+			return
+		}
+		spends[adr.Fixed()] = sum
+	}
+	for _, adr := range order {
+		balance := s.GetF(true, adr.Fixed())
+		if balance < int64(spends[adr.Fixed()]) {
+			reject(RejectInsufficientBalance, "%s has %d factoshis and needs %d",
+				primitives.ConvertFctAddressToUserStr(adr), balance, spends[adr.Fixed()])
+		}
+	}
+}
+
+// validateCommit reports what is wrong with a commit.  valid is the commit's IsValid, which
+// checks the credits and the signature, so when the signature is good the credits are wrong.
+func (s *State) validateCommit(msg interfaces.IMsg, key *primitives.ByteSlice32, credits uint8, version uint8, valid bool, sigErr error, reject func(string, string, ...interface{})) {
+	if !valid {
+		if sigErr != nil {
+			reject(RejectInvalidSignature, "%v", sigErr)
+		} else {
+			reject(RejectInvalidCredits, "a version %d commit cannot pay %d entry credits", version, credits)
+		}
+	}
+
+	balance := s.GetE(true, key.Fixed())
+	if balance < int64(credits) {
+		reject(RejectInsufficientBalance, "%s has %d entry credits and needs %d", ecUserAddress(key), balance, credits)
+	}
+
+	if !s.NoEntryYet(msg.GetHash(), nil) {
+		reject(RejectAlreadyCommitted, "entry %s is already revealed", msg.GetHash().String())
+	}
+	if !s.IsHighestCommit(msg.GetHash(), msg) {
+		reject(RejectRepeatCommit, "a commit with equal or greater payment already exists for entry %s", msg.GetHash().String())
+	}
+}
+
+// validateTime reports the first time window the timestamp of a message is outside of, and
+// returns false if there is one
+func (s *State) validateTime(msg interfaces.IMsg, reject func(string, string, ...interface{})) bool {
+	if m, ok := msg.(*messages.FactoidTransaction); ok {
+		if fs := s.GetFactoidState(); fs != nil && fs.GetCurrentBlock() != nil {
+			if err := fs.ValidateTransactionAge(m.Transaction); err != nil {
+				reject(RejectOutsideTimeWindow, "%v", err)
+				return false
+			}
+		}
+	} else {
+		filter := s.GetMessageFilterTimestamp().GetTime().UnixNano()
+		msgtime := msg.GetTimestamp().GetTime().UnixNano()
+		if msgtime < filter {
+			reject(RejectOutsideTimeWindow, "commit time %s is before the message filter %s",
+				msg.GetTimestamp().GetTime().String(), s.GetMessageFilterTimestamp().GetTime().String())
+			return false
+		} else if msgtime > filter+FilterTimeLimit {
+			reject(RejectOutsideTimeWindow, "commit time %s is too far in the future, it will be held", msg.GetTimestamp().GetTime().String())
+			return false
+		}
+	}
+
+	now := s.GetTimestamp()
+	if _, ok := s.Replay.Valid(constants.TIME_TEST, msg.GetRepeatHash().Fixed(), msg.GetTimestamp(), now); !ok {
+		reject(RejectOutsideTimeWindow, "timestamp %s is not within the replay filter of %s",
+			msg.GetTimestamp().GetTime().String(), now.GetTime().String())
+		return false
+	}
+	return true
+}
+
+// validateReplay checks the filters the API applies to every message, without adding the message
+// to them
+func (s *State) validateReplay(msg interfaces.IMsg, reject func(string, string, ...interface{})) {
+	now := s.GetTimestamp()
+	hash := msg.GetRepeatHash().Fixed()
+	if _, ok := s.FReplay.Valid(constants.BLOCK_REPLAY, hash, msg.GetTimestamp(), now); !ok {
+		reject(RejectReplay, "%x is already in a block", hash[:])
+	}
+	if _, ok := s.Replay.Valid(constants.NETWORK_REPLAY, hash, msg.GetTimestamp(), now); !ok {
+		reject(RejectReplay, "%x was already submitted", hash[:])
+	}
+}
@@ -0,0 +1,221 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
+)
+
+// ElectionHistoryLimit is how many elections are kept in the election history
+var ElectionHistoryLimit = 1000
+
+// The outcomes of an election
+const (
+	ElectionRunning   = "running"
+	ElectionElected   = "elected"
+	ElectionAbandoned = "abandoned" // the node moved past the fault before an audit server was elected
+)
+
+var electionHistoryKey = []byte("ElectionHistory")
+
+// electionHistory records the elections this node takes part in, so what happened can be audited
+// after a fault without the debug logs.  The records are saved in the key value store of the
+// database when an election ends, so they survive a restart.
+type electionHistory struct {
+	mutex   sync.Mutex
+	loaded  bool
+	records []*interfaces.ElectionRecord
+	current *interfaces.ElectionRecord
+}
+
+// loadElectionHistory reads the saved history the first time it is needed, as the database is not open when the
+// state is created
+func (s *State) loadElectionHistory() {
+	h := &s.electionHistory
+	if h.loaded || s.DB == nil {
+		return
+	}
+	h.loaded = true
+
+	data := new(primitives.ByteSlice)
+	_, err := s.DB.FetchKeyValueStore(electionHistoryKey, data)
+	if err != nil || len(data.Bytes) == 0 {
+		return
+	}
+	var saved []*interfaces.ElectionRecord
+	if err := json.Unmarshal(data.Bytes, &saved); err != nil {
+		s.LogPrintf("election", "Cannot load the election history: %v", err)
+		return
+	}
+	h.records = append(saved, h.records...)
+}
+
+func (s *State) saveElectionHistory() {
+	h := &s.electionHistory
+	if len(h.records) > ElectionHistoryLimit {
+		h.records = h.records[len(h.records)-ElectionHistoryLimit:]
+	}
+	if s.DB == nil {
+		return
+	}
+	data, err := json.Marshal(h.records)
+	if err != nil {
+		return
+	}
+	err = s.DB.SaveKeyValueStore(&primitives.ByteSlice{Bytes: data}, electionHistoryKey)
+	if err != nil {
+		s.LogPrintf("election", "Cannot save the election history: %v", err)
+	}
+}
+
+// ElectionStarted records the start of an election to replace the leader at fedIndex, which is
+// missing its EOM or DBSig for the vm.  An election still running is abandoned.
+func (s *State) ElectionStarted(dbheight uint32, minute int, vmIndex int, fedIndex int, fedID interfaces.IHash) {
+	h := &s.electionHistory
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	s.loadElectionHistory()
+	s.endElection(ElectionAbandoned, nil)
+
+	h.current = &interfaces.ElectionRecord{
+		DBHeight:     dbheight,
+		Minute:       minute,
+		VMIndex:      vmIndex,
+		FaultedIndex: fedIndex,
+		FaultedID:    fedID.String(),
+		Volunteers:   make([]string, 0),
+		Outcome:      ElectionRunning,
+		StartTime:    time.Now().UnixNano() / int64(time.Millisecond),
+	}
+	h.records = append(h.records, h.current)
+
+	if s.EventService != nil {
+		s.EventService.EmitNodeInfoMessageF(eventmessages.NodeMessageCode_GENERAL,
+			"Election started at %d minute %d vm %d to replace leader %d %s", dbheight, minute, vmIndex, fedIndex, fedID.String())
+	}
+}
+
+// ElectionVolunteer records an audit server volunteering in the running election
+func (s *State) ElectionVolunteer(serverID interfaces.IHash) {
+	h := &s.electionHistory
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.current == nil {
+		return
+	}
+	id := serverID.String()
+	for _, v := range h.current.Volunteers {
+		if v == id {
+			return
+		}
+	}
+	h.current.Volunteers = append(h.current.Volunteers, id)
+}
+
+// ElectionRound records the round the running election has reached
+func (s *State) ElectionRound(round int) {
+	h := &s.electionHistory
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.current != nil && round > h.current.Rounds {
+		h.current.Rounds = round
+	}
+}
+
+// ElectionLevel records a vote level seen in the running election
+func (s *State) ElectionLevel(level int) {
+	h := &s.electionHistory
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.current != nil && level > h.current.Level {
+		h.current.Level = level
+	}
+}
+
+// ElectionEnded records the end of the running election.  The winner is the audit server that
+// replaced the faulted leader, or nil if the election was abandoned.
+func (s *State) ElectionEnded(winner interfaces.IHash) {
+	h := &s.electionHistory
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if winner == nil {
+		s.endElection(ElectionAbandoned, nil)
+	} else {
+		s.endElection(ElectionElected, winner)
+	}
+}
+
+func (s *State) endElection(outcome string, winner interfaces.IHash) {
+	h := &s.electionHistory
+	e := h.current
+	if e == nil {
+		return
+	}
+	h.current = nil
+
+	e.Outcome = outcome
+	if winner != nil {
+		e.Winner = winner.String()
+	}
+	e.Duration = time.Now().UnixNano()/int64(time.Millisecond) - e.StartTime
+	s.saveElectionHistory()
+
+	if s.EventService != nil {
+		result := "no audit server was elected"
+		if winner != nil {
+			result = fmt.Sprintf("elected %s", e.Winner)
+		}
+		s.EventService.EmitNodeInfoMessageF(eventmessages.NodeMessageCode_GENERAL,
+			"Election ended at %d minute %d vm %d after %d rounds and %dms, %s", e.DBHeight, e.Minute, e.VMIndex, e.Rounds, e.Duration, result)
+	}
+}
+
+// GetElectionHistory returns the recorded elections, oldest first
+func (s *State) GetElectionHistory() []interfaces.ElectionRecord {
+	h := &s.electionHistory
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	s.loadElectionHistory()
+
+	history := make([]interfaces.ElectionRecord, 0, len(h.records))
+	for _, e := range h.records {
+		r := *e
+		r.Volunteers = append([]string{}, e.Volunteers...)
+		if r.Outcome == ElectionRunning {
+			r.Duration = time.Now().UnixNano()/int64(time.Millisecond) - r.StartTime
+		}
+		history = append(history, r)
+	}
+	return history
+}
+
+// ElectionHistoryString formats the recorded elections for the control panel, most recent first
+func (s *State) ElectionHistoryString() string {
+	history := s.GetElectionHistory()
+	str := fmt.Sprintf("%-25s %8s %6s %3s %10s %-9s %6s %5s %10s %10s\n",
+		"Start", "DBHeight", "Minute", "VM", "Faulted", "Outcome", "Rounds", "Level", "Winner", "Duration")
+	for i := len(history) - 1; i >= 0; i-- {
+		e := history[i]
+		volunteers := make([]string, 0, len(e.Volunteers))
+		for _, v := range e.Volunteers {
+			volunteers = append(volunteers, shortID(v))
+		}
+		str += fmt.Sprintf("%-25s %8d %6d %3d %3d-%6s %-9s %6d %5d %10s %9dms  volunteers %v\n",
+			time.Unix(0, e.StartTime*int64(time.Millisecond)).Format(time.RFC3339), e.DBHeight, e.Minute, e.VMIndex,
+			e.FaultedIndex, shortID(e.FaultedID), e.Outcome, e.Rounds, e.Level, shortID(e.Winner), e.Duration, volunteers)
+	}
+	return str
+}
+
+// shortID returns the part of a server chain id that is shown in the election displays
+func shortID(id string) string {
+	if len(id) < 12 {
+		return id
+	}
+	return id[6:12]
+}
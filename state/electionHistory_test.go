@@ -0,0 +1,66 @@
+package state
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/mapdb"
+)
+
+func TestElectionHistory(t *testing.T) {
+	s := new(State)
+	s.DB = databaseOverlay.NewOverlay(new(mapdb.MapDB))
+
+	faulted, volunteer := primitives.RandomHash(), primitives.RandomHash()
+	s.ElectionStarted(10, 3, 1, 2, faulted)
+	s.ElectionRound(1)
+	s.ElectionVolunteer(volunteer)
+	s.ElectionVolunteer(volunteer)
+	s.ElectionLevel(2)
+	s.ElectionLevel(1)
+	s.ElectionRound(2)
+
+	history := s.GetElectionHistory()
+	if len(history) != 1 || history[0].Outcome != ElectionRunning {
+		t.Fatalf("Expected one running election, found %v", history)
+	}
+
+	s.ElectionEnded(volunteer)
+	s.ElectionEnded(nil) // no election is running, so nothing changes
+	s.ElectionStarted(11, -1, 0, 0, faulted)
+	s.ElectionStarted(11, 1, 0, 0, faulted) // abandons the one before
+
+	history = s.GetElectionHistory()
+	if len(history) != 3 {
+		t.Fatalf("Expected 3 elections, found %d", len(history))
+	}
+	e := history[0]
+	if e.DBHeight != 10 || e.Minute != 3 || e.VMIndex != 1 || e.FaultedIndex != 2 || e.FaultedID != faulted.String() {
+		t.Errorf("Wrong election %+v", e)
+	}
+	if len(e.Volunteers) != 1 || e.Volunteers[0] != volunteer.String() || e.Rounds != 2 || e.Level != 2 {
+		t.Errorf("Wrong election progress %+v", e)
+	}
+	if e.Outcome != ElectionElected || e.Winner != volunteer.String() {
+		t.Errorf("Expected %s to be elected, found %+v", volunteer.String(), e)
+	}
+	if history[1].Outcome != ElectionAbandoned || history[1].Winner != "" {
+		t.Errorf("Expected the second election to be abandoned, found %+v", history[1])
+	}
+	if history[2].Outcome != ElectionRunning {
+		t.Errorf("Expected the last election to be running, found %+v", history[2])
+	}
+	if !strings.Contains(s.ElectionHistoryString(), ElectionAbandoned) {
+		t.Errorf("Expected the history string to show the abandoned election")
+	}
+
+	// A restarted node reads the ended elections back from the database
+	s2 := new(State)
+	s2.DB = s.DB
+	history = s2.GetElectionHistory()
+	if len(history) != 2 || history[0].Winner != volunteer.String() || history[1].Outcome != ElectionAbandoned {
+		t.Errorf("Expected the ended elections to be saved, found %v", history)
+	}
+}
@@ -0,0 +1,163 @@
+package state
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/globals"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// Networks other than MAIN can pay grants from a data file instead of the hard coded tables.
+// The file lists the grants and is signed by one of the keys in the config file, so a node
+// will not pay out grants from a file that the operators of the network did not approve.  The
+// signature covers the network ID, so a file signed for one network is not accepted by another
+// network that trusts the same key:
+//
+//	{
+//	  "networkid": 4203931044,
+//	  "grants": [{"height": 11, "amount": 120000000000, "address": "FA2hvRaci9Kks9cLNkEUFcxzUJuUFaaAE1eWYLqa2qk1k9pVFVBp"}],
+//	  "publickey": "<hex ed25519 public key>",
+//	  "signature": "<hex signature of the 4 byte big endian network ID followed by the grants exactly as they appear in the file>"
+//	}
+
+// GrantFileEntry is a grant as it is written in a grant file
+type GrantFileEntry struct {
+	Height  uint32 `json:"height"`
+	Amount  uint64 `json:"amount"`
+	Address string `json:"address"`
+}
+
+// GrantFile is a list of grants signed by a key trusted to define the grants of a network
+type GrantFile struct {
+	NetworkID uint32          `json:"networkid"`
+	Grants    json.RawMessage `json:"grants"`
+	PublicKey string          `json:"publickey"`
+	Signature string          `json:"signature"`
+}
+
+// The grants loaded from a grant file, paid in addition to the hard coded grants
+var fileGrants []HardGrant
+
+// NewGrantFile signs a list of grants for the network with the given key
+func NewGrantFile(grants []GrantFileEntry, networkID uint32, key *primitives.PrivateKey) (*GrantFile, error) {
+	data, err := json.Marshal(grants)
+	if err != nil {
+		return nil, err
+	}
+	f := new(GrantFile)
+	f.NetworkID = networkID
+	f.Grants = data
+	f.PublicKey = key.PublicKeyString()
+	f.Signature = hex.EncodeToString(key.Sign(f.signedData()).Bytes())
+	return f, nil
+}
+
+// signedData returns the bytes covered by the signature of the grant file
+func (f *GrantFile) signedData() []byte {
+	data := make([]byte, 4, 4+len(f.Grants))
+	binary.BigEndian.PutUint32(data, f.NetworkID)
+	return append(data, f.Grants...)
+}
+
+// ReadGrantFile reads a grant file and returns its grants if it is signed by one of the keys
+// for the network, and every grant is valid for this network
+func ReadGrantFile(filename string, keys []string, networkID uint32) ([]HardGrant, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	f := new(GrantFile)
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("grant file %s: %v", filename, err)
+	}
+	return f.Verify(keys, networkID)
+}
+
+// Verify checks the grant file is for the network and its signature against the trusted keys, and
+// returns the grants
+func (f *GrantFile) Verify(keys []string, networkID uint32) ([]HardGrant, error) {
+	if f.NetworkID != networkID {
+		return nil, fmt.Errorf("grant file is for network %x, not %x", f.NetworkID, networkID)
+	}
+	trusted := false
+	for _, k := range keys {
+		if k == f.PublicKey {
+			trusted = true
+		}
+	}
+	if !trusted {
+		return nil, fmt.Errorf("grant file is signed by %q which is not a GrantFilePublicKeys key", f.PublicKey)
+	}
+	pub, err := hex.DecodeString(f.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("grant file public key: %v", err)
+	}
+	sig, err := hex.DecodeString(f.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("grant file signature: %v", err)
+	}
+	if !primitives.VerifySlice(pub, f.signedData(), sig) {
+		return nil, fmt.Errorf("grant file signature is invalid")
+	}
+
+	var entries []GrantFileEntry
+	if err := json.Unmarshal(f.Grants, &entries); err != nil {
+		return nil, fmt.Errorf("grant file grants: %v", err)
+	}
+	grants := make([]HardGrant, 0, len(entries))
+	for i, e := range entries {
+		if !primitives.ValidateFUserStr(e.Address) {
+			return nil, fmt.Errorf("bad address %q in grant file grant[%d]", e.Address, i)
+		}
+		if e.Height%constants.COINBASE_PAYOUT_FREQUENCY != 1 {
+			return nil, fmt.Errorf("bad payout height %d in grant file grant[%d]", e.Height, i)
+		}
+		if e.Amount == 0 {
+			return nil, fmt.Errorf("grant file grant[%d] pays nothing", i)
+		}
+		grants = append(grants, HardGrant{e.Height, e.Amount, factoid.NewAddress(primitives.ConvertUserStrToAddress(e.Address))})
+	}
+	return grants, nil
+}
+
+// SetFileGrants sets the grants loaded from a grant file.  The grants of the main network are
+// part of the protocol so they can only come from the hard coded table.
+func SetFileGrants(grants []HardGrant) error {
+	if strings.ToUpper(globals.Params.NetworkName) == "MAIN" {
+		return fmt.Errorf("grants can not be loaded from a file on the MAIN network")
+	}
+	fileGrants = grants
+	return nil
+}
+
+// GetGrants returns the hard coded grants followed by the grants loaded from a grant file
+func GetGrants() []HardGrant {
+	grants := GetHardCodedGrants()
+	for _, g := range fileGrants {
+		grants = append(grants, g)
+	}
+	return grants
+}
+
+// GetUpcomingGrants returns the grants that have not been declared in a saved block yet,
+// ordered by height
+func (s *State) GetUpcomingGrants() []interfaces.IScheduledGrant {
+	saved := s.GetHighestSavedBlk()
+	upcoming := make([]interfaces.IScheduledGrant, 0)
+	for _, g := range GetGrants() {
+		if g.DBh > saved {
+			upcoming = append(upcoming, interfaces.IScheduledGrant{Height: g.DBh, Amount: g.Amount, Address: g.Address})
+		}
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool { return upcoming[i].Height < upcoming[j].Height })
+	return upcoming
+}
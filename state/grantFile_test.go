@@ -0,0 +1,87 @@
+package state
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/globals"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+func writeGrantFile(t *testing.T, f *GrantFile) string {
+	file, err := ioutil.TempFile(os.TempDir(), "GrantFile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := json.NewEncoder(file).Encode(f); err != nil {
+		t.Fatal(err)
+	}
+	return file.Name()
+}
+
+func TestReadGrantFile(t *testing.T) {
+	globals.Params.NetworkName = "LOCAL"
+	constants.SetLocalCoinBaseConstants()
+
+	key := primitives.RandomPrivateKey()
+	entries := []GrantFileEntry{
+		{31, 5e8, "FA2hvRaci9Kks9cLNkEUFcxzUJuUFaaAE1eWYLqa2qk1k9pVFVBp"},
+		{41, 7e8, "FA3AEL2H9XZy3n199USs2poCEJBkK1Egy6JXhLehfLJjUYMKh1zS"},
+	}
+	f, err := NewGrantFile(entries, constants.LOCAL_NETWORK_ID, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := writeGrantFile(t, f)
+	defer os.Remove(filename)
+
+	if _, err := ReadGrantFile(filename, []string{primitives.RandomPrivateKey().PublicKeyString()}, constants.LOCAL_NETWORK_ID); err == nil {
+		t.Error("accepted a grant file signed by an untrusted key")
+	}
+	if _, err := ReadGrantFile(filename, []string{key.PublicKeyString()}, constants.TEST_NETWORK_ID); err == nil {
+		t.Error("accepted a grant file signed for another network")
+	}
+	grants, err := ReadGrantFile(filename, []string{key.PublicKeyString()}, constants.LOCAL_NETWORK_ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(grants) != 2 || grants[0].DBh != 31 || grants[1].Amount != 7e8 {
+		t.Errorf("wrong grants %v", grants)
+	}
+
+	tampered := *f
+	tampered.Grants = []byte(`[{"height":31,"amount":500000000000,"address":"FA2hvRaci9Kks9cLNkEUFcxzUJuUFaaAE1eWYLqa2qk1k9pVFVBp"}]`)
+	if _, err := tampered.Verify([]string{key.PublicKeyString()}, constants.LOCAL_NETWORK_ID); err == nil {
+		t.Error("accepted a grant file with altered grants")
+	}
+
+	// changing the network of the file breaks the signature
+	tampered = *f
+	tampered.NetworkID = constants.TEST_NETWORK_ID
+	if _, err := tampered.Verify([]string{key.PublicKeyString()}, constants.TEST_NETWORK_ID); err == nil {
+		t.Error("accepted a grant file with an altered network")
+	}
+
+	badHeight, _ := NewGrantFile([]GrantFileEntry{{30, 1, entries[0].Address}}, constants.LOCAL_NETWORK_ID, key)
+	if _, err := badHeight.Verify([]string{key.PublicKeyString()}, constants.LOCAL_NETWORK_ID); err == nil {
+		t.Error("accepted a grant that is not on a payout height")
+	}
+
+	defer SetFileGrants(nil)
+	if err := SetFileGrants(grants); err != nil {
+		t.Fatal(err)
+	}
+	if payouts := GetGrantPayoutsFor(31); len(payouts) != 1 || payouts[0].GetAmount() != 5e8 {
+		t.Errorf("grant file grant not paid out, got %v", payouts)
+	}
+
+	globals.Params.NetworkName = "MAIN"
+	if err := SetFileGrants(grants); err == nil {
+		t.Error("loaded grants from a file on the main network")
+	}
+	globals.Params.NetworkName = "LOCAL"
+}
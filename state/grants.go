@@ -1315,7 +1315,7 @@ func validateAddress(a string) interfaces.IAddress {
 
 func CheckGrants() {
 
-	hardcodegrants := GetHardCodedGrants()
+	hardcodegrants := GetGrants()
 	// this used to be in an init block but it turns out COINBASE_PAYOUT_FREQUENCY isn't so
 	// constants (changed based on network type) so it had to move here to be valid.
 	for i, g := range hardcodegrants { // check every hardcoded grant
@@ -1336,7 +1336,7 @@ func GetGrantPayoutsFor(currentDBHeight uint32) []interfaces.ITransAddress {
 	// I opted for one list knowing it will have to be different for testnet vs mainnet because making it
 	// network sensitive just add complexity to the code.
 	// there is no need for activation height because the grants have inherent activation heights per grant
-	for _, g := range GetHardCodedGrants() { // check every hardcoded grant
+	for _, g := range GetGrants() { // check every hardcoded grant
 		if g.DBh == currentDBHeight { // if it's ready {...
 			o := factoid.NewOutAddress(g.Address, g.Amount) // Create a payout
 			outputs = append(outputs, o)                    // and add it to the list
@@ -0,0 +1,198 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants/runstate"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	llog "github.com/FactomProject/factomd/log"
+)
+
+// The kinds of journal records
+const (
+	JournalKindSeed = "seed" // the seed of the random number generators, the first record
+	JournalKindTick = "tick" // a timer tick taken from the ticker queue
+	JournalKindMsg  = "msg"  // a message arriving at the node from the network or the API
+)
+
+// The queues a journaled message arrived on
+const (
+	JournalInMsgQueue   = 1
+	JournalInMsgQueue2  = 2
+	JournalDataMsgQueue = 3
+)
+
+// JournalRecord is one line of the journal.  The journal holds the inputs that drive the state, the
+// messages as they arrive and the ticks as the ValidatorLoop takes them, in one sequence so a replay
+// can feed them to the state in the same order.  Messages the node makes for itself are not recorded,
+// the replay makes them again.  The journal does not hold everything the node depends on: code that
+// reads the clock itself, and the goroutines of elections, missing message requests and entry
+// syncing, run as they like during a replay, so a replay follows the recording closely but not exactly.
+type JournalRecord struct {
+	Seq  uint64 `json:"seq"`
+	Time int64  `json:"time"` // unix milliseconds, the timestamp the state sees during a replay
+	Kind string `json:"kind"`
+
+	Seed int64 `json:"seed,omitempty"`
+	Tick int   `json:"tick"` // the value on the ticker queue, -1 for a timer tick, a retry count otherwise
+
+	Queue         int    `json:"queue,omitempty"` // JournalInMsgQueue, JournalInMsgQueue2 or JournalDataMsgQueue
+	Local         bool   `json:"local,omitempty"`
+	Origin        int    `json:"origin,omitempty"`
+	NetworkOrigin string `json:"networkorigin,omitempty"`
+	Type          byte   `json:"type,omitempty"`
+	Msg           string `json:"msg,omitempty"` // human readable, not used by the replay
+	MsgHex        string `json:"msghex,omitempty"`
+}
+
+// Message unmarshals the message of a msg record, with the locality and origin it arrived with
+func (r *JournalRecord) Message() (interfaces.IMsg, error) {
+	data, err := hex.DecodeString(r.MsgHex)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := messages.General.UnmarshalMessage(data)
+	if err != nil {
+		return nil, err
+	}
+	msg.SetLocal(r.Local)
+	msg.SetOrigin(r.Origin)
+	msg.SetNetworkOrigin(r.NetworkOrigin)
+	return msg, nil
+}
+
+// journal appends a record to the journal file, numbering it and stamping it with the state time
+func (s *State) journal(r *JournalRecord) {
+	if !s.Journaling || len(s.JournalFile) == 0 {
+		return
+	}
+
+	s.journalMutex.Lock()
+	defer s.journalMutex.Unlock()
+
+	f, err := os.OpenFile(s.JournalFile, os.O_APPEND+os.O_WRONLY, 0666)
+	if err != nil {
+		s.JournalFile = ""
+		return
+	}
+	defer f.Close()
+
+	s.journalSeq++
+	r.Seq = s.journalSeq
+	if s.IsReplaying && s.ReplayTimestamp != nil {
+		r.Time = s.ReplayTimestamp.GetTimeMilli()
+	} else {
+		r.Time = time.Now().UnixNano() / int64(time.Millisecond)
+	}
+
+	p, err := json.Marshal(r)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(f, string(p))
+}
+
+// WaitReplayIdle waits until the queues a journal replay feeds are empty and the node ran out of work
+// after that.  It returns false if the node stopped running instead.
+func (s *State) WaitReplayIdle() bool {
+	check := time.NewTicker(time.Second)
+	defer check.Stop()
+	for {
+		empty := s.replayQueuesEmpty()
+		select {
+		case <-s.replayIdle:
+			if empty && s.replayQueuesEmpty() {
+				return true
+			}
+		case <-check.C:
+			if s.GetRunState() != runstate.Running {
+				return false
+			}
+		}
+	}
+}
+
+func (s *State) replayQueuesEmpty() bool {
+	return s.InMsgQueue().Length() == 0 && s.InMsgQueue2().Length() == 0 && len(s.tickerQueue) == 0 &&
+		len(s.ackQueue) == 0 && len(s.msgQueue) == 0 && len(s.dataQueue) == 0
+}
+
+// JournalTick records a value taken from the ticker queue
+func (s *State) JournalTick(tick int) {
+	s.journal(&JournalRecord{Kind: JournalKindTick, Tick: tick})
+}
+
+// JournalMessage writes the message to the message journal for debugging
+func (s *State) JournalMessage(msg interfaces.IMsg) {
+	s.JournalArrival(JournalInMsgQueue, msg)
+}
+
+// JournalArrival records a message as it arrives at the node, before it is put on the given queue
+func (s *State) JournalArrival(queue int, msg interfaces.IMsg) {
+	if !s.Journaling || len(s.JournalFile) == 0 {
+		return
+	}
+
+	r := &JournalRecord{
+		Kind:          JournalKindMsg,
+		Queue:         queue,
+		Local:         msg.IsLocal(),
+		Origin:        msg.GetOrigin(),
+		NetworkOrigin: msg.GetNetworkOrigin(),
+		Type:          msg.Type(),
+	}
+	r.Msg, r.MsgHex = journalMessageText(msg)
+	s.journal(r)
+}
+
+// journalMessageText returns the message as text and hex, a message that is not complete enough
+// to print or marshal is journaled with what could be had
+func journalMessageText(msg interfaces.IMsg) (str string, msgHex string) {
+	defer func() {
+		if r := recover(); r != nil {
+			llog.LogPrintf("recovery", "journal panic Error writing a message %v", r)
+		}
+	}()
+
+	if data, err := msg.MarshalBinary(); err == nil {
+		msgHex = hex.EncodeToString(data)
+	}
+	str = msg.String()
+	return str, msgHex
+}
+
+// GetJournalMessages gets all messages from the message journal
+func (s *State) GetJournalMessages() [][]byte {
+	ret := make([][]byte, 0)
+	if !s.Journaling || len(s.JournalFile) == 0 {
+		return nil
+	}
+
+	f, err := os.Open(s.JournalFile)
+	if err != nil {
+		s.JournalFile = ""
+		return nil
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		p, err := r.ReadBytes('\n')
+		if err != nil {
+			break
+		}
+		ret = append(ret, p)
+	}
+
+	return ret
+}
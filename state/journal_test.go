@@ -5,11 +5,13 @@
 package state_test
 
 import (
+	"encoding/json"
 	"os"
 	"testing"
 
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
 	. "github.com/FactomProject/factomd/testHelper"
 )
 
@@ -37,3 +39,58 @@ func TestJournal(t *testing.T) {
 		t.Error("No messages returned from journal")
 	}
 }
+
+func TestJournalRecords(t *testing.T) {
+	s := CreateAndPopulateTestStateAndStartValidator()
+	filename := "journaltest.log"
+	defer os.Remove(filename)
+	f, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	s.JournalFile = filename
+	s.Journaling = true
+
+	eom := new(messages.EOM)
+	eom.Timestamp = primitives.NewTimestampNow()
+	eom.ChainID = primitives.NewHash([]byte("journal"))
+	eom.DBHeight = 7
+	eom.Minute = 3
+	eom.SetOrigin(2)
+	eom.SetNetworkOrigin("peer")
+
+	s.JournalTick(-1)
+	s.JournalArrival(JournalInMsgQueue2, eom)
+
+	lines := s.GetJournalMessages()
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 journal records, got %d", len(lines))
+	}
+
+	tick := new(JournalRecord)
+	if err := json.Unmarshal(lines[0], tick); err != nil {
+		t.Fatal(err)
+	}
+	if tick.Kind != JournalKindTick || tick.Tick != -1 || tick.Time == 0 {
+		t.Errorf("bad tick record %+v", tick)
+	}
+
+	rec := new(JournalRecord)
+	if err := json.Unmarshal(lines[1], rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Kind != JournalKindMsg || rec.Queue != JournalInMsgQueue2 || rec.Seq != tick.Seq+1 {
+		t.Errorf("bad msg record %+v", rec)
+	}
+	msg, err := rec.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !msg.GetHash().IsSameAs(eom.GetHash()) {
+		t.Error("replayed message is not the journaled message")
+	}
+	if msg.GetOrigin() != 2 || msg.GetNetworkOrigin() != "peer" {
+		t.Errorf("origin not restored, got %d %q", msg.GetOrigin(), msg.GetNetworkOrigin())
+	}
+}
@@ -120,11 +120,22 @@ func LoadDatabase(s *State) {
 				panic(fmt.Sprintf("Could not decode Custom Bootstrap Identity (likely in config file) found: %s\n", s.CustomBootstrapIdentity))
 			}
 		}
-		dblk, ablk, fblk, ecblk := GenerateGenesisBlocks(s.GetNetworkID(), customIdentity)
+		var dblk interfaces.IDirectoryBlock
+		var ablk interfaces.IAdminBlock
+		var fblk interfaces.IFBlock
+		var ecblk interfaces.IEntryCreditBlock
+		var eblocks []interfaces.IEntryBlock
+		var entries []interfaces.IEBEntry
+		if s.CustomGenesis != nil {
+			dblk, ablk, fblk, ecblk = GenerateCustomGenesisBlocks(s.GetNetworkID(), s.CustomGenesis)
+			eblocks, entries, _ = s.CustomGenesis.identityBlocks() // validated when the genesis was read
+		} else {
+			dblk, ablk, fblk, ecblk = GenerateGenesisBlocks(s.GetNetworkID(), customIdentity)
+		}
 
 		messages.LogPrintf("marshalsizes.txt", "FBlock unmarshaled transaction count: %d", len(fblk.GetTransactions()))
 
-		msg := messages.NewDBStateMsg(s.GetTimestamp(), dblk, ablk, fblk, ecblk, nil, nil, nil)
+		msg := messages.NewDBStateMsg(s.GetTimestamp(), dblk, ablk, fblk, ecblk, eblocks, entries, nil)
 		// last block, flag it.
 		dbstate, _ := msg.(*messages.DBStateMsg)
 		dbstate.IsLast = true // this is the last DBState in this load
@@ -0,0 +1,226 @@
+package state
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/log"
+)
+
+// validityHistory remembers the last validation results of the most recently validated messages
+// so the message-status api can report them without validating outside the state's scope.  It is
+// a lock free ring, as every message the state validates is recorded once the api was asked for a
+// status, and nothing before, so a node nobody asks does no work for it.
+type validityHistory struct {
+	on      int32 // 1 once recording started, read atomically
+	next    uint64
+	results [8192]atomic.Value // *validityResult
+}
+
+// validityResult is the result of validating a message
+type validityResult struct {
+	hash        [32]byte
+	validToSend int
+	validToExec int
+}
+
+// start starts recording the results
+func (v *validityHistory) start() {
+	atomic.StoreInt32(&v.on, 1)
+}
+
+// recording returns true once recording started
+func (v *validityHistory) recording() bool {
+	return atomic.LoadInt32(&v.on) == 1
+}
+
+func (v *validityHistory) record(hash [32]byte, validToSend int, validToExec int) {
+	if !v.recording() {
+		return
+	}
+	i := atomic.AddUint64(&v.next, 1) - 1
+	v.results[i%uint64(len(v.results))].Store(&validityResult{hash, validToSend, validToExec})
+}
+
+// get returns the last result of validating a message, if it is still in the ring
+func (v *validityHistory) get(hash [32]byte) (result [2]int, ok bool) {
+	next := atomic.LoadUint64(&v.next)
+	for n := uint64(0); n < next && n < uint64(len(v.results)); n++ {
+		r, _ := v.results[(next-1-n)%uint64(len(v.results))].Load().(*validityResult)
+		if r != nil && r.hash == hash {
+			return [2]int{r.validToSend, r.validToExec}, true
+		}
+	}
+	return result, false
+}
+
+// dependentMsg is a message in dependent holding and the hash it is waiting on
+type dependentMsg struct {
+	key [32]byte
+	msg interfaces.IMsg
+}
+
+// processListEntry is where a message is in a process list
+type processListEntry struct {
+	dbheight uint32
+	vm       int
+	vmheight int
+	vmHeight int // the height the VM has processed to
+	ack      *messages.Ack
+	msg      interfaces.IMsg
+}
+
+// messageStatusSnapshot is the part of the state the message-status api reports on, copied in
+// the state's scope when the api asks for it
+type messageStatusSnapshot struct {
+	dependents  map[[32]byte]dependentMsg
+	processList map[[32]byte]processListEntry
+	stale       map[[32]byte]string // why a held message is stale
+}
+
+// snapshotMessageStatus copies dependent holding, the process lists and the stale reasons of the
+// held messages.  It must run in the state's scope.
+func (s *State) snapshotMessageStatus() *messageStatusSnapshot {
+	snapshot := &messageStatusSnapshot{
+		dependents:  s.Hold.Snapshot(),
+		processList: make(map[[32]byte]processListEntry),
+		stale:       make(map[[32]byte]string),
+	}
+	for _, pl := range s.ProcessLists.Lists {
+		if pl == nil {
+			continue
+		}
+		for i := 0; i < len(pl.FedServers) && i < len(pl.VMs); i++ {
+			vm := pl.VMs[i]
+			for j, m := range vm.List {
+				if m == nil {
+					continue
+				}
+				entry := processListEntry{dbheight: pl.DBHeight, vm: i, vmheight: j, vmHeight: vm.Height, msg: m}
+				if j < len(vm.ListAck) && vm.ListAck[j] != nil {
+					entry.ack = vm.ListAck[j]
+				}
+				snapshot.processList[m.GetMsgHash().Fixed()] = entry
+			}
+		}
+	}
+	for h, msg := range s.Holding {
+		if reason := s.Hold.staleReason(msg); reason != "" {
+			snapshot.stale[h] = reason
+		}
+	}
+	for h, d := range snapshot.dependents {
+		if reason := s.Hold.staleReason(d.msg); reason != "" {
+			snapshot.stale[h] = reason
+		}
+	}
+	return snapshot
+}
+
+// LoadDependentHoldingMap returns a snapshot of dependent holding
+func (s *State) LoadDependentHoldingMap() map[[32]byte]dependentMsg {
+	return s.loadMessageStatusSnapshot().dependents
+}
+
+// loadMessageStatusSnapshot makes a snapshot in the state's scope.  It is empty if the state did
+// not take it.
+func (s *State) loadMessageStatusSnapshot() *messageStatusSnapshot {
+	snapshot := new(messageStatusSnapshot)
+	s.inStateScope(func() {
+		snapshot = s.snapshotMessageStatus()
+	})
+	return snapshot
+}
+
+// hashToHeight returns the height and minute of a dependent holding key made by HeightToHash
+func hashToHeight(key [32]byte) (height uint32, minute int, ok bool) {
+	var zero [27]byte
+	if string(key[5:]) != string(zero[:]) {
+		return 0, 0, false
+	}
+	height = uint32(key[0])<<24 | uint32(key[1])<<16 | uint32(key[2])<<8 | uint32(key[3])
+	return height, int(key[4]), true
+}
+
+// dependentOn describes the hash a message waits on in dependent holding
+func dependentOn(key [32]byte) string {
+	if height, minute, ok := hashToHeight(key); ok {
+		return fmt.Sprintf("height %d minute %d", height, minute)
+	}
+	return "chain, entry or address " + hex.EncodeToString(key[:])
+}
+
+// queueFromHistory finds the queue a message was last put in from the debug logs, if it has not
+// been taken out of it since
+func queueFromHistory(history []string) string {
+	if len(history) == 0 {
+		return ""
+	}
+	last := strings.Fields(history[len(history)-1])
+	if len(last) < 5 || !strings.Contains(history[len(history)-1], "enqueue") {
+		return ""
+	}
+	switch logName := last[0]; logName {
+	case "inmsgqueue", "inmsgqueue2", "msgqueue", "ackqueue":
+		return logName
+	}
+	return ""
+}
+
+// GetMessageStatus explains where this node has a message and why it is still held
+func (s *State) GetMessageStatus(hash interfaces.IHash) *interfaces.MessageStatus {
+	status := new(interfaces.MessageStatus)
+	status.MsgHash = hash.String()
+	status.Locations = []string{}
+	s.validity.start() // so the next status has the validity of the message
+	status.InMsgQueueDepth = s.inMsgQueue.Length()
+	fixed := hash.Fixed()
+
+	snapshot := s.loadMessageStatusSnapshot()
+	var msg interfaces.IMsg
+	if m, ok := s.LoadHoldingMap()[fixed]; ok {
+		msg = m
+		status.Locations = append(status.Locations, "holding")
+	}
+	if d, ok := snapshot.dependents[fixed]; ok {
+		msg = d.msg
+		status.Locations = append(status.Locations, "dependentholding")
+		status.DependentKey = hex.EncodeToString(d.key[:])
+		status.DependentOn = dependentOn(d.key)
+	}
+	if ack, ok := s.LoadAcksMap()[fixed]; ok {
+		status.Locations = append(status.Locations, "acks")
+		status.AckHash = ack.GetMsgHash().String()
+	}
+	if entry, ok := snapshot.processList[fixed]; ok {
+		msg = entry.msg
+		status.Locations = append(status.Locations, "processlist")
+		status.DBHeight, status.VMIndex, status.VMHeight = entry.dbheight, entry.vm, entry.vmheight
+		if entry.ack != nil {
+			status.AckHash = entry.ack.GetMsgHash().String()
+		}
+	}
+
+	status.History = log.MessageHistory(s.FactomNodeName, status.MsgHash, 100)
+	if queue := queueFromHistory(status.History); queue != "" {
+		status.Locations = append(status.Locations, queue)
+	}
+
+	if result, ok := s.validity.get(fixed); ok {
+		status.Validated = true
+		status.ValidToSend, status.ValidToExecute = result[0], result[1]
+	}
+
+	if msg != nil {
+		status.Type = constants.MessageName(msg.Type())
+		status.Age = s.GetTimestamp().GetTimeSeconds() - msg.GetTimestamp().GetTimeSeconds()
+		status.StaleReason = snapshot.stale[fixed]
+		status.Stale = status.StaleReason != ""
+	}
+	return status
+}
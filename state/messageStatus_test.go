@@ -0,0 +1,105 @@
+package state
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+func TestGetMessageStatus(t *testing.T) {
+	s := new(State)
+	s.Hold.Init(s)
+	s.Holding = make(map[[32]byte]interfaces.IMsg)
+	s.inMsgQueue = NewInMsgQueue(10)
+	s.ProcessLists = NewProcessLists(s)
+	s.LLeaderHeight = 5
+	s.messageFilterTimestamp = primitives.NewTimestampFromSeconds(0)
+
+	held := new(messages.EOM)
+	held.Timestamp = primitives.NewTimestampNow()
+	held.ChainID = primitives.RandomHash()
+	held.DBHeight, held.Minute = 7, 2
+	s.HoldForHeight(7, 2, held)
+
+	stale := new(messages.EOM)
+	stale.Timestamp = primitives.NewTimestampNow()
+	stale.ChainID = primitives.RandomHash()
+	stale.DBHeight, stale.Minute = 4, 9
+	s.Holding[stale.GetMsgHash().Fixed()] = stale
+	s.validity.start()
+	s.validity.record(stale.GetMsgHash().Fixed(), 0, 0)
+
+	pl := &ProcessList{DBHeight: 5, FedServers: []interfaces.IServer{nil}, VMs: []*VM{new(VM)}}
+	s.ProcessLists.Lists = append(s.ProcessLists.Lists, pl)
+	processed := new(messages.EOM)
+	processed.Timestamp = primitives.NewTimestampNow()
+	processed.ChainID = primitives.RandomHash()
+	processed.DBHeight, processed.Minute = 5, 0
+	ack := new(messages.Ack)
+	ack.Timestamp = primitives.NewTimestampNow()
+	ack.MessageHash = processed.GetMsgHash()
+	ack.FullMsgHash = processed.GetFullMsgHash()
+	ack.LeaderChainID = primitives.RandomHash()
+	ack.SerialHash = primitives.RandomHash()
+	pl.VMs[0].List = append(pl.VMs[0].List, nil, processed)
+	pl.VMs[0].ListAck = append(pl.VMs[0].ListAck, nil, ack)
+
+	s.fillHoldingMap()
+
+	status := s.GetMessageStatus(processed.GetMsgHash())
+	if len(status.Locations) != 1 || status.Locations[0] != "processlist" || status.DBHeight != 5 || status.VMHeight != 1 {
+		t.Errorf("wrong status for a message in the process list %+v", status)
+	}
+	if status.AckHash != ack.GetMsgHash().String() || status.Stale {
+		t.Errorf("wrong ack for a message in the process list %+v", status)
+	}
+
+	status = s.GetMessageStatus(held.GetMsgHash())
+	if len(status.Locations) != 1 || status.Locations[0] != "dependentholding" {
+		t.Errorf("held message is in %v", status.Locations)
+	}
+	if status.DependentOn != "height 7 minute 2" || status.Stale || status.Validated {
+		t.Errorf("wrong status for a message held for a height %+v", status)
+	}
+
+	status = s.GetMessageStatus(stale.GetMsgHash())
+	if len(status.Locations) != 1 || status.Locations[0] != "holding" || status.Type != "EOM" {
+		t.Errorf("stale message is in %v", status.Locations)
+	}
+	if !status.Stale || !strings.Contains(status.StaleReason, "past minute") || !status.Validated {
+		t.Errorf("wrong status for a stale message %+v", status)
+	}
+
+	status = s.GetMessageStatus(primitives.RandomHash())
+	if len(status.Locations) != 0 || status.Type != "" {
+		t.Errorf("found a message that does not exist %+v", status)
+	}
+}
+
+func TestValidityHistory(t *testing.T) {
+	var v validityHistory
+	first := primitives.RandomHash().Fixed()
+	v.record(first, 1, 0)
+	if _, ok := v.get(first); ok {
+		t.Errorf("recorded a result before recording started")
+	}
+	v.start()
+	v.record(first, 1, 0)
+	v.record(first, 1, 1)
+	if r, ok := v.get(first); !ok || r != [2]int{1, 1} {
+		t.Errorf("got %v for the last result", r)
+	}
+	for i := 0; i < len(v.results)-1; i++ {
+		v.record(primitives.RandomHash().Fixed(), 1, 1)
+	}
+	if r, ok := v.get(first); !ok || r != [2]int{1, 1} {
+		t.Errorf("forgot the last result before the ring is full")
+	}
+	v.record(primitives.RandomHash().Fixed(), 1, 1)
+	if _, ok := v.get(first); ok {
+		t.Errorf("history holds more than %d results", len(v.results))
+	}
+}
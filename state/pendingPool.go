@@ -0,0 +1,309 @@
+package state
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The states of a pending pool item
+const (
+	PendingHeld              = "held"                 // in holding, waiting to be acknowledged
+	PendingHeldForFCTBalance = "held-for-fct-balance" // in dependent holding until an input has the factoshis to pay
+	PendingHeldForECBalance  = "held-for-ec-balance"  // in dependent holding until an EC address has the credits to pay
+	PendingHeldForCommit     = "held-for-commit"      // in dependent holding until the commit paying for an entry arrives
+	PendingHeldForChainHead  = "held-for-chain-head"  // in dependent holding until the chain of an entry is made
+	PendingAwaitingMinute    = "awaiting-minute"      // in dependent holding until this node reaches its height and minute
+	PendingAcked             = "acked"                // acknowledged in a VM but not processed yet
+	PendingProcessed         = "processed"            // processed, waiting for the block to be saved
+)
+
+// The reasons a held message is not acknowledged yet
+const (
+	HeldInvalidTransaction  = "invalid-transaction"  // a factoid transaction is malformed
+	HeldInvalidSignature    = "invalid-signature"    // a factoid transaction is not signed by its inputs
+	HeldInvalidCommit       = "invalid-commit"       // a commit has a bad signature or number of credits
+	HeldInsufficientBalance = "insufficient-balance" // an address does not have the balance to pay
+	HeldInvalid             = "invalid"              // the last validation of the message rejected it
+)
+
+// GetPendingPool returns the factoid transactions, entry credit commits and entry reveals this
+// node has not saved in a block yet, with where each one is and why it is waiting.  A non empty FA
+// or EC address limits the pool to the transactions and commits that address is part of.  The pool
+// is made in the state's scope, and is empty if the state did not make it.
+func (s *State) GetPendingPool(address string) []interfaces.IPendingPoolItem {
+	s.validity.start() // so the next pool has the validity of the held messages
+	pool := make([]interfaces.IPendingPoolItem, 0)
+	s.inStateScope(func() {
+		pool = s.pendingPool(address)
+	})
+	return pool
+}
+
+// pendingPool makes the pending pool in the order this node will get to the items, which is their
+// priority.  It must run in the state's scope.
+func (s *State) pendingPool(address string) []interfaces.IPendingPoolItem {
+	pool := make([]interfaces.IPendingPoolItem, 0)
+	seen := make(map[[32]byte]bool)
+	priority := 0
+	add := func(item interfaces.IPendingPoolItem) {
+		if seen[item.MsgHash.Fixed()] {
+			return
+		}
+		seen[item.MsgHash.Fixed()] = true
+		item.Priority = priority
+		priority++
+		if address == "" || hasAddress(item.Addresses, address) {
+			pool = append(pool, item)
+		}
+	}
+
+	// The process lists, in the order they were acked
+	saved := s.GetHighestSavedBlk()
+	var entries []processListEntry
+	for _, entry := range s.snapshotMessageStatus().processList {
+		if entry.dbheight > saved {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.dbheight != b.dbheight {
+			return a.dbheight < b.dbheight
+		}
+		if a.vm != b.vm {
+			return a.vm < b.vm
+		}
+		return a.vmheight < b.vmheight
+	})
+	for _, entry := range entries {
+		item, ok := newPendingPoolItem(entry.msg)
+		if !ok {
+			continue
+		}
+		item.DBHeight, item.VMIndex = entry.dbheight, entry.vm
+		if entry.ack != nil {
+			item.Minute = int(entry.ack.Minute)
+		}
+		if entry.vmheight < entry.vmHeight {
+			item.State = PendingProcessed
+		} else {
+			item.State = PendingAcked
+			item.Position = entry.vmheight - entry.vmHeight
+		}
+		add(item)
+	}
+
+	// Holding is reviewed oldest first, so that is the order of the queue
+	var held []interfaces.IMsg
+	for _, msg := range s.Holding {
+		held = append(held, msg)
+	}
+	sortByTimestamp(held)
+	position := make(map[int]int)
+	for _, msg := range held {
+		vm := msg.GetVMIndex()
+		position[vm]++
+		item, ok := newPendingPoolItem(msg)
+		if !ok {
+			continue
+		}
+		item.State = PendingHeld
+		item.VMIndex = vm
+		item.Position = position[vm] - 1
+		s.explainHeld(msg, &item)
+		add(item)
+	}
+
+	// Dependent holding releases the messages waiting on a key in the order they were added, once
+	// what they wait on happens.  Which key goes first is not known, so the oldest are put first.
+	type dependent struct {
+		key      [32]byte
+		msg      interfaces.IMsg
+		position int
+	}
+	var dependents []dependent
+	for key, msgs := range s.Hold.Messages() {
+		position := 0
+		for _, msg := range msgs {
+			if msg != nil {
+				dependents = append(dependents, dependent{key, msg, position})
+				position++
+			}
+		}
+	}
+	sort.SliceStable(dependents, func(i, j int) bool {
+		return dependents[i].msg.GetTimestamp().GetTimeMilli() < dependents[j].msg.GetTimestamp().GetTimeMilli()
+	})
+	for _, d := range dependents {
+		item, ok := newPendingPoolItem(d.msg)
+		if !ok {
+			continue
+		}
+		item.State = dependency(d.key, d.msg)
+		item.VMIndex = d.msg.GetVMIndex()
+		item.Position = d.position
+		s.explainHeld(d.msg, &item)
+		if height, minute, ok := hashToHeight(d.key); ok {
+			item.DBHeight, item.Minute = height, minute
+		}
+		add(item)
+	}
+	return pool
+}
+
+// dependency returns the pending state of a message in dependent holding, from the key it waits on
+// and what holds it for that key
+func dependency(key [32]byte, msg interfaces.IMsg) string {
+	if _, _, ok := hashToHeight(key); ok {
+		return PendingAwaitingMinute
+	}
+	switch m := msg.(type) {
+	case *messages.FactoidTransaction:
+		return PendingHeldForFCTBalance
+	case *messages.CommitChainMsg, *messages.CommitEntryMsg:
+		return PendingHeldForECBalance
+	case *messages.RevealEntryMsg:
+		if key == m.Entry.GetChainID().Fixed() {
+			return PendingHeldForChainHead
+		}
+		return PendingHeldForCommit
+	}
+	return PendingHeld
+}
+
+// newPendingPoolItem describes a factoid transaction or commit, and returns false for any other message
+func newPendingPoolItem(msg interfaces.IMsg) (item interfaces.IPendingPoolItem, ok bool) {
+	if msg == nil {
+		return item, false
+	}
+	item.MsgHash = msg.GetMsgHash()
+	switch m := msg.(type) {
+	case *messages.FactoidTransaction:
+		tx := m.GetTransaction()
+		item.Type = "factoid-transaction"
+		item.TransactionID = tx.GetSigHash()
+		for _, in := range tx.GetInputs() {
+			item.Addresses = append(item.Addresses, primitives.ConvertFctAddressToUserStr(in.GetAddress()))
+		}
+		for _, out := range tx.GetOutputs() {
+			item.Addresses = append(item.Addresses, primitives.ConvertFctAddressToUserStr(out.GetAddress()))
+		}
+		for _, out := range tx.GetECOutputs() {
+			item.Addresses = append(item.Addresses, primitives.ConvertECAddressToUserStr(out.GetAddress()))
+		}
+		item.Fee = factoidFee(tx)
+	case *messages.CommitChainMsg:
+		item.Type = "commit-chain"
+		item.TransactionID = m.CommitChain.GetSigHash()
+		item.Addresses = []string{ecUserAddress(m.CommitChain.ECPubKey)}
+		item.Fee = uint64(m.CommitChain.Credits)
+	case *messages.CommitEntryMsg:
+		item.Type = "commit-entry"
+		item.TransactionID = m.CommitEntry.GetSigHash()
+		item.Addresses = []string{ecUserAddress(m.CommitEntry.ECPubKey)}
+		item.Fee = uint64(m.CommitEntry.Credits)
+	case *messages.RevealEntryMsg:
+		item.Type = "reveal-entry"
+		item.TransactionID = m.Entry.GetHash()
+		item.Addresses = []string{}
+	default:
+		return item, false
+	}
+	return item, true
+}
+
+// factoidFee returns the factoshis a transaction pays above its outputs, or 0 if it pays out more
+// than it takes in
+func factoidFee(tx interfaces.ITransaction) uint64 {
+	in, err := tx.TotalInputs()
+	if err != nil {
+		return 0
+	}
+	out, err := tx.TotalOutputs()
+	if err != nil {
+		return 0
+	}
+	ec, err := tx.TotalECs()
+	if err != nil || in < out+ec {
+		return 0
+	}
+	return in - out - ec
+}
+
+// explainHeld fills in why a held message is not acknowledged yet and when it will expire.  It must
+// run in the state's scope.
+func (s *State) explainHeld(msg interfaces.IMsg, item *interfaces.IPendingPoolItem) {
+	filter := s.GetMessageFilterTimestamp().GetTimeSeconds()
+	item.ExpiresIn = msg.GetTimestamp().GetTimeSeconds() - filter
+	if item.ExpiresIn < 0 {
+		item.ExpiresIn = 0
+	}
+
+	switch m := msg.(type) {
+	case *messages.FactoidTransaction:
+		tx := m.GetTransaction()
+		if err := tx.Validate(1); err != nil {
+			item.Reason, item.ValidationError = HeldInvalidTransaction, err.Error()
+			return
+		}
+		if err := tx.ValidateSignatures(); err != nil {
+			item.Reason, item.ValidationError = HeldInvalidSignature, err.Error()
+			return
+		}
+		for _, in := range tx.GetInputs() {
+			balance := s.FactoidState.GetFactoidBalance(in.GetAddress().Fixed())
+			if balance < int64(in.GetAmount()) {
+				item.Reason = HeldInsufficientBalance
+				item.ValidationError = fmt.Sprintf("%s has %d factoshis and needs %d",
+					primitives.ConvertFctAddressToUserStr(in.GetAddress()), balance, in.GetAmount())
+				return
+			}
+		}
+	case *messages.CommitChainMsg:
+		item.Reason, item.ValidationError = s.explainCommit(m.CommitChain.ECPubKey, m.CommitChain.Credits, m.CommitChain.IsValid())
+	case *messages.CommitEntryMsg:
+		item.Reason, item.ValidationError = s.explainCommit(m.CommitEntry.ECPubKey, m.CommitEntry.Credits, m.CommitEntry.IsValid())
+	}
+
+	if item.Reason == "" {
+		if result, ok := s.validity.get(msg.GetMsgHash().Fixed()); ok && result[1] < 0 {
+			item.Reason, item.ValidationError = HeldInvalid, "rejected by the last validation"
+		}
+	}
+}
+
+// explainCommit returns the reason a commit is held, if it is invalid or its address cannot pay
+func (s *State) explainCommit(key *primitives.ByteSlice32, credits uint8, valid bool) (reason string, explanation string) {
+	if !valid {
+		return HeldInvalidCommit, "invalid signature or credits"
+	}
+	balance := s.FactoidState.GetECBalance(key.Fixed())
+	if balance < int64(credits) {
+		return HeldInsufficientBalance, fmt.Sprintf("%s has %d entry credits and needs %d", ecUserAddress(key), balance, credits)
+	}
+	return "", ""
+}
+
+func ecUserAddress(key *primitives.ByteSlice32) string {
+	return primitives.ConvertECAddressToUserStr(factoid.NewAddress(key[:]))
+}
+
+func hasAddress(addresses []string, address string) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+func sortByTimestamp(msgs []interfaces.IMsg) {
+	sort.SliceStable(msgs, func(i, j int) bool {
+		return msgs[i].GetTimestamp().GetTimeMilli() < msgs[j].GetTimestamp().GetTimeMilli()
+	})
+}
@@ -0,0 +1,102 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+func TestGetPendingPool(t *testing.T) {
+	s := new(State)
+	s.Hold.Init(s)
+	s.Holding = make(map[[32]byte]interfaces.IMsg)
+	s.ProcessLists = NewProcessLists(s)
+	s.DBStates = new(DBStateList)
+
+	var commits []*messages.CommitEntryMsg
+	for i := 0; i < 2; i++ {
+		m := messages.NewCommitEntryMsg()
+		m.CommitEntry = entryCreditBlock.NewCommitEntry()
+		m.CommitEntry.EntryHash = primitives.RandomHash()
+		m.CommitEntry.MilliTime[5] = byte(i) // the first commit is the oldest
+		copy(m.CommitEntry.ECPubKey[:], primitives.RandomHash().Bytes())
+		s.Holding[m.GetMsgHash().Fixed()] = m
+		commits = append(commits, m)
+	}
+	eom := new(messages.EOM)
+	eom.Timestamp = primitives.NewTimestampNow()
+	eom.ChainID = primitives.RandomHash()
+	s.Holding[eom.GetMsgHash().Fixed()] = eom
+
+	// dependent holding, oldest first
+	future := messages.NewCommitEntryMsg()
+	future.CommitEntry = entryCreditBlock.NewCommitEntry()
+	future.CommitEntry.EntryHash = primitives.RandomHash()
+	future.CommitEntry.MilliTime[5] = 2
+	s.HoldForHeight(7, 3, future)
+
+	unpaid := messages.NewCommitEntryMsg()
+	unpaid.CommitEntry = entryCreditBlock.NewCommitEntry()
+	unpaid.CommitEntry.EntryHash = primitives.RandomHash()
+	unpaid.CommitEntry.MilliTime[5] = 3
+	unpaid.CommitEntry.Credits = 2
+	copy(unpaid.CommitEntry.ECPubKey[:], primitives.RandomHash().Bytes())
+	s.Add(unpaid.CommitEntry.ECPubKey.Fixed(), unpaid)
+
+	reveals := make([]*messages.RevealEntryMsg, 2)
+	for i := range reveals {
+		reveals[i] = messages.NewRevealEntryMsg()
+		entry := entryBlock.NewEntry()
+		entry.ChainID = primitives.RandomHash()
+		reveals[i].Entry = entry
+		reveals[i].Timestamp = primitives.NewTimestampFromMilliseconds(uint64(1000 + i))
+	}
+	s.Add(reveals[0].Entry.GetHash().Fixed(), reveals[0])
+	s.Add(reveals[1].Entry.GetChainID().Fixed(), reveals[1])
+
+	pool := s.GetPendingPool("")
+	if len(pool) != 6 {
+		t.Fatalf("pool has %d items, want the 4 commits and 2 reveals", len(pool))
+	}
+	for i, item := range pool {
+		if item.Priority != i {
+			t.Errorf("item %d has priority %d", i, item.Priority)
+		}
+	}
+	for i, item := range pool[:2] {
+		if !item.MsgHash.IsSameAs(commits[i].GetMsgHash()) || item.Position != i {
+			t.Errorf("commit %d is at position %d", i, item.Position)
+		}
+		if item.State != PendingHeld || item.Type != "commit-entry" || item.Reason != HeldInvalidCommit {
+			t.Errorf("wrong pending pool item %+v", item)
+		}
+	}
+	if item := pool[2]; item.State != PendingAwaitingMinute || item.DBHeight != 7 || item.Minute != 3 {
+		t.Errorf("wrong pending pool item for a commit held for a minute %+v", item)
+	}
+	if item := pool[3]; item.State != PendingHeldForECBalance || !item.MsgHash.IsSameAs(unpaid.GetMsgHash()) || item.Fee != 2 {
+		t.Errorf("wrong pending pool item for a commit held for its balance %+v", item)
+	}
+	if item := pool[4]; item.State != PendingHeldForCommit || item.Type != "reveal-entry" || !item.TransactionID.IsSameAs(reveals[0].Entry.GetHash()) {
+		t.Errorf("wrong pending pool item for a reveal held for its commit %+v", item)
+	}
+	if item := pool[5]; item.State != PendingHeldForChainHead || !item.MsgHash.IsSameAs(reveals[1].GetMsgHash()) {
+		t.Errorf("wrong pending pool item for a reveal held for its chain %+v", item)
+	}
+
+	address := ecUserAddress(commits[1].CommitEntry.ECPubKey)
+	pool = s.GetPendingPool(address)
+	if len(pool) != 1 || !pool[0].MsgHash.IsSameAs(commits[1].GetMsgHash()) || pool[0].Addresses[0] != address {
+		t.Errorf("found %d items for %s", len(pool), address)
+	}
+	if len(pool) == 1 && pool[0].Priority != 1 {
+		t.Errorf("the priority of %s is %d in the whole pool, not 1", address, pool[0].Priority)
+	}
+	if pool = s.GetPendingPool("EC2DKSYyRcNWf7RS963VFYgMExoHRYLHVeCfQ9PGPmNzwrcmgm2r"); len(pool) != 0 {
+		t.Errorf("found %d items for an address with no commits", len(pool))
+	}
+}
@@ -15,6 +15,11 @@ var cntsMutex sync.Mutex
 var cnts map[string]int
 
 func PrintState(state *State) {
+	fmt.Println(DumpState(state))
+}
+
+// DumpState returns the fields of the state as text, as PrintState prints them
+func DumpState(state *State) string {
 	if cnts == nil {
 		cnts = make(map[string]int, 0)
 	}
@@ -202,5 +207,5 @@ func PrintState(state *State) {
 
 	str = fmt.Sprintf("%s%s", str, "===PrintState End===")
 
-	fmt.Println(str)
+	return str
 }
@@ -1277,6 +1277,70 @@ func (p *ProcessList) String() string {
 	return buf.String()
 }
 
+// Status returns a snapshot of the process list and each of its VMs.  It must run in the state's
+// scope, see State.GetProcessListStatus.
+func (p *ProcessList) Status() *interfaces.ProcessListStatus {
+	status := new(interfaces.ProcessListStatus)
+	status.DBHeight = p.DBHeight
+	status.Complete = p.Complete()
+	status.FedServers = make([]string, 0, len(p.FedServers))
+	for _, fed := range p.FedServers {
+		status.FedServers = append(status.FedServers, fed.GetChainID().String())
+	}
+	status.AuditServers = make([]string, 0, len(p.AuditServers))
+	for _, aud := range p.AuditServers {
+		status.AuditServers = append(status.AuditServers, aud.GetChainID().String())
+	}
+
+	status.VMs = make([]interfaces.VMStatus, 0, len(p.FedServers))
+	for i := 0; i < len(p.FedServers); i++ {
+		vm := p.VMs[i]
+		v := interfaces.VMStatus{
+			VMIndex:      i,
+			LeaderMinute: vm.LeaderMinute,
+			Height:       vm.Height,
+			Length:       len(vm.List),
+			Missing:      []int{},
+			Synced:       vm.Synced,
+			Faulted:      vm.WhenFaulted != 0,
+			WhenFaulted:  vm.WhenFaulted,
+			FaultFlag:    vm.FaultFlag,
+			Messages:     []interfaces.VMSlot{},
+		}
+		minute := vm.LeaderMinute
+		if minute > 9 {
+			minute = 9
+		}
+		if index := p.ServerMap[minute][i]; index < len(p.FedServers) {
+			v.Leader = p.FedServers[index].GetChainID().String()
+		}
+		if vm.ProcessTime != nil {
+			v.ProcessTime = vm.ProcessTime.GetTimeMilli()
+		}
+
+		for j, msg := range vm.List {
+			if msg == nil {
+				v.Missing = append(v.Missing, j)
+				continue
+			}
+			processed := j < vm.Height
+			if processed && j == 0 && msg.Type() == constants.DIRECTORY_BLOCK_SIGNATURE_MSG {
+				v.DBSig = true
+			}
+			if processed && msg.Type() == constants.EOM_MSG {
+				v.EOMs++
+			}
+			slot := interfaces.VMSlot{Height: j, Processed: processed, Type: constants.MessageName(msg.Type()), MsgHash: msg.GetMsgHash().String()}
+			if j < len(vm.ListAck) && vm.ListAck[j] != nil {
+				slot.AckHash = vm.ListAck[j].GetMsgHash().String()
+			}
+			v.Messages = append(v.Messages, slot)
+		}
+		status.VMs = append(status.VMs, v)
+	}
+	return status
+}
+
 // Intended to let a demoted leader come back before the next DB state but interfered with boot under load so disable for now
 // that means demoted leaders are not sane till the next DBState (up to 10 minutes). Maybe revisit after the missing message storms are fixed.
 func (p *ProcessList) Reset() bool {
@@ -1327,6 +1391,9 @@ func NewProcessList(state interfaces.IState, previous *ProcessList, dbheight uin
 		pl.SortFedServers()
 	} else {
 		pl.AddFedServer(state.GetNetworkBootStrapIdentity()) // Our default fed server, dependent on network type
+		if pl.State.CustomGenesis != nil {
+			pl.State.CustomGenesis.addServers(pl) // A custom network starts with every genesis server
+		}
 		// pl.AddFedServer(primitives.Sha([]byte("FNode0"))) // Our default for now fed server on LOCAL network
 	}
 
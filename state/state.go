@@ -5,11 +5,9 @@
 package state
 
 import (
-	"bufio"
 	"bytes"
 	"crypto/rand"
 	"encoding/binary"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -36,6 +34,7 @@ import (
 	"github.com/FactomProject/factomd/database/databaseOverlay"
 	"github.com/FactomProject/factomd/database/leveldb"
 	"github.com/FactomProject/factomd/database/mapdb"
+	llog "github.com/FactomProject/factomd/log"
 	"github.com/FactomProject/factomd/p2p"
 	"github.com/FactomProject/factomd/util"
 	"github.com/FactomProject/factomd/util/atomic"
@@ -48,7 +47,7 @@ import (
 
 // packageLogger is the general logger for all package related logs. You can add additional fields,
 // or create more context loggers off of this
-var packageLogger = log.WithFields(log.Fields{"package": "state"})
+var packageLogger = llog.SubsystemLogger(llog.SubsystemState).WithFields(log.Fields{"package": "state"})
 
 var _ = fmt.Print
 
@@ -94,6 +93,7 @@ type State struct {
 
 	ControlPanelPort    int
 	ControlPanelSetting int
+	ControlPanelUsers   []string // name:password:role of each control panel user
 	// Keeping the last display state lets us know when to send over the new blocks
 	LastDisplayState        *DisplayState
 	ControlPanelChannel     chan DisplayState
@@ -117,6 +117,7 @@ type State struct {
 	CustomNetworkID         []byte
 	CustomBootstrapIdentity string
 	CustomBootstrapKey      string
+	CustomGenesis           *CustomGenesis // genesis definition of a custom network, nil to use the built in genesis
 
 	IdentityChainID interfaces.IHash // If this node has an identity, this is it
 	//Identities      []*Identity      // Identities of all servers in management chain
@@ -142,6 +143,7 @@ type State struct {
 	HoldingMutex sync.RWMutex
 	HoldingLast  int64
 	HoldingMap   map[[32]byte]interfaces.IMsg
+	validity     validityHistory // last validation results of recent messages, for the message-status api
 
 	// Elections are managed through the Elections Structure
 	EFactory  interfaces.IElectionsFactory
@@ -150,6 +152,8 @@ type State struct {
 	Election1 string // Election state for display
 	Election2 string // Election state for display
 	Election3 string // Election leader list
+	// Record of the recent elections, for the election-history api
+	electionHistory electionHistory
 
 	//  pending entry/transaction api calls for the ack queue do not have proper scope
 	//  This is used to create a temporary, correctly scoped ackqueue snapshot for the calls on demand
@@ -171,6 +175,8 @@ type State struct {
 	ExpireCnt int
 
 	tickerQueue            chan int
+	replayIdle             chan struct{} // signalled when a node replaying a journal runs out of work
+	apiRequests            chan func()   // run in the state's scope for the APIs, see inStateScope
 	timerMsgQueue          chan interfaces.IMsg
 	TimeOffset             interfaces.Timestamp
 	MaxTimeOffset          interfaces.Timestamp
@@ -189,8 +195,16 @@ type State struct {
 	prioritizedMsgQueue chan interfaces.IMsg
 
 	ShutdownChan chan int // For gracefully halting Factom
+
+	// called by shutdown once the database is closed
+	shutdownHandlers      []func()
+	shutdownHandlersMutex sync.Mutex
+
 	JournalFile  string
 	Journaling   bool
+	JournalSeed  int64 // seed of the random number generators, the first record of the journal
+	journalMutex sync.Mutex
+	journalSeq   uint64
 
 	ServerPrivKey         *primitives.PrivateKey
 	ServerPubKey          *primitives.PublicKey
@@ -506,6 +520,7 @@ func (s *State) Clone(cloneNumber int) interfaces.IState {
 	newState.LdbPath = s.LdbPath + "/Sim" + number
 	newState.JournalFile = s.LogPath + "/journal" + number + ".log"
 	newState.Journaling = s.Journaling
+	newState.JournalSeed = s.JournalSeed
 	newState.BoltDBPath = s.BoltDBPath + "/Sim" + number
 	newState.LogLevel = s.LogLevel
 	newState.ConsoleLogLevel = s.ConsoleLogLevel
@@ -533,12 +548,14 @@ func (s *State) Clone(cloneNumber int) interfaces.IState {
 	newState.CustomNetworkID = s.CustomNetworkID
 	newState.CustomBootstrapIdentity = s.CustomBootstrapIdentity
 	newState.CustomBootstrapKey = s.CustomBootstrapKey
+	newState.CustomGenesis = s.CustomGenesis
 
 	newState.DirectoryBlockInSeconds = s.DirectoryBlockInSeconds
 	newState.PortNumber = s.PortNumber
 
 	newState.ControlPanelPort = s.ControlPanelPort
 	newState.ControlPanelSetting = s.ControlPanelSetting
+	newState.ControlPanelUsers = s.ControlPanelUsers
 	newState.EventService = s.EventService
 
 	//newState.Identities = s.Identities
@@ -856,6 +873,9 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 		s.TestSpecialPeers = cfg.App.TestSpecialPeers
 		s.CustomBootstrapIdentity = cfg.App.CustomBootstrapIdentity
 		s.CustomBootstrapKey = cfg.App.CustomBootstrapKey
+		if cfg.App.CustomGenesisFile != "" && strings.ToUpper(s.Network) == "CUSTOM" {
+			s.loadCustomGenesis(filename, cfg.App.CustomGenesisFile)
+		}
 		s.LocalNetworkPort = cfg.App.LocalNetworkPort
 		s.LocalSeedURL = cfg.App.LocalSeedURL
 		s.LocalSpecialPeers = cfg.App.LocalSpecialPeers
@@ -867,6 +887,7 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 		s.DirectoryBlockInSeconds = cfg.App.DirectoryBlockInSeconds
 		s.PortNumber = cfg.App.PortNumber
 		s.ControlPanelPort = cfg.App.ControlPanelPort
+		s.ControlPanelUsers = cfg.App.ControlPanelUsers
 		s.RpcUser = cfg.App.FactomdRpcUser
 		s.RpcPass = cfg.App.FactomdRpcPass
 		s.RequestTimeout = cfg.App.RequestTimeout
@@ -1055,6 +1076,8 @@ func (s *State) Init() {
 
 	s.ShutdownChan = make(chan int, 1)                //Channel to gracefully shut down.
 	s.tickerQueue = make(chan int, 100)               //ticks from a clock
+	s.replayIdle = make(chan struct{})                //a replay waits on it for the node to run out of work
+	s.apiRequests = make(chan func())                 //the APIs ask for what they read from the state
 	s.timerMsgQueue = make(chan interfaces.IMsg, 100) //incoming eom notifications, used by leaders
 	s.ControlPanelChannel = make(chan DisplayState, 20)
 	s.networkInvalidMsgQueue = make(chan interfaces.IMsg, 100)              //incoming message queue from the network messages
@@ -1079,6 +1102,7 @@ func (s *State) Init() {
 			s.JournalFile = ""
 		}
 		f.Close()
+		s.journal(&JournalRecord{Kind: JournalKindSeed, Seed: s.JournalSeed})
 	}
 	// Set up struct to stop replay attacks
 	s.Replay = new(Replay)
@@ -1264,7 +1288,7 @@ func (s *State) Init() {
 		}
 	}
 
-	s.Logger = log.WithFields(log.Fields{"node-name": s.GetFactomNodeName(), "identity": s.GetIdentityChainID().String()})
+	s.Logger = packageLogger.WithFields(log.Fields{"node-name": s.GetFactomNodeName(), "identity": s.GetIdentityChainID().String()})
 
 	// Set up Logstash Hook for Logrus (if enabled)
 	if s.UseLogstash {
@@ -1290,7 +1314,7 @@ func (s *State) HookLogstash() error {
 	hook.ReconnectDelayMultiplier = 2
 	hook.MaxReconnectRetries = 10
 
-	s.Logger.Logger.Hooks.Add(hook)
+	log.StandardLogger().Hooks.Add(hook) // the subsystem loggers fire the hooks of the standard logger
 	return nil
 }
 
@@ -1974,59 +1998,6 @@ func (s *State) DatabaseContains(hash interfaces.IHash) bool {
 	return false
 }
 
-// JournalMessage writes the message to the message journal for debugging
-func (s *State) JournalMessage(msg interfaces.IMsg) {
-	type journalentry struct {
-		Type    byte
-		Message interfaces.IMsg
-	}
-
-	if s.Journaling && len(s.JournalFile) != 0 {
-		f, err := os.OpenFile(s.JournalFile, os.O_APPEND+os.O_WRONLY, 0666)
-		if err != nil {
-			s.JournalFile = ""
-			return
-		}
-		defer f.Close()
-
-		e := new(journalentry)
-		e.Type = msg.Type()
-		e.Message = msg
-
-		p, err := json.Marshal(e)
-		if err != nil {
-			return
-		}
-		fmt.Fprintln(f, string(p))
-	}
-}
-
-// GetJournalMessages gets all messages from the message journal
-func (s *State) GetJournalMessages() [][]byte {
-	ret := make([][]byte, 0)
-	if !s.Journaling || len(s.JournalFile) == 0 {
-		return nil
-	}
-
-	f, err := os.Open(s.JournalFile)
-	if err != nil {
-		s.JournalFile = ""
-		return nil
-	}
-	defer f.Close()
-
-	r := bufio.NewReader(f)
-	for {
-		p, err := r.ReadBytes('\n')
-		if err != nil {
-			break
-		}
-		ret = append(ret, p)
-	}
-
-	return ret
-}
-
 func (s *State) GetLeaderVM() int {
 	return s.LeaderVMIndex
 }
@@ -2080,6 +2051,7 @@ func (s *State) UpdateState() (progress bool) {
 	// check to see if a holding queue list request has been made
 	s.fillHoldingMap()
 	s.fillAcksMap()
+	s.runAPIRequests()
 
 entryHashProcessing:
 	for {
@@ -2292,8 +2264,7 @@ func (s *State) SetIsDoneReplaying() {
 
 // Returns a millisecond timestamp
 func (s *State) GetTimestamp() interfaces.Timestamp {
-	if s.IsReplaying == true {
-		fmt.Println("^^^^^^^^ IsReplying is true")
+	if s.IsReplaying == true && s.ReplayTimestamp != nil {
 		return s.ReplayTimestamp
 	}
 	return primitives.NewTimestampNow()
@@ -2492,6 +2463,17 @@ func (s *State) GetLeaderPL() interfaces.IProcessList {
 	return s.LeaderPL
 }
 
+// GetProcessListStatus returns a snapshot of the process list at dbheight, taken in the state's
+// scope, or nil if there is none or the state did not take it
+func (s *State) GetProcessListStatus(dbheight uint32) (status *interfaces.ProcessListStatus) {
+	s.inStateScope(func() {
+		if pl := s.ProcessLists.GetSafe(dbheight); pl != nil {
+			status = pl.Status()
+		}
+	})
+	return status
+}
+
 // Getting the cfg state for Factom doesn't force a read of the config file unless
 // it hasn't been read yet.
 func (s *State) GetCfg() interfaces.IFactomConfig {
@@ -2596,6 +2578,10 @@ func (s *State) GetNetworkSkeletonIdentity() (rval interfaces.IHash) {
 		id, _ := primitives.HexToHash("8888888888888888888888888888888888888888888888888888888888888888")
 		return id
 	case constants.NETWORK_CUSTOM:
+		if s.CustomGenesis != nil && s.CustomGenesis.SkeletonIdentity != "" {
+			id, _ := primitives.HexToHash(s.CustomGenesis.SkeletonIdentity)
+			return id
+		}
 		id, _ := primitives.HexToHash("88888816d408cd0d7b1b28760f3371a40e98dc2e985c28410e781935954afdf3")
 		return id
 	}
@@ -3073,6 +3059,11 @@ func (s *State) GetLastStatus() string {
 	return str
 }
 
+// GetNetworkController returns the p2p controller, or nil if this node is not on the network
+func (s *State) GetNetworkController() *p2p.Controller {
+	return s.NetworkController
+}
+
 func (s *State) updateNetworkControllerConfig() {
 	if s.NetworkController == nil {
 		return
@@ -3145,6 +3136,24 @@ func (s *State) ShutdownNode(exitCode int) {
 	s.ShutdownChan <- exitCode
 }
 
+// AddShutdownHandler adds a handler to call once the node shut down and closed its database
+func (s *State) AddShutdownHandler(handler func()) {
+	s.shutdownHandlersMutex.Lock()
+	defer s.shutdownHandlersMutex.Unlock()
+	s.shutdownHandlers = append(s.shutdownHandlers, handler)
+}
+
+// runShutdownHandlers calls the handlers added by AddShutdownHandler
+func (s *State) runShutdownHandlers() {
+	s.shutdownHandlersMutex.Lock()
+	handlers := s.shutdownHandlers
+	s.shutdownHandlers = nil
+	s.shutdownHandlersMutex.Unlock()
+	for _, handler := range handlers {
+		handler()
+	}
+}
+
 func (s *State) GetDBFinished() bool {
 	return s.DBFinished
 }
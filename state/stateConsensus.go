@@ -25,6 +25,7 @@ import (
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
+	llog "github.com/FactomProject/factomd/log"
 	"github.com/FactomProject/factomd/util"
 	"github.com/FactomProject/factomd/util/atomic"
 
@@ -52,12 +53,17 @@ func (s *State) DebugExec() (ret bool) {
 	return globals.Params.DebugLogRegEx != ""
 }
 
+// logFields are the fields of the records the node logs
+func (s *State) logFields() llog.Fields {
+	return llog.Fields{Node: s.FactomNodeName, DBHeight: int(s.LLeaderHeight), Minute: s.CurrentMinute, VM: s.LeaderVMIndex}
+}
+
 func (s *State) LogMessage(logName string, comment string, msg interfaces.IMsg) {
 	if s.DebugExec() {
 		if s == nil {
 			messages.StateLogMessage("unknown", 0, 0, logName, comment, msg)
 		} else {
-			messages.StateLogMessage(s.FactomNodeName, int(s.LLeaderHeight), int(s.CurrentMinute), logName, comment, msg)
+			messages.NodeLogMessage(s.logFields(), logName, comment, msg)
 		}
 	}
 }
@@ -67,7 +73,7 @@ func (s *State) LogPrintf(logName string, format string, more ...interface{}) {
 		if s == nil {
 			messages.StateLogPrintf("unknown", 0, 0, logName, format, more...)
 		} else {
-			messages.StateLogPrintf(s.FactomNodeName, int(s.LLeaderHeight), int(s.CurrentMinute), logName, format, more...)
+			messages.NodeLogPrintf(s.logFields(), logName, format, more...)
 		}
 	}
 }
@@ -109,6 +115,12 @@ func (s *State) Validate(msg interfaces.IMsg) (validToSend int, validToExec int)
 
 	defer func() {
 		s.LogMessage("msgvalidation", fmt.Sprintf("send=%d execute=%d local=%v %s", *(&validToSend), *(&validToExec), msg.IsLocal(), atomic.WhereAmIString(1)), msg)
+		if !s.validity.recording() {
+			return
+		}
+		if h := msg.GetMsgHash(); h != nil {
+			s.validity.record(h.Fixed(), validToSend, validToExec)
+		}
 	}()
 
 	// During boot ignore messages that are more than 15 minutes old...
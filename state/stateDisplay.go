@@ -53,6 +53,7 @@ type DisplayState struct {
 	ProcessList2        string
 	Election            string
 	SimElection         string
+	ElectionHistory     string
 	SyncingState        [256]string
 	SyncingStateCurrent int
 	IgnoreDone          bool
@@ -256,6 +257,8 @@ func DeepStateDisplayCopyDifference(s *State, prev *DisplayState) (*DisplayState
 		}
 	}
 
+	ds.ElectionHistory = s.ElectionHistoryString()
+
 	ds.SyncingState = s.SyncingState
 	ds.SyncingStateCurrent = s.SyncingStateCurrent
 	ds.IgnoreDone = s.GetIgnoreDone()
@@ -325,6 +328,7 @@ func (d *DisplayState) Clone() *DisplayState {
 	ds.Election = d.Election
 
 	ds.SimElection = d.SimElection
+	ds.ElectionHistory = d.ElectionHistory
 	ds.SyncingStateCurrent = d.SyncingStateCurrent
 	ds.SyncingState = d.SyncingState
 
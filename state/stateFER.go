@@ -215,3 +215,12 @@ func (this *State) GetPredictiveFER() uint64 {
 
 	return this.FERChangePrice
 }
+
+// GetFERChange returns the exchange rate the FER chain has scheduled and the height it takes effect,
+// or the current rate and a zero height when no change is pending
+func (this *State) GetFERChange() (price uint64, height uint32) {
+	if this.FERChangePrice == 0 || this.FERChangeHeight <= this.GetDBHeightComplete() {
+		return this.GetFactoshisPerEC(), 0
+	}
+	return this.FERChangePrice, this.FERChangeHeight
+}
@@ -0,0 +1,46 @@
+package state
+
+import (
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants/runstate"
+)
+
+// stateScopeTimeout is how long an API waits for the state to take a request
+const stateScopeTimeout = 5 * time.Second
+
+// inStateScope runs f in the state's scope and waits for it, so an API can read what the
+// validator changes.  A running node runs f from UpdateState.  A node that is not running yet has
+// nothing changing its state, so f runs right away.  It returns false if f did not run, as when
+// the node is shutting down.
+func (s *State) inStateScope(f func()) bool {
+	switch s.GetRunState() {
+	case runstate.New, runstate.Booting:
+		f()
+		return true
+	case runstate.Running:
+	default:
+		return false
+	}
+
+	done := make(chan struct{})
+	select {
+	case s.apiRequests <- func() { f(); close(done) }:
+		<-done // taken by UpdateState, which runs it right away
+		return true
+	case <-time.After(stateScopeTimeout):
+		return false
+	}
+}
+
+// runAPIRequests runs the functions the APIs asked to run in the state's scope
+func (s *State) runAPIRequests() {
+	for {
+		select {
+		case f := <-s.apiRequests:
+			f()
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants/runstate"
+)
+
+func TestInStateScope(t *testing.T) {
+	s := new(State)
+	s.apiRequests = make(chan func())
+
+	ran := false
+	if !s.inStateScope(func() { ran = true }) || !ran {
+		t.Error("a node that is not running did not run the request")
+	}
+
+	s.RunState = runstate.Running
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				s.runAPIRequests()
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}()
+	ran = false
+	if !s.inStateScope(func() { ran = true }) || !ran {
+		t.Error("a running node did not run the request")
+	}
+
+	s.RunState = runstate.Stopping
+	if s.inStateScope(func() { t.Error("a stopping node ran the request") }) {
+		t.Error("a stopping node took the request")
+	}
+}
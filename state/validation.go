@@ -64,6 +64,12 @@ func (s *State) DoProcessing() {
 		// if we were unable to accomplish any work sleep a bit.
 		if !p1 && !p2 && !p3 {
 			// No work? Sleep for a bit
+			if s.IsReplaying {
+				select {
+				case s.replayIdle <- struct{}{}: // tell a journal replay waiting for the node
+				default:
+				}
+			}
 			time.Sleep(10 * time.Millisecond)
 			s.ValidatorLoopSleepCnt++
 			i3++
@@ -110,6 +116,7 @@ func (s *State) ValidatorLoop() {
 			time.Sleep(10 * time.Second) // wait till database close is complete
 			return
 		case c := <-s.tickerQueue: // Look for pending messages, and get one if there is one.
+			s.JournalTick(c)
 			if !s.RunLeader || !s.DBFinished { // don't generate EOM if we are not ready to execute as a leader or are loading the DBState messages
 				continue
 			}
@@ -125,7 +132,7 @@ func (s *State) ValidatorLoop() {
 				if c == -1 { // This means we received a normal eom cadence timer
 					c = 8 // Send 8 retries on a 1/10 of the normal minute period
 				}
-				if c > 0 {
+				if c > 0 && !s.IsReplaying { // a replay takes the retries from the journal
 					go func() {
 						// We sleep for 1/10 of a minute, and try again
 						time.Sleep(s.GetMinuteDuration() / 10)
@@ -188,5 +195,6 @@ func shutdown(state *State) {
 	state.StateSaverStruct.StopSaving()
 	state.DB.Close()
 	fmt.Println("Database on", state.GetFactomNodeName(), "closed")
+	state.runShutdownHandlers()
 	state.RunState = runstate.Stopped
 }
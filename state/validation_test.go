@@ -0,0 +1,18 @@
+package state
+
+import "testing"
+
+func TestShutdownHandlers(t *testing.T) {
+	s := new(State)
+	calls := 0
+	s.AddShutdownHandler(func() { calls++ })
+	s.AddShutdownHandler(func() { calls += 10 })
+	s.runShutdownHandlers()
+	if calls != 11 {
+		t.Errorf("the shutdown handlers added up to %d, want 11", calls)
+	}
+	s.runShutdownHandlers()
+	if calls != 11 {
+		t.Error("the shutdown handlers were called again")
+	}
+}
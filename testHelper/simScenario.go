@@ -0,0 +1,471 @@
+package testHelper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/factom"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/engine"
+	"github.com/FactomProject/factomd/state"
+	"gopkg.in/yaml.v2"
+)
+
+// SimScenario is a simulation test written as data rather than code.  It sets up the nodes the
+// way SetupSim does, runs its actions as node 0 reaches the blocks and minutes they name, and
+// checks its expectations once the actions are done.  Scenarios are read from YAML or JSON:
+//
+//	name: fund and write entries
+//	nodes: LLLAF
+//	height: 12
+//	accounts:
+//	  alice: Fs2BNvoDgSoGJpWg4PvRUxqvLE28CQexp5FZM9X5qU6QvzFBUn6D
+//	actions:
+//	  - block: 5
+//	    fund: { account: alice, fct: "10", ec: 100 }
+//	  - waitblocks: 1
+//	    entries: { account: alice, chain: [ "foo", "bar" ], create: true, count: 5 }
+//	  - waitminutes: 3
+//	    cmds: [ "2", "x" ]   # take leader 2 offline
+//	expect:
+//	  authorities: LLLAF
+//	  chains:
+//	    - { chain: [ "foo", "bar" ], entries: 6, head: "fund and write entries 4" }
+type SimScenario struct {
+	Name      string            `json:"name" yaml:"name"`
+	Nodes     string            `json:"nodes" yaml:"nodes"`         // the roles of the nodes, as SetupSim takes them
+	Options   map[string]string `json:"options" yaml:"options"`     // command line options added to the defaults
+	Height    int               `json:"height" yaml:"height"`       // blocks the test should take, for its timeout
+	Elections int               `json:"elections" yaml:"elections"` // elections the test expects, for its timeout
+	Rounds    int               `json:"rounds" yaml:"rounds"`       // election rounds the test expects, for its timeout
+	Accounts  map[string]string `json:"accounts" yaml:"accounts"`   // account names and their factoid secret keys
+	Actions   []SimAction       `json:"actions" yaml:"actions"`
+	Expect    SimExpect         `json:"expect" yaml:"expect"`
+}
+
+// SimAction is done once node 0 reaches Block and Minute, or once WaitBlocks and WaitMinutes have
+// passed since the action before it.  An action may do several things, in the order of the fields.
+type SimAction struct {
+	Block       int `json:"block" yaml:"block"`
+	Minute      int `json:"minute" yaml:"minute"`
+	WaitBlocks  int `json:"waitblocks" yaml:"waitblocks"`
+	WaitMinutes int `json:"waitminutes" yaml:"waitminutes"`
+
+	Cmds        []string      `json:"cmds" yaml:"cmds"`               // simControl commands, as typed at the console
+	Partition   [][]int       `json:"partition" yaml:"partition"`     // groups of nodes that cannot reach each other
+	Heal        bool          `json:"heal" yaml:"heal"`               // reconnect partitioned nodes
+	Link        *SimLinkFault `json:"link" yaml:"link"`               // faults on the link between two nodes
+	ClearFaults bool          `json:"clearfaults" yaml:"clearfaults"` // remove every partition and link fault
+	Load        string        `json:"load" yaml:"load"`               // entries per second from the load generator, "0" stops it
+	Fund        *SimFund      `json:"fund" yaml:"fund"`
+	Entries     *SimEntries   `json:"entries" yaml:"entries"`
+}
+
+// SimLinkFault is the latency and jitter in milliseconds, and how many out of every 1000 messages
+// are reordered and duplicated, on the link from one node to another
+type SimLinkFault struct {
+	From      int   `json:"from" yaml:"from"`
+	To        int   `json:"to" yaml:"to"`
+	Latency   int64 `json:"latency" yaml:"latency"`
+	Jitter    int64 `json:"jitter" yaml:"jitter"`
+	Reorder   int   `json:"reorder" yaml:"reorder"`
+	Duplicate int   `json:"duplicate" yaml:"duplicate"`
+}
+
+// SimFund sends factoids and buys entry credits for an account from the coinbase bank
+type SimFund struct {
+	Account string `json:"account" yaml:"account"`
+	FCT     string `json:"fct" yaml:"fct"` // in factoids, "1.5"
+	EC      uint64 `json:"ec" yaml:"ec"`
+}
+
+// SimEntries writes entries to a chain, paid for by an account
+type SimEntries struct {
+	Account string   `json:"account" yaml:"account"`
+	Chain   []string `json:"chain" yaml:"chain"`   // the external ids of the first entry, which name the chain
+	Create  bool     `json:"create" yaml:"create"` // create the chain first, which is an entry of its own
+	Count   int      `json:"count" yaml:"count"`
+}
+
+// SimExpect is what the network should look like once the actions are done and WaitBlocks more
+// blocks have passed
+type SimExpect struct {
+	WaitBlocks  int          `json:"waitblocks" yaml:"waitblocks"`   // 1 if not set
+	Authorities string       `json:"authorities" yaml:"authorities"` // the roles of the nodes, as AssertAuthoritySet takes them
+	Height      int          `json:"height" yaml:"height"`           // the lowest block height every running node should be at
+	Balances    []SimBalance `json:"balances" yaml:"balances"`
+	Chains      []SimChain   `json:"chains" yaml:"chains"`
+}
+
+// SimBalance is the factoid and entry credit balance an account should have.  Either may be left out.
+type SimBalance struct {
+	Account string `json:"account" yaml:"account"`
+	FCT     string `json:"fct" yaml:"fct"` // in factoids
+	EC      *int64 `json:"ec" yaml:"ec"`
+}
+
+// SimChain is a chain that should have the number of entries given, or whose head entry block
+// should end with the content given at or above a height.  Either the entries or the head must be set.
+type SimChain struct {
+	Chain      []string `json:"chain" yaml:"chain"`
+	ChainID    string   `json:"chainid" yaml:"chainid"` // instead of the external ids that name it
+	Entries    int      `json:"entries" yaml:"entries"`
+	Head       string   `json:"head" yaml:"head"`             // the content of the last entry in the head entry block
+	HeadHeight int      `json:"headheight" yaml:"headheight"` // the lowest height the head entry block may be at
+}
+
+// LoadSimScenario reads a scenario, as YAML if the file name ends in .yaml or .yml and as JSON otherwise
+func LoadSimScenario(filename string) (*SimScenario, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	ext := strings.ToLower(filepath.Ext(filename))
+	return ParseSimScenario(data, ext == ".yaml" || ext == ".yml")
+}
+
+// ParseSimScenario decodes a scenario and checks the nodes, accounts and chains it refers to
+func ParseSimScenario(data []byte, isYAML bool) (*SimScenario, error) {
+	s := new(SimScenario)
+	var err error
+	if isYAML {
+		err = yaml.UnmarshalStrict(data, s)
+	} else {
+		err = json.Unmarshal(data, s)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	roles := func(nodes string) error {
+		if len(nodes) == 0 || strings.Trim(nodes, "LAF") != "" {
+			return fmt.Errorf("scenario %q: nodes %q must be made of L, A and F", s.Name, nodes)
+		}
+		return nil
+	}
+	if err := roles(s.Nodes); err != nil {
+		return nil, err
+	}
+	if s.Expect.Authorities != "" {
+		if err := roles(s.Expect.Authorities); err != nil {
+			return nil, err
+		}
+	}
+	if s.Height < 1 {
+		return nil, fmt.Errorf("scenario %q: height must be set", s.Name)
+	}
+
+	account := func(name string) error {
+		if name == "bank" {
+			return nil
+		}
+		secret, ok := s.Accounts[name]
+		if !ok {
+			return fmt.Errorf("scenario %q: no account %q", s.Name, name)
+		}
+		if _, err := primitives.HumanReadableFactoidPrivateKeyToPrivateKey(secret); err != nil {
+			return fmt.Errorf("scenario %q: account %q: %v", s.Name, name, err)
+		}
+		return nil
+	}
+	node := func(n int) error {
+		if n < 0 || n >= len(s.Nodes) {
+			return fmt.Errorf("scenario %q: no node %d", s.Name, n)
+		}
+		return nil
+	}
+
+	for i, a := range s.Actions {
+		if a.Minute < 0 || a.Minute > 9 {
+			return nil, fmt.Errorf("scenario %q: action %d: minute %d is not 0-9", s.Name, i, a.Minute)
+		}
+		for _, group := range a.Partition {
+			for _, n := range group {
+				if err := node(n); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if a.Link != nil {
+			if err := node(a.Link.From); err != nil {
+				return nil, err
+			}
+			if err := node(a.Link.To); err != nil {
+				return nil, err
+			}
+		}
+		if a.Fund != nil {
+			if err := account(a.Fund.Account); err != nil {
+				return nil, err
+			}
+		}
+		if a.Entries != nil {
+			if err := account(a.Entries.Account); err != nil {
+				return nil, err
+			}
+			if len(a.Entries.Chain) == 0 {
+				return nil, fmt.Errorf("scenario %q: action %d: entries need the external ids of their chain", s.Name, i)
+			}
+		}
+	}
+	for _, b := range s.Expect.Balances {
+		if err := account(b.Account); err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range s.Expect.Chains {
+		if len(c.Chain) == 0 && c.ChainID == "" {
+			return nil, fmt.Errorf("scenario %q: expected chains need their external ids or chain id", s.Name)
+		}
+		if c.Entries <= 0 && c.Head == "" {
+			return nil, fmt.Errorf("scenario %q: expected chains need an entry count or a head", s.Name)
+		}
+	}
+	return s, nil
+}
+
+// RunSimScenario loads a scenario and runs it as the test t
+func RunSimScenario(t *testing.T, filename string) {
+	s, err := LoadSimScenario(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Options == nil {
+		s.Options = make(map[string]string)
+	}
+	if s.Options["--factomhome"] == "" {
+		// SetupSim names the home after the test, which it cannot find from this deep in the stack
+		homeDir := GetSimTestHome(t)
+		if err := os.MkdirAll(filepath.Join(homeDir, "/.factom/m2"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		s.Options["--factomhome"] = homeDir
+	}
+	s.Run(t)
+}
+
+// Run sets up the simulation, does the actions, checks the expectations and shuts the simulation down
+func (s *SimScenario) Run(t *testing.T) {
+	state0 := SetupSim(s.Nodes, s.Options, s.Height, s.Elections, s.Rounds, t)
+
+	for i, a := range s.Actions {
+		if a.Block > 0 || a.Minute > 0 {
+			if a.Block*10+a.Minute >= int(state0.LLeaderHeight)*10+state0.CurrentMinute {
+				WaitForQuiet(state0, a.Block, a.Minute)
+			} else {
+				t.Logf("Scenario %q action %d at %d-:-%d is late, node 0 is at %d-:-%d", s.Name, i, a.Block, a.Minute, state0.LLeaderHeight, state0.CurrentMinute)
+			}
+		}
+		if a.WaitBlocks > 0 {
+			WaitBlocks(state0, a.WaitBlocks)
+		}
+		if a.WaitMinutes > 0 {
+			WaitMinutes(state0, a.WaitMinutes)
+		}
+		s.do(t, state0, a)
+	}
+
+	wait := s.Expect.WaitBlocks
+	if wait == 0 {
+		wait = 1
+	}
+	WaitBlocks(state0, wait)
+	WaitForAllNodes(state0)
+	s.check(t, state0)
+	ShutDownEverything(t)
+}
+
+func (s *SimScenario) do(t *testing.T, state0 *state.State, a SimAction) {
+	for _, cmd := range a.Cmds {
+		RunCmd(cmd)
+	}
+	if len(a.Partition) > 0 {
+		if err := engine.PartitionNodes(a.Partition); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if a.Heal {
+		engine.HealPartitions()
+	}
+	if a.Link != nil {
+		err := engine.SetLinkFaults(a.Link.From, a.Link.To, engine.LinkFaults{
+			Latency: a.Link.Latency, Jitter: a.Link.Jitter, Reorder: a.Link.Reorder, Duplicate: a.Link.Duplicate})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if a.ClearFaults {
+		engine.ClearNetworkFaults()
+	}
+	if a.Load != "" {
+		RunCmd("R" + a.Load)
+	}
+	if a.Fund != nil {
+		account := s.account(a.Fund.Account)
+		if a.Fund.FCT != "" {
+			account.FundFCT(factom.FactoidToFactoshi(a.Fund.FCT))
+		}
+		if a.Fund.EC > 0 {
+			account.FundEC(a.Fund.EC)
+		}
+	}
+	if a.Entries != nil {
+		s.writeEntries(t, state0, a.Entries)
+	}
+}
+
+func (s *SimScenario) writeEntries(t *testing.T, state0 *state.State, e *SimEntries) {
+	account := s.account(e.Account)
+	extIDs := make([][]byte, len(e.Chain))
+	for i, id := range e.Chain {
+		extIDs[i] = []byte(id)
+	}
+	chainID := simChainID(e.Chain)
+
+	if e.Create {
+		first := factom.Entry{ChainID: chainID, ExtIDs: extIDs, Content: []byte(s.Name)}
+		chain := factom.NewChain(&first)
+		commit, err := ComposeChainCommit(account.Priv, chain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reveal, err := ComposeRevealEntryMsg(account.Priv, chain.FirstEntry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state0.APIQueue().Enqueue(commit)
+		state0.APIQueue().Enqueue(reveal)
+	}
+
+	for i := 0; i < e.Count; i++ {
+		entry := factom.Entry{ChainID: chainID, ExtIDs: extIDs, Content: []byte(fmt.Sprintf("%s %d", s.Name, i))}
+		commit, err := ComposeCommitEntryMsg(account.Priv, entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reveal, err := ComposeRevealEntryMsg(account.Priv, &entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state0.APIQueue().Enqueue(commit)
+		state0.APIQueue().Enqueue(reveal)
+	}
+}
+
+func (s *SimScenario) check(t *testing.T, state0 *state.State) {
+	if s.Expect.Authorities != "" {
+		AdjustAuthoritySet(s.Expect.Authorities)
+		AssertAuthoritySet(t, s.Expect.Authorities)
+	}
+
+	if s.Expect.Height > 0 {
+		for i, fn := range engine.GetFnodes() {
+			if fn.State.GetNetStateOff() {
+				continue
+			}
+			if h := int(fn.State.GetLLeaderHeight()); h < s.Expect.Height {
+				t.Errorf("Scenario %q: node %d is at height %d, expected at least %d", s.Name, i, h, s.Expect.Height)
+			}
+		}
+	}
+
+	for _, b := range s.Expect.Balances {
+		account := s.account(b.Account)
+		if b.FCT != "" {
+			expected := int64(factom.FactoidToFactoshi(b.FCT))
+			if bal := engine.GetBalance(state0, account.FctPub()); bal != expected {
+				t.Errorf("Scenario %q: account %s has %d factoshis, expected %d", s.Name, b.Account, bal, expected)
+			}
+		}
+		if b.EC != nil {
+			if bal := engine.GetBalanceEC(state0, account.EcPub()); bal != *b.EC {
+				t.Errorf("Scenario %q: account %s has %d entry credits, expected %d", s.Name, b.Account, bal, *b.EC)
+			}
+		}
+	}
+
+	for _, c := range s.Expect.Chains {
+		chainID := c.ChainID
+		if chainID == "" {
+			chainID = simChainID(c.Chain)
+		}
+		id, err := primitives.HexToHash(chainID)
+		if err != nil {
+			t.Errorf("Scenario %q: chain %s: %v", s.Name, chainID, err)
+			continue
+		}
+		head, err := state0.DB.FetchHeadIndexByChainID(id)
+		if err != nil || head == nil {
+			t.Errorf("Scenario %q: chain %s has no head", s.Name, chainID)
+			continue
+		}
+		if c.Head != "" || c.HeadHeight > 0 {
+			s.checkHead(t, state0, chainID, head, c)
+		}
+		if c.Entries > 0 {
+			entries, err := state0.DB.FetchAllEntriesByChainID(id)
+			if err != nil {
+				t.Errorf("Scenario %q: chain %s: %v", s.Name, chainID, err)
+				continue
+			}
+			if len(entries) != c.Entries {
+				t.Errorf("Scenario %q: chain %s has %d entries, expected %d", s.Name, chainID, len(entries), c.Entries)
+			}
+		}
+	}
+}
+
+// checkHead checks the height of the head entry block of a chain and the content of its last entry
+func (s *SimScenario) checkHead(t *testing.T, state0 *state.State, chainID string, head interfaces.IHash, c SimChain) {
+	eblock, err := state0.DB.FetchEBlock(head)
+	if err != nil || eblock == nil {
+		t.Errorf("Scenario %q: chain %s: head entry block %x not found", s.Name, chainID, head.Bytes())
+		return
+	}
+	if h := int(eblock.GetHeader().GetDBHeight()); h < c.HeadHeight {
+		t.Errorf("Scenario %q: chain %s has its head at height %d, expected at least %d", s.Name, chainID, h, c.HeadHeight)
+	}
+	if c.Head == "" {
+		return
+	}
+	var last interfaces.IHash
+	for _, hash := range eblock.GetEntryHashes() {
+		if !hash.IsMinuteMarker() {
+			last = hash
+		}
+	}
+	if last == nil {
+		t.Errorf("Scenario %q: chain %s has no entries in its head entry block", s.Name, chainID)
+		return
+	}
+	entry, err := state0.DB.FetchEntry(last)
+	if err != nil || entry == nil {
+		t.Errorf("Scenario %q: chain %s: entry %x not found", s.Name, chainID, last.Bytes())
+		return
+	}
+	if content := string(entry.GetContent()); content != c.Head {
+		t.Errorf("Scenario %q: chain %s ends with %q, expected %q", s.Name, chainID, content, c.Head)
+	}
+}
+
+func (s *SimScenario) account(name string) *testAccount {
+	if name == "bank" {
+		return GetBankAccount()
+	}
+	return AccountFromFctSecret(s.Accounts[name])
+}
+
+// simChainID is the id of the chain whose first entry has the external ids given
+func simChainID(chain []string) string {
+	extIDs := make([][]byte, len(chain))
+	for i, id := range chain {
+		extIDs[i] = []byte(id)
+	}
+	return factom.NewChain(&factom.Entry{ExtIDs: extIDs}).ChainID
+}
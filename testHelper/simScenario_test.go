@@ -0,0 +1,77 @@
+package testHelper_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/testHelper"
+)
+
+func TestParseSimScenario(t *testing.T) {
+	good := `
+name: good
+nodes: LLAF
+height: 10
+accounts:
+  alice: Fs2BNvoDgSoGJpWg4PvRUxqvLE28CQexp5FZM9X5qU6QvzFBUn6D
+actions:
+  - block: 4
+    minute: 2
+    fund: { account: alice, fct: "1.5", ec: 20 }
+  - waitminutes: 5
+    partition: [ [0, 1], [2, 3] ]
+    entries: { account: alice, chain: [ "a" ], create: true, count: 2 }
+  - waitblocks: 1
+    heal: true
+    load: ".5"
+expect:
+  authorities: LLAF
+  balances:
+    - { account: alice, ec: 17 }
+    - { account: bank }
+  chains:
+    - { chain: [ "a" ], entries: 3 }
+    - { chain: [ "a" ], head: "good 1", headheight: 5 }
+`
+	s, err := ParseSimScenario([]byte(good), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Actions) != 3 || s.Actions[0].Fund.FCT != "1.5" || len(s.Actions[1].Partition) != 2 || s.Actions[2].Load != ".5" {
+		t.Errorf("Scenario decoded wrong %+v", s)
+	}
+	if *s.Expect.Balances[0].EC != 17 || s.Expect.Balances[1].EC != nil {
+		t.Errorf("Balances decoded wrong %+v", s.Expect.Balances)
+	}
+	if c := s.Expect.Chains[1]; c.Head != "good 1" || c.HeadHeight != 5 || c.Entries != 0 {
+		t.Errorf("Chains decoded wrong %+v", s.Expect.Chains)
+	}
+
+	json := `{"name": "json", "nodes": "LF", "height": 5, "actions": [{"block": 3, "cmds": ["1", "x"]}], "expect": {"height": 4}}`
+	s, err = ParseSimScenario([]byte(json), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Actions[0].Cmds[1] != "x" || s.Expect.Height != 4 {
+		t.Errorf("Scenario decoded wrong %+v", s)
+	}
+
+	bad := []string{
+		`{"nodes": "LXF", "height": 5}`,
+		`{"nodes": "LF"}`,
+		`{"nodes": "LF", "height": 5, "actions": [{"minute": 10}]}`,
+		`{"nodes": "LF", "height": 5, "actions": [{"partition": [[0], [2]]}]}`,
+		`{"nodes": "LF", "height": 5, "actions": [{"fund": {"account": "bob"}}]}`,
+		`{"nodes": "LF", "height": 5, "accounts": {"bob": "Fs1"}, "actions": [{"fund": {"account": "bob"}}]}`,
+		`{"nodes": "LF", "height": 5, "actions": [{"entries": {"account": "bank"}}]}`,
+		`{"nodes": "LF", "height": 5, "expect": {"chains": [{"entries": 1}]}}`,
+		`{"nodes": "LF", "height": 5, "expect": {"chains": [{"chain": ["a"], "headheight": 3}]}}`,
+	}
+	for _, data := range bad {
+		if _, err := ParseSimScenario([]byte(data), false); err == nil {
+			t.Errorf("Expected an error parsing %s", data)
+		}
+	}
+	if _, err := ParseSimScenario([]byte("nodes: LF\nheight: 5\nunknown: 1\n"), true); err == nil {
+		t.Errorf("Expected an error for an unknown YAML field")
+	}
+}
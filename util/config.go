@@ -22,6 +22,7 @@ type FactomdConfig struct {
 		ControlPanelPort                       int
 		ControlPanelFilesPath                  string
 		ControlPanelSetting                    string
+		ControlPanelUsers                      []string
 		DBType                                 string
 		LdbPath                                string
 		BoltDBPath                             string
@@ -43,6 +44,8 @@ type FactomdConfig struct {
 		ExchangeRateAuthorityPublicKeyLocalNet string
 		BitcoinAnchorRecordPublicKeys          []string
 		EthereumAnchorRecordPublicKeys         []string
+		GrantFile                              string
+		GrantFilePublicKeys                    []string
 
 		// Network Configuration
 		Network                 string
@@ -61,6 +64,9 @@ type FactomdConfig struct {
 		CustomSpecialPeers      string
 		CustomBootstrapIdentity string
 		CustomBootstrapKey      string
+		CustomActivations       string
+		CustomActivationsFile   string
+		CustomGenesisFile       string
 		P2PIncoming             int
 		P2POutgoing             int
 		FactomdTlsEnabled       bool
@@ -134,6 +140,9 @@ HomeDir                               = ""
 ; --------------- ControlPanel disabled | readonly | readwrite
 ControlPanelSetting                   = readonly
 ControlPanelPort                      = 8090
+; --------------- ControlPanelUsers: name:password:readonly|readwrite, repeat the line for each user.
+; --------------- A user never has more access than the ControlPanelSetting.
+; ControlPanelUsers                   = "operator:secret:readwrite"
 ; --------------- DBType: LDB | Bolt | Map
 DBType                                = "LDB"
 LdbPath                               = "database/ldb"
@@ -161,6 +170,12 @@ CustomSeedURL        = ""
 CustomSpecialPeers   = ""
 CustomBootstrapIdentity     = 38bab1455b7bd7e5efd15c53c777c79d0c988e9210f1da49a99d95b3a6417be9
 CustomBootstrapKey          = cc1985cdfae4e32b5a454dfda8ce5e1361558482684f3367649c3ad852c8e31a
+; Activation heights for a custom network, eg "AuthorityMaxDelta=1000, TestNetCoinBasePeriod=20",
+; or the path to a JSON file of them, eg {"AuthorityMaxDelta": 1000}.  Not allowed on other networks.
+CustomActivations           = ""
+CustomActivationsFile       = ""
+; The genesis definition of a custom network, made by Utilities/CustomNetwork.  Empty to use the built in genesis.
+CustomGenesisFile           = ""
 ; The maximum number of other peers dialing into this node that will be accepted
 P2PIncoming	= 200
 ; The maximum number of peers this node will attempt to dial into
@@ -203,6 +218,10 @@ CorsDomains                           = ""
 ; Specifying when to change ACKs for switching leader servers
 ChangeAcksHeight                      = 0
 
+; Networks other than MAIN can pay grants from a JSON file signed for the network by one of the GrantFilePublicKeys
+; (repeat the GrantFilePublicKeys line for each key)
+GrantFile                             = ""
+
 ; ------------------------------------------------------------------------------
 ; logLevel - allowed values are: debug, info, notice, warning, error, critical, alert, emergency and none
 ; ConsoleLogLevel - allowed values are: debug, standard
@@ -266,6 +285,7 @@ func (s *FactomdConfig) String() string {
 	out.WriteString(fmt.Sprintf("\n    ControlPanelPort        %v", s.App.ControlPanelPort))
 	out.WriteString(fmt.Sprintf("\n    ControlPanelFilesPath   %v", s.App.ControlPanelFilesPath))
 	out.WriteString(fmt.Sprintf("\n    ControlPanelSetting     %v", s.App.ControlPanelSetting))
+	out.WriteString(fmt.Sprintf("\n    ControlPanelUsers       %v users", len(s.App.ControlPanelUsers)))
 	out.WriteString(fmt.Sprintf("\n    DBType                  %v", s.App.DBType))
 	out.WriteString(fmt.Sprintf("\n    LdbPath                 %v", s.App.LdbPath))
 	out.WriteString(fmt.Sprintf("\n    BoltDBPath              %v", s.App.BoltDBPath))
@@ -289,6 +309,9 @@ func (s *FactomdConfig) String() string {
 	out.WriteString(fmt.Sprintf("\n    CustomSpecialPeers      %v", s.App.CustomSpecialPeers))
 	out.WriteString(fmt.Sprintf("\n    CustomBootstrapIdentity %v", s.App.CustomBootstrapIdentity))
 	out.WriteString(fmt.Sprintf("\n    CustomBootstrapKey      %v", s.App.CustomBootstrapKey))
+	out.WriteString(fmt.Sprintf("\n    CustomActivations       %v", s.App.CustomActivations))
+	out.WriteString(fmt.Sprintf("\n    CustomActivationsFile   %v", s.App.CustomActivationsFile))
+	out.WriteString(fmt.Sprintf("\n    CustomGenesisFile       %v", s.App.CustomGenesisFile))
 	out.WriteString(fmt.Sprintf("\n    P2PIncoming             %v", s.App.P2PIncoming))
 	out.WriteString(fmt.Sprintf("\n    P2POutgoing             %v", s.App.P2POutgoing))
 	out.WriteString(fmt.Sprintf("\n    NodeMode                %v", s.App.NodeMode))
@@ -306,6 +329,8 @@ func (s *FactomdConfig) String() string {
 	out.WriteString(fmt.Sprintf("\n    ChangeAcksHeight         %v", s.App.ChangeAcksHeight))
 	out.WriteString(fmt.Sprintf("\n    BitcoinAnchorRecordPublicKeys    %v", s.App.BitcoinAnchorRecordPublicKeys))
 	out.WriteString(fmt.Sprintf("\n    EthereumAnchorRecordPublicKeys    %v", s.App.EthereumAnchorRecordPublicKeys))
+	out.WriteString(fmt.Sprintf("\n    GrantFile               %v", s.App.GrantFile))
+	out.WriteString(fmt.Sprintf("\n    GrantFilePublicKeys     %v", s.App.GrantFilePublicKeys))
 
 	out.WriteString(fmt.Sprintf("\n  Log"))
 	out.WriteString(fmt.Sprintf("\n    LogPath                 %v", s.Log.LogPath))
@@ -8,8 +8,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/FactomProject/factomd/common/globals"
@@ -18,6 +20,8 @@ import (
 
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
+	llog "github.com/FactomProject/factomd/log"
+	"github.com/FactomProject/factomd/p2p"
 )
 
 type success struct {
@@ -67,6 +71,10 @@ func HandleDebugRequest(state interfaces.IState, j *primitives.JSON2Request) (*p
 	params := j.Params
 	wsDebugLog.Printf("request %v", j.String())
 
+	if protectedMethods[j.Method] && state.GetRpcUser() == "" {
+		return nil, NewCustomInvalidRequestError(j.Method + " needs the API to be protected with -rpcuser and -rpcpass")
+	}
+
 	switch j.Method {
 	case "audit-servers":
 		resp, jsonError = HandleAuditServers(state, params)
@@ -92,12 +100,18 @@ func HandleDebugRequest(state interfaces.IState, j *primitives.JSON2Request) (*p
 	case "set-drop-rate":
 		resp, jsonError = HandleSetDropRate(state, params)
 		break
+	case "election-history":
+		resp, jsonError = HandleElectionHistory(state, params)
+		break
 	case "federated-servers":
 		resp, jsonError = HandleFedServers(state, params)
 		break
 	case "holding-queue":
 		resp, jsonError = HandleHoldingQueue(state, params)
 		break
+	case "message-status":
+		resp, jsonError = HandleMessageStatus(state, params)
+		break
 	case "messages":
 		resp, jsonError = HandleMessages(state, params)
 		break
@@ -113,6 +127,9 @@ func HandleDebugRequest(state interfaces.IState, j *primitives.JSON2Request) (*p
 	case "process-list":
 		resp, jsonError = HandleProcessList(state, params)
 		break
+	case "process-list-status":
+		resp, jsonError = HandleProcessListStatus(state, params)
+		break
 	case "write-configuration":
 		resp, jsonError = HandleWriteConfig(state, params)
 		break
@@ -137,6 +154,75 @@ func HandleDebugRequest(state interfaces.IState, j *primitives.JSON2Request) (*p
 	case "message-filter":
 		resp, jsonError = HandleMessageFilter(state, params)
 		break
+	case "peers":
+		resp, jsonError = HandlePeers(state, params)
+		break
+	case "peer-dial":
+		resp, jsonError = HandlePeerDial(state, params)
+		break
+	case "peer-disconnect":
+		resp, jsonError = HandlePeerDisconnect(state, params)
+		break
+	case "peer-ban":
+		resp, jsonError = HandlePeerBan(state, params)
+		break
+	case "peer-unban":
+		resp, jsonError = HandlePeerUnban(state, params)
+		break
+	case "special-peers-set":
+		resp, jsonError = HandleSpecialPeersSet(state, params)
+		break
+	case "network-partition":
+		resp, jsonError = HandleNetworkPartition(state, params)
+		break
+	case "network-heal":
+		resp, jsonError = HandleNetworkHeal(state, params)
+		break
+	case "network-link-faults":
+		resp, jsonError = HandleNetworkLinkFaults(state, params)
+		break
+	case "network-clear-faults":
+		resp, jsonError = HandleNetworkClearFaults(state, params)
+		break
+	case "sim-nodes":
+		resp, jsonError = HandleSimNodes(state, params)
+		break
+	case "sim-select-node":
+		resp, jsonError = HandleSimSelectNode(state, params)
+		break
+	case "sim-promote-leader":
+		resp, jsonError = HandleSimPromoteLeader(state, params)
+		break
+	case "sim-promote-audit":
+		resp, jsonError = HandleSimPromoteAudit(state, params)
+		break
+	case "sim-remove-server":
+		resp, jsonError = HandleSimRemoveServer(state, params)
+		break
+	case "sim-set-offline":
+		resp, jsonError = HandleSimSetOffline(state, params)
+		break
+	case "sim-kill-node":
+		resp, jsonError = HandleSimKillNode(state, params)
+		break
+	case "sim-add-identities":
+		resp, jsonError = HandleSimAddIdentities(state, params)
+		break
+	case "sim-set-drop-rate":
+		resp, jsonError = HandleSimSetDropRate(state, params)
+		break
+	case "sim-logging":
+		resp, jsonError = HandleSimLogging(state, params)
+		break
+	case "log-levels":
+		resp, jsonError = HandleLogLevels(state, params)
+		break
+	case "set-log-level":
+		resp, jsonError = HandleSetLogLevel(state, params)
+		break
+	case "set-debug-log-regex":
+		resp, jsonError = HandleSetDebugLogRegEx(state, params)
+		break
 	default:
 		jsonError = NewMethodNotFoundError()
 		break
@@ -265,6 +351,16 @@ func HandleHoldingQueue(state interfaces.IState, params interface{}) (interface{
 	return r, nil
 }
 
+// HandleElectionHistory returns the elections this node has recorded, oldest first
+func HandleElectionHistory(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	type ret struct {
+		Elections []interfaces.ElectionRecord `json:"elections"`
+	}
+	r := new(ret)
+	r.Elections = state.GetElectionHistory()
+	return r, nil
+}
+
 func HandleMessages(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	type ret struct {
 		Messages []json.RawMessage
@@ -276,6 +372,19 @@ func HandleMessages(state interfaces.IState, params interface{}) (interface{}, *
 	return r, nil
 }
 
+// HandleMessageStatus explains where the node has a message and why it is still held
+func HandleMessageStatus(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	request := new(MessageStatusRequest)
+	if err := MapToObject(params, request); err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	hash, err := primitives.HexToHash(request.Hash)
+	if err != nil {
+		return nil, NewInvalidHashError()
+	}
+	return state.GetMessageStatus(hash), nil
+}
+
 func HandleNetworkInfo(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	type ret struct {
 		NodeName      string
@@ -321,6 +430,24 @@ func HandleProcessList(state interfaces.IState, params interface{}) (interface{}
 	return r, nil
 }
 
+// HandleProcessListStatus returns the VMs of the current and previous process lists as JSON
+func HandleProcessListStatus(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	type ret struct {
+		LeaderHeight uint32                        `json:"leaderheight"`
+		Minute       int                           `json:"minute"`
+		Current      *interfaces.ProcessListStatus `json:"current"`
+		Previous     *interfaces.ProcessListStatus `json:"previous"`
+	}
+	r := new(ret)
+	r.LeaderHeight = state.GetLeaderHeight()
+	r.Minute = state.GetCurrentMinute()
+	r.Current = state.GetProcessListStatus(r.LeaderHeight)
+	if r.LeaderHeight > 0 {
+		r.Previous = state.GetProcessListStatus(r.LeaderHeight - 1)
+	}
+	return r, nil
+}
+
 func HandleReloadConfig(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	// LoacConfig with "" strings should load the default location
 	state.LoadConfig(state.GetConfigPath(), state.GetNetworkName())
@@ -378,10 +505,86 @@ type SetDropRateRequest struct {
 	DropRate int `json:"droprate"`
 }
 
+type MessageStatusRequest struct {
+	Hash string `json:"hash"`
+}
+
 type GetCommands struct {
 	Commands []string `json:"commands"`
 }
 
+type PeerDialRequest struct {
+	Address    string `json:"address"`
+	Persistent bool   `json:"persistent"`
+}
+
+type PeerHashRequest struct {
+	Hash string `json:"hash"`
+}
+
+type PeerAddressRequest struct {
+	Address string `json:"address"`
+}
+
+type SpecialPeersRequest struct {
+	Peers []string `json:"peers"`
+}
+
+type NetworkPartitionRequest struct {
+	Groups [][]int `json:"groups"`
+}
+
+type NetworkHealRequest struct {
+	Height *uint32 `json:"height"` // heal once a node reaches this block, omit to heal now
+	Minute int     `json:"minute"`
+}
+
+type NetworkLinkFaultsRequest struct {
+	From      int   `json:"from"`
+	To        int   `json:"to"`
+	Latency   int64 `json:"latency"`   // milliseconds
+	Jitter    int64 `json:"jitter"`    // milliseconds
+	Reorder   int   `json:"reorder"`   // out of 1000 messages
+	Duplicate int   `json:"duplicate"` // out of 1000 messages
+}
+
+type SimNodeRequest struct {
+	Node        *int `json:"node"`
+	NewIdentity bool `json:"newidentity"` // for sim-promote-leader and sim-promote-audit
+	Audit       bool `json:"audit"`       // for sim-remove-server
+	Offline     bool `json:"offline"`     // for sim-set-offline
+}
+
+type SimAddIdentitiesRequest struct {
+	Count int `json:"count"`
+}
+
+type SimSetDropRateRequest struct {
+	Node     *int `json:"node"` // omit to set every node
+	DropRate int  `json:"droprate"`
+}
+
+type SimLoggingRequest struct {
+	Option string `json:"option"`
+	On     bool   `json:"on"`
+}
+
+type SetLogLevelRequest struct {
+	Subsystem string `json:"subsystem"` // state, p2p, wsapi, elections or events
+	Level     string `json:"level"`
+	Revert    int64  `json:"revert"` // seconds until the level goes back, 0 to keep it
+}
+
+type SetDebugLogRegExRequest struct {
+	RegEx  string `json:"regex"`  // as -debuglog takes it, "" to stop the debug logs
+	Revert int64  `json:"revert"` // seconds until the regex goes back, 0 to keep it
+}
+
+type LogLevelsResponse struct {
+	Levels        map[string]string `json:"levels"`
+	DebugLogRegEx string            `json:"debuglogregex"`
+}
+
 func HandleMessageFilter(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	wsDebugLog.Println("Factom Node Name: ", state.GetFactomNodeName())
 	x, ok := params.(map[string]interface{})
@@ -416,6 +619,132 @@ func HandleMessageFilter(state interfaces.IState, params interface{}) (interface
 	return h, nil
 }
 
+// getNetworkController returns the p2p controller of the node, if it has one.  Only the
+// node connected to the real network (fnode0) has a controller.
+func getNetworkController(state interfaces.IState) (*p2p.Controller, *primitives.JSONError) {
+	s, ok := state.(interface {
+		GetNetworkController() *p2p.Controller
+	})
+	if !ok || s.GetNetworkController() == nil {
+		return nil, NewCustomInternalError("Networking is not enabled on this node")
+	}
+	return s.GetNetworkController(), nil
+}
+
+func HandlePeers(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	type ret struct {
+		Connections  map[string]p2p.ConnectionMetrics `json:"connections"`
+		SpecialPeers []string                         `json:"specialpeers"`
+	}
+	controller, jsonError := getNetworkController(state)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	r := new(ret)
+	r.Connections = controller.GetConnectionMetrics()
+	r.SpecialPeers = controller.GetSpecialPeers()
+	return r, nil
+}
+
+func HandlePeerDial(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	controller, jsonError := getNetworkController(state)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	dial := new(PeerDialRequest)
+	err := MapToObject(params, dial)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	address, port, err := net.SplitHostPort(dial.Address)
+	if err != nil {
+		return nil, NewCustomInvalidParamsError(fmt.Sprintf("%s is not a valid peer, use format: 127.0.0.1:8108", dial.Address))
+	}
+
+	peer := new(p2p.Peer).Init(address, port, 0, p2p.RegularPeer, 0)
+	peer.Source["Debug-API"] = time.Now()
+	controller.DialPeer(*peer, dial.Persistent)
+
+	r := new(success)
+	r.Status = "Success!"
+	return r, nil
+}
+
+func HandlePeerDisconnect(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	controller, jsonError := getNetworkController(state)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	peer := new(PeerHashRequest)
+	err := MapToObject(params, peer)
+	if err != nil || peer.Hash == "" {
+		return nil, NewInvalidParamsError()
+	}
+	controller.Disconnect(peer.Hash)
+
+	r := new(success)
+	r.Status = "Success!"
+	return r, nil
+}
+
+func HandlePeerBan(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	controller, jsonError := getNetworkController(state)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	peer := new(PeerHashRequest)
+	err := MapToObject(params, peer)
+	if err != nil || peer.Hash == "" {
+		return nil, NewInvalidParamsError()
+	}
+	controller.Ban(peer.Hash)
+
+	r := new(success)
+	r.Status = "Success!"
+	return r, nil
+}
+
+func HandlePeerUnban(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	controller, jsonError := getNetworkController(state)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	peer := new(PeerAddressRequest)
+	err := MapToObject(params, peer)
+	if err != nil || peer.Address == "" {
+		return nil, NewInvalidParamsError()
+	}
+	controller.Unban(peer.Address)
+
+	r := new(success)
+	r.Status = "Success!"
+	return r, nil
+}
+
+// HandleSpecialPeersSet replaces the special peers that came from the config file.  Peers
+// given on the command line are kept.  The change lasts until the config file is reloaded.
+func HandleSpecialPeersSet(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	controller, jsonError := getNetworkController(state)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	peers := new(SpecialPeersRequest)
+	err := MapToObject(params, peers)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	for _, peer := range peers.Peers {
+		if _, _, err := net.SplitHostPort(peer); err != nil {
+			return nil, NewCustomInvalidParamsError(fmt.Sprintf("%s is not a valid peer, use format: 127.0.0.1:8108", peer))
+		}
+	}
+	controller.ReloadSpecialPeers(strings.Join(peers.Peers, " "))
+
+	r := new(success)
+	r.Status = "Success!"
+	return r, nil
+}
+
 func getParamMap(params interface{}) (x map[string]interface{}, ok bool) {
 	x, ok = params.(map[string]interface{})
 	return x, ok
@@ -512,3 +841,265 @@ func getRole(s interfaces.IState) string {
 	}
 	return "Follower"
 }
+
+// The network fault methods only apply to simulated nodes.  Like the sim methods they are
+// carried out by the simulator control API of the engine, so they fail unless the node runs a
+// simulation of more than one node or was started with -simapi.
+
+func HandleNetworkPartition(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	sim, jsonError := getSimController()
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	partition := new(NetworkPartitionRequest)
+	err := MapToObject(params, partition)
+	if err != nil || len(partition.Groups) == 0 {
+		return nil, NewInvalidParamsError()
+	}
+	for _, group := range partition.Groups {
+		if len(group) == 0 {
+			return nil, NewCustomInvalidParamsError("Partition groups must not be empty")
+		}
+	}
+	return simResponse(sim.PartitionNodes(partition.Groups))
+}
+
+func HandleNetworkHeal(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	sim, jsonError := getSimController()
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	heal := new(NetworkHealRequest)
+	if params != nil {
+		err := MapToObject(params, heal)
+		if err != nil || heal.Minute < 0 || heal.Minute > 9 {
+			return nil, NewInvalidParamsError()
+		}
+	}
+	return simResponse(sim.HealPartitions(heal.Height, heal.Minute))
+}
+
+func HandleNetworkLinkFaults(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	sim, jsonError := getSimController()
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	faults := new(NetworkLinkFaultsRequest)
+	err := MapToObject(params, faults)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	if faults.From < 0 || faults.To < 0 || faults.From == faults.To {
+		return nil, NewCustomInvalidParamsError("Specify the two nodes of a link")
+	}
+	if faults.Latency < 0 || faults.Jitter < 0 {
+		return nil, NewCustomInvalidParamsError("Latency and jitter must not be negative")
+	}
+	if faults.Reorder < 0 || faults.Reorder > 1000 || faults.Duplicate < 0 || faults.Duplicate > 1000 {
+		return nil, NewCustomInvalidParamsError("Specify reorder and duplicate rates between 0 and 1000")
+	}
+	return simResponse(sim.SetLinkFaults(faults.From, faults.To, faults.Latency, faults.Jitter, faults.Reorder, faults.Duplicate))
+}
+
+func HandleNetworkClearFaults(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	sim, jsonError := getSimController()
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	return simResponse(sim.ClearNetworkFaults())
+}
+
+// The sim methods control the nodes of a simulation through the simulator control API of the
+// engine.  They fail if the node is not running a simulation.
+
+var simController interfaces.ISimController
+
+// protectedMethods are the methods that change the node or the simulation.  They are only served
+// by an API that checks the authorization of its callers.
+var protectedMethods = map[string]bool{
+	"peer-dial":            true,
+	"peer-disconnect":      true,
+	"peer-ban":             true,
+	"peer-unban":           true,
+	"special-peers-set":    true,
+	"set-log-level":        true,
+	"set-debug-log-regex":  true,
+	"network-partition":    true,
+	"network-heal":         true,
+	"network-link-faults":  true,
+	"network-clear-faults": true,
+	"sim-select-node":      true,
+	"sim-promote-leader":   true,
+	"sim-promote-audit":    true,
+	"sim-remove-server":    true,
+	"sim-set-offline":      true,
+	"sim-kill-node":        true,
+	"sim-add-identities":   true,
+	"sim-set-drop-rate":    true,
+	"sim-logging":          true,
+}
+
+// SetSimController gives the sim methods the simulator to control
+func SetSimController(c interfaces.ISimController) {
+	simController = c
+}
+
+func getSimController() (interfaces.ISimController, *primitives.JSONError) {
+	if simController == nil {
+		return nil, NewCustomInternalError("Not running a simulation")
+	}
+	return simController, nil
+}
+
+// simNodeRequest decodes the parameters of a sim method that acts on one node
+func simNodeRequest(params interface{}) (interfaces.ISimController, *SimNodeRequest, *primitives.JSONError) {
+	sim, jsonError := getSimController()
+	if jsonError != nil {
+		return nil, nil, jsonError
+	}
+	request := new(SimNodeRequest)
+	err := MapToObject(params, request)
+	if err != nil || request.Node == nil {
+		return nil, nil, NewInvalidParamsError()
+	}
+	return sim, request, nil
+}
+
+func simResponse(result *interfaces.SimResult, err error) (interface{}, *primitives.JSONError) {
+	if err != nil {
+		return nil, NewCustomInvalidParamsError(err.Error())
+	}
+	return result, nil
+}
+
+func HandleSimNodes(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	sim, jsonError := getSimController()
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	return sim.Nodes(), nil
+}
+
+func HandleSimSelectNode(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	sim, request, jsonError := simNodeRequest(params)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	return simResponse(sim.SelectNode(*request.Node))
+}
+
+func HandleSimPromoteLeader(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	sim, request, jsonError := simNodeRequest(params)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	return simResponse(sim.PromoteLeader(*request.Node, request.NewIdentity))
+}
+
+func HandleSimPromoteAudit(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	sim, request, jsonError := simNodeRequest(params)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	return simResponse(sim.PromoteAudit(*request.Node, request.NewIdentity))
+}
+
+func HandleSimRemoveServer(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	sim, request, jsonError := simNodeRequest(params)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	return simResponse(sim.RemoveServer(*request.Node, request.Audit))
+}
+
+func HandleSimSetOffline(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	sim, request, jsonError := simNodeRequest(params)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	return simResponse(sim.SetOffline(*request.Node, request.Offline))
+}
+
+func HandleSimKillNode(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	sim, request, jsonError := simNodeRequest(params)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	return simResponse(sim.KillNode(*request.Node))
+}
+
+func HandleSimAddIdentities(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	sim, jsonError := getSimController()
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	request := new(SimAddIdentitiesRequest)
+	err := MapToObject(params, request)
+	if err != nil || request.Count < 1 {
+		return nil, NewInvalidParamsError()
+	}
+	return simResponse(sim.AddIdentities(request.Count))
+}
+
+func HandleSimSetDropRate(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	sim, jsonError := getSimController()
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	request := new(SimSetDropRateRequest)
+	err := MapToObject(params, request)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	node := -1
+	if request.Node != nil {
+		node = *request.Node
+	}
+	return simResponse(sim.SetDropRate(node, request.DropRate))
+}
+
+func HandleSimLogging(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	sim, jsonError := getSimController()
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	request := new(SimLoggingRequest)
+	err := MapToObject(params, request)
+	if err != nil || request.Option == "" {
+		return nil, NewInvalidParamsError()
+	}
+	return simResponse(sim.SetLogging(request.Option, request.On))
+}
+
+func HandleLogLevels(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	r := new(LogLevelsResponse)
+	r.Levels = llog.SubsystemLevels()
+	r.DebugLogRegEx = llog.DebugLogRegEx()
+	return r, nil
+}
+
+func HandleSetLogLevel(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	request := new(SetLogLevelRequest)
+	err := MapToObject(params, request)
+	if err != nil || request.Subsystem == "" || request.Level == "" || request.Revert < 0 {
+		return nil, NewInvalidParamsError()
+	}
+	err = llog.SetSubsystemLevel(request.Subsystem, request.Level, time.Duration(request.Revert)*time.Second)
+	if err != nil {
+		return nil, NewCustomInvalidParamsError(err.Error())
+	}
+	return HandleLogLevels(state, params)
+}
+
+func HandleSetDebugLogRegEx(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	request := new(SetDebugLogRegExRequest)
+	err := MapToObject(params, request)
+	if err != nil || request.Revert < 0 {
+		return nil, NewInvalidParamsError()
+	}
+	err = llog.SetDebugLogRegEx(request.RegEx, time.Duration(request.Revert)*time.Second)
+	if err != nil {
+		return nil, NewCustomInvalidParamsError(err.Error())
+	}
+	return HandleLogLevels(state, params)
+}
@@ -0,0 +1,365 @@
+package wsapi_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/p2p"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func TestHandleDebugPeersWithoutNetwork(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+
+	// Changing the peers needs an API protected by a user and password
+	for _, method := range []string{"peer-dial", "peer-disconnect", "peer-ban", "peer-unban", "special-peers-set"} {
+		request := primitives.NewJSON2Request(method, 1, map[string]interface{}{})
+		_, jsonError := HandleDebugRequest(state, request)
+		if jsonError == nil || !strings.Contains(fmt.Sprint(jsonError.Message, jsonError.Data), "-rpcuser") {
+			t.Errorf("%s: expected an error from an API without a user, got %v", method, jsonError)
+		}
+	}
+	state.RpcUser = "user"
+
+	for _, method := range []string{"peers", "peer-dial", "peer-disconnect", "peer-ban", "peer-unban", "special-peers-set"} {
+		request := primitives.NewJSON2Request(method, 1, map[string]interface{}{})
+		_, jsonError := HandleDebugRequest(state, request)
+		if jsonError == nil {
+			t.Errorf("%s: expected an error from a node without a network controller", method)
+		}
+	}
+}
+
+func TestHandleDebugNetworkFaultsInvalidParams(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	defer SetSimController(nil)
+
+	request := primitives.NewJSON2Request("network-clear-faults", 1, nil)
+	if _, jsonError := HandleDebugRequest(state, request); jsonError == nil {
+		t.Error("network-clear-faults: expected an error from a node that is not running a simulation")
+	}
+
+	SetSimController(new(fakeSim))
+	requests := map[string]interface{}{
+		"network-partition":   map[string]interface{}{"groups": [][]int{}},
+		"network-heal":        map[string]interface{}{"height": 10, "minute": 12},
+		"network-link-faults": map[string]interface{}{"from": 1, "to": 1},
+	}
+	for method, params := range requests {
+		request := primitives.NewJSON2Request(method, 1, params)
+		_, jsonError := HandleDebugRequest(state, request)
+		if jsonError == nil {
+			t.Errorf("%s: expected an error for params %v", method, params)
+		}
+	}
+
+	request = primitives.NewJSON2Request("network-link-faults", 1, map[string]interface{}{"from": 0, "to": 1, "reorder": 1001})
+	if _, jsonError := HandleDebugRequest(state, request); jsonError == nil {
+		t.Error("network-link-faults: expected an error for a reorder rate above 1000")
+	}
+}
+
+func TestHandleDebugProcessListStatus(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+
+	request := primitives.NewJSON2Request("process-list-status", 1, nil)
+	resp, jsonError := HandleDebugRequest(state, request)
+	if jsonError != nil {
+		t.Fatal(jsonError)
+	}
+	data, err := json.Marshal(resp.Result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var status struct {
+		LeaderHeight uint32
+		Current      *struct {
+			DBHeight   uint32
+			FedServers []string
+			VMs        []struct {
+				Leader  string
+				Missing []int
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Current == nil || status.Current.DBHeight != status.LeaderHeight {
+		t.Fatalf("no process list at the leader height in %s", data)
+	}
+	if len(status.Current.VMs) != len(status.Current.FedServers) {
+		t.Errorf("%d VMs for %d federated servers", len(status.Current.VMs), len(status.Current.FedServers))
+	}
+	for i, vm := range status.Current.VMs {
+		if vm.Leader == "" || vm.Missing == nil {
+			t.Errorf("incomplete status for VM %d: %+v", i, vm)
+		}
+	}
+}
+
+func TestHandleDebugMessageStatus(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+
+	request := primitives.NewJSON2Request("message-status", 1, map[string]interface{}{"hash": "not a hash"})
+	if _, jsonError := HandleDebugRequest(state, request); jsonError == nil {
+		t.Error("expected an error for a bad message hash")
+	}
+
+	hash := primitives.RandomHash().String()
+	request = primitives.NewJSON2Request("message-status", 1, map[string]interface{}{"hash": hash})
+	resp, jsonError := HandleDebugRequest(state, request)
+	if jsonError != nil {
+		t.Fatal(jsonError)
+	}
+	status := resp.Result.(*interfaces.MessageStatus)
+	if status.MsgHash != hash || len(status.Locations) != 0 {
+		t.Errorf("unknown message reported as %+v", status)
+	}
+}
+
+// fakeSim records the operations the sim methods ask for
+type fakeSim struct {
+	calls []string
+}
+
+func (f *fakeSim) result(call string) (*interfaces.SimResult, error) {
+	f.calls = append(f.calls, call)
+	return &interfaces.SimResult{Message: call}, nil
+}
+
+func (f *fakeSim) Nodes() []interfaces.SimNode {
+	return []interfaces.SimNode{{Index: 0, Name: "FNode0", Role: "Leader"}}
+}
+func (f *fakeSim) SelectNode(node int) (*interfaces.SimResult, error) {
+	return f.result(fmt.Sprintf("select %d", node))
+}
+func (f *fakeSim) PromoteLeader(node int, newIdentity bool) (*interfaces.SimResult, error) {
+	return f.result(fmt.Sprintf("leader %d %v", node, newIdentity))
+}
+func (f *fakeSim) PromoteAudit(node int, newIdentity bool) (*interfaces.SimResult, error) {
+	return f.result(fmt.Sprintf("audit %d %v", node, newIdentity))
+}
+func (f *fakeSim) RemoveServer(node int, audit bool) (*interfaces.SimResult, error) {
+	return f.result(fmt.Sprintf("remove %d %v", node, audit))
+}
+func (f *fakeSim) SetOffline(node int, offline bool) (*interfaces.SimResult, error) {
+	return f.result(fmt.Sprintf("offline %d %v", node, offline))
+}
+func (f *fakeSim) KillNode(node int) (*interfaces.SimResult, error) {
+	if node > 0 {
+		return nil, fmt.Errorf("there is no node %d", node)
+	}
+	return f.result(fmt.Sprintf("kill %d", node))
+}
+func (f *fakeSim) AddIdentities(count int) (*interfaces.SimResult, error) {
+	return f.result(fmt.Sprintf("identities %d", count))
+}
+func (f *fakeSim) SetDropRate(node int, rate int) (*interfaces.SimResult, error) {
+	return f.result(fmt.Sprintf("droprate %d %d", node, rate))
+}
+func (f *fakeSim) SetLogging(option string, on bool) (*interfaces.SimResult, error) {
+	return f.result(fmt.Sprintf("logging %s %v", option, on))
+}
+func (f *fakeSim) PartitionNodes(groups [][]int) (*interfaces.SimResult, error) {
+	for _, group := range groups {
+		for _, node := range group {
+			if node > 3 {
+				return nil, fmt.Errorf("there is no node %d", node)
+			}
+		}
+	}
+	return f.result(fmt.Sprintf("partition %v", groups))
+}
+func (f *fakeSim) HealPartitions(height *uint32, minute int) (*interfaces.SimResult, error) {
+	if height == nil {
+		return f.result("heal")
+	}
+	return f.result(fmt.Sprintf("heal %d.%d", *height, minute))
+}
+func (f *fakeSim) SetLinkFaults(from int, to int, latency int64, jitter int64, reorder int, duplicate int) (*interfaces.SimResult, error) {
+	if from > 3 || to > 3 {
+		return nil, fmt.Errorf("no link between nodes %d and %d", from, to)
+	}
+	return f.result(fmt.Sprintf("link %d %d %d %d %d %d", from, to, latency, jitter, reorder, duplicate))
+}
+func (f *fakeSim) ClearNetworkFaults() (*interfaces.SimResult, error) {
+	return f.result("clear")
+}
+
+func TestHandleDebugSimMethods(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	defer SetSimController(nil)
+
+	request := primitives.NewJSON2Request("sim-nodes", 1, nil)
+	if _, jsonError := HandleDebugRequest(state, request); jsonError == nil {
+		t.Error("sim-nodes: expected an error from a node that is not running a simulation")
+	}
+
+	sim := new(fakeSim)
+	SetSimController(sim)
+
+	// Changing the simulation needs an API protected by a user and password
+	request = primitives.NewJSON2Request("sim-kill-node", 1, map[string]interface{}{"node": 0})
+	if _, jsonError := HandleDebugRequest(state, request); jsonError == nil {
+		t.Error("sim-kill-node: expected an error from an API without a user")
+	}
+	if _, jsonError := HandleDebugRequest(state, primitives.NewJSON2Request("sim-nodes", 1, nil)); jsonError != nil {
+		t.Errorf("sim-nodes: %v", jsonError)
+	}
+	state.RpcUser = "user"
+
+	requests := []struct {
+		method string
+		params map[string]interface{}
+		call   string
+	}{
+		{"sim-select-node", map[string]interface{}{"node": 2}, "select 2"},
+		{"sim-promote-leader", map[string]interface{}{"node": 1, "newidentity": true}, "leader 1 true"},
+		{"sim-promote-audit", map[string]interface{}{"node": 3}, "audit 3 false"},
+		{"sim-remove-server", map[string]interface{}{"node": 1, "audit": true}, "remove 1 true"},
+		{"sim-set-offline", map[string]interface{}{"node": 4, "offline": true}, "offline 4 true"},
+		{"sim-kill-node", map[string]interface{}{"node": 0}, "kill 0"},
+		{"sim-add-identities", map[string]interface{}{"count": 5}, "identities 5"},
+		{"sim-set-drop-rate", map[string]interface{}{"droprate": 100}, "droprate -1 100"},
+		{"sim-set-drop-rate", map[string]interface{}{"node": 2, "droprate": 10}, "droprate 2 10"},
+		{"sim-logging", map[string]interface{}{"option": "consensus", "on": true}, "logging consensus true"},
+		{"network-partition", map[string]interface{}{"groups": [][]int{{0, 1}, {2}}}, "partition [[0 1] [2]]"},
+		{"network-heal", nil, "heal"},
+		{"network-heal", map[string]interface{}{"height": 10, "minute": 5}, "heal 10.5"},
+		{"network-link-faults", map[string]interface{}{"from": 0, "to": 2, "latency": 500, "reorder": 10}, "link 0 2 500 0 10 0"},
+		{"network-clear-faults", nil, "clear"},
+	}
+	for _, r := range requests {
+		resp, jsonError := HandleDebugRequest(state, primitives.NewJSON2Request(r.method, 1, r.params))
+		if jsonError != nil {
+			t.Errorf("%s: %v", r.method, jsonError)
+			continue
+		}
+		result, ok := resp.Result.(*interfaces.SimResult)
+		if !ok || result.Message != r.call {
+			t.Errorf("%s: expected %q, got %v", r.method, r.call, resp.Result)
+		}
+	}
+
+	resp, jsonError := HandleDebugRequest(state, primitives.NewJSON2Request("sim-nodes", 1, nil))
+	if jsonError != nil {
+		t.Fatal(jsonError)
+	}
+	if nodes, ok := resp.Result.([]interfaces.SimNode); !ok || len(nodes) != 1 || nodes[0].Role != "Leader" {
+		t.Errorf("sim-nodes: unexpected result %v", resp.Result)
+	}
+
+	invalid := map[string]interface{}{
+		"sim-select-node":     map[string]interface{}{},
+		"sim-kill-node":       map[string]interface{}{"node": 7},
+		"sim-add-identities":  map[string]interface{}{"count": 0},
+		"sim-logging":         map[string]interface{}{"on": true},
+		"network-partition":   map[string]interface{}{"groups": [][]int{{0}, {7}}},
+		"network-link-faults": map[string]interface{}{"from": 0, "to": 7},
+	}
+	for method, params := range invalid {
+		if _, jsonError := HandleDebugRequest(state, primitives.NewJSON2Request(method, 1, params)); jsonError == nil {
+			t.Errorf("%s: expected an error for params %v", method, params)
+		}
+	}
+}
+
+func TestHandleDebugLogLevels(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+
+	resp, jsonError := HandleDebugRequest(state, primitives.NewJSON2Request("log-levels", 1, nil))
+	if jsonError != nil {
+		t.Fatal(jsonError)
+	}
+	old := resp.Result.(*LogLevelsResponse).Levels["wsapi"]
+
+	// Changing the logs needs an API protected by a user and password
+	protected := map[string]interface{}{
+		"set-log-level":       map[string]interface{}{"subsystem": "wsapi", "level": "debug"},
+		"set-debug-log-regex": map[string]interface{}{"regex": ""},
+	}
+	for method, params := range protected {
+		if _, jsonError := HandleDebugRequest(state, primitives.NewJSON2Request(method, 1, params)); jsonError == nil {
+			t.Errorf("%s: expected an error from an API without a user", method)
+		}
+	}
+	state.RpcUser = "user"
+	defer HandleDebugRequest(state, primitives.NewJSON2Request("set-log-level", 1, map[string]interface{}{"subsystem": "wsapi", "level": old}))
+
+	request := primitives.NewJSON2Request("set-log-level", 1, map[string]interface{}{"subsystem": "wsapi", "level": "debug"})
+	resp, jsonError = HandleDebugRequest(state, request)
+	if jsonError != nil {
+		t.Fatal(jsonError)
+	}
+	levels, ok := resp.Result.(*LogLevelsResponse)
+	if !ok || levels.Levels["wsapi"] != "debug" {
+		t.Errorf("set-log-level: unexpected result %v", resp.Result)
+	}
+
+	invalid := map[string]interface{}{
+		"set-log-level":       map[string]interface{}{"subsystem": "nosuchsubsystem", "level": "debug"},
+		"set-debug-log-regex": map[string]interface{}{"regex": "(", "revert": 10},
+	}
+	for method, params := range invalid {
+		if _, jsonError := HandleDebugRequest(state, primitives.NewJSON2Request(method, 1, params)); jsonError == nil {
+			t.Errorf("%s: expected an error for params %v", method, params)
+		}
+	}
+
+	resp, jsonError = HandleDebugRequest(state, primitives.NewJSON2Request("log-levels", 1, nil))
+	if jsonError != nil {
+		t.Fatal(jsonError)
+	}
+	if levels, ok := resp.Result.(*LogLevelsResponse); !ok || levels.Levels["wsapi"] != "debug" {
+		t.Errorf("log-levels: unexpected result %v", resp.Result)
+	}
+}
+
+func TestHandleDebugSpecialPeers(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	file, err := ioutil.TempFile(os.TempDir(), "PeersTesting")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+	defer os.Remove(file.Name() + ".key")
+	state.NetworkController = new(p2p.Controller).Init(p2p.ControllerInit{
+		NodeName:                 "debugtest",
+		Port:                     "8108",
+		PeersFile:                file.Name(),
+		Network:                  p2p.LocalNet,
+		ConfigPeers:              "10.0.0.1:8108",
+		ConnectionMetricsChannel: make(chan interface{}, p2p.StandardChannelSize),
+		Transport:                p2p.NewMemoryNetwork(1).Transport("10.0.0.9"),
+	})
+	defer func() { state.NetworkController = nil }()
+	state.RpcUser = "user"
+
+	request := primitives.NewJSON2Request("special-peers-set", 1, map[string]interface{}{"peers": []string{"10.0.0.2:8108", "10.0.0.3:8108"}})
+	if _, jsonError := HandleDebugRequest(state, request); jsonError != nil {
+		t.Fatal(jsonError)
+	}
+
+	resp, jsonError := HandleDebugRequest(state, primitives.NewJSON2Request("peers", 1, nil))
+	if jsonError != nil {
+		t.Fatal(jsonError)
+	}
+	data, _ := json.Marshal(resp.Result)
+	result := new(struct {
+		SpecialPeers []string `json:"specialpeers"`
+	})
+	json.Unmarshal(data, result)
+	if fmt.Sprint(result.SpecialPeers) != "[10.0.0.2:8108 10.0.0.3:8108]" {
+		t.Errorf("expected the reloaded special peers, got %v", result.SpecialPeers)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"io/ioutil"
 	"os"
 
+	"github.com/FactomProject/factomd/common/globals"
+	llog "github.com/FactomProject/factomd/log"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -36,6 +38,9 @@ func NewLogFromConfig(logPath, logLevel, prefix string) *log.Entry {
 
 		logger := log.New()
 		logger.SetOutput(logFile)
+		if globals.Params.Logjson {
+			logger.SetFormatter(&log.JSONFormatter{})
+		}
 		lvl, err := log.ParseLevel(logLevel)
 		if err != nil {
 			panic(err)
@@ -49,4 +54,5 @@ func NewLogFromConfig(logPath, logLevel, prefix string) *log.Entry {
 func InitLogs(logPath, logLevel string) {
 	wsDebugLog = NewLogFromConfig(logPath, logLevel, "APIDEBUGLOG")
 	wsLog = NewLogFromConfig(logPath, logLevel, "WSAPI")
+	llog.RegisterSubsystemLoggers(llog.SubsystemWSAPI, wsLog.Logger, wsDebugLog.Logger)
 }
@@ -5,6 +5,7 @@
 package wsapi
 
 import (
+	"github.com/FactomProject/factomd/activations"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/FactomProject/factomd/receipts"
@@ -157,11 +158,80 @@ type EntryCreditRateResponse struct {
 	Rate int64 `json:"rate"`
 }
 
+type FeeEstimateResponse struct {
+	EntryCredits  uint64 `json:"entrycredits,omitempty"`
+	Rate          uint64 `json:"rate"`
+	Fee           uint64 `json:"fee"`
+	PredictedRate uint64 `json:"predictedrate"`
+	PredictedFee  uint64 `json:"predictedfee"`
+	ChangeHeight  uint32 `json:"changeheight,omitempty"`
+}
+
+type AuthoritiesAtHeightResponse struct {
+	Height    uint32              `json:"height"`
+	Federated []AuthorityAtHeight `json:"federated"`
+	Audit     []AuthorityAtHeight `json:"audit"`
+}
+
+type AuthorityAtHeight struct {
+	ChainID         string             `json:"chainid"`
+	SigningKey      string             `json:"signingkey"`
+	KeyHistory      []HistoricKeyEntry `json:"keyhistory"`
+	Efficiency      uint16             `json:"efficiency"`
+	CoinbaseAddress string             `json:"coinbaseaddress"`
+}
+
+type HistoricKeyEntry struct {
+	ActiveHeight uint32 `json:"activeheight"`
+	SigningKey   string `json:"signingkey"`
+}
+
+type AuthorityChangesResponse struct {
+	From    uint32            `json:"from"`
+	To      uint32            `json:"to"`
+	Changes []AuthorityChange `json:"changes"`
+}
+
+type AuthorityChange struct {
+	ChainID string `json:"chainid"`
+	Change  string `json:"change"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+}
+
 type PropertiesResponse struct {
 	FactomdVersion string `json:"factomdversion"`
 	ApiVersion     string `json:"factomdapiversion"`
 }
 
+type ActivationsResponse struct {
+	Network     string               `json:"network"`
+	Activations []ActivationResponse `json:"activations"`
+}
+
+type ActivationResponse struct {
+	activations.ScheduledActivation
+	Active bool `json:"active"`
+}
+
+type GrantsResponse struct {
+	Height uint32          `json:"height"`
+	Grants []GrantResponse `json:"grants"`
+}
+
+type GrantResponse struct {
+	Height       uint32 `json:"height"`       // block the payout is declared in
+	PayoutHeight uint32 `json:"payoutheight"` // block the payout is made in
+	Amount       uint64 `json:"amount"`
+	Address      string `json:"address"`
+}
+
+type PendingPoolResponse struct {
+	LeaderHeight uint32                        `json:"leaderheight"`
+	Minute       int                           `json:"minute"`
+	Pending      []interfaces.IPendingPoolItem `json:"pending"`
+}
+
 type SendRawMessageResponse struct {
 	Message string `json:"message"`
 }
@@ -320,6 +390,22 @@ type PendingTransaction struct {
 	Status        string           `json:"status"`
 }
 
+// FeeEstimateRequest is the shape of a factoid transaction, or the size of an entry or new chain
+type FeeEstimateRequest struct {
+	Inputs     int  `json:"inputs"`
+	Outputs    int  `json:"outputs"`
+	ECOutputs  int  `json:"ecoutputs"`
+	Signatures int  `json:"signatures"` // defaults to one per input
+	EntrySize  int  `json:"entrysize"`  // the bytes of the external IDs and content
+	ExtIDs     int  `json:"extids"`     // the number of external IDs, each costs 2 bytes more
+	Chain      bool `json:"chain"`
+}
+
+type HeightRangeRequest struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
+
 type TransactionRequest struct {
 	Transaction string `json:"transaction"`
 }
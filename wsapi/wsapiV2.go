@@ -13,8 +13,10 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/FactomProject/factomd/activations"
 	"github.com/FactomProject/factomd/anchor"
 	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/directoryBlock/dbInfo"
@@ -22,10 +24,12 @@ import (
 	"github.com/FactomProject/factomd/common/entryCreditBlock"
 	"github.com/FactomProject/factomd/common/factoid"
 	"github.com/FactomProject/factomd/common/globals"
+	"github.com/FactomProject/factomd/common/identity"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/FactomProject/factomd/receipts"
+	"github.com/FactomProject/factomd/util"
 )
 
 const API_VERSION string = "2.0"
@@ -101,6 +105,10 @@ func HandleV2JSONRequest(state interfaces.IState, j *primitives.JSON2Request) (*
 		resp, jsonError = HandleV2CommitChain(state, params)
 	case "commit-entry":
 		resp, jsonError = HandleV2CommitEntry(state, params)
+	case "commit-chain-validate":
+		resp, jsonError = HandleV2CommitChainValidate(state, params)
+	case "commit-entry-validate":
+		resp, jsonError = HandleV2CommitEntryValidate(state, params)
 	case "current-minute":
 		resp, jsonError = HandleV2CurrentMinute(state, params)
 	case "directory-block":
@@ -121,14 +129,22 @@ func HandleV2JSONRequest(state interfaces.IState, j *primitives.JSON2Request) (*
 		resp, jsonError = HandleV2EntryCreditBalance(state, params)
 	case "entry-credit-rate":
 		resp, jsonError = HandleV2EntryCreditRate(state, params)
+	case "fee-estimate":
+		resp, jsonError = HandleV2FeeEstimate(state, params)
 	case "factoid-balance":
 		resp, jsonError = HandleV2FactoidBalance(state, params)
 	case "factoid-submit":
 		resp, jsonError = HandleV2FactoidSubmit(state, params)
+	case "factoid-validate":
+		resp, jsonError = HandleV2FactoidValidate(state, params)
 	case "heights":
 		resp, jsonError = HandleV2Heights(state, params)
 	case "properties":
 		resp, jsonError = HandleV2Properties(state, params)
+	case "activations":
+		resp, jsonError = HandleV2Activations(state, params)
+	case "grants":
+		resp, jsonError = HandleV2Grants(state, params)
 	case "raw-data":
 		resp, jsonError = HandleV2RawData(state, params)
 	case "receipt":
@@ -145,6 +161,8 @@ func HandleV2JSONRequest(state interfaces.IState, j *primitives.JSON2Request) (*
 		resp, jsonError = HandleV2GetPendingEntries(state, params)
 	case "pending-transactions":
 		resp, jsonError = HandleV2GetPendingTransactions(state, params)
+	case "pending-pool":
+		resp, jsonError = HandleV2PendingPool(state, params)
 	case "send-raw-message":
 		resp, jsonError = HandleV2SendRawMessage(state, params)
 	case "transaction":
@@ -159,6 +177,10 @@ func HandleV2JSONRequest(state interfaces.IState, j *primitives.JSON2Request) (*
 		resp, jsonError = HandleV2ABlockByHeight(state, params)
 	case "authorities":
 		resp, jsonError = HandleAuthorities(state, params)
+	case "authorities-at-height":
+		resp, jsonError = HandleV2AuthoritiesAtHeight(state, params)
+	case "authority-changes":
+		resp, jsonError = HandleV2AuthorityChanges(state, params)
 	case "tps-rate":
 		resp, jsonError = HandleV2TransactionRate(state, params)
 	case "ack":
@@ -1194,6 +1216,170 @@ func HandleV2EntryCreditRate(state interfaces.IState, params interface{}) (inter
 	return resp, nil
 }
 
+// HandleV2FeeEstimate returns the fee of a factoid transaction of the given shape, or the cost of an
+// entry or chain of the given size, at the current exchange rate and at the rate the FER chain has
+// scheduled next
+func HandleV2FeeEstimate(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	request := new(FeeEstimateRequest)
+	err := MapToObject(params, request)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	resp := new(FeeEstimateResponse)
+	resp.Rate = state.GetFactoshisPerEC()
+	resp.PredictedRate, resp.ChangeHeight = state.GetFERChange()
+
+	switch {
+	case request.Inputs > 0 && request.EntrySize == 0 && !request.Chain:
+		if request.Signatures == 0 {
+			request.Signatures = request.Inputs
+		}
+		resp.Fee, err = factoid.EstimateFee(resp.Rate, request.Inputs, request.Outputs, request.ECOutputs, request.Signatures)
+		if err != nil {
+			return nil, NewCustomInvalidParamsError(err.Error())
+		}
+		resp.PredictedFee, _ = factoid.EstimateFee(resp.PredictedRate, request.Inputs, request.Outputs, request.ECOutputs, request.Signatures)
+	case request.Inputs == 0 && request.EntrySize >= 0 && request.ExtIDs >= 0 && (request.EntrySize > 0 || request.ExtIDs > 0 || request.Chain):
+		// Each external ID is prefixed with its length, so cost an entry of the same size
+		entry := entryBlock.NewEntry()
+		entry.ExtIDs = make([]primitives.ByteSlice, request.ExtIDs)
+		entry.Content = primitives.ByteSlice{Bytes: make([]byte, request.EntrySize)}
+		data, err := entry.MarshalBinary()
+		if err != nil {
+			return nil, NewCustomInvalidParamsError(err.Error())
+		}
+		credits, err := util.EntryCost(data)
+		if err != nil {
+			return nil, NewCustomInvalidParamsError(err.Error())
+		}
+		resp.EntryCredits = uint64(credits)
+		if request.Chain {
+			resp.EntryCredits += 10
+		}
+		resp.Fee = resp.EntryCredits * resp.Rate
+		resp.PredictedFee = resp.EntryCredits * resp.PredictedRate
+	default:
+		return nil, NewCustomInvalidParamsError("Expected the inputs of a transaction, or the entrysize of an entry or chain")
+	}
+	return resp, nil
+}
+
+// HandleV2AuthoritiesAtHeight returns the federated and audit servers, their signing keys, efficiencies
+// and coinbase addresses at a directory block height, rebuilt from the admin blocks up to it
+func HandleV2AuthoritiesAtHeight(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	heightRequest := new(HeightRequest)
+	err := MapToObject(params, heightRequest)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	if jErr := checkABlockExists(state, heightRequest.Height); jErr != nil {
+		return nil, jErr
+	}
+
+	set, err := authorityHistory(state).At(uint32(heightRequest.Height))
+	if err != nil {
+		return nil, NewInternalDatabaseError()
+	}
+
+	resp := new(AuthoritiesAtHeightResponse)
+	resp.Height = set.DBHeight
+	resp.Federated = authoritiesAtHeight(set, set.GetFederatedServers())
+	resp.Audit = authoritiesAtHeight(set, set.GetAuditServers())
+	return resp, nil
+}
+
+// authorityHistories keeps the authority history of each database the API serves, so the
+// authority calls do not replay the admin blocks from 0 on every request.  The history of a node
+// is dropped when the node shuts down.
+var authorityHistories = struct {
+	sync.Mutex
+	m map[interfaces.DBOverlaySimple]*identity.AuthorityHistory
+}{m: make(map[interfaces.DBOverlaySimple]*identity.AuthorityHistory)}
+
+func authorityHistory(state interfaces.IState) *identity.AuthorityHistory {
+	authorityHistories.Lock()
+	defer authorityHistories.Unlock()
+	db := state.GetDB()
+	history, ok := authorityHistories.m[db]
+	if !ok {
+		history = identity.NewAuthorityHistory(identity.AuthorityCheckpointInterval, db.FetchABlockByHeight)
+		authorityHistories.m[db] = history
+		state.AddShutdownHandler(func() {
+			authorityHistories.Lock()
+			defer authorityHistories.Unlock()
+			delete(authorityHistories.m, db)
+		})
+	}
+	return history
+}
+
+func authoritiesAtHeight(set *identity.AuthoritySet, auths []*identity.Authority) []AuthorityAtHeight {
+	list := make([]AuthorityAtHeight, 0)
+	for _, auth := range auths {
+		a := AuthorityAtHeight{
+			ChainID:         auth.AuthorityChainID.String(),
+			SigningKey:      auth.SigningKey.String(),
+			KeyHistory:      make([]HistoricKeyEntry, 0),
+			Efficiency:      auth.Efficiency,
+			CoinbaseAddress: identity.CoinbaseAddressString(auth.CoinbaseAddress),
+		}
+		for _, key := range set.GetKeyHistory(auth.AuthorityChainID) {
+			a.KeyHistory = append(a.KeyHistory, HistoricKeyEntry{key.ActiveDBHeight, key.SigningKey.String()})
+		}
+		list = append(list, a)
+	}
+	return list
+}
+
+func checkABlockExists(state interfaces.IState, height int64) *primitives.JSONError {
+	if height < 0 || height > int64(^uint32(0)) {
+		return NewInvalidParamsError()
+	}
+	block, err := state.GetDB().FetchABlockByHeight(uint32(height))
+	if err != nil {
+		return NewInternalDatabaseError()
+	}
+	if block == nil {
+		return NewBlockNotFoundError()
+	}
+	return nil
+}
+
+// HandleV2AuthorityChanges returns how the authority set changed from one directory block height to another
+func HandleV2AuthorityChanges(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	rangeRequest := new(HeightRangeRequest)
+	err := MapToObject(params, rangeRequest)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	if rangeRequest.From < 0 || rangeRequest.From > rangeRequest.To {
+		return nil, NewCustomInvalidParamsError("Expected 0 <= from <= to")
+	}
+	if jErr := checkABlockExists(state, rangeRequest.To); jErr != nil {
+		return nil, jErr
+	}
+
+	history := authorityHistory(state)
+	from, err := history.At(uint32(rangeRequest.From))
+	if err != nil {
+		return nil, NewInternalDatabaseError()
+	}
+	to, err := history.At(uint32(rangeRequest.To))
+	if err != nil {
+		return nil, NewInternalDatabaseError()
+	}
+
+	resp := new(AuthorityChangesResponse)
+	resp.From = from.DBHeight
+	resp.To = to.DBHeight
+	resp.Changes = make([]AuthorityChange, 0)
+	for _, c := range from.Changes(to) {
+		resp.Changes = append(resp.Changes, AuthorityChange{c.AuthorityChainID.String(), c.Change, c.From, c.To})
+	}
+	return resp, nil
+}
+
 func HandleV2FactoidSubmit(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	n := time.Now()
 	defer HandleV2APICallFctTx.Observe(float64(time.Since(n).Nanoseconds()))
@@ -1227,6 +1413,73 @@ func HandleV2FactoidSubmit(state interfaces.IState, params interface{}) (interfa
 	return resp, nil
 }
 
+// HandleV2FactoidValidate runs the checks factoid-submit would on a transaction, without submitting it
+func HandleV2FactoidValidate(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	t := new(TransactionRequest)
+	err := MapToObject(params, t)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	msg := new(messages.FactoidTransaction)
+	p, err := hex.DecodeString(t.Transaction)
+	if err != nil {
+		return nil, NewUnableToDecodeTransactionError()
+	}
+	_, err = msg.UnmarshalTransData(p)
+	if err != nil {
+		return nil, NewUnableToDecodeTransactionError()
+	}
+
+	return state.ValidateWithoutSubmit(msg), nil
+}
+
+// HandleV2CommitChainValidate runs the checks commit-chain would on a commit, without submitting it
+func HandleV2CommitChainValidate(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	commitChainMsg := new(MessageRequest)
+	err := MapToObject(params, commitChainMsg)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	commit := entryCreditBlock.NewCommitChain()
+	p, err := hex.DecodeString(commitChainMsg.Message)
+	if err != nil {
+		return nil, NewInvalidCommitChainError()
+	}
+	_, err = commit.UnmarshalBinaryData(p)
+	if err != nil {
+		return nil, NewInvalidCommitChainError()
+	}
+
+	msg := new(messages.CommitChainMsg)
+	msg.CommitChain = commit
+	return state.ValidateWithoutSubmit(msg), nil
+}
+
+// HandleV2CommitEntryValidate runs the checks commit-entry would on a commit, without submitting it
+func HandleV2CommitEntryValidate(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	commitEntryMsg := new(MessageRequest)
+	err := MapToObject(params, commitEntryMsg)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	commit := entryCreditBlock.NewCommitEntry()
+	p, err := hex.DecodeString(commitEntryMsg.Message)
+	if err != nil {
+		return nil, NewInvalidCommitEntryError()
+	}
+	_, err = commit.UnmarshalBinaryData(p)
+	if err != nil {
+		return nil, NewInvalidCommitEntryError()
+	}
+
+	msg := new(messages.CommitEntryMsg)
+	msg.CommitEntry = commit
+	return state.ValidateWithoutSubmit(msg), nil
+}
+
 func HandleV2FactoidBalance(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	n := time.Now()
 	defer HandleV2APICallFABal.Observe(float64(time.Since(n).Nanoseconds()))
@@ -1237,21 +1490,8 @@ func HandleV2FactoidBalance(state interfaces.IState, params interface{}) (interf
 		return nil, NewInvalidParamsError()
 	}
 
-	var adr []byte
-
-	if primitives.ValidateFUserStr(fadr.Address) {
-		adr = primitives.ConvertUserStrToAddress(fadr.Address)
-	} else {
-		adr, err = hex.DecodeString(fadr.Address)
-		if err == nil && len(adr) != constants.HASH_LENGTH {
-			return nil, NewInvalidAddressError()
-		}
-		if err != nil {
-			return nil, NewInvalidAddressError()
-		}
-	}
-
-	if len(adr) != constants.HASH_LENGTH {
+	adr, ok := factoidAddressBytes(fadr.Address)
+	if !ok || len(adr) != constants.HASH_LENGTH {
 		return nil, NewInvalidAddressError()
 	}
 
@@ -1260,6 +1500,30 @@ func HandleV2FactoidBalance(state interfaces.IState, params interface{}) (interf
 	return resp, nil
 }
 
+// factoidAddressBytes decodes a factoid address given as an FA address, the hex of the address, or
+// the hex of the RCD the address is the hash of, so a multisig address can be found by its RCD
+func factoidAddressBytes(address string) ([]byte, bool) {
+	if primitives.ValidateFUserStr(address) {
+		return primitives.ConvertUserStrToAddress(address), true
+	}
+	data, err := hex.DecodeString(address)
+	if err != nil {
+		return nil, false
+	}
+	if len(data) == constants.HASH_LENGTH {
+		return data, true
+	}
+	rcd, rest, err := factoid.UnmarshalBinaryAuth(data)
+	if err != nil || len(rest) != 0 {
+		return nil, false
+	}
+	adr, err := rcd.GetAddress()
+	if err != nil {
+		return nil, false
+	}
+	return adr.Bytes(), true
+}
+
 func HandleV2Heights(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	n := time.Now()
 	defer HandleV2APICallHeights.Observe(float64(time.Since(n).Nanoseconds()))
@@ -1307,6 +1571,26 @@ func HandleV2GetPendingTransactions(state interfaces.IState, params interface{})
 	return pending, nil
 }
 
+// HandleV2PendingPool returns the transactions and commits waiting to be saved in a block, optionally
+// only those of an FA or EC address, with why each one is waiting
+func HandleV2PendingPool(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	request := new(AddressRequest)
+	if params != nil {
+		if err := MapToObject(params, request); err != nil {
+			return nil, NewInvalidParamsError()
+		}
+	}
+	if request.Address != "" && !primitives.ValidateFUserStr(request.Address) && !primitives.ValidateECUserStr(request.Address) {
+		return nil, NewInvalidAddressError()
+	}
+
+	resp := new(PendingPoolResponse)
+	resp.LeaderHeight = state.GetLeaderHeight()
+	resp.Minute = state.GetCurrentMinute()
+	resp.Pending = state.GetPendingPool(request.Address)
+	return resp, nil
+}
+
 func HandleV2Properties(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	n := time.Now()
 	defer HandleV2APICallProp.Observe(float64(time.Since(n).Nanoseconds()))
@@ -1317,6 +1601,33 @@ func HandleV2Properties(state interfaces.IState, params interface{}) (interface{
 	return p, nil
 }
 
+func HandleV2Activations(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	resp := new(ActivationsResponse)
+	resp.Network = activations.NetworkName()
+	for _, a := range activations.Schedule() {
+		resp.Activations = append(resp.Activations, ActivationResponse{
+			ScheduledActivation: a,
+			Active:              state.IsActive(activations.ActivationType(a.Id)),
+		})
+	}
+	return resp, nil
+}
+
+func HandleV2Grants(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	resp := new(GrantsResponse)
+	resp.Height = state.GetHighestSavedBlk()
+	resp.Grants = []GrantResponse{}
+	for _, g := range state.GetUpcomingGrants() {
+		resp.Grants = append(resp.Grants, GrantResponse{
+			Height:       g.Height,
+			PayoutHeight: g.Height + constants.COINBASE_DECLARATION,
+			Amount:       g.Amount,
+			Address:      primitives.ConvertFctAddressToUserStr(g.Address),
+		})
+	}
+	return resp, nil
+}
+
 func HandleV2SendRawMessage(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	n := time.Now()
 	defer HandleV2APICallSendRaw.Observe(float64(time.Since(n).Nanoseconds()))
@@ -1527,7 +1838,7 @@ func HandleV2MultipleFCTBalances(state interfaces.IState, params interface{}) (i
 			errStruct.TempBal = 0
 			errStruct.Error = "No FCT addresses"
 			totalBalances[i] = errStruct
-		} else if primitives.ValidateFUserStr(a.(string)) != true {
+		} else if adr, ok := factoidAddressBytes(a.(string)); ok != true {
 			errStruct := new(interfaces.StructToReturnValues)
 			errStruct.PermBal = 0
 			errStruct.TempBal = 0
@@ -1535,7 +1846,7 @@ func HandleV2MultipleFCTBalances(state interfaces.IState, params interface{}) (i
 			totalBalances[i] = errStruct
 		} else {
 			covertedAdd := [32]byte{}
-			copy(covertedAdd[:], primitives.ConvertUserStrToAddress(a.(string)))
+			copy(covertedAdd[:], adr)
 			cHeight, sHeight, temp, perm, error := state.GetFactoidState().GetMultipleFactoidBalances(covertedAdd)
 			currentHeight = cHeight
 			savedHeight = sHeight
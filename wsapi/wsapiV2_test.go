@@ -2,6 +2,7 @@ package wsapi_test
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
 	"reflect"
@@ -12,6 +13,9 @@ import (
 
 	"time"
 
+	"github.com/FactomProject/factomd/activations"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/FactomProject/factomd/receipts"
@@ -210,6 +214,15 @@ func TestHandleV2Requests(t *testing.T) {
 			},
 			nil,
 		},
+		"activations": {
+			"activations",
+			nil,
+			http.StatusOK,
+			map[string]interface{}{
+				"network": activations.NetworkName(),
+			},
+			nil,
+		},
 		"diagnostics": {
 			"diagnostics",
 			nil,
@@ -436,6 +449,113 @@ func TestHandleV2GetTransaction(t *testing.T) {
 	}
 }
 
+func TestHandleV2PendingPool(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+
+	resp, jErr := HandleV2PendingPool(state, nil)
+	assert.Nil(t, jErr)
+	assert.Equal(t, 0, len(resp.(*PendingPoolResponse).Pending))
+
+	request := new(AddressRequest)
+	request.Address = "EC2DKSYyRcNWf7RS963VFYgMExoHRYLHVeCfQ9PGPmNzwrcmgm2r"
+	_, jErr = HandleV2PendingPool(state, request)
+	assert.Nil(t, jErr)
+
+	request.Address = "notanaddress"
+	_, jErr = HandleV2PendingPool(state, request)
+	assert.Equal(t, NewInvalidAddressError(), jErr)
+}
+
+func TestHandleV2ValidateWithoutSubmit(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	codes := func(resp interface{}) []string {
+		var c []string
+		for _, r := range resp.(*interfaces.IValidationReport).Rejections {
+			c = append(c, r.Code)
+		}
+		return c
+	}
+
+	// Spends factoids the address does not have, and pays no fee
+	tx := new(factoid.Transaction)
+	tx.AddInput(testHelper.NewFactoidAddress(1), 1000)
+	tx.AddOutput(testHelper.NewFactoidAddress(2), 1000)
+	tx.SetTimestamp(primitives.NewTimestampNow())
+	testHelper.SignFactoidTransaction(1, tx)
+	data, err := tx.MarshalBinary()
+	assert.Nil(t, err)
+
+	resp, jErr := HandleV2FactoidValidate(state, &TransactionRequest{Transaction: hex.EncodeToString(data)})
+	assert.Nil(t, jErr)
+	assert.False(t, resp.(*interfaces.IValidationReport).Valid)
+	assert.Equal(t, []string{"insufficient-fee", "insufficient-balance"}, codes(resp))
+	assert.Equal(t, tx.GetSigHash().String(), resp.(*interfaces.IValidationReport).TransactionID.String())
+	assert.Equal(t, 0, len(state.LoadHoldingMap()), "the transaction was submitted")
+
+	_, jErr = HandleV2FactoidValidate(state, &TransactionRequest{Transaction: "notatransaction"})
+	assert.Equal(t, NewUnableToDecodeTransactionError(), jErr)
+
+	// Pays too much for an entry, from an address with no entry credits, at a time outside the filters
+	commit := entryCreditBlock.NewCommitEntry()
+	commit.EntryHash = primitives.RandomHash()
+	commit.Credits = 11
+	testHelper.SignCommit(5, commit)
+	data, err = commit.MarshalBinary()
+	assert.Nil(t, err)
+
+	resp, jErr = HandleV2CommitEntryValidate(state, &MessageRequest{Message: hex.EncodeToString(data)})
+	assert.Nil(t, jErr)
+	assert.Equal(t, []string{"invalid-credits", "insufficient-balance", "outside-time-window"}, codes(resp))
+
+	// Changing the credits after signing breaks the signature
+	commit.Credits = 1
+	data, err = commit.MarshalBinary()
+	assert.Nil(t, err)
+	resp, jErr = HandleV2CommitEntryValidate(state, &MessageRequest{Message: hex.EncodeToString(data)})
+	assert.Nil(t, jErr)
+	assert.Equal(t, []string{"invalid-signature", "insufficient-balance", "outside-time-window"}, codes(resp))
+
+	_, jErr = HandleV2CommitChainValidate(state, &MessageRequest{Message: hex.EncodeToString(data)})
+	assert.Equal(t, NewInvalidCommitChainError(), jErr)
+}
+
+func TestHandleV2FeeEstimate(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	state.SetFactoshisPerEC(1000)
+
+	resp, jErr := HandleV2FeeEstimate(state, &FeeEstimateRequest{Inputs: 1, Outputs: 1})
+	assert.Nil(t, jErr)
+	r := resp.(*FeeEstimateResponse)
+	assert.Equal(t, uint64(1000), r.Rate)
+	assert.Equal(t, uint64(1000+10000+1000), r.Fee)
+	assert.Equal(t, r.Fee, r.PredictedFee, "no exchange rate change is scheduled")
+	assert.Equal(t, uint32(0), r.ChangeHeight)
+
+	resp, jErr = HandleV2FeeEstimate(state, &FeeEstimateRequest{EntrySize: 1025, Chain: true})
+	assert.Nil(t, jErr)
+	assert.Equal(t, uint64(12), resp.(*FeeEstimateResponse).EntryCredits)
+	assert.Equal(t, uint64(12000), resp.(*FeeEstimateResponse).Fee)
+
+	state.FERChangePrice = 3000
+	state.FERChangeHeight = state.GetDBHeightComplete() + 5
+	resp, jErr = HandleV2FeeEstimate(state, &FeeEstimateRequest{EntrySize: 100})
+	assert.Nil(t, jErr)
+	r = resp.(*FeeEstimateResponse)
+	assert.Equal(t, uint64(1000), r.Fee)
+	assert.Equal(t, uint64(3000), r.PredictedFee)
+	assert.Equal(t, state.FERChangeHeight, r.ChangeHeight)
+
+	// The length prefix of the external ID takes the entry over 1KB
+	resp, jErr = HandleV2FeeEstimate(state, &FeeEstimateRequest{EntrySize: 1024, ExtIDs: 1})
+	assert.Nil(t, jErr)
+	assert.Equal(t, uint64(2), resp.(*FeeEstimateResponse).EntryCredits)
+
+	for _, bad := range []*FeeEstimateRequest{{}, {Inputs: 1, EntrySize: 10}, {EntrySize: 10241}, {EntrySize: 10240, ExtIDs: 1}, {ExtIDs: -1, Chain: true}, {Inputs: 2, Signatures: 1}} {
+		_, jErr = HandleV2FeeEstimate(state, bad)
+		assert.NotNil(t, jErr, "estimated %+v", bad)
+	}
+}
+
 func TestJSONString(t *testing.T) {
 	eblock := new(EBlock)
 	eblock.Header.BlockSequenceNumber = 5
@@ -512,3 +632,36 @@ func v2Request(req *primitives.JSON2Request) (*primitives.JSON2Response, error)
 func number(n string) json.Number {
 	return json.Number(n)
 }
+
+func TestHandleV2AuthoritiesAtHeight(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	fed := "38bab1455b7bd7e5efd15c53c777c79d0c988e9210f1da49a99d95b3a6417be9"
+
+	resp, jErr := HandleV2AuthoritiesAtHeight(state, &HeightRequest{Height: 0})
+	assert.Nil(t, jErr)
+	assert.Len(t, resp.(*AuthoritiesAtHeightResponse).Federated, 0, "the first server is added for height 1")
+
+	resp, jErr = HandleV2AuthoritiesAtHeight(state, &HeightRequest{Height: 1})
+	assert.Nil(t, jErr)
+	r := resp.(*AuthoritiesAtHeightResponse)
+	if assert.Len(t, r.Federated, 1) {
+		assert.Equal(t, fed, r.Federated[0].ChainID)
+		assert.Equal(t, "cc1985cdfae4e32b5a454dfda8ce5e1361558482684f3367649c3ad852c8e31a", r.Federated[0].SigningKey)
+		assert.Equal(t, []HistoricKeyEntry{{1, r.Federated[0].SigningKey}}, r.Federated[0].KeyHistory)
+	}
+	assert.Len(t, r.Audit, 0)
+
+	_, jErr = HandleV2AuthoritiesAtHeight(state, &HeightRequest{Height: 1000})
+	assert.NotNil(t, jErr)
+
+	resp, jErr = HandleV2AuthorityChanges(state, &HeightRangeRequest{From: 0, To: 5})
+	assert.Nil(t, jErr)
+	changes := resp.(*AuthorityChangesResponse).Changes
+	if assert.Len(t, changes, 2) {
+		assert.Equal(t, AuthorityChange{fed, "added", "", "federated"}, changes[0])
+		assert.Equal(t, "signing-key", changes[1].Change)
+	}
+
+	_, jErr = HandleV2AuthorityChanges(state, &HeightRangeRequest{From: 2, To: 1})
+	assert.NotNil(t, jErr)
+}